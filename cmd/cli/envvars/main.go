@@ -16,6 +16,8 @@ import (
 type config struct {
 	DatabaseURL   string `split_words:"true"`
 	EnvVarsSecret string `split_words:"true"`
+	// GitProvider is where the repositories of the commands are hosted
+	GitProvider string `split_words:"true" default:"github"`
 }
 
 func main() {
@@ -56,7 +58,11 @@ func main() {
 		if len(args) >= 4 {
 			branch = pointer.String(args[4])
 		}
-		err := envVarProvider.Upsert(context.Background(), owner, repo, name, value, branch)
+		err := envVarProvider.Upsert(context.Background(), cfg.GitProvider, owner, repo, envvars.EnvVar{
+			Name:   name,
+			Value:  value,
+			Branch: branch,
+		})
 		if err != nil {
 			panic(errors.Wrap(err, "fail to upsert environment variable"))
 		}
@@ -70,7 +76,7 @@ func main() {
 		owner := args[0]
 		repo := args[1]
 		name := args[2]
-		err := envVarProvider.Delete(context.Background(), owner, repo, name, nil)
+		err := envVarProvider.Delete(context.Background(), cfg.GitProvider, owner, repo, name, nil)
 		if err != nil {
 			panic(errors.Wrap(err, "fail to delete environment variable"))
 		}
@@ -83,7 +89,7 @@ func main() {
 		}
 		owner := args[0]
 		repo := args[1]
-		vars, err := envVarProvider.ListByRepo(context.Background(), owner, repo)
+		vars, err := envVarProvider.ListByRepo(context.Background(), cfg.GitProvider, owner, repo)
 		if err != nil {
 			panic(errors.Wrap(err, "fail to list environment variables"))
 		}
@@ -15,6 +15,8 @@ import (
 type config struct {
 	DatabaseURL          string `split_words:"true"`
 	PrivRegistriesSecret string `split_words:"true"`
+	// GitProvider is where the owners of the commands are hosted
+	GitProvider string `split_words:"true" default:"github"`
 }
 
 func main() {
@@ -49,7 +51,7 @@ func main() {
 		}
 		owner := args[0]
 		image := args[1]
-		creds, err := privRegistryProvider.FetchCreds(context.Background(), owner, image)
+		creds, err := privRegistryProvider.FetchCreds(context.Background(), cfg.GitProvider, owner, image)
 		if err != nil {
 			if errors.Is(err, privregistry.ErrRegistryNotFound) {
 				fmt.Println("Private registry not found")
@@ -68,7 +70,7 @@ func main() {
 		url := args[1]
 		provider := args[2]
 		credentials := args[3]
-		err := privRegistryProvider.StoreRegistry(context.Background(), owner, url, provider, credentials)
+		err := privRegistryProvider.StoreRegistry(context.Background(), cfg.GitProvider, owner, url, provider, credentials)
 		if err != nil {
 			panic(errors.Wrap(err, "fail to store private registry"))
 		}
@@ -10,6 +10,7 @@ import (
 	"github.com/ergomake/ergomake/internal/api"
 	"github.com/ergomake/ergomake/internal/env"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
 )
 
 func main() {
@@ -19,7 +20,12 @@ func main() {
 		panic(errors.Wrap(err, "fail to load environment variables"))
 	}
 
-	ghApp, err := ghapp.NewGithubClient(cfg.GithubPrivateKey, cfg.GithubAppID)
+	ghEndpoint, err := ghendpoint.New(cfg.GithubURL, cfg.GithubAPIURL, cfg.GithubUploadURL)
+	if err != nil {
+		panic(errors.Wrap(err, "fail to create GitHub endpoint"))
+	}
+
+	ghApp, err := ghapp.NewGithubClient(cfg.GithubPrivateKey, cfg.GithubAppID, ghEndpoint)
 	if err != nil {
 		panic(errors.Wrap(err, "fail to create GitHub client"))
 	}
@@ -8,22 +8,33 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/client-go/kubernetes/scheme"
 
+	"github.com/ergomake/ergomake/internal/allowedhosts"
 	"github.com/ergomake/ergomake/internal/api"
 	"github.com/ergomake/ergomake/internal/buildpack"
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/deploymodes"
 	"github.com/ergomake/ergomake/internal/elastic"
 	"github.com/ergomake/ergomake/internal/env"
 	"github.com/ergomake/ergomake/internal/environments"
 	"github.com/ergomake/ergomake/internal/envvars"
+	"github.com/ergomake/ergomake/internal/gitea/giteaclient"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
 	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/github/ghoauth"
+	"github.com/ergomake/ergomake/internal/gitlab/glclient"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/launchqueue"
 	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/payment"
 	"github.com/ergomake/ergomake/internal/permanentbranches"
+	"github.com/ergomake/ergomake/internal/prfilters"
 	"github.com/ergomake/ergomake/internal/privregistry"
 	"github.com/ergomake/ergomake/internal/servicelogs"
 	"github.com/ergomake/ergomake/internal/stale"
+	"github.com/ergomake/ergomake/internal/urltemplates"
 	"github.com/ergomake/ergomake/internal/users"
 	"github.com/ergomake/ergomake/internal/watcher"
 
@@ -46,7 +57,13 @@ func main() {
 		log.Fatal().AnErr("err", err).Msg("fail to connect to database")
 	}
 
-	ghApp, err := ghapp.NewGithubClient(cfg.GithubPrivateKey, cfg.GithubAppID)
+	ghEndpoint, err := ghendpoint.New(cfg.GithubURL, cfg.GithubAPIURL, cfg.GithubUploadURL)
+	if err != nil {
+		log.Fatal().AnErr("err", err).Msg("fail to create GitHub endpoint")
+	}
+	ghoauth.UseEndpoint(ghEndpoint)
+
+	ghApp, err := ghapp.NewGithubClient(cfg.GithubPrivateKey, cfg.GithubAppID, ghEndpoint)
 	if err != nil {
 		log.Fatal().AnErr("err", err).Msg("fail to create GitHub client")
 	}
@@ -69,6 +86,10 @@ func main() {
 		cfg.StripeProfessionalPlanProductID, cfg.Friends, cfg.BestFriends)
 
 	permanentBranchesProvider := permanentbranches.NewDBEnvironmentsProvider(db)
+	urlTemplatesProvider := urltemplates.NewDBURLTemplatesProvider(db)
+	allowedHostsProvider := allowedhosts.NewDBAllowedHostsProvider(db)
+	deployModesProvider := deploymodes.NewDBDeployModesProvider(db)
+	prFiltersProvider := prfilters.NewDBPRFiltersProvider(db)
 
 	environmentsProvider := environments.NewDBEnvironmentsProvider(
 		db,
@@ -76,39 +97,79 @@ func main() {
 		cfg.EnvironmentsLimit,
 		permanentBranchesProvider,
 		clusterClient,
+		ghApp,
 	)
 
 	usersService := users.NewDBUsersService(db)
 	privRegistryProvider := privregistry.NewDBPrivRegistryProvider(db, cfg.PrivRegistriesSecret)
 
-	ghLauncher := ghlauncher.NewGHLauncher(
+	glProjectsProvider := glprojects.NewDBGLProjectsProvider(db, cfg.GitlabTokensSecret)
+	glClient, err := glclient.NewGitLabClient(cfg.GitlabURL, glProjectsProvider)
+	if err != nil {
+		log.Fatal().AnErr("err", err).Msg("fail to create GitLab client")
+	}
+
+	giteaClient, err := giteaclient.NewGiteaClient(cfg.GiteaURL, cfg.GiteaToken)
+	if err != nil {
+		log.Fatal().AnErr("err", err).Msg("fail to create Gitea client")
+	}
+
+	envLauncher := launcher.NewLauncher(
 		db,
-		ghApp,
 		clusterClient,
 		envVarsProvider,
 		privRegistryProvider,
 		environmentsProvider,
+		paymentProvider,
+		urlTemplatesProvider,
+		allowedHostsProvider,
 		cfg.DockerhubPullSecretName,
 		cfg.FrontendURL,
+		ghlauncher.NewGitHubHost(ghApp),
+		launcher.NewReportingHost(database.ProviderGitLab, glClient),
+		launcher.NewReportingHost(database.ProviderGitea, giteaClient),
+	)
+
+	launchQueue := launchqueue.NewDBLaunchQueue(
+		db,
+		envLauncher,
+		environmentsProvider,
+		deployModesProvider,
+		cfg.LaunchQueueConcurrency,
+		cfg.LaunchQueueOwnerConcurrency,
 	)
 
 	var wg sync.WaitGroup
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		launchQueue.Run(context.Background())
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		api := api.NewServer(
-			ghLauncher,
+			envLauncher,
 			privRegistryProvider,
 			db,
 			logStreamer,
 			ghApp,
+			ghEndpoint,
 			clusterClient,
 			envVarsProvider,
 			environmentsProvider,
 			usersService,
 			paymentProvider,
 			permanentBranchesProvider,
+			urlTemplatesProvider,
+			allowedHostsProvider,
+			deployModesProvider,
+			launchQueue,
+			prFiltersProvider,
+			glClient,
+			glProjectsProvider,
 			&cfg,
 		)
 		api.Listen(":8080")
@@ -122,6 +183,7 @@ func main() {
 			clusterClient,
 			environmentsProvider,
 			paymentProvider,
+			ghApp,
 			cfg.FrontendURL,
 			time.Hour,
 			cfg.IngressNamespace,
@@ -149,7 +211,7 @@ func main() {
 		innerWg.Wait()
 	}()
 
-	stopWatcher := watcher.WatchEnvironments(context.Background(), db, environmentsProvider, ghApp, ghLauncher)
+	stopWatcher := watcher.WatchEnvironments(context.Background(), db, environmentsProvider, ghApp, envLauncher)
 	defer stopWatcher()
 
 	clean, err := buildpack.WatchBuilds(clusterClient, db, ghApp, cfg.FrontendURL)
@@ -0,0 +1,240 @@
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/api"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
+	"github.com/ergomake/ergomake/internal/launcher"
+	allowedhostsMocks "github.com/ergomake/ergomake/mocks/allowedhosts"
+	clusterMocks "github.com/ergomake/ergomake/mocks/cluster"
+	deploymodesMocks "github.com/ergomake/ergomake/mocks/deploymodes"
+	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
+	envvarsMocks "github.com/ergomake/ergomake/mocks/envvars"
+	ghAppMocks "github.com/ergomake/ergomake/mocks/github/ghapp"
+	glclientMocks "github.com/ergomake/ergomake/mocks/gitlab/glclient"
+	glprojectsMocks "github.com/ergomake/ergomake/mocks/gitlab/glprojects"
+	launcherMocks "github.com/ergomake/ergomake/mocks/launcher"
+	launchqueueMocks "github.com/ergomake/ergomake/mocks/launchqueue"
+	paymentMocks "github.com/ergomake/ergomake/mocks/payment"
+	permanentbranchesMocks "github.com/ergomake/ergomake/mocks/permanentbranches"
+	prfiltersMocks "github.com/ergomake/ergomake/mocks/prfilters"
+	privregistryMocks "github.com/ergomake/ergomake/mocks/privregistry"
+	servicelogsMocks "github.com/ergomake/ergomake/mocks/servicelogs"
+	urltemplatesMocks "github.com/ergomake/ergomake/mocks/urltemplates"
+	usersMocks "github.com/ergomake/ergomake/mocks/users"
+)
+
+func getPullRequestEvent(action string, headRepoID int) map[string]interface{} {
+	return map[string]interface{}{
+		"action": action,
+		"number": 3,
+		"pull_request": map[string]interface{}{
+			"head": map[string]interface{}{"ref": "feature", "sha": "abc123", "repo_id": headRepoID},
+			"base": map[string]interface{}{"ref": "main", "sha": "def456", "repo_id": 1},
+		},
+		"repository": map[string]interface{}{"id": 1, "name": "repo", "owner": map[string]interface{}{"login": "owner"}},
+		"sender":     map[string]interface{}{"login": "author"},
+	}
+}
+
+func getPushEvent(after string) map[string]interface{} {
+	return map[string]interface{}{
+		"ref":        "refs/heads/main",
+		"after":      after,
+		"repository": map[string]interface{}{"id": 1, "name": "repo", "owner": map[string]interface{}{"login": "owner"}},
+		"pusher":     map[string]interface{}{"login": "author"},
+	}
+}
+
+func sign(t *testing.T, payload interface{}) string {
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestV2GiteaWebhook(t *testing.T) {
+	prNumber := 3
+	prTerminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitea,
+		Owner:    "owner",
+		Repo:     "repo",
+		Branch:   "feature",
+		PrNumber: &prNumber,
+	}
+	prLaunchReq := launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitea,
+		Owner:       "owner",
+		BranchOwner: "owner",
+		Repo:        "repo",
+		Branch:      "feature",
+		SHA:         "abc123",
+		PrNumber:    &prNumber,
+		Author:      "author",
+		IsPrivate:   true,
+	}
+
+	type mocks struct {
+		environmentsProvider *environmentsMocks.EnvironmentsProvider
+		launchQueue          *launchqueueMocks.LaunchQueue
+	}
+	testCases := []struct {
+		name           string
+		event          string
+		signature      string
+		payload        interface{}
+		expectedStatus int
+		setup          func(m mocks)
+	}{
+		{
+			name:           "missing signature gives 401",
+			event:          "pull_request",
+			payload:        getPullRequestEvent("opened", 1),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid signature gives 401",
+			event:          "pull_request",
+			signature:      "invalid",
+			payload:        getPullRequestEvent("opened", 1),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "opened pull request launches environment",
+			event:          "pull_request",
+			payload:        getPullRequestEvent("opened", 1),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.launchQueue.EXPECT().EnqueueLaunch(mock.Anything, prTerminateReq, prLaunchReq).Return(nil)
+			},
+		},
+		{
+			name:           "synchronized pull request launches environment",
+			event:          "pull_request",
+			payload:        getPullRequestEvent("synchronized", 1),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.launchQueue.EXPECT().EnqueueLaunch(mock.Anything, prTerminateReq, prLaunchReq).Return(nil)
+			},
+		},
+		{
+			name:           "pull request from fork is ignored",
+			event:          "pull_request",
+			payload:        getPullRequestEvent("opened", 2),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "closed pull request terminates environment",
+			event:          "pull_request",
+			payload:        getPullRequestEvent("closed", 1),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.launchQueue.EXPECT().EnqueueTerminate(mock.Anything, prTerminateReq).Return(nil)
+			},
+		},
+		{
+			name:           "push to permanent branch launches environment",
+			event:          "push",
+			payload:        getPushEvent("abc123"),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.environmentsProvider.EXPECT().ShouldDeploy(mock.Anything, "owner", "repo", "main").Return(true, nil)
+				m.launchQueue.EXPECT().EnqueueLaunch(
+					mock.Anything,
+					environments.TerminateEnvironmentRequest{
+						Provider: database.ProviderGitea,
+						Owner:    "owner",
+						Repo:     "repo",
+						Branch:   "main",
+					},
+					launcher.LaunchEnvironmentRequest{
+						Provider:    database.ProviderGitea,
+						Owner:       "owner",
+						BranchOwner: "owner",
+						Repo:        "repo",
+						Branch:      "main",
+						SHA:         "abc123",
+						Author:      "author",
+						IsPrivate:   true,
+					},
+				).Return(nil)
+			},
+		},
+		{
+			name:           "push to other branches is ignored",
+			event:          "push",
+			payload:        getPushEvent("abc123"),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.environmentsProvider.EXPECT().ShouldDeploy(mock.Anything, "owner", "repo", "main").Return(false, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := testutils.CreateRandomDB(t)
+
+			m := mocks{
+				environmentsProvider: environmentsMocks.NewEnvironmentsProvider(t),
+				launchQueue:          launchqueueMocks.NewLaunchQueue(t),
+			}
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			apiServer := api.NewServer(
+				launcherMocks.NewLauncher(t),
+				privregistryMocks.NewPrivRegistryProvider(t),
+				db,
+				servicelogsMocks.NewLogStreamer(t),
+				ghAppMocks.NewGHAppClient(t),
+				ghendpoint.GitHubDotCom,
+				clusterMocks.NewClient(t),
+				envvarsMocks.NewEnvVarsProvider(t),
+				m.environmentsProvider,
+				usersMocks.NewService(t),
+				paymentMocks.NewPaymentProvider(t),
+				permanentbranchesMocks.NewPermanentBranchesProvider(t),
+				urltemplatesMocks.NewURLTemplatesProvider(t),
+				allowedhostsMocks.NewAllowedHostsProvider(t),
+				deploymodesMocks.NewDeployModesProvider(t),
+				m.launchQueue,
+				prfiltersMocks.NewPRFiltersProvider(t),
+				glclientMocks.NewGLClient(t),
+				glprojectsMocks.NewGLProjectsProvider(t),
+				&api.Config{GiteaWebhookSecret: "secret"},
+			)
+
+			signature := tc.signature
+			if signature == "" && tc.expectedStatus != http.StatusUnauthorized {
+				signature = sign(t, tc.payload)
+			}
+
+			server := httptest.NewServer(apiServer)
+			e := httpexpect.Default(t, server.URL)
+			e.POST("/v2/gitea/webhook").
+				WithHeader("X-Gitea-Event", tc.event).
+				WithHeader("X-Gitea-Signature", signature).
+				WithJSON(tc.payload).
+				Expect().Status(tc.expectedStatus)
+		})
+	}
+}
@@ -13,15 +13,23 @@ import (
 	"github.com/ergomake/ergomake/e2e/testutils"
 	"github.com/ergomake/ergomake/internal/api"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
+	allowedhostsMocks "github.com/ergomake/ergomake/mocks/allowedhosts"
 	clusterMocks "github.com/ergomake/ergomake/mocks/cluster"
+	deploymodesMocks "github.com/ergomake/ergomake/mocks/deploymodes"
 	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
 	envvarsMocks "github.com/ergomake/ergomake/mocks/envvars"
 	ghAppMocks "github.com/ergomake/ergomake/mocks/github/ghapp"
-	ghlauncherMocks "github.com/ergomake/ergomake/mocks/github/ghlauncher"
+	glclientMocks "github.com/ergomake/ergomake/mocks/gitlab/glclient"
+	glprojectsMocks "github.com/ergomake/ergomake/mocks/gitlab/glprojects"
+	launcherMocks "github.com/ergomake/ergomake/mocks/launcher"
+	launchqueueMocks "github.com/ergomake/ergomake/mocks/launchqueue"
 	paymentMocks "github.com/ergomake/ergomake/mocks/payment"
 	permanentbranchesMocks "github.com/ergomake/ergomake/mocks/permanentbranches"
+	prfiltersMocks "github.com/ergomake/ergomake/mocks/prfilters"
 	privregistryMocks "github.com/ergomake/ergomake/mocks/privregistry"
 	servicelogsMocks "github.com/ergomake/ergomake/mocks/servicelogs"
+	urltemplatesMocks "github.com/ergomake/ergomake/mocks/urltemplates"
 	usersMocks "github.com/ergomake/ergomake/mocks/users"
 )
 
@@ -112,17 +120,25 @@ func TestMarketplaceWebhook(t *testing.T) {
 
 			ghApp := ghAppMocks.NewGHAppClient(t)
 			apiServer := api.NewServer(
-				ghlauncherMocks.NewGHLauncher(t),
+				launcherMocks.NewLauncher(t),
 				privregistryMocks.NewPrivRegistryProvider(t),
 				db,
 				servicelogsMocks.NewLogStreamer(t),
 				ghApp,
+				ghendpoint.GitHubDotCom,
 				clusterClient,
 				envvarsMocks.NewEnvVarsProvider(t),
 				environmentsMocks.NewEnvironmentsProvider(t),
 				usersMocks.NewService(t),
 				paymentMocks.NewPaymentProvider(t),
 				permanentbranchesMocks.NewPermanentBranchesProvider(t),
+				urltemplatesMocks.NewURLTemplatesProvider(t),
+				allowedhostsMocks.NewAllowedHostsProvider(t),
+				deploymodesMocks.NewDeployModesProvider(t),
+				launchqueueMocks.NewLaunchQueue(t),
+				prfiltersMocks.NewPRFiltersProvider(t),
+				glclientMocks.NewGLClient(t),
+				glprojectsMocks.NewGLProjectsProvider(t),
 				cfg,
 			)
 
@@ -24,13 +24,21 @@ import (
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
+	"github.com/ergomake/ergomake/internal/launchqueue"
+	"github.com/ergomake/ergomake/internal/prfilters"
+	allowedhostsMocks "github.com/ergomake/ergomake/mocks/allowedhosts"
+	deploymodesMocks "github.com/ergomake/ergomake/mocks/deploymodes"
 	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
 	envvarsMocks "github.com/ergomake/ergomake/mocks/envvars"
-	ghlauncherMocks "github.com/ergomake/ergomake/mocks/github/ghlauncher"
+	glclientMocks "github.com/ergomake/ergomake/mocks/gitlab/glclient"
+	glprojectsMocks "github.com/ergomake/ergomake/mocks/gitlab/glprojects"
+	launcherMocks "github.com/ergomake/ergomake/mocks/launcher"
 	paymentMocks "github.com/ergomake/ergomake/mocks/payment"
 	permanentbranchesMocks "github.com/ergomake/ergomake/mocks/permanentbranches"
 	privregistryMocks "github.com/ergomake/ergomake/mocks/privregistry"
 	servicelogsMocks "github.com/ergomake/ergomake/mocks/servicelogs"
+	urltemplatesMocks "github.com/ergomake/ergomake/mocks/urltemplates"
 	usersMocks "github.com/ergomake/ergomake/mocks/users"
 )
 
@@ -138,7 +146,7 @@ func waitForNewDBEnvironment(db *database.DB, pr int, owner, repo, branch string
 	timeout := time.After(10 * time.Second)
 
 	for {
-		envs, err := db.FindEnvironmentsByPullRequest(pr, owner, repo, branch, database.FindEnvironmentsOptions{})
+		envs, err := db.FindEnvironmentsByPullRequest(database.ProviderGitHub, pr, owner, repo, branch, database.FindEnvironmentsOptions{})
 		if err != nil {
 			return nil, err
 		}
@@ -181,7 +189,7 @@ func waitForDBEnvironmentDeletion(db *database.DB, pr int, owner, repo, branch s
 	// wait at most 10 seconds for all envs to be deleted from db
 	timeout := time.After(10 * time.Second)
 	for {
-		envs, err := db.FindEnvironmentsByPullRequest(pr, owner, repo, branch, database.FindEnvironmentsOptions{})
+		envs, err := db.FindEnvironmentsByPullRequest(database.ProviderGitHub, pr, owner, repo, branch, database.FindEnvironmentsOptions{})
 		if err != nil {
 			return err
 		}
@@ -241,6 +249,28 @@ func TestV2GithubWebhook(t *testing.T) {
 		}
 	}
 
+	getComment := func(body string) github.IssueCommentEvent {
+		return github.IssueCommentEvent{
+			Action: github.String("created"),
+			Repo: &github.Repository{
+				Owner: &github.User{
+					Login: github.String("ergomake"),
+				},
+				Name: github.String("preview-e2e-app"),
+			},
+			Issue: &github.Issue{
+				Number:           github.Int(1),
+				PullRequestLinks: &github.PullRequestLinks{URL: github.String("https://github.com")},
+			},
+			Comment: &github.IssueComment{
+				Body: github.String(body),
+			},
+			Sender: &github.User{
+				Login: github.String("vieiralucas"),
+			},
+		}
+	}
+
 	tt := []*testCase{
 		{
 			name:    "sends 401 when missing signature",
@@ -259,6 +289,15 @@ func TestV2GithubWebhook(t *testing.T) {
 			payload: "not valid",
 			want:    want{status: http.StatusBadRequest},
 		},
+		{
+			name: "ignores pull request comments that are not commands",
+			headers: map[string]string{
+				"X-Hub-Signature-256": genSignature(t, getComment("looks good to me")),
+				"X-GitHub-Event":      "issue_comment",
+			},
+			payload: getComment("looks good to me"),
+			want:    want{status: http.StatusNoContent},
+		},
 		{
 			name: "spins up an environment when a pr is opened",
 			headers: map[string]string{
@@ -423,7 +462,7 @@ func TestV2GithubWebhook(t *testing.T) {
 				branch := payload.GetPullRequest().GetHead().GetRef()
 				pr := payload.GetPullRequest().GetNumber()
 
-				envs, err := db.FindEnvironmentsByPullRequest(pr, owner, repo, branch, database.FindEnvironmentsOptions{})
+				envs, err := db.FindEnvironmentsByPullRequest(database.ProviderGitHub, pr, owner, repo, branch, database.FindEnvironmentsOptions{})
 				require.NoError(t, err)
 				namespaces := []string{}
 				for _, env := range envs {
@@ -496,7 +535,7 @@ func TestV2GithubWebhook(t *testing.T) {
 				branch := payload.GetPullRequest().GetHead().GetRef()
 				pr := payload.GetPullRequest().GetNumber()
 
-				envs, err := db.FindEnvironmentsByPullRequest(pr, owner, repo, branch, database.FindEnvironmentsOptions{})
+				envs, err := db.FindEnvironmentsByPullRequest(database.ProviderGitHub, pr, owner, repo, branch, database.FindEnvironmentsOptions{})
 				require.NoError(t, err)
 				require.Greater(t, len(envs), 0)
 				namespaces := []string{}
@@ -544,20 +583,35 @@ func TestV2GithubWebhook(t *testing.T) {
 
 			db := testutils.CreateRandomDB(t)
 
-			ghApp, err := ghapp.NewGithubClient(cfg.GithubPrivateKey, cfg.GithubAppID)
+			ghApp, err := ghapp.NewGithubClient(cfg.GithubPrivateKey, cfg.GithubAppID, ghendpoint.GitHubDotCom)
 			require.NoError(t, err)
 			apiServer := api.NewServer(
-				ghlauncherMocks.NewGHLauncher(t),
+				launcherMocks.NewLauncher(t),
 				privregistryMocks.NewPrivRegistryProvider(t),
 				db,
 				servicelogsMocks.NewLogStreamer(t),
 				ghApp,
+				ghendpoint.GitHubDotCom,
 				clusterClient,
 				envvarsMocks.NewEnvVarsProvider(t),
 				environmentsMocks.NewEnvironmentsProvider(t),
 				usersMocks.NewService(t),
 				paymentMocks.NewPaymentProvider(t),
 				permanentbranchesMocks.NewPermanentBranchesProvider(t),
+				urltemplatesMocks.NewURLTemplatesProvider(t),
+				allowedhostsMocks.NewAllowedHostsProvider(t),
+				deploymodesMocks.NewDeployModesProvider(t),
+				launchqueue.NewDBLaunchQueue(
+					db,
+					launcherMocks.NewLauncher(t),
+					environmentsMocks.NewEnvironmentsProvider(t),
+					deploymodesMocks.NewDeployModesProvider(t),
+					0,
+					0,
+				),
+				prfilters.NewDBPRFiltersProvider(db),
+				glclientMocks.NewGLClient(t),
+				glprojectsMocks.NewGLProjectsProvider(t),
 				&cfg,
 			)
 
@@ -0,0 +1,265 @@
+package gitlab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/api"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/launcher"
+	allowedhostsMocks "github.com/ergomake/ergomake/mocks/allowedhosts"
+	clusterMocks "github.com/ergomake/ergomake/mocks/cluster"
+	deploymodesMocks "github.com/ergomake/ergomake/mocks/deploymodes"
+	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
+	envvarsMocks "github.com/ergomake/ergomake/mocks/envvars"
+	ghAppMocks "github.com/ergomake/ergomake/mocks/github/ghapp"
+	glclientMocks "github.com/ergomake/ergomake/mocks/gitlab/glclient"
+	glprojectsMocks "github.com/ergomake/ergomake/mocks/gitlab/glprojects"
+	launcherMocks "github.com/ergomake/ergomake/mocks/launcher"
+	launchqueueMocks "github.com/ergomake/ergomake/mocks/launchqueue"
+	paymentMocks "github.com/ergomake/ergomake/mocks/payment"
+	permanentbranchesMocks "github.com/ergomake/ergomake/mocks/permanentbranches"
+	prfiltersMocks "github.com/ergomake/ergomake/mocks/prfilters"
+	privregistryMocks "github.com/ergomake/ergomake/mocks/privregistry"
+	servicelogsMocks "github.com/ergomake/ergomake/mocks/servicelogs"
+	urltemplatesMocks "github.com/ergomake/ergomake/mocks/urltemplates"
+	usersMocks "github.com/ergomake/ergomake/mocks/users"
+)
+
+func getMergeRequestEvent(action, oldRev string, sourceProjectID int) map[string]interface{} {
+	return map[string]interface{}{
+		"object_kind": "merge_request",
+		"user":        map[string]interface{}{"username": "author"},
+		"project":     map[string]interface{}{"path_with_namespace": "group/sub/repo"},
+		"object_attributes": map[string]interface{}{
+			"iid":               7,
+			"action":            action,
+			"source_branch":     "feature",
+			"source_project_id": sourceProjectID,
+			"target_project_id": 1,
+			"oldrev":            oldRev,
+			"last_commit":       map[string]interface{}{"id": "abc123"},
+		},
+	}
+}
+
+func getPushEvent(after string) map[string]interface{} {
+	return map[string]interface{}{
+		"object_kind":   "push",
+		"ref":           "refs/heads/main",
+		"after":         after,
+		"user_username": "author",
+		"project":       map[string]interface{}{"path_with_namespace": "group/sub/repo"},
+	}
+}
+
+func TestV2GitlabWebhook(t *testing.T) {
+	mrNumber := 7
+	project := &glprojects.Project{Owner: "group/sub", Repo: "repo", Token: "token", WebhookToken: "secret"}
+
+	mrTerminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitLab,
+		Owner:    "group/sub",
+		Repo:     "repo",
+		Branch:   "feature",
+		PrNumber: &mrNumber,
+	}
+	mrLaunchReq := launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitLab,
+		Owner:       "group/sub",
+		BranchOwner: "group/sub",
+		Repo:        "repo",
+		Branch:      "feature",
+		SHA:         "abc123",
+		PrNumber:    &mrNumber,
+		Author:      "author",
+		IsPrivate:   true,
+	}
+
+	type mocks struct {
+		glProjectsProvider   *glprojectsMocks.GLProjectsProvider
+		environmentsProvider *environmentsMocks.EnvironmentsProvider
+		launchQueue          *launchqueueMocks.LaunchQueue
+	}
+	testCases := []struct {
+		name           string
+		event          string
+		token          string
+		payload        interface{}
+		expectedStatus int
+		setup          func(m mocks)
+	}{
+		{
+			name:           "unknown project gives 401",
+			event:          "Merge Request Hook",
+			token:          "secret",
+			payload:        getMergeRequestEvent("open", "", 1),
+			expectedStatus: http.StatusUnauthorized,
+			setup: func(m mocks) {
+				m.glProjectsProvider.EXPECT().Get(mock.Anything, "group/sub", "repo").
+					Return(nil, glprojects.ErrProjectNotFound)
+			},
+		},
+		{
+			name:           "invalid token gives 401",
+			event:          "Merge Request Hook",
+			token:          "invalid",
+			payload:        getMergeRequestEvent("open", "", 1),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "opened merge request launches environment",
+			event:          "Merge Request Hook",
+			token:          "secret",
+			payload:        getMergeRequestEvent("open", "", 1),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.launchQueue.EXPECT().EnqueueLaunch(mock.Anything, mrTerminateReq, mrLaunchReq).Return(nil)
+			},
+		},
+		{
+			name:           "merge request update with new commits launches environment",
+			event:          "Merge Request Hook",
+			token:          "secret",
+			payload:        getMergeRequestEvent("update", "def456", 1),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.launchQueue.EXPECT().EnqueueLaunch(mock.Anything, mrTerminateReq, mrLaunchReq).Return(nil)
+			},
+		},
+		{
+			name:           "merge request update without new commits is ignored",
+			event:          "Merge Request Hook",
+			token:          "secret",
+			payload:        getMergeRequestEvent("update", "", 1),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "merge request from fork is ignored",
+			event:          "Merge Request Hook",
+			token:          "secret",
+			payload:        getMergeRequestEvent("open", "", 2),
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "merged merge request terminates environment",
+			event:          "Merge Request Hook",
+			token:          "secret",
+			payload:        getMergeRequestEvent("merge", "", 1),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.launchQueue.EXPECT().EnqueueTerminate(mock.Anything, mrTerminateReq).Return(nil)
+			},
+		},
+		{
+			name:           "push to permanent branch launches environment",
+			event:          "Push Hook",
+			token:          "secret",
+			payload:        getPushEvent("abc123"),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.environmentsProvider.EXPECT().ShouldDeploy(mock.Anything, "group/sub", "repo", "main").Return(true, nil)
+				m.launchQueue.EXPECT().EnqueueLaunch(
+					mock.Anything,
+					environments.TerminateEnvironmentRequest{
+						Provider: database.ProviderGitLab,
+						Owner:    "group/sub",
+						Repo:     "repo",
+						Branch:   "main",
+					},
+					launcher.LaunchEnvironmentRequest{
+						Provider:    database.ProviderGitLab,
+						Owner:       "group/sub",
+						BranchOwner: "group/sub",
+						Repo:        "repo",
+						Branch:      "main",
+						SHA:         "abc123",
+						Author:      "author",
+						IsPrivate:   true,
+					},
+				).Return(nil)
+			},
+		},
+		{
+			name:           "push deleting a permanent branch terminates environment",
+			event:          "Push Hook",
+			token:          "secret",
+			payload:        getPushEvent("0000000000000000000000000000000000000000"),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.environmentsProvider.EXPECT().ShouldDeploy(mock.Anything, "group/sub", "repo", "main").Return(true, nil)
+				m.launchQueue.EXPECT().EnqueueTerminate(mock.Anything, environments.TerminateEnvironmentRequest{
+					Provider: database.ProviderGitLab,
+					Owner:    "group/sub",
+					Repo:     "repo",
+					Branch:   "main",
+				}).Return(nil)
+			},
+		},
+		{
+			name:           "push to other branches is ignored",
+			event:          "Push Hook",
+			token:          "secret",
+			payload:        getPushEvent("abc123"),
+			expectedStatus: http.StatusNoContent,
+			setup: func(m mocks) {
+				m.environmentsProvider.EXPECT().ShouldDeploy(mock.Anything, "group/sub", "repo", "main").Return(false, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := testutils.CreateRandomDB(t)
+
+			m := mocks{
+				glProjectsProvider:   glprojectsMocks.NewGLProjectsProvider(t),
+				environmentsProvider: environmentsMocks.NewEnvironmentsProvider(t),
+				launchQueue:          launchqueueMocks.NewLaunchQueue(t),
+			}
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+			m.glProjectsProvider.EXPECT().Get(mock.Anything, "group/sub", "repo").Return(project, nil).Maybe()
+
+			apiServer := api.NewServer(
+				launcherMocks.NewLauncher(t),
+				privregistryMocks.NewPrivRegistryProvider(t),
+				db,
+				servicelogsMocks.NewLogStreamer(t),
+				ghAppMocks.NewGHAppClient(t),
+				ghendpoint.GitHubDotCom,
+				clusterMocks.NewClient(t),
+				envvarsMocks.NewEnvVarsProvider(t),
+				m.environmentsProvider,
+				usersMocks.NewService(t),
+				paymentMocks.NewPaymentProvider(t),
+				permanentbranchesMocks.NewPermanentBranchesProvider(t),
+				urltemplatesMocks.NewURLTemplatesProvider(t),
+				allowedhostsMocks.NewAllowedHostsProvider(t),
+				deploymodesMocks.NewDeployModesProvider(t),
+				m.launchQueue,
+				prfiltersMocks.NewPRFiltersProvider(t),
+				glclientMocks.NewGLClient(t),
+				m.glProjectsProvider,
+				&api.Config{},
+			)
+
+			server := httptest.NewServer(apiServer)
+			e := httpexpect.Default(t, server.URL)
+			e.POST("/v2/gitlab/webhook").
+				WithHeader("X-Gitlab-Event", tc.event).
+				WithHeader("X-Gitlab-Token", tc.token).
+				WithJSON(tc.payload).
+				Expect().Status(tc.expectedStatus)
+		})
+	}
+}
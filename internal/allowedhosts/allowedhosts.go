@@ -0,0 +1,75 @@
+package allowedhosts
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+// AllowedHostsProvider stores the outbound hosts environments of a repository may reach,
+// entries are hostnames, IPs or CIDRs.
+type AllowedHostsProvider interface {
+	List(ctx context.Context, provider, owner, repo string) ([]string, error)
+	Replace(ctx context.Context, provider, owner, repo string, hosts []string) error
+}
+
+var hostnameRegexp = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func Validate(host string) error {
+	if _, _, err := net.ParseCIDR(host); err == nil {
+		return nil
+	}
+
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	if len(host) > 253 || !hostnameRegexp.MatchString(strings.ToLower(host)) {
+		return errors.Errorf("%s is not a hostname, an IP or a CIDR", host)
+	}
+
+	return nil
+}
+
+// ToCIDRs turns every allowed host into the CIDRs a NetworkPolicy can match, hostnames are
+// resolved at the time of the call so they only keep working while their addresses don't change.
+func ToCIDRs(ctx context.Context, hosts []string) []string {
+	cidrs := []string{}
+	for _, host := range hosts {
+		if _, ipNet, err := net.ParseCIDR(host); err == nil {
+			cidrs = append(cidrs, ipNet.String())
+			continue
+		}
+
+		if ip := net.ParseIP(host); ip != nil {
+			cidrs = append(cidrs, ipToCIDR(ip))
+			continue
+		}
+
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("host", host).Msg("fail to resolve allowed host")
+			continue
+		}
+
+		for _, addr := range addrs {
+			cidrs = append(cidrs, ipToCIDR(addr.IP))
+		}
+	}
+
+	return cidrs
+}
+
+func ipToCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return fmt.Sprintf("%s/32", ip.String())
+	}
+
+	return fmt.Sprintf("%s/128", ip.String())
+}
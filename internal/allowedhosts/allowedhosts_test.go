@@ -0,0 +1,27 @@
+package allowedhosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, host := range []string{"api.stripe.com", "localhost", "10.0.0.0/8", "1.2.3.4", "::1", "2001:db8::/32"} {
+		assert.NoError(t, Validate(host), host)
+	}
+
+	for _, host := range []string{"", "https://api.stripe.com", "api.stripe.com/v1", "-nope.com", "a..b", "10.0.0.0/33"} {
+		assert.Error(t, Validate(host), host)
+	}
+}
+
+func TestToCIDRs(t *testing.T) {
+	t.Parallel()
+
+	cidrs := ToCIDRs(context.Background(), []string{"10.1.2.3/8", "1.2.3.4", "2001:db8::1"})
+	assert.Equal(t, []string{"10.0.0.0/8", "1.2.3.4/32", "2001:db8::1/128"}, cidrs)
+}
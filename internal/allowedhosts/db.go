@@ -0,0 +1,58 @@
+package allowedhosts
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/reposettings"
+)
+
+type dbAllowedHostsProvider struct {
+	db *database.DB
+}
+
+func NewDBAllowedHostsProvider(db *database.DB) *dbAllowedHostsProvider {
+	return &dbAllowedHostsProvider{db}
+}
+
+func (ahp *dbAllowedHostsProvider) List(ctx context.Context, provider, owner, repo string) ([]string, error) {
+	hosts := make([]string, 0)
+	err := reposettings.Scope(ctx, ahp.db.DB, "allowed_hosts", reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}).
+		Order("host ASC").
+		Pluck("host", &hosts).Error
+
+	return hosts, errors.Wrapf(err, "fail to query allowed hosts of %s/%s", owner, repo)
+}
+
+func (ahp *dbAllowedHostsProvider) Replace(ctx context.Context, provider, owner, repo string, hosts []string) error {
+	key := reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}
+	return ahp.db.Transaction(func(tx *gorm.DB) error {
+		err := reposettings.Reset(ctx, tx, "allowed_hosts", key)
+		if err != nil {
+			return err
+		}
+
+		seen := map[string]struct{}{}
+		for _, host := range hosts {
+			if _, ok := seen[host]; ok {
+				continue
+			}
+			seen[host] = struct{}{}
+
+			err := tx.Table("allowed_hosts").Create(map[string]interface{}{
+				"provider": provider,
+				"owner":    owner,
+				"repo":     repo,
+				"host":     host,
+			}).Error
+			if err != nil {
+				return errors.Wrapf(err, "fail to create allowed host %s of %s/%s", host, owner, repo)
+			}
+		}
+
+		return nil
+	})
+}
@@ -5,24 +5,36 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/ergomake/ergomake/internal/allowedhosts"
 	"github.com/ergomake/ergomake/internal/api/auth"
 	environmentsApi "github.com/ergomake/ergomake/internal/api/environments"
+	"github.com/ergomake/ergomake/internal/api/gitea"
 	"github.com/ergomake/ergomake/internal/api/github"
+	"github.com/ergomake/ergomake/internal/api/gitlab"
+	launchqueueApi "github.com/ergomake/ergomake/internal/api/launchqueue"
 	permanentbranchesApi "github.com/ergomake/ergomake/internal/api/permanentbranches"
 	"github.com/ergomake/ergomake/internal/api/registries"
+	"github.com/ergomake/ergomake/internal/api/reposettings"
 	"github.com/ergomake/ergomake/internal/api/stripe"
 	"github.com/ergomake/ergomake/internal/api/variables"
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/deploymodes"
 	"github.com/ergomake/ergomake/internal/environments"
 	"github.com/ergomake/ergomake/internal/envvars"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
+	"github.com/ergomake/ergomake/internal/gitlab/glclient"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/launchqueue"
 	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/payment"
 	"github.com/ergomake/ergomake/internal/permanentbranches"
+	"github.com/ergomake/ergomake/internal/prfilters"
 	"github.com/ergomake/ergomake/internal/privregistry"
 	"github.com/ergomake/ergomake/internal/servicelogs"
+	"github.com/ergomake/ergomake/internal/urltemplates"
 	"github.com/ergomake/ergomake/internal/users"
 )
 
@@ -32,6 +44,9 @@ type Config struct {
 	GithubAppID                     int64    `split_words:"true"`
 	GithubClientID                  string   `split_words:"true"`
 	GithubClientSecret              string   `split_words:"true"`
+	GithubURL                       string   `split_words:"true" default:"https://github.com"`
+	GithubAPIURL                    string   `split_words:"true"`
+	GithubUploadURL                 string   `split_words:"true"`
 	DatabaseURL                     string   `split_words:"true"`
 	Cluster                         string   `split_words:"true"`
 	AuthRedirectURL                 string   `split_words:"true"`
@@ -53,6 +68,14 @@ type Config struct {
 	Friends                         []string `split_words:"true"`
 	BestFriends                     []string `split_words:"true"`
 	DockerhubPullSecretName         string   `split_words:"true"`
+	ClusterDomain                   string   `split_words:"true"`
+	LaunchQueueConcurrency          int      `split_words:"true"`
+	LaunchQueueOwnerConcurrency     int      `split_words:"true"`
+	GitlabURL                       string   `split_words:"true" default:"https://gitlab.com"`
+	GitlabTokensSecret              string   `split_words:"true"`
+	GiteaURL                        string   `split_words:"true"`
+	GiteaToken                      string   `split_words:"true"`
+	GiteaWebhookSecret              string   `split_words:"true"`
 }
 
 type server struct {
@@ -76,17 +99,25 @@ func corsMiddleware(allowOrigin string) gin.HandlerFunc {
 }
 
 func NewServer(
-	ghLauncher ghlauncher.GHLauncher,
+	envLauncher launcher.Launcher,
 	privRegistryProvider privregistry.PrivRegistryProvider,
 	db *database.DB,
 	logStreamer servicelogs.LogStreamer,
 	ghApp ghapp.GHAppClient,
+	ghEndpoint *ghendpoint.Endpoint,
 	clusterClient cluster.Client,
 	envVarsProvider envvars.EnvVarsProvider,
 	environmentsProvider environments.EnvironmentsProvider,
 	usersService users.Service,
 	paymentProvider payment.PaymentProvider,
 	permanentBranchesProvider permanentbranches.PermanentBranchesProvider,
+	urlTemplatesProvider urltemplates.URLTemplatesProvider,
+	allowedHostsProvider allowedhosts.AllowedHostsProvider,
+	deployModesProvider deploymodes.DeployModesProvider,
+	launchQueue launchqueue.LaunchQueue,
+	prFiltersProvider prfilters.PRFiltersProvider,
+	glClient glclient.GLClient,
+	glProjectsProvider glprojects.GLProjectsProvider,
 	cfg *Config,
 ) *server {
 	router := gin.New()
@@ -103,7 +134,8 @@ func NewServer(
 	})
 
 	ghRouter := github.NewGithubRouter(
-		ghLauncher,
+		launchQueue,
+		prFiltersProvider,
 		db,
 		ghApp,
 		clusterClient,
@@ -117,6 +149,12 @@ func NewServer(
 	)
 	ghRouter.AddRoutes(v2.Group("/github"))
 
+	glRouter := gitlab.NewGitlabRouter(glClient, glProjectsProvider, launchQueue, environmentsProvider)
+	glRouter.AddRoutes(v2.Group("/gitlab"))
+
+	giteaRouter := gitea.NewGiteaRouter(launchQueue, environmentsProvider, cfg.GiteaWebhookSecret)
+	giteaRouter.AddRoutes(v2.Group("/gitea"))
+
 	stripeProvider := payment.NewStripePaymentProvider(
 		db, cfg.StripeSecretKey, cfg.StripeStandardPlanProductID, cfg.StripeProfessionalPlanProductID,
 		cfg.Friends, cfg.BestFriends)
@@ -132,13 +170,14 @@ func NewServer(
 		usersService,
 		cfg.FrontendURL,
 		ghApp,
+		ghEndpoint,
 	)
 	authRouter.AddRoutes(v2.Group("/auth"))
 
 	registriesRouter := registries.NewRegistriesRouter(privRegistryProvider)
 	registriesRouter.AddRoutes(v2)
 
-	environmentsRouter := environmentsApi.NewEnvironmentsRouter(db, logStreamer, clusterClient, cfg.JWTSecret)
+	environmentsRouter := environmentsApi.NewEnvironmentsRouter(db, logStreamer, clusterClient, envVarsProvider, cfg.JWTSecret)
 	environmentsRouter.AddRoutes(v2.Group("/environments"))
 
 	variablesRouter := variables.NewVariablesRouter(envVarsProvider)
@@ -146,12 +185,23 @@ func NewServer(
 
 	permanentbranchesRouter := permanentbranchesApi.NewPermanentBranchesRouter(
 		ghApp,
-		ghLauncher,
+		envLauncher,
 		permanentBranchesProvider,
 		environmentsProvider,
 	)
 	permanentbranchesRouter.AddRoutes(v2)
 
+	repoSettingsRouter := reposettings.NewRepoSettingsRouter(
+		reposettings.NewURLTemplateSetting(urlTemplatesProvider, cfg.ClusterDomain),
+		reposettings.NewAllowedHostsSetting(allowedHostsProvider),
+		reposettings.NewDeployModeSetting(deployModesProvider),
+		reposettings.NewPRFiltersSetting(prFiltersProvider),
+	)
+	repoSettingsRouter.AddRoutes(v2)
+
+	launchQueueRouter := launchqueueApi.NewLaunchQueueRouter(launchQueue)
+	launchQueueRouter.AddRoutes(v2)
+
 	return &server{router}
 }
 
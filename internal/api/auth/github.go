@@ -3,16 +3,24 @@ package auth
 import (
 	"context"
 
-	"github.com/google/go-github/v52/github"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/github/ghoauth"
 )
 
+// IsAuthorized tells whether the github user of authData belongs to owner on provider, sessions only
+// hold github users so owners of other providers are never authorized even when they share a name.
 // TODO: move this to ghoauth.GHOAuthClient
-func IsAuthorized(ctx context.Context, owner string, authData *AuthData) (bool, error) {
+func IsAuthorized(ctx context.Context, provider, owner string, authData *AuthData) (bool, error) {
+	if provider != database.ProviderGitHub {
+		return false, nil
+	}
+
 	tokenSource := oauth2.StaticTokenSource(authData.GithubToken)
 	oauth2Client := oauth2.NewClient(ctx, tokenSource)
-	client := github.NewClient(oauth2Client)
+	client := ghoauth.NewClient(oauth2Client)
 
 	user, _, err := client.Users.Get(ctx, "")
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
 	"github.com/ergomake/ergomake/internal/users"
 )
 
@@ -26,15 +27,13 @@ func NewAuthRouter(
 	usersService users.Service,
 	frontendURL string,
 	ghapp ghapp.GHAppClient,
+	ghEndpoint *ghendpoint.Endpoint,
 ) *authRouter {
 	oauthConfig := &oauth2.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		RedirectURL:  redirectURL,
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://github.com/login/oauth/authorize",
-			TokenURL: "https://github.com/login/oauth/access_token",
-		},
+		Endpoint:     ghEndpoint.OAuth2(),
 	}
 
 	return &authRouter{oauthConfig, jwtSecret, secure, usersService, frontendURL, ghapp}
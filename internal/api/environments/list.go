@@ -25,7 +25,7 @@ func (er *environmentsRouter) list(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to create registry")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -37,7 +37,7 @@ func (er *environmentsRouter) list(c *gin.Context) {
 		return
 	}
 
-	ownerEnvs, err := er.db.FindEnvironmentsByOwner(owner, database.FindEnvironmentsOptions{})
+	ownerEnvs, err := er.db.FindEnvironmentsByOwner(database.ProviderGitHub, owner, database.FindEnvironmentsOptions{})
 	if err != nil {
 		logger.Ctx(c).Err(err).Msgf("fail to find environments for owner %s", owner)
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -62,10 +62,20 @@ func (er *environmentsRouter) list(c *gin.Context) {
 
 		services := make([]gin.H, len(env.Services))
 		for i, service := range env.Services {
+			urls := make([]gin.H, len(service.Urls))
+			for j, url := range service.Urls {
+				urls[j] = gin.H{
+					"url":  url.Url,
+					"port": url.Port,
+					"path": url.Path,
+				}
+			}
+
 			services[i] = gin.H{
 				"id":    service.ID,
 				"name":  service.Name,
 				"url":   service.Url,
+				"urls":  urls,
 				"build": service.Build,
 			}
 		}
@@ -11,6 +11,8 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/envvars"
 	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/servicelogs"
 )
@@ -42,7 +44,7 @@ func (er *environmentsRouter) logs(c *gin.Context, build bool) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, env.Owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, env.Provider, env.Owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to check if caller is authorized")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -61,22 +63,45 @@ func (er *environmentsRouter) logs(c *gin.Context, build bool) {
 		return
 	}
 
+	// build secrets of any branch are redacted, builds of other branches may have logged them too
+	secretValues := []string{}
+	if build {
+		vars, err := er.envVarsProvider.ListByRepo(c, env.Provider, env.Owner, env.Repo)
+		if err != nil {
+			logger.Ctx(c).Err(err).Msgf("fail to list variables for repo %s/%s", env.Owner, env.Repo)
+			c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+		secretValues = envvars.BuildSecretValues(vars)
+	}
+
 	logChan := make(chan []servicelogs.LogEntry)
 	errChan := make(chan error)
 
 	if build {
-		containers := []string{
-			"detect",
-			"restore",
-			"build",
-			"completion",
+		// kpack builds run in their own namespace, the jobs of the other builders run in preview-builds
+		kpackServices := []database.Service{}
+		jobServices := []database.Service{}
+		for _, service := range services {
+			if service.BuildTool == database.BuildToolBuildpacks {
+				kpackServices = append(kpackServices, service)
+			} else {
+				jobServices = append(jobServices, service)
+			}
+		}
+
+		if len(kpackServices) > 0 {
+			containers := []string{
+				"detect",
+				"restore",
+				"build",
+				"completion",
+			}
+			go er.logStreamer.Stream(c.Request.Context(), kpackServices, "kpack", containers, logChan, errChan)
 		}
-		namespace := "kpack"
-		if env.BuildTool == "kaniko" {
-			namespace = "preview-builds"
-			containers = []string{}
+		if len(jobServices) > 0 {
+			go er.logStreamer.Stream(c.Request.Context(), jobServices, "preview-builds", []string{}, logChan, errChan)
 		}
-		go er.logStreamer.Stream(c.Request.Context(), services, namespace, containers, logChan, errChan)
 	} else {
 		go er.logStreamer.Stream(c.Request.Context(), services, env.ID.String(), nil, logChan, errChan)
 	}
@@ -90,6 +115,7 @@ func (er *environmentsRouter) logs(c *gin.Context, build bool) {
 		select {
 		case logs := <-logChan:
 			for _, log := range logs {
+				log.Message = envvars.Redact(log.Message, secretValues)
 				c.SSEvent("log", log)
 			}
 		case <-time.After(5 * time.Second):
@@ -5,23 +5,26 @@ import (
 
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/envvars"
 	"github.com/ergomake/ergomake/internal/servicelogs"
 )
 
 type environmentsRouter struct {
-	db            *database.DB
-	logStreamer   servicelogs.LogStreamer
-	clusterClient cluster.Client
-	jwtSecret     string
+	db              *database.DB
+	logStreamer     servicelogs.LogStreamer
+	clusterClient   cluster.Client
+	envVarsProvider envvars.EnvVarsProvider
+	jwtSecret       string
 }
 
 func NewEnvironmentsRouter(
 	db *database.DB,
 	logStreamer servicelogs.LogStreamer,
 	clusterClient cluster.Client,
+	envVarsProvider envvars.EnvVarsProvider,
 	jwtSecret string,
 ) *environmentsRouter {
-	return &environmentsRouter{db, logStreamer, clusterClient, jwtSecret}
+	return &environmentsRouter{db, logStreamer, clusterClient, envVarsProvider, jwtSecret}
 }
 
 func (er *environmentsRouter) AddRoutes(router *gin.RouterGroup) {
@@ -0,0 +1,30 @@
+package gitea
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/launchqueue"
+)
+
+type giteaRouter struct {
+	launchQueue          launchqueue.LaunchQueue
+	environmentsProvider environments.EnvironmentsProvider
+	webhookSecret        string
+}
+
+func NewGiteaRouter(
+	launchQueue launchqueue.LaunchQueue,
+	environmentsProvider environments.EnvironmentsProvider,
+	webhookSecret string,
+) *giteaRouter {
+	return &giteaRouter{
+		launchQueue,
+		environmentsProvider,
+		webhookSecret,
+	}
+}
+
+func (gtr *giteaRouter) AddRoutes(router *gin.RouterGroup) {
+	router.POST("/webhook", gtr.webhook)
+}
@@ -0,0 +1,265 @@
+package gitea
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/ginutils"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+const (
+	pullRequestEvent = "pull_request"
+	pushEvent        = "push"
+)
+
+type eventUser struct {
+	Login string `json:"login"`
+}
+
+type eventRepository struct {
+	ID    int64     `json:"id"`
+	Name  string    `json:"name"`
+	Owner eventUser `json:"owner"`
+}
+
+type pullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Head struct {
+			Ref    string `json:"ref"`
+			Sha    string `json:"sha"`
+			RepoID int64  `json:"repo_id"`
+		} `json:"head"`
+		Base struct {
+			RepoID int64 `json:"repo_id"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Repository eventRepository `json:"repository"`
+	Sender     eventUser       `json:"sender"`
+}
+
+type pushPayload struct {
+	Ref        string          `json:"ref"`
+	After      string          `json:"after"`
+	Repository eventRepository `json:"repository"`
+	Pusher     eventUser       `json:"pusher"`
+}
+
+// deletedSHA is the after of the push that deletes a branch
+const deletedSHA = "0000000000000000000000000000000000000000"
+
+func (gtr *giteaRouter) webhook(c *gin.Context) {
+	log := logger.Ctx(c.Request.Context())
+
+	var bodyBytes []byte
+	err := c.ShouldBindBodyWith(&bodyBytes, ginutils.BYTES)
+	if err != nil {
+		log.Err(err).Msg("fail to read body")
+		c.JSON(
+			http.StatusInternalServerError,
+			http.StatusText(http.StatusInternalServerError),
+		)
+		return
+	}
+
+	if !validSignature(bodyBytes, c.GetHeader("X-Gitea-Signature"), gtr.webhookSecret) {
+		c.JSON(
+			http.StatusUnauthorized,
+			http.StatusText(http.StatusUnauthorized),
+		)
+		return
+	}
+
+	giteaDelivery := c.GetHeader("X-Gitea-Delivery")
+
+	switch c.GetHeader("X-Gitea-Event") {
+	case pullRequestEvent:
+		var payload pullRequestPayload
+		err = json.Unmarshal(bodyBytes, &payload)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+			return
+		}
+
+		err = gtr.handlePullRequestEvent(giteaDelivery, &payload)
+	case pushEvent:
+		var payload pushPayload
+		err = json.Unmarshal(bodyBytes, &payload)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+			return
+		}
+
+		err = gtr.handlePushEvent(giteaDelivery, &payload)
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			http.StatusText(http.StatusInternalServerError),
+		)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// validSignature checks the HMAC-SHA256 of body gitea signs webhooks with,
+// without a secret every webhook is rejected
+func validSignature(body []byte, signature, secret string) bool {
+	if secret == "" {
+		return false
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func (gtr *giteaRouter) handlePullRequestEvent(giteaDelivery string, payload *pullRequestPayload) error {
+	owner := payload.Repository.Owner.Login
+	repo := payload.Repository.Name
+	branch := payload.PullRequest.Head.Ref
+
+	logCtx := logger.With(logger.Get()).
+		Str("giteaDelivery", giteaDelivery).
+		Str("action", payload.Action).
+		Str("owner", owner).
+		Str("repo", repo).
+		Str("branch", branch).
+		Int("prNumber", payload.Number).
+		Str("SHA", payload.PullRequest.Head.Sha).
+		Str("event", pullRequestEvent).
+		Logger()
+	log := &logCtx
+	ctx := log.WithContext(context.Background())
+
+	if payload.PullRequest.Head.RepoID != payload.PullRequest.Base.RepoID {
+		log.Info().Msg("pull request from a fork ignored")
+		return nil
+	}
+
+	terminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitea,
+		Owner:    owner,
+		Repo:     repo,
+		Branch:   branch,
+		PrNumber: &payload.Number,
+	}
+
+	switch payload.Action {
+	case "opened", "reopened", "synchronized":
+	case "closed":
+		err := gtr.launchQueue.EnqueueTerminate(ctx, terminateReq)
+		if err != nil {
+			log.Err(err).Msg("fail to enqueue environment termination")
+		}
+		return err
+	default:
+		return nil
+	}
+
+	log.Info().Msg("got a pull request event from gitea")
+
+	err := gtr.launchQueue.EnqueueLaunch(ctx, terminateReq, launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitea,
+		Owner:       owner,
+		BranchOwner: owner,
+		Repo:        repo,
+		Branch:      branch,
+		SHA:         payload.PullRequest.Head.Sha,
+		PrNumber:    &payload.Number,
+		Author:      payload.Sender.Login,
+		// repositories are always cloned with the token of the gitea user
+		IsPrivate: true,
+	})
+	if err != nil {
+		log.Err(err).Msg("fail to enqueue environment launch")
+	}
+
+	return err
+}
+
+func (gtr *giteaRouter) handlePushEvent(giteaDelivery string, payload *pushPayload) error {
+	if !strings.HasPrefix(payload.Ref, "refs/heads/") {
+		return nil
+	}
+
+	owner := payload.Repository.Owner.Login
+	repo := payload.Repository.Name
+	branch := strings.TrimPrefix(payload.Ref, "refs/heads/")
+
+	logCtx := logger.With(logger.Get()).
+		Str("giteaDelivery", giteaDelivery).
+		Str("owner", owner).
+		Str("repo", repo).
+		Str("author", payload.Pusher.Login).
+		Str("branch", branch).
+		Str("SHA", payload.After).
+		Str("event", pushEvent).
+		Logger()
+	log := &logCtx
+	ctx := log.WithContext(context.Background())
+
+	shouldDeploy, err := gtr.environmentsProvider.ShouldDeploy(ctx, owner, repo, branch)
+	if err != nil {
+		err = errors.Wrap(err, "fail to check if branch should be deployed")
+		log.Err(err).Msg("fail to handle push event")
+		return err
+	}
+
+	if !shouldDeploy {
+		return nil
+	}
+
+	terminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitea,
+		Owner:    owner,
+		Repo:     repo,
+		Branch:   branch,
+	}
+
+	if payload.After == deletedSHA {
+		err := gtr.launchQueue.EnqueueTerminate(ctx, terminateReq)
+		if err != nil {
+			log.Err(err).Msg("fail to enqueue termination of deleted branch environment")
+		}
+		return err
+	}
+
+	log.Info().Msg("got a push event from gitea")
+
+	err = gtr.launchQueue.EnqueueLaunch(ctx, terminateReq, launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitea,
+		Owner:       owner,
+		BranchOwner: owner,
+		Repo:        repo,
+		Branch:      branch,
+		SHA:         payload.After,
+		Author:      payload.Pusher.Login,
+		IsPrivate:   true,
+	})
+	if err != nil {
+		log.Err(err).Msg("fail to enqueue environment launch")
+	}
+
+	return err
+}
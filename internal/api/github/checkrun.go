@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+// handleCheckRunEvent redeploys the commit of a check run when its redeploy button is clicked or it is re-run
+func (r *githubRouter) handleCheckRunEvent(githubDelivery string, event *github.CheckRunEvent) error {
+	action := event.GetAction()
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repoName := event.GetRepo().GetName()
+	checkRun := event.GetCheckRun()
+	sha := checkRun.GetHeadSHA()
+	author := event.GetSender().GetLogin()
+
+	logCtx := logger.With(logger.Get()).
+		Str("githubDelivery", githubDelivery).
+		Str("action", action).
+		Str("owner", owner).
+		Str("repo", repoName).
+		Str("author", author).
+		Str("SHA", sha).
+		Str("event", "check_run").
+		Logger()
+	log := &logCtx
+	ctx := log.WithContext(context.Background())
+
+	if _, blocked := ownersBlockList[owner]; blocked {
+		log.Warn().Msg("event ignored because owner is in block list")
+		return nil
+	}
+
+	redeploy := action == "rerequested" ||
+		(action == "requested_action" && event.GetRequestedAction().Identifier == ghapp.RedeployAction)
+	if !redeploy || checkRun.GetName() != ghapp.CheckName {
+		return nil
+	}
+
+	envID, err := uuid.Parse(checkRun.GetExternalID())
+	if err != nil {
+		log.Warn().Str("externalID", checkRun.GetExternalID()).Msg("check run does not belong to an environment")
+		return nil
+	}
+
+	// the environment may be terminated already, it still tells what to deploy
+	var env database.Environment
+	err = r.db.Unscoped().First(&env, "id = ?", envID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn().Str("env", envID.String()).Msg("environment of check run not found")
+			return nil
+		}
+
+		err = errors.Wrapf(err, "fail to find environment %s", envID)
+		log.Err(err).Msg("fail to handle check run event")
+		return err
+	}
+
+	if env.Owner != owner || env.Repo != repoName {
+		log.Warn().Str("env", envID.String()).Msg("environment of check run belongs to another repo")
+		return nil
+	}
+
+	var prNumber *int
+	if env.PullRequest.Valid {
+		prNumber = github.Int(int(env.PullRequest.Int32))
+
+		// the check run outlives its pull request, a redeploy must not bring back what was closed or filtered out
+		pr, err := r.getOpenPullRequest(ctx, owner, repoName, *prNumber)
+		if err != nil {
+			var cmdErr commandError
+			if errors.As(err, &cmdErr) {
+				log.Info().Str("env", envID.String()).Msg("redeploy ignored because pull request is not open")
+				return nil
+			}
+
+			log.Err(err).Msg("fail to get pull request of check run")
+			return err
+		}
+
+		allowed, reason, err := r.isPullRequestAllowed(ctx, owner, repoName, pr)
+		if err != nil {
+			log.Err(err).Msg("fail to evaluate pr filters")
+			return err
+		}
+
+		if !allowed {
+			log.Info().Str("reason", reason).Msg("redeploy ignored because pull request is filtered out")
+			return nil
+		}
+	}
+
+	terminateEnv := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    env.Owner,
+		Repo:     env.Repo,
+		Branch:   env.Branch.String,
+		PrNumber: prNumber,
+	}
+
+	launchEnv := launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitHub,
+		Owner:       env.Owner,
+		BranchOwner: env.BranchOwner,
+		Repo:        env.Repo,
+		Branch:      env.Branch.String,
+		SHA:         sha,
+		PrNumber:    prNumber,
+		Author:      author,
+		IsPrivate:   event.GetRepo().GetPrivate(),
+	}
+
+	log.Info().Str("env", envID.String()).Msg("redeploying environment from check run")
+
+	err = r.redeployEnvironment(ctx, terminateEnv, launchEnv)
+	if err != nil {
+		log.Err(err).Msg("fail to enqueue launch")
+	}
+
+	return err
+}
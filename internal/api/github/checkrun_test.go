@@ -0,0 +1,87 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/prfilters"
+	ghappMocks "github.com/ergomake/ergomake/mocks/github/ghapp"
+	launchqueueMocks "github.com/ergomake/ergomake/mocks/launchqueue"
+	prfiltersMocks "github.com/ergomake/ergomake/mocks/prfilters"
+)
+
+func TestHandleCheckRunEvent_redeploy(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	pr := 3
+	env := database.NewEnvironment(uuid.New(), "acme", "acme", "web", "feature", &pr, "author", database.EnvSuccess)
+	require.NoError(t, db.Create(env).Error)
+
+	tt := []struct {
+		name     string
+		state    string
+		rules    prfilters.Rules
+		launches bool
+	}{
+		{name: "open pull request", state: "open", launches: true},
+		{name: "closed pull request", state: "closed", launches: false},
+		{
+			name:     "filtered out pull request",
+			state:    "open",
+			rules:    prfilters.Rules{Labels: prfilters.Filter{Include: []string{"preview"}}},
+			launches: false,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ghApp := ghappMocks.NewGHAppClient(t)
+			launchQueue := launchqueueMocks.NewLaunchQueue(t)
+			prFiltersProvider := prfiltersMocks.NewPRFiltersProvider(t)
+			ghApp.EXPECT().GetPullRequest(mock.Anything, "acme", "web", pr).Return(&github.PullRequest{
+				Number: github.Int(pr),
+				State:  github.String(tc.state),
+				Head:   &github.PullRequestBranch{Ref: github.String("feature"), SHA: github.String("sha")},
+			}, nil)
+			if tc.state == "open" {
+				prFiltersProvider.EXPECT().Get(mock.Anything, database.ProviderGitHub, "acme", "web").Return(tc.rules, nil)
+			}
+			if tc.launches {
+				launchQueue.EXPECT().EnqueueLaunch(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			r := &githubRouter{
+				db:                db,
+				ghApp:             ghApp,
+				launchQueue:       launchQueue,
+				prFiltersProvider: prFiltersProvider,
+			}
+
+			err := r.handleCheckRunEvent("delivery", &github.CheckRunEvent{
+				Action: github.String("rerequested"),
+				Repo: &github.Repository{
+					Name:  github.String("web"),
+					Owner: &github.User{Login: github.String("acme")},
+				},
+				CheckRun: &github.CheckRun{
+					Name:       github.String(ghapp.CheckName),
+					HeadSHA:    github.String("sha"),
+					ExternalID: github.String(env.ID.String()),
+				},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/stale"
+)
+
+const commandPrefix = "/ergomake"
+
+// serviceLogsSize keeps the logs replied to a comment short enough to read in a pull request
+const serviceLogsSize = 3000
+
+const commandUsage = "Available commands are `/ergomake redeploy`, `/ergomake stop`, `/ergomake wake` and `/ergomake logs <service>`."
+
+// commandError is a failure the author of a command gets to read in the reply
+type commandError struct {
+	message string
+}
+
+func (e commandError) Error() string {
+	return e.message
+}
+
+// parseCommand reads a command from the first line of a comment,
+// ok is false when the comment is not addressed to ergomake
+func parseCommand(body string) (command string, args []string, ok bool) {
+	line := strings.SplitN(strings.TrimSpace(body), "\n", 2)[0]
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != commandPrefix {
+		return "", nil, false
+	}
+
+	if len(fields) == 1 {
+		return "", nil, true
+	}
+
+	return fields[1], fields[2:], true
+}
+
+// handleIssueCommentEvent runs the commands people leave in pull request comments
+func (r *githubRouter) handleIssueCommentEvent(githubDelivery string, event *github.IssueCommentEvent) error {
+	action := event.GetAction()
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repoName := event.GetRepo().GetName()
+	prNumber := event.GetIssue().GetNumber()
+	author := event.GetSender().GetLogin()
+
+	if action != "created" || !event.GetIssue().IsPullRequest() {
+		return nil
+	}
+
+	command, args, ok := parseCommand(event.GetComment().GetBody())
+	if !ok {
+		return nil
+	}
+
+	logCtx := logger.With(logger.Get()).
+		Str("githubDelivery", githubDelivery).
+		Str("action", action).
+		Str("owner", owner).
+		Str("repo", repoName).
+		Int("prNumber", prNumber).
+		Str("author", author).
+		Str("command", command).
+		Str("event", "issue_comment").
+		Logger()
+	log := &logCtx
+	ctx := log.WithContext(context.Background())
+
+	if _, blocked := ownersBlockList[owner]; blocked {
+		log.Warn().Msg("event ignored because owner is in block list")
+		return nil
+	}
+
+	canWrite, err := r.ghApp.CanWriteToRepo(ctx, owner, repoName, author)
+	if err != nil {
+		log.Err(err).Msg("fail to check permission of comment author")
+		return err
+	}
+
+	var reply string
+	if canWrite {
+		log.Info().Msg("running command from pull request comment")
+		reply, err = r.runCommand(ctx, event, command, args)
+	} else {
+		err = commandError{fmt.Sprintf("@%s needs write access to this repository to run commands.", author)}
+	}
+
+	var cmdErr commandError
+	switch {
+	case err == nil:
+		r.replyToCommand(ctx, event, "+1", reply)
+	case errors.As(err, &cmdErr):
+		r.replyToCommand(ctx, event, "confused", cmdErr.message)
+	default:
+		// the author was already told, failing the delivery would have github redeliver it and run the command again
+		log.Err(err).Msg("fail to run command from pull request comment")
+		r.replyToCommand(ctx, event, "confused", "Something went wrong running this command, please try again.")
+	}
+
+	return nil
+}
+
+func (r *githubRouter) runCommand(
+	ctx context.Context,
+	event *github.IssueCommentEvent,
+	command string,
+	args []string,
+) (string, error) {
+	switch command {
+	case "redeploy":
+		return r.redeployCommand(ctx, event)
+	case "stop":
+		return r.stopCommand(ctx, event)
+	case "wake":
+		return r.wakeCommand(ctx, event)
+	case "logs":
+		if len(args) != 1 {
+			return "", commandError{"Tell which service to show the logs of, like `/ergomake logs <service>`."}
+		}
+
+		return r.logsCommand(ctx, event, args[0])
+	}
+
+	if command == "" {
+		return "", commandError{commandUsage}
+	}
+
+	return "", commandError{fmt.Sprintf("Unknown command `%s`. %s", command, commandUsage)}
+}
+
+func (r *githubRouter) redeployCommand(ctx context.Context, event *github.IssueCommentEvent) (string, error) {
+	pr, err := r.getOpenPullRequest(
+		ctx,
+		event.GetRepo().GetOwner().GetLogin(),
+		event.GetRepo().GetName(),
+		event.GetIssue().GetNumber(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	terminateEnv := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    event.GetRepo().GetOwner().GetLogin(),
+		Repo:     event.GetRepo().GetName(),
+		Branch:   pr.GetHead().GetRef(),
+		PrNumber: github.Int(pr.GetNumber()),
+	}
+
+	launchEnv := launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitHub,
+		Owner:       event.GetRepo().GetOwner().GetLogin(),
+		BranchOwner: pr.GetHead().GetRepo().GetOwner().GetLogin(),
+		Repo:        event.GetRepo().GetName(),
+		Branch:      pr.GetHead().GetRef(),
+		SHA:         pr.GetHead().GetSHA(),
+		PrNumber:    github.Int(pr.GetNumber()),
+		Author:      event.GetSender().GetLogin(),
+		IsPrivate:   event.GetRepo().GetPrivate(),
+	}
+
+	err = r.redeployEnvironment(ctx, terminateEnv, launchEnv)
+	return "", errors.Wrap(err, "fail to enqueue launch")
+}
+
+func (r *githubRouter) stopCommand(ctx context.Context, event *github.IssueCommentEvent) (string, error) {
+	pr, err := r.getPullRequest(ctx, event)
+	if err != nil {
+		return "", err
+	}
+
+	err = r.terminateEnvironment(ctx, environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    event.GetRepo().GetOwner().GetLogin(),
+		Repo:     event.GetRepo().GetName(),
+		Branch:   pr.GetHead().GetRef(),
+		PrNumber: github.Int(pr.GetNumber()),
+	})
+	return "", errors.Wrap(err, "fail to enqueue termination")
+}
+
+func (r *githubRouter) wakeCommand(ctx context.Context, event *github.IssueCommentEvent) (string, error) {
+	env, err := r.findPullRequestEnvironment(ctx, event)
+	if err != nil {
+		return "", err
+	}
+
+	if env.Status != database.EnvStale {
+		return "", commandError{fmt.Sprintf("The environment is not asleep, it is `%s`.", env.Status)}
+	}
+
+	err = stale.WakeEnvironment(ctx, r.clusterClient, env)
+	if err != nil {
+		return "", errors.Wrap(err, "fail to wake environment up")
+	}
+
+	go func() {
+		log := logger.Ctx(ctx)
+		ctx, cancel := context.WithTimeout(log.WithContext(context.Background()), time.Minute*10)
+		defer cancel()
+
+		stale.MarkAwake(ctx, r.clusterClient, r.environmentsProvider, r.ghApp, r.frontendURL, env)
+	}()
+
+	return "", nil
+}
+
+func (r *githubRouter) logsCommand(ctx context.Context, event *github.IssueCommentEvent, service string) (string, error) {
+	env, err := r.findPullRequestEnvironment(ctx, event)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, len(env.Services))
+	found, isJob := false, false
+	for i, svc := range env.Services {
+		names[i] = fmt.Sprintf("`%s`", svc.Name)
+		if svc.Name == service {
+			found, isJob = true, svc.Job
+		}
+	}
+
+	if !found {
+		return "", commandError{fmt.Sprintf(
+			"The environment has no service `%s`, its services are %s.",
+			service,
+			strings.Join(names, ", "),
+		)}
+	}
+
+	if env.Status == database.EnvStale {
+		return "", commandError{"The environment is asleep, wake it up with `/ergomake wake` first."}
+	}
+
+	var logs string
+	if isJob {
+		logs, err = r.getJobLogs(ctx, env.ID.String(), service)
+	} else {
+		logs, err = r.clusterClient.GetServiceLogs(ctx, env.ID.String(), service, serviceLogsSize)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to get logs of service %s", service)
+	}
+
+	logs = strings.TrimSpace(strings.ToValidUTF8(logs, ""))
+	if logs == "" {
+		return fmt.Sprintf("Service `%s` has no logs yet.", service), nil
+	}
+
+	return fmt.Sprintf("Latest logs of `%s`:\n\n```\n%s\n```", service, logs), nil
+}
+
+// getJobLogs returns the logs of the job of a service, jobs have no kubernetes service to find their pods through
+func (r *githubRouter) getJobLogs(ctx context.Context, namespace, service string) (string, error) {
+	jobs, err := r.clusterClient.ListJobs(ctx, namespace)
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to list jobs of namespace %s", namespace)
+	}
+
+	for _, job := range jobs {
+		if job.GetName() == service {
+			return r.clusterClient.GetJobLogs(ctx, job, serviceLogsSize)
+		}
+	}
+
+	return "", commandError{fmt.Sprintf("Job `%s` did not run yet, it has no logs.", service)}
+}
+
+func (r *githubRouter) getPullRequest(ctx context.Context, event *github.IssueCommentEvent) (*github.PullRequest, error) {
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repoName := event.GetRepo().GetName()
+
+	pr, err := r.ghApp.GetPullRequest(ctx, owner, repoName, event.GetIssue().GetNumber())
+	return pr, errors.Wrap(err, "fail to get pull request")
+}
+
+func (r *githubRouter) getOpenPullRequest(
+	ctx context.Context,
+	owner, repoName string,
+	number int,
+) (*github.PullRequest, error) {
+	pr, err := r.ghApp.GetPullRequest(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get pull request")
+	}
+
+	if pr.GetState() != "open" {
+		return nil, commandError{"This pull request is closed, reopen it to get an environment."}
+	}
+
+	return pr, nil
+}
+
+// findPullRequestEnvironment returns the newest environment of the pull request the comment belongs to
+func (r *githubRouter) findPullRequestEnvironment(
+	ctx context.Context,
+	event *github.IssueCommentEvent,
+) (*database.Environment, error) {
+	pr, err := r.getPullRequest(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	envs, err := r.environmentsProvider.ListEnvironmentsByBranch(
+		ctx,
+		database.ProviderGitHub,
+		event.GetRepo().GetOwner().GetLogin(),
+		event.GetRepo().GetName(),
+		pr.GetHead().GetRef(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to list environments of branch")
+	}
+
+	var newest *database.Environment
+	for _, env := range envs {
+		if !env.PullRequest.Valid || int(env.PullRequest.Int32) != pr.GetNumber() {
+			continue
+		}
+
+		if newest == nil || env.CreatedAt.After(newest.CreatedAt) {
+			newest = env
+		}
+	}
+
+	if newest == nil {
+		return nil, commandError{"This pull request has no environment, create one with `/ergomake redeploy`."}
+	}
+
+	return newest, nil
+}
+
+// replyToCommand reacts to the comment of a command and, when there is a message, answers it
+func (r *githubRouter) replyToCommand(ctx context.Context, event *github.IssueCommentEvent, reaction, message string) {
+	log := logger.Ctx(ctx)
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repoName := event.GetRepo().GetName()
+
+	err := r.ghApp.ReactToComment(ctx, owner, repoName, event.GetComment().GetID(), reaction)
+	if err != nil {
+		log.Err(err).Str("reaction", reaction).Msg("fail to react to command comment")
+	}
+
+	if message == "" {
+		return
+	}
+
+	command := strings.SplitN(strings.TrimSpace(event.GetComment().GetBody()), "\n", 2)[0]
+	reply := fmt.Sprintf("> %s\n\n%s", command, message)
+	_, err = r.ghApp.UpsertComment(ctx, owner, repoName, event.GetIssue().GetNumber(), 0, reply)
+	if err != nil {
+		log.Err(err).Msg("fail to reply to command comment")
+	}
+}
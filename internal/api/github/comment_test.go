@@ -0,0 +1,119 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ergomake/ergomake/internal/database"
+	clusterMocks "github.com/ergomake/ergomake/mocks/cluster"
+	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
+	ghappMocks "github.com/ergomake/ergomake/mocks/github/ghapp"
+)
+
+func TestHandleIssueCommentEvent_logs(t *testing.T) {
+	t.Parallel()
+
+	pr := 3
+	env := database.NewEnvironment(uuid.New(), "acme", "acme", "web", "feature", &pr, "author", database.EnvSuccess)
+	env.Services = []database.Service{{Name: "api"}, {Name: "migrate", Job: true}}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: env.ID.String()}}
+
+	tt := []struct {
+		name     string
+		service  string
+		mock     func(clusterClient *clusterMocks.Client)
+		reaction string
+		reply    string
+	}{
+		{
+			name:    "service",
+			service: "api",
+			mock: func(clusterClient *clusterMocks.Client) {
+				clusterClient.EXPECT().GetServiceLogs(mock.Anything, env.ID.String(), "api", int64(serviceLogsSize)).
+					Return("listening", nil)
+			},
+			reaction: "+1",
+			reply:    "> /ergomake logs api\n\nLatest logs of `api`:\n\n```\nlistening\n```",
+		},
+		{
+			name:    "job",
+			service: "migrate",
+			mock: func(clusterClient *clusterMocks.Client) {
+				clusterClient.EXPECT().ListJobs(mock.Anything, env.ID.String()).Return([]*batchv1.Job{job}, nil)
+				clusterClient.EXPECT().GetJobLogs(mock.Anything, job, int64(serviceLogsSize)).Return("migrated", nil)
+			},
+			reaction: "+1",
+			reply:    "> /ergomake logs migrate\n\nLatest logs of `migrate`:\n\n```\nmigrated\n```",
+		},
+		{
+			name:    "job that did not run",
+			service: "migrate",
+			mock: func(clusterClient *clusterMocks.Client) {
+				clusterClient.EXPECT().ListJobs(mock.Anything, env.ID.String()).Return(nil, nil)
+			},
+			reaction: "confused",
+			reply:    "> /ergomake logs migrate\n\nJob `migrate` did not run yet, it has no logs.",
+		},
+		{
+			// the delivery succeeds so github doesn't redeliver it and the author isn't replied to twice
+			name:    "failure",
+			service: "api",
+			mock: func(clusterClient *clusterMocks.Client) {
+				clusterClient.EXPECT().GetServiceLogs(mock.Anything, env.ID.String(), "api", int64(serviceLogsSize)).
+					Return("", assert.AnError)
+			},
+			reaction: "confused",
+			reply:    "> /ergomake logs api\n\nSomething went wrong running this command, please try again.",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ghApp := ghappMocks.NewGHAppClient(t)
+			ghApp.EXPECT().CanWriteToRepo(mock.Anything, "acme", "web", "author").Return(true, nil)
+			ghApp.EXPECT().GetPullRequest(mock.Anything, "acme", "web", pr).Return(&github.PullRequest{
+				Number: github.Int(pr),
+				Head:   &github.PullRequestBranch{Ref: github.String("feature")},
+			}, nil)
+			ghApp.EXPECT().ReactToComment(mock.Anything, "acme", "web", int64(1), tc.reaction).Return(nil)
+			ghApp.EXPECT().UpsertComment(mock.Anything, "acme", "web", pr, int64(0), tc.reply).Return(nil, nil)
+
+			environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+			environmentsProvider.EXPECT().ListEnvironmentsByBranch(mock.Anything, "github", "acme", "web", "feature").
+				Return([]*database.Environment{env}, nil)
+
+			clusterClient := clusterMocks.NewClient(t)
+			tc.mock(clusterClient)
+
+			r := &githubRouter{
+				ghApp:                ghApp,
+				clusterClient:        clusterClient,
+				environmentsProvider: environmentsProvider,
+			}
+
+			err := r.handleIssueCommentEvent("delivery", &github.IssueCommentEvent{
+				Action: github.String("created"),
+				Repo: &github.Repository{
+					Name:  github.String("web"),
+					Owner: &github.User{Login: github.String("acme")},
+				},
+				Issue: &github.Issue{
+					Number:           github.Int(pr),
+					PullRequestLinks: &github.PullRequestLinks{},
+				},
+				Comment: &github.IssueComment{ID: github.Int64(1), Body: github.String("/ergomake logs " + tc.service)},
+				Sender:  &github.User{Login: github.String("author")},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,12 +2,12 @@ package github
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -21,7 +21,7 @@ func (ghr *githubRouter) configureRepo(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).
 			Str("owner", owner).
@@ -115,6 +115,5 @@ In our platform, you can configure branches to be permanently deployed. That way
 		return
 	}
 
-	url := fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, repo, pr.GetNumber())
-	c.JSON(http.StatusOK, gin.H{"pullRequestURL": url})
+	c.JSON(http.StatusOK, gin.H{"pullRequestURL": pr.GetHTMLURL()})
 }
@@ -3,9 +3,15 @@ package github
 import (
 	"context"
 
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/launcher"
 )
 
-func (r *githubRouter) launchEnvironment(ctx context.Context, event ghlauncher.LaunchEnvironmentRequest) error {
-	return r.ghLauncher.LaunchEnvironment(ctx, event)
+// redeployEnvironment queues the environment of a branch to be brought to a new commit
+func (r *githubRouter) redeployEnvironment(
+	ctx context.Context,
+	terminateReq environments.TerminateEnvironmentRequest,
+	launchReq launcher.LaunchEnvironmentRequest,
+) error {
+	return r.launchQueue.EnqueueLaunch(ctx, terminateReq, launchReq)
 }
@@ -20,7 +20,7 @@ func (ghr *githubRouter) listReposForOwner(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).
 			Str("owner", owner).
@@ -34,7 +34,7 @@ func (ghr *githubRouter) listReposForOwner(c *gin.Context) {
 		return
 	}
 
-	environments, err := ghr.db.FindEnvironmentsByOwner(owner, database.FindEnvironmentsOptions{IncludeDeleted: true})
+	environments, err := ghr.db.FindEnvironmentsByOwner(database.ProviderGitHub, owner, database.FindEnvironmentsOptions{IncludeDeleted: true})
 	if err != nil {
 		logger.Ctx(c).Err(err).
 			Str("owner", owner).
@@ -4,13 +4,16 @@ import (
 	"context"
 
 	"github.com/google/go-github/v52/github"
+	"github.com/pkg/errors"
 
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/environments"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/prfilters"
 )
 
-func (r *githubRouter) handlePullRequestEvent(githubDelivery string, event *github.PullRequestEvent) {
+func (r *githubRouter) handlePullRequestEvent(githubDelivery string, event *github.PullRequestEvent) error {
 	action := event.GetAction()
 
 	owner := event.GetRepo().GetOwner().GetLogin()
@@ -37,10 +40,11 @@ func (r *githubRouter) handlePullRequestEvent(githubDelivery string, event *gith
 
 	if _, blocked := ownersBlockList[owner]; blocked {
 		log.Warn().Msg("event ignored because owner is in block list")
-		return
+		return nil
 	}
 
 	terminateEnv := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
 		Owner:    owner,
 		Repo:     repoName,
 		Branch:   branch,
@@ -49,13 +53,50 @@ func (r *githubRouter) handlePullRequestEvent(githubDelivery string, event *gith
 
 	log.Info().Msg("got a pull request event from github")
 	switch action {
-	case "opened", "reopened", "synchronize":
-		err := r.terminateEnvironment(ctx, terminateEnv)
+	case "opened", "reopened", "synchronize", "ready_for_review", "labeled", "unlabeled":
+		// labels can still change after a pr is closed, no close event would terminate an environment launched then
+		if event.GetPullRequest().GetState() != "open" {
+			log.Info().Str("state", event.GetPullRequest().GetState()).Msg("event ignored because pull request is not open")
+			return nil
+		}
+
+		allowed, reason, err := r.isPullRequestAllowed(ctx, owner, repoName, event.GetPullRequest())
 		if err != nil {
-			log.Err(err).Msg("fail to terminate environment")
+			log.Err(err).Msg("fail to evaluate pr filters")
+			return err
+		}
+
+		// labels and drafts change often, they only matter when they change whether the pr gets an environment
+		onlyOnChange := action == "ready_for_review" || action == "labeled" || action == "unlabeled"
+		hasEnv := false
+		if onlyOnChange || !allowed {
+			hasEnv, err = r.hasPullRequestEnvironment(ctx, owner, repoName, branch, prNumber)
+			if err != nil {
+				log.Err(err).Msg("fail to check if pull request has an environment")
+				return err
+			}
+		}
+
+		if !allowed {
+			log.Info().Str("reason", reason).Msg("pull request filtered out")
+			if !hasEnv {
+				return nil
+			}
+
+			err := r.terminateEnvironment(ctx, terminateEnv)
+			if err != nil {
+				log.Err(err).Msg("fail to enqueue termination")
+			}
+
+			return err
 		}
 
-		launchEnv := ghlauncher.LaunchEnvironmentRequest{
+		if onlyOnChange && hasEnv {
+			return nil
+		}
+
+		launchEnv := launcher.LaunchEnvironmentRequest{
+			Provider:    database.ProviderGitHub,
 			Owner:       owner,
 			BranchOwner: branchOwner,
 			Repo:        repoName,
@@ -66,14 +107,74 @@ func (r *githubRouter) handlePullRequestEvent(githubDelivery string, event *gith
 			IsPrivate:   repo.GetPrivate(),
 		}
 
-		err = r.launchEnvironment(ctx, launchEnv)
+		err = r.redeployEnvironment(ctx, terminateEnv, launchEnv)
 		if err != nil {
-			log.Err(err).Msg("fail to launch environment")
+			log.Err(err).Msg("fail to enqueue launch")
 		}
+
+		return err
 	case "closed":
 		err := r.terminateEnvironment(ctx, terminateEnv)
 		if err != nil {
-			log.Err(err).Msg("fail to terminate environment")
+			log.Err(err).Msg("fail to enqueue termination")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// isPullRequestAllowed evaluates the pr filters of the repo, the reason tells why a pr was filtered out
+func (r *githubRouter) isPullRequestAllowed(
+	ctx context.Context,
+	owner, repoName string,
+	pr *github.PullRequest,
+) (bool, string, error) {
+	rules, err := r.prFiltersProvider.Get(ctx, database.ProviderGitHub, owner, repoName)
+	if err != nil {
+		return false, "", errors.Wrap(err, "fail to get pr filters")
+	}
+
+	if rules.Empty() {
+		return true, "", nil
+	}
+
+	labels := make([]string, len(pr.Labels))
+	for i, label := range pr.Labels {
+		labels[i] = label.GetName()
+	}
+
+	var changedFiles []string
+	if rules.NeedsChangedFiles() {
+		changedFiles, err = r.ghApp.ListPullRequestFiles(ctx, owner, repoName, pr.GetNumber())
+		if err != nil {
+			return false, "", errors.Wrap(err, "fail to list pull request files")
 		}
 	}
+
+	allowed, reason := rules.Evaluate(prfilters.PullRequest{
+		Labels:       labels,
+		Author:       pr.GetUser().GetLogin(),
+		BaseBranch:   pr.GetBase().GetRef(),
+		Draft:        pr.GetDraft(),
+		ChangedFiles: changedFiles,
+	})
+
+	return allowed, reason, nil
+}
+
+func (r *githubRouter) hasPullRequestEnvironment(ctx context.Context, owner, repo, branch string, prNumber int) (bool, error) {
+	envs, err := r.environmentsProvider.ListEnvironmentsByBranch(ctx, database.ProviderGitHub, owner, repo, branch)
+	if err != nil {
+		return false, errors.Wrap(err, "fail to list environments of branch")
+	}
+
+	for _, env := range envs {
+		if env.PullRequest.Valid && int(env.PullRequest.Int32) == prNumber {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
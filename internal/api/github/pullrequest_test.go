@@ -0,0 +1,66 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ergomake/ergomake/internal/prfilters"
+	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
+	launchqueueMocks "github.com/ergomake/ergomake/mocks/launchqueue"
+	prfiltersMocks "github.com/ergomake/ergomake/mocks/prfilters"
+)
+
+func TestHandlePullRequestEvent_labels(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		action   string
+		state    string
+		launches bool
+	}{
+		{name: "labeled open pull request", action: "labeled", state: "open", launches: true},
+		{name: "unlabeled open pull request", action: "unlabeled", state: "open", launches: true},
+		{name: "labeled closed pull request", action: "labeled", state: "closed", launches: false},
+		{name: "unlabeled closed pull request", action: "unlabeled", state: "closed", launches: false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			launchQueue := launchqueueMocks.NewLaunchQueue(t)
+			prFiltersProvider := prfiltersMocks.NewPRFiltersProvider(t)
+			environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+			if tc.launches {
+				prFiltersProvider.EXPECT().Get(mock.Anything, "github", "acme", "web").Return(prfilters.Rules{}, nil)
+				environmentsProvider.EXPECT().ListEnvironmentsByBranch(mock.Anything, "github", "acme", "web", "feature").Return(nil, nil)
+				launchQueue.EXPECT().EnqueueLaunch(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			r := &githubRouter{
+				launchQueue:          launchQueue,
+				prFiltersProvider:    prFiltersProvider,
+				environmentsProvider: environmentsProvider,
+			}
+
+			err := r.handlePullRequestEvent("delivery", &github.PullRequestEvent{
+				Action: github.String(tc.action),
+				Repo: &github.Repository{
+					Name:  github.String("web"),
+					Owner: &github.User{Login: github.String("acme")},
+				},
+				PullRequest: &github.PullRequest{
+					Number: github.Int(3),
+					State:  github.String(tc.state),
+					Head:   &github.PullRequestBranch{Ref: github.String("feature"), SHA: github.String("sha")},
+				},
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
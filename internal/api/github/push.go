@@ -7,12 +7,13 @@ import (
 	"github.com/google/go-github/v52/github"
 	"github.com/pkg/errors"
 
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/environments"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
-func (r *githubRouter) handlePushEvent(githubDelivery string, event *github.PushEvent) {
+func (r *githubRouter) handlePushEvent(githubDelivery string, event *github.PushEvent) error {
 	owner := event.GetRepo().GetOwner().GetLogin()
 	repo := event.GetRepo()
 	repoName := repo.GetName()
@@ -34,33 +35,32 @@ func (r *githubRouter) handlePushEvent(githubDelivery string, event *github.Push
 
 	if _, blocked := ownersBlockList[owner]; blocked {
 		log.Warn().Msg("event ignored because owner is in block list")
-		return
+		return nil
 	}
 
 	log.Info().Msg("got a push event from github")
 
 	shouldDeploy, err := r.environmentsProvider.ShouldDeploy(ctx, owner, repoName, branch)
 	if err != nil {
-		log.Err(errors.Wrap(err, "fail to check if branch should be deployed")).Msg("fail to handle push event")
-		return
+		err = errors.Wrap(err, "fail to check if branch should be deployed")
+		log.Err(err).Msg("fail to handle push event")
+		return err
 	}
 
 	if !shouldDeploy {
-		return
+		return nil
 	}
 
 	terminateEnv := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
 		Owner:    owner,
 		Repo:     repoName,
 		Branch:   branch,
 		PrNumber: nil,
 	}
-	err = r.environmentsProvider.TerminateEnvironment(ctx, terminateEnv)
-	if err != nil {
-		log.Err(err).Msg("fail to terminate environment")
-	}
 
-	launchEnv := ghlauncher.LaunchEnvironmentRequest{
+	launchEnv := launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitHub,
 		Owner:       owner,
 		BranchOwner: owner,
 		Repo:        repoName,
@@ -71,8 +71,10 @@ func (r *githubRouter) handlePushEvent(githubDelivery string, event *github.Push
 		IsPrivate:   repo.GetPrivate(),
 	}
 
-	err = r.launchEnvironment(ctx, launchEnv)
+	err = r.redeployEnvironment(ctx, terminateEnv, launchEnv)
 	if err != nil {
-		log.Err(err).Msg("fail to launch environment")
+		log.Err(err).Msg("fail to enqueue launch")
 	}
+
+	return err
 }
@@ -8,13 +8,15 @@ import (
 	"github.com/ergomake/ergomake/internal/environments"
 	"github.com/ergomake/ergomake/internal/envvars"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/launchqueue"
 	"github.com/ergomake/ergomake/internal/payment"
+	"github.com/ergomake/ergomake/internal/prfilters"
 	"github.com/ergomake/ergomake/internal/privregistry"
 )
 
 type githubRouter struct {
-	ghLauncher              ghlauncher.GHLauncher
+	launchQueue             launchqueue.LaunchQueue
+	prFiltersProvider       prfilters.PRFiltersProvider
 	db                      *database.DB
 	ghApp                   ghapp.GHAppClient
 	clusterClient           cluster.Client
@@ -28,7 +30,8 @@ type githubRouter struct {
 }
 
 func NewGithubRouter(
-	ghLauncher ghlauncher.GHLauncher,
+	launchQueue launchqueue.LaunchQueue,
+	prFiltersProvider prfilters.PRFiltersProvider,
 	db *database.DB,
 	ghApp ghapp.GHAppClient,
 	clusterClient cluster.Client,
@@ -41,7 +44,8 @@ func NewGithubRouter(
 	dockerhubPullSecretName string,
 ) *githubRouter {
 	return &githubRouter{
-		ghLauncher,
+		launchQueue,
+		prFiltersProvider,
 		db,
 		ghApp,
 		clusterClient,
@@ -7,5 +7,5 @@ import (
 )
 
 func (r *githubRouter) terminateEnvironment(ctx context.Context, req environments.TerminateEnvironmentRequest) error {
-	return r.environmentsProvider.TerminateEnvironment(ctx, req)
+	return r.launchQueue.EnqueueTerminate(ctx, req)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/github/ghoauth"
 	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/payment"
@@ -52,7 +53,7 @@ func (ghr *githubRouter) listUserOrganizations(c *gin.Context) {
 	}
 
 	if isUserInstalled {
-		paymentPlan, err := ghr.paymentProvider.GetOwnerPlan(c, user.GetLogin())
+		paymentPlan, err := ghr.paymentProvider.GetOwnerPlan(c, database.ProviderGitHub, user.GetLogin())
 		if err != nil {
 			logger.Ctx(c).Err(err).Str("user", user.GetLogin()).
 				Msg("fail to get user payment plan")
@@ -87,7 +88,7 @@ func (ghr *githubRouter) listUserOrganizations(c *gin.Context) {
 	}
 
 	for _, org := range orgs {
-		paymentPlan, err := ghr.paymentProvider.GetOwnerPlan(c, org.GetLogin())
+		paymentPlan, err := ghr.paymentProvider.GetOwnerPlan(c, database.ProviderGitHub, org.GetLogin())
 		if err != nil {
 			logger.Ctx(c).Err(err).Str("owner", org.GetLogin()).
 				Msg("fail to get owner payment plan")
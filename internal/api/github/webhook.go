@@ -50,16 +50,27 @@ func (r *githubRouter) webhook(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusNoContent)
-
 	githubDelivery := c.GetHeader("X-GitHub-Delivery")
 
-	go func() {
-		switch event := event.(type) {
-		case *github.PushEvent:
-			r.handlePushEvent(githubDelivery, event)
-		case *github.PullRequestEvent:
-			r.handlePullRequestEvent(githubDelivery, event)
-		}
-	}()
+	// events only get as far as the launch queue here, when that fails github
+	// sees a failed delivery that can be redelivered
+	switch event := event.(type) {
+	case *github.PushEvent:
+		err = r.handlePushEvent(githubDelivery, event)
+	case *github.PullRequestEvent:
+		err = r.handlePullRequestEvent(githubDelivery, event)
+	case *github.CheckRunEvent:
+		err = r.handleCheckRunEvent(githubDelivery, event)
+	case *github.IssueCommentEvent:
+		err = r.handleIssueCommentEvent(githubDelivery, event)
+	}
+	if err != nil {
+		c.JSON(
+			http.StatusInternalServerError,
+			http.StatusText(http.StatusInternalServerError),
+		)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,88 @@
+package gitlab
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+type registerProjectRequest struct {
+	// Path is the full path of the project, e.g. group/subgroup/project
+	Path  string `json:"path"`
+	Token string `json:"token"`
+	// WebhookToken is the webhook token of the current registration, only required to register a project again
+	WebhookToken string `json:"webhookToken"`
+}
+
+// registerProject saves the project access token of a project, the webhook token it answers with
+// must be set as the secret token of the project webhook
+func (glr *gitlabRouter) registerProject(c *gin.Context) {
+	if _, ok := auth.GetAuthData(c); !ok {
+		c.JSON(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	var req registerProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "malformed-payload"})
+		return
+	}
+
+	owner, repo, ok := splitProjectPath(req.Path)
+	if !ok || req.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"reason": "malformed-payload"})
+		return
+	}
+
+	// only who holds a maintainer token of the project gets to register it
+	canMaintain, err := glr.glClient.CanMaintainProject(c, owner, repo, req.Token)
+	if err != nil {
+		logger.Ctx(c).Err(err).Msgf("fail to check access to gitlab project %s", req.Path)
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !canMaintain {
+		c.JSON(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	// registering again replaces the token and the webhook token, it must come from who registered it
+	current, err := glr.glProjectsProvider.Get(c, owner, repo)
+	if err != nil && !errors.Is(err, glprojects.ErrProjectNotFound) {
+		logger.Ctx(c).Err(err).Msgf("fail to get gitlab project %s", req.Path)
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if current != nil && subtle.ConstantTimeCompare([]byte(current.WebhookToken), []byte(req.WebhookToken)) != 1 {
+		c.JSON(http.StatusForbidden, gin.H{"reason": "project-already-registered"})
+		return
+	}
+
+	project, err := glr.glProjectsProvider.Register(c, owner, repo, req.Token)
+	if err != nil {
+		logger.Ctx(c).Err(err).Msgf("fail to register gitlab project %s", req.Path)
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"owner": project.Owner, "repo": project.Repo, "webhookToken": project.WebhookToken})
+}
+
+// splitProjectPath splits the full path of a project into its namespace, used as owner, and its name
+func splitProjectPath(path string) (string, string, bool) {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 || i == len(path)-1 {
+		return "", "", false
+	}
+
+	return path[:i], path[i+1:], true
+}
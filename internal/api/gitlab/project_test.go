@@ -0,0 +1,92 @@
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	glclientMocks "github.com/ergomake/ergomake/mocks/gitlab/glclient"
+	glprojectsMocks "github.com/ergomake/ergomake/mocks/gitlab/glprojects"
+)
+
+func TestRegisterProject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registered := &glprojects.Project{Owner: "group", Repo: "repo", Token: "old", WebhookToken: "secret"}
+
+	tt := []struct {
+		name          string
+		authenticated bool
+		canMaintain   bool
+		current       *glprojects.Project
+		webhookToken  string
+		registers     bool
+		expected      int
+	}{
+		{name: "unauthenticated", expected: http.StatusUnauthorized},
+		{name: "not a maintainer", authenticated: true, expected: http.StatusForbidden},
+		{name: "new project", authenticated: true, canMaintain: true, registers: true, expected: http.StatusOK},
+		{
+			name:          "registered by someone else",
+			authenticated: true,
+			canMaintain:   true,
+			current:       registered,
+			webhookToken:  "guess",
+			expected:      http.StatusForbidden,
+		},
+		{
+			name:          "registered again by its owner",
+			authenticated: true,
+			canMaintain:   true,
+			current:       registered,
+			webhookToken:  "secret",
+			registers:     true,
+			expected:      http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			glClient := glclientMocks.NewGLClient(t)
+			glProjectsProvider := glprojectsMocks.NewGLProjectsProvider(t)
+			if tc.authenticated {
+				glClient.EXPECT().CanMaintainProject(mock.Anything, "group", "repo", "token").Return(tc.canMaintain, nil)
+			}
+			if tc.canMaintain {
+				if tc.current != nil {
+					glProjectsProvider.EXPECT().Get(mock.Anything, "group", "repo").Return(tc.current, nil)
+				} else {
+					glProjectsProvider.EXPECT().Get(mock.Anything, "group", "repo").Return(nil, glprojects.ErrProjectNotFound)
+				}
+			}
+			if tc.registers {
+				glProjectsProvider.EXPECT().Register(mock.Anything, "group", "repo", "token").
+					Return(&glprojects.Project{Owner: "group", Repo: "repo", Token: "token", WebhookToken: "new"}, nil)
+			}
+
+			glr := &gitlabRouter{glClient: glClient, glProjectsProvider: glProjectsProvider}
+
+			body, err := json.Marshal(registerProjectRequest{Path: "group/repo", Token: "token", WebhookToken: tc.webhookToken})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader(body))
+			if tc.authenticated {
+				c.Set("customClaims", &auth.AuthData{})
+			}
+
+			glr.registerProject(c)
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
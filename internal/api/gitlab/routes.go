@@ -0,0 +1,36 @@
+package gitlab
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/gitlab/glclient"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/launchqueue"
+)
+
+type gitlabRouter struct {
+	glClient             glclient.GLClient
+	glProjectsProvider   glprojects.GLProjectsProvider
+	launchQueue          launchqueue.LaunchQueue
+	environmentsProvider environments.EnvironmentsProvider
+}
+
+func NewGitlabRouter(
+	glClient glclient.GLClient,
+	glProjectsProvider glprojects.GLProjectsProvider,
+	launchQueue launchqueue.LaunchQueue,
+	environmentsProvider environments.EnvironmentsProvider,
+) *gitlabRouter {
+	return &gitlabRouter{
+		glClient,
+		glProjectsProvider,
+		launchQueue,
+		environmentsProvider,
+	}
+}
+
+func (glr *gitlabRouter) AddRoutes(router *gin.RouterGroup) {
+	router.POST("/webhook", glr.webhook)
+	router.POST("/projects", glr.registerProject)
+}
@@ -0,0 +1,241 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+const (
+	mergeRequestHook = "Merge Request Hook"
+	pushHook         = "Push Hook"
+)
+
+// deletedSHA is the after of the push that deletes a branch
+const deletedSHA = "0000000000000000000000000000000000000000"
+
+type eventProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+type mergeRequestEvent struct {
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project          eventProject `json:"project"`
+	ObjectAttributes struct {
+		IID             int    `json:"iid"`
+		Action          string `json:"action"`
+		SourceBranch    string `json:"source_branch"`
+		SourceProjectID int64  `json:"source_project_id"`
+		TargetProjectID int64  `json:"target_project_id"`
+		// OldRev is only set on updates that pushed new commits
+		OldRev     string `json:"oldrev"`
+		LastCommit struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
+
+type pushEvent struct {
+	Ref          string       `json:"ref"`
+	After        string       `json:"after"`
+	UserUsername string       `json:"user_username"`
+	Project      eventProject `json:"project"`
+}
+
+func (glr *gitlabRouter) webhook(c *gin.Context) {
+	eventType := c.GetHeader("X-Gitlab-Event")
+
+	var body json.RawMessage
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	var event struct {
+		Project eventProject `json:"project"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	owner, repo, ok := splitProjectPath(event.Project.PathWithNamespace)
+	if !ok {
+		c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	logCtx := logger.With(logger.Get()).
+		Str("gitlabEventUUID", c.GetHeader("X-Gitlab-Event-UUID")).
+		Str("owner", owner).
+		Str("repo", repo).
+		Str("event", eventType).
+		Logger()
+	log := &logCtx
+	ctx := log.WithContext(context.Background())
+
+	project, err := glr.glProjectsProvider.Get(ctx, owner, repo)
+	if err != nil {
+		if errors.Is(err, glprojects.ErrProjectNotFound) {
+			c.JSON(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+			return
+		}
+
+		log.Err(err).Msg("fail to get gitlab project")
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	token := c.GetHeader("X-Gitlab-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(project.WebhookToken)) != 1 {
+		c.JSON(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	switch eventType {
+	case mergeRequestHook:
+		var event mergeRequestEvent
+		err = json.Unmarshal(body, &event)
+		if err == nil {
+			err = glr.handleMergeRequestEvent(ctx, owner, repo, &event)
+		}
+	case pushHook:
+		var event pushEvent
+		err = json.Unmarshal(body, &event)
+		if err == nil {
+			err = glr.handlePushEvent(ctx, owner, repo, &event)
+		}
+	}
+	if err != nil {
+		log.Err(err).Msg("fail to handle gitlab event")
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (glr *gitlabRouter) handleMergeRequestEvent(ctx context.Context, owner, repo string, event *mergeRequestEvent) error {
+	attrs := event.ObjectAttributes
+	log := logger.With(logger.Ctx(ctx)).
+		Str("action", attrs.Action).
+		Int("mrIID", attrs.IID).
+		Str("branch", attrs.SourceBranch).
+		Str("SHA", attrs.LastCommit.ID).
+		Logger()
+	ctx = log.WithContext(ctx)
+
+	if attrs.SourceProjectID != attrs.TargetProjectID {
+		log.Info().Msg("merge request from a fork ignored")
+		return nil
+	}
+
+	switch attrs.Action {
+	case "open", "reopen":
+	case "update":
+		if attrs.OldRev == "" {
+			// title, description and label changes need no new environment
+			return nil
+		}
+	case "close", "merge":
+		err := glr.launchQueue.EnqueueTerminate(ctx, environments.TerminateEnvironmentRequest{
+			Provider: database.ProviderGitLab,
+			Owner:    owner,
+			Repo:     repo,
+			Branch:   attrs.SourceBranch,
+			PrNumber: &attrs.IID,
+		})
+		return errors.Wrap(err, "fail to enqueue termination of merge request environment")
+	default:
+		return nil
+	}
+
+	log.Info().Msg("got a merge request event from gitlab")
+
+	err := glr.launchQueue.EnqueueLaunch(
+		ctx,
+		environments.TerminateEnvironmentRequest{
+			Provider: database.ProviderGitLab,
+			Owner:    owner,
+			Repo:     repo,
+			Branch:   attrs.SourceBranch,
+			PrNumber: &attrs.IID,
+		},
+		launcher.LaunchEnvironmentRequest{
+			Provider:    database.ProviderGitLab,
+			Owner:       owner,
+			BranchOwner: owner,
+			Repo:        repo,
+			Branch:      attrs.SourceBranch,
+			SHA:         attrs.LastCommit.ID,
+			PrNumber:    &attrs.IID,
+			Author:      event.User.Username,
+			// projects are always cloned with the token they were registered with
+			IsPrivate: true,
+		},
+	)
+
+	return errors.Wrap(err, "fail to enqueue launch of merge request environment")
+}
+
+func (glr *gitlabRouter) handlePushEvent(ctx context.Context, owner, repo string, event *pushEvent) error {
+	if !strings.HasPrefix(event.Ref, "refs/heads/") {
+		return nil
+	}
+
+	branch := strings.TrimPrefix(event.Ref, "refs/heads/")
+	log := logger.With(logger.Ctx(ctx)).
+		Str("branch", branch).
+		Str("SHA", event.After).
+		Logger()
+	ctx = log.WithContext(ctx)
+
+	shouldDeploy, err := glr.environmentsProvider.ShouldDeploy(ctx, owner, repo, branch)
+	if err != nil {
+		return errors.Wrap(err, "fail to check if branch should be deployed")
+	}
+
+	if !shouldDeploy {
+		return nil
+	}
+
+	terminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitLab,
+		Owner:    owner,
+		Repo:     repo,
+		Branch:   branch,
+	}
+
+	if event.After == deletedSHA {
+		err := glr.launchQueue.EnqueueTerminate(ctx, terminateReq)
+		return errors.Wrap(err, "fail to enqueue termination of deleted branch environment")
+	}
+
+	log.Info().Msg("got a push event from gitlab")
+
+	err = glr.launchQueue.EnqueueLaunch(ctx, terminateReq, launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitLab,
+		Owner:       owner,
+		BranchOwner: owner,
+		Repo:        repo,
+		Branch:      branch,
+		SHA:         event.After,
+		Author:      event.UserUsername,
+		IsPrivate:   true,
+	})
+
+	return errors.Wrap(err, "fail to enqueue launch of branch environment")
+}
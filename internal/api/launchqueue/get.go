@@ -0,0 +1,46 @@
+package launchqueue
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+func (lqr *launchQueueRouter) get(c *gin.Context) {
+	authData, ok := auth.GetAuthData(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	}
+
+	owner := c.Param("owner")
+	if owner == "" {
+		c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
+	if err != nil {
+		logger.Ctx(c).Err(err).Msg("fail to check for authorization")
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	if !isAuthorized {
+		c.JSON(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	stats, err := lqr.launchQueue.Stats(c, database.ProviderGitHub, owner)
+	if err != nil {
+		logger.Ctx(c).Err(err).Msgf("fail to get launch queue stats for owner %s", owner)
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
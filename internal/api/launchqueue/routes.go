@@ -0,0 +1,19 @@
+package launchqueue
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ergomake/ergomake/internal/launchqueue"
+)
+
+type launchQueueRouter struct {
+	launchQueue launchqueue.LaunchQueue
+}
+
+func NewLaunchQueueRouter(launchQueue launchqueue.LaunchQueue) *launchQueueRouter {
+	return &launchQueueRouter{launchQueue}
+}
+
+func (lqr *launchQueueRouter) AddRoutes(router *gin.RouterGroup) {
+	router.GET("/owner/:owner/launch-queue", lqr.get)
+}
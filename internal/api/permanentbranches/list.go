@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -23,7 +24,7 @@ func (pbr *permanentBranchesRouter) list(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to list variables")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -5,24 +5,24 @@ import (
 
 	"github.com/ergomake/ergomake/internal/environments"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/permanentbranches"
 )
 
 type permanentBranchesRouter struct {
 	ghApp                     ghapp.GHAppClient
-	ghLaunccher               ghlauncher.GHLauncher
+	envLauncher               launcher.Launcher
 	permanentbranchesProvider permanentbranches.PermanentBranchesProvider
 	environmentsProvider      environments.EnvironmentsProvider
 }
 
 func NewPermanentBranchesRouter(
 	ghApp ghapp.GHAppClient,
-	ghLaunccher ghlauncher.GHLauncher,
+	envLauncher launcher.Launcher,
 	permanentbranchesProvider permanentbranches.PermanentBranchesProvider,
 	environmentsProvider environments.EnvironmentsProvider,
 ) *permanentBranchesRouter {
-	return &permanentBranchesRouter{ghApp, ghLaunccher, permanentbranchesProvider, environmentsProvider}
+	return &permanentBranchesRouter{ghApp, envLauncher, permanentbranchesProvider, environmentsProvider}
 }
 
 func (er *permanentBranchesRouter) AddRoutes(router *gin.RouterGroup) {
@@ -9,10 +9,11 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/environments"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
 	"github.com/ergomake/ergomake/internal/github/ghoauth"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -34,7 +35,7 @@ func (pbr *permanentBranchesRouter) upsert(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to check for authorization")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -86,6 +87,7 @@ func (pbr *permanentBranchesRouter) upsert(c *gin.Context) {
 				defer wg.Done()
 
 				req := environments.TerminateEnvironmentRequest{
+					Provider: database.ProviderGitHub,
 					Owner:    owner,
 					Repo:     repoStr,
 					Branch:   branch,
@@ -131,7 +133,8 @@ func (pbr *permanentBranchesRouter) upsert(c *gin.Context) {
 					return
 				}
 
-				req := ghlauncher.LaunchEnvironmentRequest{
+				req := launcher.LaunchEnvironmentRequest{
+					Provider:    database.ProviderGitHub,
 					Owner:       owner,
 					BranchOwner: owner,
 					Repo:        repoStr,
@@ -140,7 +143,7 @@ func (pbr *permanentBranchesRouter) upsert(c *gin.Context) {
 					Author:      user.GetLogin(),
 					IsPrivate:   isPrivate,
 				}
-				err = pbr.ghLaunccher.LaunchEnvironment(ctx, req)
+				err = pbr.envLauncher.LaunchEnvironment(ctx, req)
 				if err != nil {
 					log.Err(err).Str("branch", branchStr).
 						Msg("fial to launch environment after branch was added from permanent branches")
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -34,7 +35,9 @@ func (rr *registriesRouter) create(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	// the owner is on github unless the provider query parameter says otherwise
+	provider := c.DefaultQuery("provider", database.ProviderGitHub)
+	isAuthorized, err := auth.IsAuthorized(c, provider, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to create registry")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -46,7 +49,7 @@ func (rr *registriesRouter) create(c *gin.Context) {
 		return
 	}
 
-	err = rr.privRegistryProvider.StoreRegistry(c, owner, body.URL, body.Provider, body.Credentials)
+	err = rr.privRegistryProvider.StoreRegistry(c, provider, owner, body.URL, body.Provider, body.Credentials)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to create registry")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -24,7 +25,7 @@ func (rr *registriesRouter) del(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	isAuthorized, err := auth.IsAuthorized(c, database.ProviderGitHub, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to create registry")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -22,7 +23,9 @@ func (rr *registriesRouter) list(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	// the owner is on github unless the provider query parameter says otherwise
+	provider := c.DefaultQuery("provider", database.ProviderGitHub)
+	isAuthorized, err := auth.IsAuthorized(c, provider, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to list registries")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -34,7 +37,7 @@ func (rr *registriesRouter) list(c *gin.Context) {
 		return
 	}
 
-	creds, err := rr.privRegistryProvider.ListCredsByOwner(c, owner, true)
+	creds, err := rr.privRegistryProvider.ListCredsByOwner(c, provider, owner, true)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msgf("fail to list registries for owner %s", owner)
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -0,0 +1,137 @@
+package reposettings
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/reposettings"
+)
+
+// Setting is a per repository setting clients read and replace as a whole
+type Setting struct {
+	// Path is the last segment of the routes of the setting
+	Path string
+	// Name is how logs refer to the setting
+	Name string
+	// Get returns the setting of repo as it is sent to clients
+	Get func(ctx context.Context, repo reposettings.Repo) (interface{}, error)
+	// NewBody makes the value the body of an update is decoded into
+	NewBody func() interface{}
+	// Set validates and saves the decoded body, it returns the setting as it is sent to clients
+	// and an *InvalidError when the body is not a valid setting
+	Set func(ctx context.Context, repo reposettings.Repo, body interface{}) (interface{}, error)
+}
+
+// InvalidError rejects an update with the reason and the message clients get
+type InvalidError struct {
+	Reason string
+	Err    error
+}
+
+func (e *InvalidError) Error() string {
+	return e.Err.Error()
+}
+
+type repoSettingsRouter struct {
+	settings     []Setting
+	isAuthorized func(ctx context.Context, provider, owner string, authData *auth.AuthData) (bool, error)
+}
+
+func NewRepoSettingsRouter(settings ...Setting) *repoSettingsRouter {
+	return &repoSettingsRouter{settings, auth.IsAuthorized}
+}
+
+func (rsr *repoSettingsRouter) AddRoutes(router *gin.RouterGroup) {
+	for _, setting := range rsr.settings {
+		router.GET("/owner/:owner/repos/:repo/"+setting.Path, rsr.get(setting))
+		router.POST("/owner/:owner/repos/:repo/"+setting.Path, rsr.upsert(setting))
+	}
+}
+
+func (rsr *repoSettingsRouter) get(setting Setting) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		repo, ok := rsr.authorize(c)
+		if !ok {
+			return
+		}
+
+		value, err := setting.Get(c, repo)
+		if err != nil {
+			logger.Ctx(c).Err(err).Msgf("fail to get %s for repo %s", setting.Name, repo)
+			c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		c.JSON(http.StatusOK, value)
+	}
+}
+
+func (rsr *repoSettingsRouter) upsert(setting Setting) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		repo, ok := rsr.authorize(c)
+		if !ok {
+			return
+		}
+
+		body := setting.NewBody()
+		if err := c.ShouldBindJSON(body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"reason": "malformed-payload"})
+			return
+		}
+
+		value, err := setting.Set(c, repo, body)
+		if err != nil {
+			var invalidErr *InvalidError
+			if errors.As(err, &invalidErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"reason": invalidErr.Reason, "message": invalidErr.Error()})
+				return
+			}
+
+			logger.Ctx(c).Err(err).Msgf("fail to upsert %s for repo %s", setting.Name, repo)
+			c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+
+		c.JSON(http.StatusOK, value)
+	}
+}
+
+// authorize reads the repository of the route and checks that the user can manage its settings,
+// the repository is on github unless the provider query parameter says otherwise
+func (rsr *repoSettingsRouter) authorize(c *gin.Context) (reposettings.Repo, bool) {
+	authData, ok := auth.GetAuthData(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return reposettings.Repo{}, false
+	}
+
+	repo := reposettings.Repo{
+		Provider: c.DefaultQuery("provider", database.ProviderGitHub),
+		Owner:    c.Param("owner"),
+		Repo:     c.Param("repo"),
+	}
+	if repo.Owner == "" || repo.Repo == "" {
+		c.JSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return reposettings.Repo{}, false
+	}
+
+	isAuthorized, err := rsr.isAuthorized(c, repo.Provider, repo.Owner, authData)
+	if err != nil {
+		logger.Ctx(c).Err(err).Msg("fail to check for authorization")
+		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return reposettings.Repo{}, false
+	}
+
+	if !isAuthorized {
+		c.JSON(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return reposettings.Repo{}, false
+	}
+
+	return repo, true
+}
@@ -0,0 +1,117 @@
+package reposettings
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
+	deploymodesMocks "github.com/ergomake/ergomake/mocks/deploymodes"
+)
+
+func TestRepoSettingsRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tt := []struct {
+		name          string
+		method        string
+		query         string
+		body          string
+		authenticated bool
+		mock          func(provider *deploymodesMocks.DeployModesProvider)
+		expected      int
+		expectedBody  string
+	}{
+		{name: "unauthenticated", method: http.MethodGet, expected: http.StatusUnauthorized},
+		{
+			name:          "owner of another provider",
+			method:        http.MethodGet,
+			query:         "?provider=gitlab",
+			authenticated: true,
+			expected:      http.StatusForbidden,
+		},
+		{
+			name:          "gets the setting",
+			method:        http.MethodGet,
+			authenticated: true,
+			mock: func(provider *deploymodesMocks.DeployModesProvider) {
+				provider.EXPECT().Get(mock.Anything, "github", "owner", "repo").Return("update", nil)
+			},
+			expected:     http.StatusOK,
+			expectedBody: `{"mode":"update"}`,
+		},
+		{
+			name:          "malformed payload",
+			method:        http.MethodPost,
+			body:          "{",
+			authenticated: true,
+			expected:      http.StatusBadRequest,
+		},
+		{
+			name:          "invalid setting",
+			method:        http.MethodPost,
+			body:          `{"mode":"rolling"}`,
+			authenticated: true,
+			expected:      http.StatusBadRequest,
+		},
+		{
+			name:          "sets the setting",
+			method:        http.MethodPost,
+			body:          `{"mode":"update"}`,
+			authenticated: true,
+			mock: func(provider *deploymodesMocks.DeployModesProvider) {
+				provider.EXPECT().Upsert(mock.Anything, "github", "owner", "repo", "update").Return(nil)
+			},
+			expected:     http.StatusOK,
+			expectedBody: `{"mode":"update"}`,
+		},
+		{
+			name:          "fails to set the setting",
+			method:        http.MethodPost,
+			body:          `{"mode":"update"}`,
+			authenticated: true,
+			mock: func(provider *deploymodesMocks.DeployModesProvider) {
+				provider.EXPECT().Upsert(mock.Anything, "github", "owner", "repo", "update").Return(assert.AnError)
+			},
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			deployModesProvider := deploymodesMocks.NewDeployModesProvider(t)
+			if tc.mock != nil {
+				tc.mock(deployModesProvider)
+			}
+
+			rsr := NewRepoSettingsRouter(NewDeployModeSetting(deployModesProvider))
+			rsr.isAuthorized = func(ctx context.Context, provider, owner string, authData *auth.AuthData) (bool, error) {
+				return provider == database.ProviderGitHub && owner == "owner", nil
+			}
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tc.authenticated {
+					c.Set("customClaims", &auth.AuthData{})
+				}
+			})
+			rsr.AddRoutes(router.Group(""))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, "/owner/owner/repos/repo/deploy-mode"+tc.query, strings.NewReader(tc.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package reposettings
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ergomake/ergomake/internal/allowedhosts"
+	"github.com/ergomake/ergomake/internal/deploymodes"
+	"github.com/ergomake/ergomake/internal/prfilters"
+	"github.com/ergomake/ergomake/internal/reposettings"
+	"github.com/ergomake/ergomake/internal/urltemplates"
+)
+
+type upsertURLTemplate struct {
+	Template string `json:"template"`
+}
+
+func NewURLTemplateSetting(urlTemplatesProvider urltemplates.URLTemplatesProvider, clusterDomain string) Setting {
+	// same default the transformer uses when running outside of the cluster
+	if clusterDomain == "" {
+		clusterDomain = urltemplates.DefaultDomain
+	}
+
+	makeValue := func(template string) interface{} {
+		return gin.H{"template": template, "isDefault": template == urltemplates.DefaultTemplate}
+	}
+
+	return Setting{
+		Path: "url-template",
+		Name: "url template",
+		Get: func(ctx context.Context, repo reposettings.Repo) (interface{}, error) {
+			template, err := urlTemplatesProvider.Get(ctx, repo.Provider, repo.Owner, repo.Repo)
+			return makeValue(template), err
+		},
+		NewBody: func() interface{} { return &upsertURLTemplate{} },
+		Set: func(ctx context.Context, repo reposettings.Repo, body interface{}) (interface{}, error) {
+			template := strings.TrimSpace(body.(*upsertURLTemplate).Template)
+			if template == "" {
+				template = urltemplates.DefaultTemplate
+			}
+
+			err := urltemplates.Validate(template, repo.Owner, repo.Repo, clusterDomain)
+			if err != nil {
+				return nil, &InvalidError{Reason: "invalid-template", Err: err}
+			}
+
+			return makeValue(template), urlTemplatesProvider.Upsert(ctx, repo.Provider, repo.Owner, repo.Repo, template)
+		},
+	}
+}
+
+type upsertAllowedHosts struct {
+	Hosts []string `json:"hosts"`
+}
+
+func NewAllowedHostsSetting(allowedHostsProvider allowedhosts.AllowedHostsProvider) Setting {
+	makeValue := func(hosts []string) interface{} {
+		output := make([]gin.H, 0)
+		for _, h := range hosts {
+			output = append(output, gin.H{"host": h})
+		}
+		return output
+	}
+
+	return Setting{
+		Path: "allowed-hosts",
+		Name: "allowed hosts",
+		Get: func(ctx context.Context, repo reposettings.Repo) (interface{}, error) {
+			hosts, err := allowedHostsProvider.List(ctx, repo.Provider, repo.Owner, repo.Repo)
+			return makeValue(hosts), err
+		},
+		NewBody: func() interface{} { return &upsertAllowedHosts{} },
+		Set: func(ctx context.Context, repo reposettings.Repo, body interface{}) (interface{}, error) {
+			hosts := make([]string, 0)
+			for _, host := range body.(*upsertAllowedHosts).Hosts {
+				host = strings.ToLower(strings.TrimSpace(host))
+				if err := allowedhosts.Validate(host); err != nil {
+					return nil, &InvalidError{Reason: "invalid-host", Err: err}
+				}
+
+				hosts = append(hosts, host)
+			}
+
+			return makeValue(hosts), allowedHostsProvider.Replace(ctx, repo.Provider, repo.Owner, repo.Repo, hosts)
+		},
+	}
+}
+
+type upsertDeployMode struct {
+	Mode string `json:"mode"`
+}
+
+func NewDeployModeSetting(deployModesProvider deploymodes.DeployModesProvider) Setting {
+	return Setting{
+		Path: "deploy-mode",
+		Name: "deploy mode",
+		Get: func(ctx context.Context, repo reposettings.Repo) (interface{}, error) {
+			mode, err := deployModesProvider.Get(ctx, repo.Provider, repo.Owner, repo.Repo)
+			return gin.H{"mode": mode}, err
+		},
+		NewBody: func() interface{} { return &upsertDeployMode{} },
+		Set: func(ctx context.Context, repo reposettings.Repo, body interface{}) (interface{}, error) {
+			mode := strings.TrimSpace(body.(*upsertDeployMode).Mode)
+			if mode == "" {
+				mode = deploymodes.Default
+			}
+
+			err := deploymodes.Validate(mode)
+			if err != nil {
+				return nil, &InvalidError{Reason: "invalid-mode", Err: err}
+			}
+
+			return gin.H{"mode": mode}, deployModesProvider.Upsert(ctx, repo.Provider, repo.Owner, repo.Repo, mode)
+		},
+	}
+}
+
+func NewPRFiltersSetting(prFiltersProvider prfilters.PRFiltersProvider) Setting {
+	return Setting{
+		Path: "pr-filters",
+		Name: "pr filters",
+		Get: func(ctx context.Context, repo reposettings.Repo) (interface{}, error) {
+			return prFiltersProvider.Get(ctx, repo.Provider, repo.Owner, repo.Repo)
+		},
+		NewBody: func() interface{} { return &prfilters.Rules{} },
+		Set: func(ctx context.Context, repo reposettings.Repo, body interface{}) (interface{}, error) {
+			rules := *body.(*prfilters.Rules)
+			err := prfilters.Validate(rules)
+			if err != nil {
+				return nil, &InvalidError{Reason: "invalid-rules", Err: err}
+			}
+
+			return rules, prFiltersProvider.Upsert(ctx, repo.Provider, repo.Owner, repo.Repo, rules)
+		},
+	}
+}
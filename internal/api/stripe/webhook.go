@@ -9,6 +9,7 @@ import (
 	"github.com/stripe/stripe-go/v74"
 	"github.com/stripe/stripe-go/v74/webhook"
 
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -52,6 +53,8 @@ func (stp *stripeRouter) webhook(c *gin.Context) {
 
 	err = stp.paymentProvider.SaveSubscription(
 		c,
+		// checkout sessions are started by github users
+		database.ProviderGitHub,
 		checkoutSession.ClientReferenceID,
 		checkoutSession.Subscription.ID,
 	)
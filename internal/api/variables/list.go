@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -23,7 +24,9 @@ func (vr *variablesRouter) list(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	// the owner is on github unless the provider query parameter says otherwise
+	provider := c.DefaultQuery("provider", database.ProviderGitHub)
+	isAuthorized, err := auth.IsAuthorized(c, provider, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to list variables")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -35,7 +38,7 @@ func (vr *variablesRouter) list(c *gin.Context) {
 		return
 	}
 
-	variables, err := vr.envVarsProvider.ListByRepo(c, owner, repo)
+	variables, err := vr.envVarsProvider.ListByRepo(c, provider, owner, repo)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msgf("fail to list variables for repo %s/%s", owner, repo)
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -7,10 +7,28 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/ergomake/ergomake/internal/api/auth"
+	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/envvars"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
+// upsertVariable leaves the build secret flags of existing variables as they are when they are omitted
+type upsertVariable struct {
+	Name          string  `json:"name"`
+	Value         string  `json:"value"`
+	Branch        *string `json:"branch"`
+	BuildSecret   *bool   `json:"buildSecret"`
+	AllowBuildArg *bool   `json:"allowBuildArg"`
+}
+
+func variableKey(name string, branch *string) string {
+	if branch == nil {
+		return fmt.Sprintf("%s/", name)
+	}
+
+	return fmt.Sprintf("%s/%s", name, *branch)
+}
+
 func (vr *variablesRouter) upsert(c *gin.Context) {
 	authData, ok := auth.GetAuthData(c)
 	if !ok {
@@ -25,7 +43,9 @@ func (vr *variablesRouter) upsert(c *gin.Context) {
 		return
 	}
 
-	isAuthorized, err := auth.IsAuthorized(c, owner, authData)
+	// the owner is on github unless the provider query parameter says otherwise
+	provider := c.DefaultQuery("provider", database.ProviderGitHub)
+	isAuthorized, err := auth.IsAuthorized(c, provider, owner, authData)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msg("fail to check for authorization")
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -37,45 +57,56 @@ func (vr *variablesRouter) upsert(c *gin.Context) {
 		return
 	}
 
-	var body []envvars.EnvVar
+	var body []upsertVariable
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"reason": "malformed-payload"})
 		return
 	}
 
-	existingList, err := vr.envVarsProvider.ListByRepo(c, owner, repo)
+	existingList, err := vr.envVarsProvider.ListByRepo(c, provider, owner, repo)
 	if err != nil {
 		logger.Ctx(c).Err(err).Msgf("fail to list variables for repo %s/%s", owner, repo)
 		c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
+	existing := make(map[string]envvars.EnvVar)
+	for _, v := range existingList {
+		existing[variableKey(v.Name, v.Branch)] = v
+	}
+
 	toKeep := make(map[string]bool)
+	upserted := make([]envvars.EnvVar, 0, len(body))
 	for _, v := range body {
-		err := vr.envVarsProvider.Upsert(c, owner, repo, v.Name, v.Value, v.Branch)
+		key := variableKey(v.Name, v.Branch)
+		envVar := envvars.EnvVar{
+			Name:          v.Name,
+			Value:         v.Value,
+			Branch:        v.Branch,
+			BuildSecret:   existing[key].BuildSecret,
+			AllowBuildArg: existing[key].AllowBuildArg,
+		}
+		if v.BuildSecret != nil {
+			envVar.BuildSecret = *v.BuildSecret
+		}
+		if v.AllowBuildArg != nil {
+			envVar.AllowBuildArg = *v.AllowBuildArg
+		}
+
+		err := vr.envVarsProvider.Upsert(c, provider, owner, repo, envVar)
 		if err != nil {
 			logger.Ctx(c).Err(err).Msgf("fail to upsert variable %s", v.Name)
 			c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 			return
 		}
 
-		branch := ""
-		if v.Branch != nil {
-			branch = *v.Branch
-		}
-
-		toKeep[fmt.Sprintf("%s/%s", v.Name, branch)] = true
+		toKeep[key] = true
+		upserted = append(upserted, envVar)
 	}
 
 	for _, v := range existingList {
-		branch := ""
-		if v.Branch != nil {
-			branch = *v.Branch
-		}
-
-		key := fmt.Sprintf("%s/%s", v.Name, branch)
-		if !toKeep[key] {
-			err := vr.envVarsProvider.Delete(c, owner, repo, v.Name, v.Branch)
+		if !toKeep[variableKey(v.Name, v.Branch)] {
+			err := vr.envVarsProvider.Delete(c, provider, owner, repo, v.Name, v.Branch)
 			if err != nil {
 				logger.Ctx(c).Err(err).Msgf("fail to delete variable %s", v.Name)
 				c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
@@ -84,5 +115,5 @@ func (vr *variablesRouter) upsert(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, body)
+	c.JSON(http.StatusOK, upserted)
 }
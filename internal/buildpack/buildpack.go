@@ -2,7 +2,7 @@ package buildpack
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	kpackBuild "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	kpackCore "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
@@ -15,8 +15,10 @@ import (
 
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/git"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
 	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/transformer"
 )
@@ -47,6 +49,8 @@ func WatchBuilds(
 	ghApp ghapp.GHAppClient,
 	frontendURL string,
 ) (func(), error) {
+	// only github environments are built with buildpacks
+	host := ghlauncher.NewGitHubHost(ghApp)
 	buildCh := make(chan *kpackBuild.Build)
 	stopCh := make(chan struct{})
 
@@ -144,10 +148,16 @@ func WatchBuilds(
 					continue outer
 				}
 
-				envFrontendLink := fmt.Sprintf(
-					"%s/gh/%s/repos/%s/envs/%s",
-					frontendURL, env.Owner, env.Repo, env.ID.String(),
-				)
+				envFrontendLink := launcher.EnvironmentFrontendLink(frontendURL, &env)
+
+				err = host.ReportStatus(ctx, db, envFrontendLink, &env, sha, launcher.LaunchStatus{
+					State: git.CommitStateRunning,
+					Title: "Building environment",
+				})
+				if err != nil {
+					logger.Get().Err(err).Str("env", env.ID.String()).Msg("fail to report build progress")
+				}
+
 				success := true
 				for _, service := range env.Services {
 					if service.BuildStatus == "building" {
@@ -163,22 +173,28 @@ func WatchBuilds(
 
 				if success {
 					for _, service := range env.Services {
-						err := clusterClient.ScaleDeployment(ctx, env.ID.String(), service.Name, 1)
+						// jobs have no deployment
+						if service.Job {
+							continue
+						}
+
+						// services waiting for jobs are scaled up once those complete
+						deployment, err := clusterClient.GetDeployment(ctx, env.ID.String(), service.Name)
+						if err == nil && deployment.GetLabels()[cluster.WaitsForJobsLabel] == "true" {
+							continue
+						}
+
+						err = clusterClient.ScaleDeployment(ctx, env.ID.String(), service.Name, 1)
 						if err != nil {
 							logger.Get().Err(err).Str("env", env.ID.String()).Str("service", service.Name).
 								Msg("fail to scale deployment up when bringing environment up")
-							ghlauncher.FailRun(ctx, ghApp, db, envFrontendLink, &env, sha, nil)
+							launcher.FailRun(ctx, host, db, envFrontendLink, &env, sha, nil)
 							continue outer
 						}
 					}
 
-					err := db.Model(&env).Update("status", database.EnvSuccess).Error
-					if err != nil {
-						logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to update db environment status to success")
-						ghlauncher.FailRun(ctx, ghApp, db, envFrontendLink, &env, sha, nil)
-						continue outer
-					}
-					ghlauncher.SuccessRun(ctx, ghApp, db, envFrontendLink, transformer.EnvironmentFromDB(&env), &env, sha)
+					// setup jobs can take a while, don't hold other builds while they run
+					go finishEnvironment(ctx, clusterClient, db, host, envFrontendLink, env, sha)
 				} else {
 					err := db.Model(&env).Update("status", database.EnvDegraded).Error
 					if err != nil {
@@ -191,7 +207,7 @@ func WatchBuilds(
 						continue outer
 					}
 
-					ghlauncher.FailRun(ctx, ghApp, db, envFrontendLink, &env, sha, nil)
+					launcher.FailRun(ctx, host, db, envFrontendLink, &env, sha, nil)
 				}
 
 				logger.Ctx(ctx).Info().Str("env", env.ID.String()).Bool("success", success).
@@ -210,3 +226,60 @@ func WatchBuilds(
 
 	return clean, err
 }
+
+func finishEnvironment(
+	ctx context.Context,
+	clusterClient cluster.Client,
+	db *database.DB,
+	host launcher.GitHost,
+	envFrontendLink string,
+	env database.Environment,
+	sha string,
+) {
+	dependencyJobsCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	defer cancel()
+	dependencyJobsResult, err := cluster.RunDependencyJobs(dependencyJobsCtx, clusterClient, env.ID.String())
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to run dependency jobs")
+		launcher.FailRun(ctx, host, db, envFrontendLink, &env, sha, nil)
+		return
+	}
+
+	if len(dependencyJobsResult.Failed) > 0 {
+		err := db.Model(&env).Update("status", database.EnvDegraded).Error
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to update db environment status to degraded")
+		}
+
+		launcher.FailJobsRun(ctx, host, db, clusterClient, envFrontendLink, &env, sha, dependencyJobsResult.Failed)
+		return
+	}
+
+	jobsCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	defer cancel()
+	jobsResult, err := cluster.RunSetupJobs(jobsCtx, clusterClient, env.ID.String())
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to run setup jobs")
+		launcher.FailRun(ctx, host, db, envFrontendLink, &env, sha, nil)
+		return
+	}
+
+	if len(jobsResult.Failed) > 0 {
+		err := db.Model(&env).Update("status", database.EnvDegraded).Error
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to update db environment status to degraded")
+		}
+
+		launcher.FailJobsRun(ctx, host, db, clusterClient, envFrontendLink, &env, sha, jobsResult.Failed)
+		return
+	}
+
+	err = db.Model(&env).Update("status", database.EnvSuccess).Error
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to update db environment status to success")
+		launcher.FailRun(ctx, host, db, envFrontendLink, &env, sha, nil)
+		return
+	}
+
+	launcher.SuccessRun(ctx, host, db, envFrontendLink, transformer.EnvironmentFromDB(&env), &env, sha)
+}
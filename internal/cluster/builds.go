@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BuildEnvironmentLabel marks the build jobs, kpack builds and build secrets of an environment
+// so they can be stopped together when the launch that started them is cancelled.
+const BuildEnvironmentLabel = "preview.ergomake.dev/environment"
+
+var (
+	buildJobsResource   = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	kpackBuildsResource = schema.GroupVersionResource{Group: "kpack.io", Version: "v1alpha2", Resource: "builds"}
+	secretsResource     = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+// buildNamespaces are where the builds of every environment run, outside of the namespace of the environment
+var buildNamespaces = []string{"preview-builds", "kpack"}
+
+// DeleteBuilds deletes the kaniko jobs, the kpack builds and the build secrets created for the environment
+func DeleteBuilds(ctx context.Context, client Client, envID string) error {
+	selector := fmt.Sprintf("%s=%s", BuildEnvironmentLabel, envID)
+
+	err := client.DeleteCollection(ctx, buildJobsResource, "preview-builds", selector)
+	if err != nil {
+		return errors.Wrap(err, "fail to delete build jobs")
+	}
+
+	err = client.DeleteCollection(ctx, kpackBuildsResource, "kpack", selector)
+	if err != nil {
+		return errors.Wrap(err, "fail to delete kpack builds")
+	}
+
+	return DeleteBuildSecrets(ctx, client, envID)
+}
+
+// DeleteBuildSecrets deletes the build secrets created for the environment, finished builds no longer need them
+func DeleteBuildSecrets(ctx context.Context, client Client, envID string) error {
+	selector := fmt.Sprintf("%s=%s", BuildEnvironmentLabel, envID)
+	for _, namespace := range buildNamespaces {
+		err := client.DeleteCollection(ctx, secretsResource, namespace, selector)
+		if err != nil {
+			return errors.Wrapf(err, "fail to delete build secrets in namespace %s", namespace)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+	mocks "github.com/ergomake/ergomake/mocks/cluster"
+)
+
+func TestDeleteBuilds(t *testing.T) {
+	t.Parallel()
+
+	selector := "preview.ergomake.dev/environment=env-id"
+	isJobs := func(gvr schema.GroupVersionResource) bool { return gvr.Resource == "jobs" }
+	isBuilds := func(gvr schema.GroupVersionResource) bool { return gvr.Group == "kpack.io" && gvr.Resource == "builds" }
+	isSecrets := func(gvr schema.GroupVersionResource) bool { return gvr.Group == "" && gvr.Resource == "secrets" }
+
+	t.Run("deletes jobs, kpack builds and build secrets of the environment", func(t *testing.T) {
+		client := mocks.NewClient(t)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isJobs), "preview-builds", selector).Return(nil)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isBuilds), "kpack", selector).Return(nil)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isSecrets), "preview-builds", selector).Return(nil)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isSecrets), "kpack", selector).Return(nil)
+
+		assert.NoError(t, cluster.DeleteBuilds(context.TODO(), client, "env-id"))
+	})
+
+	t.Run("errors when jobs can't be deleted", func(t *testing.T) {
+		client := mocks.NewClient(t)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isJobs), "preview-builds", selector).
+			Return(errors.New("rip"))
+
+		assert.Error(t, cluster.DeleteBuilds(context.TODO(), client, "env-id"))
+	})
+}
+
+func TestDeleteBuildSecrets(t *testing.T) {
+	t.Parallel()
+
+	selector := "preview.ergomake.dev/environment=env-id"
+	isSecrets := func(gvr schema.GroupVersionResource) bool { return gvr.Group == "" && gvr.Resource == "secrets" }
+
+	t.Run("deletes build secrets of the environment in every build namespace", func(t *testing.T) {
+		client := mocks.NewClient(t)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isSecrets), "preview-builds", selector).Return(nil)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isSecrets), "kpack", selector).Return(nil)
+
+		assert.NoError(t, cluster.DeleteBuildSecrets(context.TODO(), client, "env-id"))
+	})
+
+	t.Run("errors when secrets can't be deleted", func(t *testing.T) {
+		client := mocks.NewClient(t)
+		client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isSecrets), "preview-builds", selector).
+			Return(errors.New("rip"))
+
+		assert.Error(t, cluster.DeleteBuildSecrets(context.TODO(), client, "env-id"))
+	})
+}
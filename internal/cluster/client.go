@@ -9,6 +9,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 )
@@ -26,12 +27,13 @@ type Starter interface {
 
 type Client interface {
 	CreateNamespace(ctx context.Context, namespace string) error
+	EnsureNamespace(ctx context.Context, namespace string) (bool, error)
+	ApplyObject(ctx context.Context, obj runtime.Object) error
+	DeleteCollection(ctx context.Context, gvr schema.GroupVersionResource, namespace, labelSelector string) error
 	DeleteNamespace(ctx context.Context, namespace string) error
-	CreateService(ctx context.Context, service *corev1.Service) error
 	CreateDeployment(ctx context.Context, deployment *appsv1.Deployment) error
-	CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error
-	CreateIngress(ctx context.Context, ingress *networkingv1.Ingress) error
 	CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error)
+	ResumeJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error)
 	CreateSecret(ctx context.Context, secret *corev1.Secret) error
 	CreateServiceAccount(ctx context.Context, svcAcc *corev1.ServiceAccount) error
 	GetPreviewNamespaces(ctx context.Context) ([]corev1.Namespace, error)
@@ -45,6 +47,7 @@ type Client interface {
 	GetJobLogs(ctx context.Context, job *batchv1.Job, size int64) (string, error)
 	ListJobs(ctx context.Context, namespace string) ([]*batchv1.Job, error)
 	AreServicesAlive(ctx context.Context, namespace string) (bool, error)
+	GetServiceLogs(ctx context.Context, namespace, name string, size int64) (string, error)
 	WatchServiceLogs(ctx context.Context, namespace, name string, sinceSeconds int64) (<-chan string, <-chan error, error)
 	ApplyKPackBuilds(ctx context.Context, builds []*kpack.Build) error
 	WatchResource(ctx context.Context, gvr schema.GroupVersionResource, handler cache.ResourceEventHandlerFuncs) (Starter, error)
@@ -2,29 +2,65 @@ package cluster
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 )
 
 type ClusterEnv struct {
 	Namespace string
 	Objects   []runtime.Object
+	// Prune deletes what previous deploys of the namespace applied that is no longer part of Objects
+	Prune bool
 }
 
-func Deploy(ctx context.Context, client Client, env *ClusterEnv) (err error) {
-	err = client.CreateNamespace(ctx, env.Namespace)
+// DeployLabel tells which deploy last applied an object, objects a deploy did not apply are pruned
+const DeployLabel = "preview.ergomake.dev/deploy"
+
+// pruneResources are the kinds of objects environments are made of
+var pruneResources = []schema.GroupVersionResource{
+	appsv1.SchemeGroupVersion.WithResource("deployments"),
+	batchv1.SchemeGroupVersion.WithResource("jobs"),
+	corev1.SchemeGroupVersion.WithResource("services"),
+	corev1.SchemeGroupVersion.WithResource("configmaps"),
+	corev1.SchemeGroupVersion.WithResource("secrets"),
+	corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"),
+	networkingv1.SchemeGroupVersion.WithResource("ingresses"),
+	networkingv1.SchemeGroupVersion.WithResource("networkpolicies"),
+}
 
+var deployBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// Deploy applies every object of env, creating the ones that don't exist and updating the ones that do,
+// so it can both launch a new environment and update one in place.
+func Deploy(ctx context.Context, client Client, env *ClusterEnv) (err error) {
+	created, err := client.EnsureNamespace(ctx, env.Namespace)
 	if err != nil {
 		return errors.Wrapf(err, "fail to create namespace %s", env.Namespace)
 	}
 
 	// this works like a rollback, it deletes the namespace when error
+	// but only when this deploy created it, updates keep what was already running
 	defer func() {
-		if err == nil {
+		if err == nil || !created {
 			return
 		}
 
@@ -32,49 +68,69 @@ func Deploy(ctx context.Context, client Client, env *ClusterEnv) (err error) {
 		client.DeleteNamespace(ctx, env.Namespace)
 	}()
 
+	deployID := uuid.NewString()
 	for _, obj := range env.Objects {
-		switch obj := obj.(type) {
-
-		case *corev1.Secret:
-			err = client.CreateSecret(ctx, obj)
-			if err != nil {
-				return errors.Wrapf(err, "fail to create %s secret", obj.Name)
+		obj := obj
+		if accessor, err := meta.Accessor(obj); err == nil {
+			if accessor.GetNamespace() == "" {
+				accessor.SetNamespace(env.Namespace)
 			}
-		case *corev1.Service:
-			err = client.CreateService(ctx, obj)
-			if err != nil {
-				return errors.Wrapf(err, "fail to create %s service", obj.Name)
-			}
-		case *appsv1.Deployment:
-			err = client.CreateDeployment(ctx, obj)
-			if err != nil {
-				return errors.Wrapf(err, "fail to create %s deployment", obj.Name)
-			}
-		case *corev1.ConfigMap:
-			err = client.CreateConfigMap(ctx, obj)
-			if err != nil {
-				return errors.Wrapf(err, "fail to create %s configmap", obj.Name)
-			}
-		case *networkingv1.Ingress:
-			err = client.CreateIngress(ctx, obj)
-			if err != nil {
-				return errors.Wrapf(err, "fail to create %s ingress", obj.Name)
+
+			// the labels may be shared with the pod template, changing them there would roll the pods
+			labels := map[string]string{}
+			for k, v := range accessor.GetLabels() {
+				labels[k] = v
 			}
+			labels[DeployLabel] = deployID
+			accessor.SetLabels(labels)
+		}
+
+		err = retry.OnError(deployBackoff, IsTransientError, func() error {
+			return client.ApplyObject(ctx, obj)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "fail to apply %s", describeObject(obj))
+		}
+	}
+
+	if !env.Prune {
+		return nil
+	}
 
-		case *networkingv1.NetworkPolicy:
-			// TODO: this is being ignored because the default policy disallows ingress from outside of the cluster
-			// but, we probably should have a network policy
-			//
-			// _, err := clientset.NetworkingV1().NetworkPolicies(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
-			// if err != nil {
-			// 	return err
-			// }
-			continue
-		// Add cases for other object types here
-		default:
-			return errors.Errorf("unknown object type: %T", obj)
+	selector := fmt.Sprintf("%s,%s!=%s", DeployLabel, DeployLabel, deployID)
+	for _, gvr := range pruneResources {
+		gvr := gvr
+		err = retry.OnError(deployBackoff, IsTransientError, func() error {
+			return client.DeleteCollection(ctx, gvr, env.Namespace, selector)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "fail to prune %s", gvr.Resource)
 		}
 	}
 
 	return nil
 }
+
+// IsTransientError tells whether a request to the cluster may succeed if tried again
+func IsTransientError(err error) bool {
+	if k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsInternalError(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsConflict(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func describeObject(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+
+	return fmt.Sprintf("%T %s", obj, accessor.GetName())
+}
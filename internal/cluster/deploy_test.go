@@ -3,14 +3,15 @@ package cluster_test
 import (
 	"context"
 	"errors"
-	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -42,7 +43,7 @@ func TestDeploy(t *testing.T) {
 			name: "errors when namespace errors",
 			client: func(t *testing.T, _ *cluster.ClusterEnv) cluster.Client {
 				client := mocks.NewClient(t)
-				client.EXPECT().CreateNamespace(mock.Anything, "namespace").Return(errors.New("rip"))
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(false, errors.New("rip"))
 				return client
 			},
 			env: &cluster.ClusterEnv{
@@ -52,9 +53,10 @@ func TestDeploy(t *testing.T) {
 		},
 		{
 			name: "errors on unexpected object and deletes namespace",
-			client: func(t *testing.T, _ *cluster.ClusterEnv) cluster.Client {
+			client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
 				client := mocks.NewClient(t)
-				client.EXPECT().CreateNamespace(mock.Anything, "namespace").Return(nil)
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(true, nil)
+				client.EXPECT().ApplyObject(mock.Anything, env.Objects[0]).Return(errors.New("unknown kind")).Once()
 				client.EXPECT().DeleteNamespace(mock.Anything, "namespace").Return(nil)
 				return client
 			},
@@ -65,16 +67,13 @@ func TestDeploy(t *testing.T) {
 			errors: true,
 		},
 		{
-			name: "creates all sorts of objects",
+			name: "applies all sorts of objects",
 			client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
 				client := mocks.NewClient(t)
-				client.EXPECT().CreateNamespace(mock.Anything, "namespace").Return(nil)
-				client.EXPECT().CreateService(mock.Anything, env.Objects[0]).Return(nil)
-				client.EXPECT().CreateDeployment(mock.Anything, env.Objects[1]).Return(nil)
-				client.EXPECT().CreateConfigMap(mock.Anything, env.Objects[2]).Return(nil)
-				client.EXPECT().CreateIngress(mock.Anything, env.Objects[3]).Return(nil)
-				client.EXPECT().CreateIngress(mock.Anything, env.Objects[3]).Return(nil)
-				client.EXPECT().CreateSecret(mock.Anything, env.Objects[4]).Return(nil)
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(true, nil)
+				for _, obj := range env.Objects {
+					client.EXPECT().ApplyObject(mock.Anything, obj).Return(nil).Once()
+				}
 				return client
 			},
 			env: &cluster.ClusterEnv{
@@ -86,42 +85,85 @@ func TestDeploy(t *testing.T) {
 					&networkingv1.Ingress{},
 					&corev1.Secret{},
 					&networkingv1.NetworkPolicy{},
+					&corev1.PersistentVolumeClaim{},
+					&batchv1.Job{},
+					&appsv1.StatefulSet{},
+					&batchv1.CronJob{},
 				},
 			},
 			errors: false,
 		},
-	}
+		{
+			name: "retries transient errors",
+			client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
+				client := mocks.NewClient(t)
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(true, nil)
+				client.EXPECT().ApplyObject(mock.Anything, env.Objects[0]).
+					Return(k8serrors.NewServiceUnavailable("busy")).Once()
+				client.EXPECT().ApplyObject(mock.Anything, env.Objects[0]).Return(nil).Once()
+				return client
+			},
+			env: &cluster.ClusterEnv{
+				Namespace: "namespace",
+				Objects:   []runtime.Object{&appsv1.Deployment{}},
+			},
+			errors: false,
+		},
+		{
+			name: "does not retry other errors",
+			client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
+				client := mocks.NewClient(t)
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(true, nil)
+				client.EXPECT().ApplyObject(mock.Anything, env.Objects[0]).
+					Return(k8serrors.NewBadRequest("nope")).Once()
+				client.EXPECT().DeleteNamespace(mock.Anything, "namespace").Return(nil)
+				return client
+			},
+			env: &cluster.ClusterEnv{
+				Namespace: "namespace",
+				Objects:   []runtime.Object{&appsv1.Deployment{}},
+			},
+			errors: true,
+		},
+		{
+			name: "keeps a namespace it did not create",
+			client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
+				client := mocks.NewClient(t)
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(false, nil)
+				client.EXPECT().ApplyObject(mock.Anything, env.Objects[0]).Return(errors.New("rip")).Once()
+				return client
+			},
+			env: &cluster.ClusterEnv{
+				Namespace: "namespace",
+				Objects:   []runtime.Object{&batchv1.Job{}},
+			},
+			errors: true,
+		},
+		{
+			name: "prunes what previous deploys applied",
+			client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
+				client := mocks.NewClient(t)
+				client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(false, nil)
+				client.EXPECT().ApplyObject(mock.Anything, env.Objects[0]).Return(nil).Once()
 
-	failures := []struct {
-		obj    runtime.Object
-		method string
-	}{
-		{&corev1.Service{}, "CreateService"},
-		{&appsv1.Deployment{}, "CreateDeployment"},
-		{&corev1.ConfigMap{}, "CreateConfigMap"},
-		{&networkingv1.Ingress{}, "CreateIngress"},
-		{&corev1.Secret{}, "CreateSecret"},
-	}
-	for _, failure := range failures {
-		func(method string, obj runtime.Object) {
-			tt = append(tt, testCase{
-				name: fmt.Sprintf("errors when %s errors and deletes namespace", failure.method),
-				client: func(t *testing.T, env *cluster.ClusterEnv) cluster.Client {
-					client := mocks.NewClient(t)
-
-					client.EXPECT().CreateNamespace(mock.Anything, "namespace").Return(nil)
-					client.EXPECT().DeleteNamespace(mock.Anything, "namespace").Return(nil)
-					client.On(method, mock.Anything, env.Objects[0]).Return(errors.New("rip"))
-
-					return client
-				},
-				env: &cluster.ClusterEnv{
-					Namespace: "namespace",
-					Objects:   []runtime.Object{obj},
-				},
-				errors: true,
-			})
-		}(failure.method, failure.obj)
+				isPrevious := func(selector string) bool {
+					deployID := env.Objects[0].(*appsv1.Deployment).GetLabels()[cluster.DeployLabel]
+					return deployID != "" && selector == cluster.DeployLabel+","+cluster.DeployLabel+"!="+deployID
+				}
+				isJobs := func(gvr schema.GroupVersionResource) bool { return gvr.Resource == "jobs" }
+				client.EXPECT().DeleteCollection(mock.Anything, mock.MatchedBy(isJobs), "namespace", mock.MatchedBy(isPrevious)).
+					Return(k8serrors.NewServiceUnavailable("busy")).Once()
+				client.EXPECT().DeleteCollection(mock.Anything, mock.Anything, "namespace", mock.MatchedBy(isPrevious)).
+					Return(nil).Times(8)
+				return client
+			},
+			env: &cluster.ClusterEnv{
+				Namespace: "namespace",
+				Objects:   []runtime.Object{&appsv1.Deployment{}},
+				Prune:     true,
+			},
+			errors: false,
+		},
 	}
 
 	for _, tc := range tt {
@@ -135,3 +177,38 @@ func TestDeploy(t *testing.T) {
 		})
 	}
 }
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, cluster.IsTransientError(k8serrors.NewServiceUnavailable("busy")))
+	assert.True(t, cluster.IsTransientError(k8serrors.NewTooManyRequests("slow down", 1)))
+	assert.True(t, cluster.IsTransientError(k8serrors.NewInternalError(errors.New("rip"))))
+	assert.False(t, cluster.IsTransientError(k8serrors.NewBadRequest("nope")))
+	assert.False(t, cluster.IsTransientError(errors.New("rip")))
+}
+
+func TestDeployLabelsObjects(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"app": "api"}
+	deployment := &appsv1.Deployment{}
+	deployment.SetLabels(labels)
+	deployment.Spec.Template.SetLabels(labels)
+
+	client := mocks.NewClient(t)
+	client.EXPECT().EnsureNamespace(mock.Anything, "namespace").Return(false, nil)
+	client.EXPECT().ApplyObject(mock.Anything, deployment).Return(nil).Once()
+
+	err := cluster.Deploy(context.Background(), client, &cluster.ClusterEnv{
+		Namespace: "namespace",
+		Objects:   []runtime.Object{deployment},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "namespace", deployment.GetNamespace())
+	assert.NotEmpty(t, deployment.GetLabels()[cluster.DeployLabel])
+	assert.Equal(t, "api", deployment.GetLabels()["app"])
+	// the pods are left alone so they don't roll on every deploy
+	assert.Equal(t, map[string]string{"app": "api"}, deployment.Spec.Template.GetLabels())
+}
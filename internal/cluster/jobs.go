@@ -0,0 +1,145 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// SetupJobLabel marks the jobs that must complete before an environment is ready,
+// they are created suspended by Deploy and only started by RunSetupJobs.
+const SetupJobLabel = "preview.ergomake.dev/setup-job"
+
+// DependencyJobLabel marks the jobs services depend on to complete successfully, they are
+// created suspended by Deploy and started by RunDependencyJobs before those services are scaled up.
+const DependencyJobLabel = "preview.ergomake.dev/dependency-job"
+
+// AfterJobsAnnotation lists, comma separated, the jobs that must complete before a job starts
+const AfterJobsAnnotation = "preview.ergomake.dev/after-jobs"
+
+// DependantsAnnotation lists, comma separated, the deployments that wait for a dependency job,
+// they are deployed without replicas and scaled up once every dependency job completes
+const DependantsAnnotation = "preview.ergomake.dev/dependants"
+
+// WaitsForJobsLabel marks the deployments that stay scaled down until the dependency jobs complete
+const WaitsForJobsLabel = "preview.ergomake.dev/waits-for-jobs"
+
+func RunSetupJobs(ctx context.Context, client Client, namespace string) (*WaitJobsResult, error) {
+	result, _, err := runLabeledJobs(ctx, client, namespace, SetupJobLabel)
+	return result, errors.Wrap(err, "fail to run setup jobs")
+}
+
+// RunDependencyJobs runs the jobs services depend on to complete and scales those services up
+// once every job succeeded, they are left scaled down when any of them fails
+func RunDependencyJobs(ctx context.Context, client Client, namespace string) (*WaitJobsResult, error) {
+	result, jobs, err := runLabeledJobs(ctx, client, namespace, DependencyJobLabel)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to run dependency jobs")
+	}
+
+	if len(result.Failed) > 0 {
+		return result, nil
+	}
+
+	dependants := map[string]struct{}{}
+	for _, job := range jobs {
+		for _, name := range splitNames(job.GetAnnotations()[DependantsAnnotation]) {
+			dependants[name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(dependants))
+	for name := range dependants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err := client.ScaleDeployment(ctx, namespace, name, 1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to scale up deployment %s after its dependency jobs", name)
+		}
+	}
+
+	return result, nil
+}
+
+// runLabeledJobs resumes the jobs of namespace labeled with label and waits for them, a job only
+// starts after the jobs of its AfterJobsAnnotation succeeded. Once a job fails the ones that
+// did not start yet are left suspended.
+func runLabeledJobs(ctx context.Context, client Client, namespace, label string) (*WaitJobsResult, []*batchv1.Job, error) {
+	jobs, err := client.ListJobs(ctx, namespace)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "fail to list jobs of namespace %s", namespace)
+	}
+
+	pending := map[string]*batchv1.Job{}
+	for _, job := range jobs {
+		if job.GetLabels()[label] == "true" {
+			pending[job.GetName()] = job
+		}
+	}
+
+	all := make([]*batchv1.Job, 0, len(pending))
+	for _, job := range pending {
+		all = append(all, job)
+	}
+
+	result := &WaitJobsResult{}
+	for len(pending) > 0 {
+		wave := []*batchv1.Job{}
+		for _, job := range pending {
+			ready := true
+			for _, after := range splitNames(job.GetAnnotations()[AfterJobsAnnotation]) {
+				// jobs that aren't of this kind already ran
+				if _, ok := pending[after]; ok {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				wave = append(wave, job)
+			}
+		}
+
+		if len(wave) == 0 {
+			return nil, nil, errors.Errorf("jobs of namespace %s wait for each other", namespace)
+		}
+		sort.Slice(wave, func(i, j int) bool { return wave[i].GetName() < wave[j].GetName() })
+
+		resumed := make([]*batchv1.Job, len(wave))
+		for i, job := range wave {
+			resumed[i], err = client.ResumeJob(ctx, job)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "fail to resume job %s", job.GetName())
+			}
+
+			delete(pending, job.GetName())
+		}
+
+		waveResult, err := client.WaitJobs(ctx, resumed)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "fail to wait for jobs to complete")
+		}
+
+		result.Succeeded = append(result.Succeeded, waveResult.Succeeded...)
+		result.Failed = append(result.Failed, waveResult.Failed...)
+		if len(waveResult.Failed) > 0 {
+			break
+		}
+	}
+
+	return result, all, nil
+}
+
+func splitNames(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,136 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+	mocks "github.com/ergomake/ergomake/mocks/cluster"
+)
+
+func makeJob(name string, setup bool) *batchv1.Job {
+	labels := map[string]string{}
+	if setup {
+		labels[cluster.SetupJobLabel] = "true"
+	}
+
+	return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace", Labels: labels}}
+}
+
+func TestRunSetupJobs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resumes and waits only for setup jobs", func(t *testing.T) {
+		t.Parallel()
+
+		migrate := makeJob("migrate", true)
+		build := makeJob("build", false)
+		waitResult := &cluster.WaitJobsResult{Succeeded: []*batchv1.Job{migrate}}
+
+		client := mocks.NewClient(t)
+		client.EXPECT().ListJobs(mock.Anything, "namespace").Return([]*batchv1.Job{migrate, build}, nil)
+		client.EXPECT().ResumeJob(mock.Anything, migrate).Return(migrate, nil)
+		client.EXPECT().WaitJobs(mock.Anything, []*batchv1.Job{migrate}).Return(waitResult, nil)
+
+		result, err := cluster.RunSetupJobs(context.Background(), client, "namespace")
+		require.NoError(t, err)
+		assert.Equal(t, waitResult, result)
+	})
+
+	t.Run("does not wait when there are no setup jobs", func(t *testing.T) {
+		t.Parallel()
+
+		client := mocks.NewClient(t)
+		client.EXPECT().ListJobs(mock.Anything, "namespace").Return([]*batchv1.Job{makeJob("build", false)}, nil)
+
+		result, err := cluster.RunSetupJobs(context.Background(), client, "namespace")
+		require.NoError(t, err)
+		assert.Empty(t, result.Failed)
+	})
+
+	t.Run("errors when resume fails", func(t *testing.T) {
+		t.Parallel()
+
+		migrate := makeJob("migrate", true)
+
+		client := mocks.NewClient(t)
+		client.EXPECT().ListJobs(mock.Anything, "namespace").Return([]*batchv1.Job{migrate}, nil)
+		client.EXPECT().ResumeJob(mock.Anything, migrate).Return(nil, errors.New("rip"))
+
+		_, err := cluster.RunSetupJobs(context.Background(), client, "namespace")
+		assert.Error(t, err)
+	})
+}
+
+func makeDependencyJob(name, after, dependants string) *batchv1.Job {
+	return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   "namespace",
+		Labels:      map[string]string{cluster.DependencyJobLabel: "true"},
+		Annotations: map[string]string{cluster.AfterJobsAnnotation: after, cluster.DependantsAnnotation: dependants},
+	}}
+}
+
+func TestRunDependencyJobs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs jobs after the ones they depend on and scales up dependants", func(t *testing.T) {
+		t.Parallel()
+
+		migrate := makeDependencyJob("migrate", "", "api")
+		seed := makeDependencyJob("seed", "migrate", "api,worker")
+
+		client := mocks.NewClient(t)
+		client.EXPECT().ListJobs(mock.Anything, "namespace").Return([]*batchv1.Job{seed, migrate, makeJob("build", false)}, nil)
+		client.EXPECT().ResumeJob(mock.Anything, migrate).Return(migrate, nil).Once()
+		client.EXPECT().WaitJobs(mock.Anything, []*batchv1.Job{migrate}).
+			Return(&cluster.WaitJobsResult{Succeeded: []*batchv1.Job{migrate}}, nil).Once()
+		client.EXPECT().ResumeJob(mock.Anything, seed).Return(seed, nil).Once()
+		client.EXPECT().WaitJobs(mock.Anything, []*batchv1.Job{seed}).
+			Return(&cluster.WaitJobsResult{Succeeded: []*batchv1.Job{seed}}, nil).Once()
+		client.EXPECT().ScaleDeployment(mock.Anything, "namespace", "api", int32(1)).Return(nil).Once()
+		client.EXPECT().ScaleDeployment(mock.Anything, "namespace", "worker", int32(1)).Return(nil).Once()
+
+		result, err := cluster.RunDependencyJobs(context.Background(), client, "namespace")
+		require.NoError(t, err)
+		assert.Equal(t, []*batchv1.Job{migrate, seed}, result.Succeeded)
+		assert.Empty(t, result.Failed)
+	})
+
+	t.Run("leaves later jobs suspended and dependants scaled down when a job fails", func(t *testing.T) {
+		t.Parallel()
+
+		migrate := makeDependencyJob("migrate", "", "api")
+		seed := makeDependencyJob("seed", "migrate", "api")
+
+		client := mocks.NewClient(t)
+		client.EXPECT().ListJobs(mock.Anything, "namespace").Return([]*batchv1.Job{migrate, seed}, nil)
+		client.EXPECT().ResumeJob(mock.Anything, migrate).Return(migrate, nil).Once()
+		client.EXPECT().WaitJobs(mock.Anything, []*batchv1.Job{migrate}).
+			Return(&cluster.WaitJobsResult{Failed: []*batchv1.Job{migrate}}, nil).Once()
+
+		result, err := cluster.RunDependencyJobs(context.Background(), client, "namespace")
+		require.NoError(t, err)
+		assert.Equal(t, []*batchv1.Job{migrate}, result.Failed)
+	})
+
+	t.Run("errors when jobs wait for each other", func(t *testing.T) {
+		t.Parallel()
+
+		client := mocks.NewClient(t)
+		client.EXPECT().ListJobs(mock.Anything, "namespace").Return([]*batchv1.Job{
+			makeDependencyJob("migrate", "seed", "api"),
+			makeDependencyJob("seed", "migrate", "api"),
+		}, nil)
+
+		_, err := cluster.RunDependencyJobs(context.Background(), client, "namespace")
+		assert.Error(t, err)
+	})
+}
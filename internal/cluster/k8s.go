@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	kpackBuild "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
@@ -21,13 +22,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
@@ -36,9 +44,13 @@ import (
 
 type k8sClient struct {
 	*kubernetes.Clientset
-	config *rest.Config
+	config  *rest.Config
+	dynamic dynamic.Interface
+	mapper  meta.ResettableRESTMapper
 }
 
+const fieldManager = "ergomake"
+
 func k8sConfig() (*rest.Config, error) {
 	configPath := filepath.Join(homedir.HomeDir(), ".kube", "config")
 	_, err := os.Stat(configPath)
@@ -61,7 +73,14 @@ func NewK8sClient() (*k8sClient, error) {
 		return nil, errors.Wrap(err, "fail to create k8s clientset")
 	}
 
-	return &k8sClient{clientset, config}, nil
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to create k8s dynamic client")
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
+
+	return &k8sClient{clientset, config, dynamicClient, mapper}, nil
 }
 
 func (k8s *k8sClient) CreateNamespace(ctx context.Context, namespace string) error {
@@ -79,6 +98,120 @@ func (k8s *k8sClient) CreateNamespace(ctx context.Context, namespace string) err
 	return err
 }
 
+// EnsureNamespace creates the namespace when it doesn't exist yet and tells whether it did
+func (k8s *k8sClient) EnsureNamespace(ctx context.Context, namespace string) (bool, error) {
+	err := k8s.CreateNamespace(ctx, namespace)
+	if k8serrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// ApplyObject creates or updates obj with server-side apply, any kind known to the scheme works
+func (k8s *k8sClient) ApplyObject(ctx context.Context, obj runtime.Object) error {
+	u, gvk, resource, err := k8s.resourceFor(obj)
+	if err != nil {
+		return err
+	}
+	unstructured.RemoveNestedField(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+
+	opts := metav1.ApplyOptions{FieldManager: fieldManager, Force: true}
+	_, err = resource.Apply(ctx, u.GetName(), u, opts)
+	if k8serrors.IsInvalid(err) && gvk.GroupKind() == batchv1.SchemeGroupVersion.WithKind("Job").GroupKind() {
+		// the template of a job can't change, it must be recreated instead. The old job and its pods
+		// must be gone before applying or the apply would land on the job being terminated.
+		propagation := metav1.DeletePropagationForeground
+		err = resource.Delete(ctx, u.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "fail to delete job %s to recreate it", u.GetName())
+		}
+
+		err = waitDeleted(ctx, resource, u.GetName())
+		if err != nil {
+			return errors.Wrapf(err, "fail to wait for job %s to be deleted", u.GetName())
+		}
+
+		_, err = resource.Apply(ctx, u.GetName(), u, opts)
+	}
+
+	return errors.Wrapf(err, "fail to apply %s %s", gvk.Kind, u.GetName())
+}
+
+// waitDeleted polls for the object called name until it is gone, for at most 2 minutes
+func waitDeleted(ctx context.Context, resource dynamic.ResourceInterface, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	for {
+		_, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// DeleteCollection deletes every object of gvr in namespace that matches labelSelector
+func (k8s *k8sClient) DeleteCollection(
+	ctx context.Context,
+	gvr schema.GroupVersionResource,
+	namespace, labelSelector string,
+) error {
+	propagation := metav1.DeletePropagationBackground
+	err := k8s.dynamic.Resource(gvr).Namespace(namespace).DeleteCollection(
+		ctx,
+		metav1.DeleteOptions{PropagationPolicy: &propagation},
+		metav1.ListOptions{LabelSelector: labelSelector},
+	)
+
+	return errors.Wrapf(err, "fail to delete %s matching %s in namespace %s", gvr.Resource, labelSelector, namespace)
+}
+
+// resourceFor converts obj to unstructured and finds the dynamic resource that serves its kind
+func (k8s *k8sClient) resourceFor(
+	obj runtime.Object,
+) (*unstructured.Unstructured, schema.GroupVersionKind, dynamic.ResourceInterface, error) {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, schema.GroupVersionKind{}, nil, errors.Wrapf(err, "fail to get kind of %T", obj)
+	}
+	gvk := gvks[0]
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, gvk, nil, errors.Wrapf(err, "fail to convert %s to unstructured", gvk.Kind)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+
+	mapping, err := k8s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// the kind may have been installed after discovery was cached
+		k8s.mapper.Reset()
+		mapping, err = k8s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, gvk, nil, errors.Wrapf(err, "fail to map kind %s to a resource", gvk.Kind)
+	}
+
+	var resource dynamic.ResourceInterface = k8s.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = k8s.dynamic.Resource(mapping.Resource).Namespace(u.GetNamespace())
+	}
+
+	return u, gvk, resource, nil
+}
+
 func (k8s *k8sClient) GetPreviewNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
 	namespaces, err := k8s.CoreV1().Namespaces().
 		List(ctx, metav1.ListOptions{
@@ -117,13 +250,6 @@ func (k8s *k8sClient) DeleteNamespace(ctx context.Context, namespace string) err
 	}
 }
 
-func (k8s *k8sClient) CreateService(ctx context.Context, service *corev1.Service) error {
-	_, err := k8s.CoreV1().Services(service.GetNamespace()).
-		Create(ctx, service, metav1.CreateOptions{})
-
-	return err
-}
-
 func (k8s *k8sClient) CreateDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
 	_, err := k8s.AppsV1().Deployments(deployment.GetNamespace()).
 		Create(ctx, deployment, metav1.CreateOptions{})
@@ -131,24 +257,16 @@ func (k8s *k8sClient) CreateDeployment(ctx context.Context, deployment *appsv1.D
 	return err
 }
 
-func (k8s *k8sClient) CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
-	_, err := k8s.CoreV1().ConfigMaps(configMap.GetNamespace()).
-		Create(ctx, configMap, metav1.CreateOptions{})
-
-	return err
-}
-
-func (k8s *k8sClient) CreateIngress(ctx context.Context, ingress *networkingv1.Ingress) error {
-	_, err := k8s.NetworkingV1().Ingresses(ingress.GetNamespace()).
-		Create(ctx, ingress, metav1.CreateOptions{})
-
-	return err
-}
-
 func (k8s *k8sClient) CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
 	return k8s.BatchV1().Jobs(job.GetNamespace()).Create(ctx, job, metav1.CreateOptions{})
 }
 
+func (k8s *k8sClient) ResumeJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	patch := []byte(`{"spec":{"suspend":false}}`)
+	return k8s.BatchV1().Jobs(job.GetNamespace()).
+		Patch(ctx, job.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
 func (k8s *k8sClient) CreateSecret(ctx context.Context, secret *corev1.Secret) error {
 	_, err := k8s.CoreV1().Secrets(secret.GetNamespace()).
 		Create(ctx, secret, metav1.CreateOptions{})
@@ -294,18 +412,6 @@ func (k8s *k8sClient) WaitDeployments(ctx context.Context, namespace string) err
 }
 
 func (k8s *k8sClient) GetJobLogs(ctx context.Context, job *batchv1.Job, size int64) (string, error) {
-	pods, err := k8s.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
-		TypeMeta:      metav1.TypeMeta{},
-		LabelSelector: labels.Set(job.Spec.Selector.MatchLabels).String(),
-	})
-	if err != nil {
-		return "", errors.Wrapf(err, "fail to list pods of job %s/%s", job.Namespace, job.Name)
-	}
-
-	if len(pods.Items) == 0 {
-		return "", errors.Errorf("no pods found for job %s/%s", job.Namespace, job.Name)
-	}
-
 	containerName := ""
 	for _, container := range job.Spec.Template.Spec.Containers {
 		if container.Name == "" {
@@ -316,36 +422,10 @@ func (k8s *k8sClient) GetJobLogs(ctx context.Context, job *batchv1.Job, size int
 		}
 	}
 
-	// sort the pods by creation timestamp, with the latest one first
-	sort.Slice(pods.Items, func(i, j int) bool {
-		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
-	})
-
-	req := k8s.CoreV1().Pods(pods.Items[0].Namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{
-		Container: containerName,
-		// since a line has at least 1 char, reading `size` lines will give us at least `size` amount of chars
-		TailLines: &size,
-	})
-
-	stream, err := req.Stream(ctx)
-	if err != nil {
-		return "", err
-	}
-	defer stream.Close()
+	selector := labels.Set(job.Spec.Selector.MatchLabels).String()
+	logs, err := k8s.tailPodLogs(ctx, job.Namespace, selector, containerName, size)
 
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, stream)
-
-	if err != nil {
-		return "", errors.Wrap(err, "fail to copy bytes from stream to buffer")
-	}
-
-	logs := buf.String()
-	if int64(len(logs)) > size {
-		logs = logs[int64(len(logs))-size:]
-	}
-
-	return logs, nil
+	return logs, errors.Wrapf(err, "fail to get logs of job %s/%s", job.Namespace, job.Name)
 }
 
 func (k8s *k8sClient) ListJobs(ctx context.Context, namespace string) ([]*batchv1.Job, error) {
@@ -409,6 +489,77 @@ func (k8s *k8sClient) AreServicesAlive(ctx context.Context, namespace string) (b
 
 }
 
+// GetServiceLogs returns the tail of the logs of the newest pod of a service, at most size characters of them
+func (k8s *k8sClient) GetServiceLogs(ctx context.Context, namespace, name string, size int64) (string, error) {
+	service, err := k8s.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to get service %s/%s", namespace, name)
+	}
+
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector})
+	logs, err := k8s.tailPodLogs(ctx, namespace, selector, "", size)
+
+	return logs, errors.Wrapf(err, "fail to get logs of service %s/%s", namespace, name)
+}
+
+// tailPodLogs returns at most size characters from the end of the logs of container in the newest
+// pod matching selector, the first container of the pod when container is empty. A line cut
+// by size is left out.
+func (k8s *k8sClient) tailPodLogs(ctx context.Context, namespace, selector, container string, size int64) (string, error) {
+	pods, err := k8s.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", errors.Wrap(err, "fail to list pods")
+	}
+
+	if len(pods.Items) == 0 {
+		return "", errors.New("no pods found")
+	}
+
+	// sort the pods by creation timestamp, with the latest one first
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+
+	pod := pods.Items[0]
+	if container == "" {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	req := k8s.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		// since a line has at least 1 char, reading `size` lines will give us at least `size` amount of chars
+		TailLines: &size,
+	})
+
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to stream logs of pod %s", pod.Name)
+	}
+	defer stream.Close()
+
+	buf := new(bytes.Buffer)
+	_, err = io.Copy(buf, stream)
+	if err != nil {
+		return "", errors.Wrap(err, "fail to copy bytes from stream to buffer")
+	}
+
+	return tailLogs(buf.String(), size), nil
+}
+
+// tailLogs keeps at most size characters from the end of logs, without the line that was cut
+func tailLogs(logs string, size int64) string {
+	if int64(len(logs)) <= size {
+		return logs
+	}
+
+	logs = logs[int64(len(logs))-size:]
+	if i := strings.IndexByte(logs, '\n'); i >= 0 && i < len(logs)-1 {
+		logs = logs[i+1:]
+	}
+
+	return strings.ToValidUTF8(logs, "")
+}
+
 func (k8s *k8sClient) WatchServiceLogs(ctx context.Context, namespace, name string, sinceSeconds int64) (<-chan string, <-chan error, error) {
 	logsCh := make(chan string)
 	errCh := make(chan error)
@@ -535,7 +686,7 @@ func (k8 *k8sClient) CopySecret(ctx context.Context, fromNS, toNS, name string)
 		},
 		Data:       from.Data,
 		StringData: from.StringData,
-		Type:       corev1.SecretTypeDockerConfigJson,
+		Type:       from.Type,
 	}
 
 	return secret, errors.Wrap(err, "fail to create secret")
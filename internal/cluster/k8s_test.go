@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailLogs(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name string
+		logs string
+		size int64
+		want string
+	}{
+		{name: "keeps short logs", logs: "one\ntwo\n", size: 100, want: "one\ntwo\n"},
+		{name: "leaves out the line that was cut", logs: "first line\nsecond\nthird\n", size: 15, want: "second\nthird\n"},
+		{name: "keeps a single long line cut", logs: "aaaaaaaaaa", size: 4, want: "aaaa"},
+		{name: "keeps the last line when it is the only one that fits", logs: "first\nlast line", size: 8, want: "ast line"},
+		{name: "does not cut runes", logs: "olá mundo", size: 7, want: " mundo"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tailLogs(tc.logs, tc.size))
+		})
+	}
+}
@@ -20,6 +20,14 @@ const (
 	EnvDegraded EnvStatus = "degraded"
 	EnvLimited  EnvStatus = "limited"
 	EnvStale    EnvStatus = "stale"
+	// EnvCancelled environments were superseded by a newer commit before they finished launching
+	EnvCancelled EnvStatus = "cancelled"
+)
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
 )
 
 type Environment struct {
@@ -37,7 +45,14 @@ type Environment struct {
 	DegradedReason json.RawMessage `gorm:"type:jsonb"`
 	Services       []Service       `gorm:"foreignKey:EnvironmentID"`
 	GHCommentID    int64           `gorm:"column:gh_comment_id"`
-	BuildTool      string
+	GHCheckRunID   int64           `gorm:"column:gh_check_run_id"`
+	GHDeploymentID int64           `gorm:"column:gh_deployment_id"`
+	// CommentTemplate is the mustache template of the pull request comments, empty for the built-in ones
+	CommentTemplate string
+	// Provider is where the repository of the environment is hosted, ProviderGitHub, ProviderGitLab or ProviderGitea
+	Provider string `gorm:"default:github"`
+	// ProviderCommentID is the pull request comment of environments not hosted on github
+	ProviderCommentID int64
 }
 
 func NewEnvironment(
@@ -74,6 +89,7 @@ func (db *DB) FindEnvironmentByID(id uuid.UUID) (Environment, error) {
 		Preload("Services", func(db *gorm.DB) *gorm.DB {
 			return db.Order("services.index ASC")
 		}).
+		Preload("Services.Urls").
 		First(&env, "id = ?", id)
 
 	return env, result.Error
@@ -84,6 +100,7 @@ type FindEnvironmentsOptions struct {
 }
 
 func (db *DB) FindEnvironmentsByPullRequest(
+	provider string,
 	pullRequest int,
 	owner string,
 	repo string,
@@ -92,6 +109,7 @@ func (db *DB) FindEnvironmentsByPullRequest(
 ) ([]Environment, error) {
 	envs := make([]Environment, 0)
 	where := map[string]interface{}{
+		"provider":     provider,
 		"pull_request": pullRequest,
 		"owner":        owner,
 		"repo":         repo,
@@ -116,8 +134,9 @@ func (db *DB) FindEnvironmentsByPullRequest(
 	return envs, result.Error
 }
 
-func (db *DB) DeleteEnvironmentByPullRequest(pullRequest int, owner, repo, branch string) error {
+func (db *DB) DeleteEnvironmentByPullRequest(provider string, pullRequest int, owner, repo, branch string) error {
 	result := db.Where(map[string]interface{}{
+		"provider":     provider,
 		"pull_request": pullRequest,
 		"owner":        owner,
 		"repo":         repo,
@@ -127,11 +146,12 @@ func (db *DB) DeleteEnvironmentByPullRequest(pullRequest int, owner, repo, branc
 	return result.Error
 }
 
-func (db *DB) FindEnvironmentsByOwner(owner string, options FindEnvironmentsOptions) ([]Environment, error) {
+func (db *DB) FindEnvironmentsByOwner(provider, owner string, options FindEnvironmentsOptions) ([]Environment, error) {
 	envs := make([]Environment, 0)
 
 	where := map[string]interface{}{
-		"owner": owner,
+		"provider": provider,
+		"owner":    owner,
 	}
 
 	result := db.DB
@@ -143,6 +163,7 @@ func (db *DB) FindEnvironmentsByOwner(owner string, options FindEnvironmentsOpti
 		Preload("Services", func(db *gorm.DB) *gorm.DB {
 			return db.Order("services.index ASC")
 		}).
+		Preload("Services.Urls").
 		Find(&envs)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +10,12 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	BuildToolKaniko     = "kaniko"
+	BuildToolBuildpacks = "buildpacks"
+	BuildToolBuildKit   = "buildkit"
+)
+
 type Service struct {
 	ID            string `gorm:"primaryKey"`
 	Name          string
@@ -17,14 +24,40 @@ type Service struct {
 	Image         string
 	Build         string
 	BuildStatus   string
+	BuildHash     string
+	BuildLogs     string
+	// BuildSettingsHash digests the inputs of the build that are not files, its args and secrets among them
+	BuildSettingsHash string
+	// BuildTool is the BuildTool* that builds the image, empty for services that use an image
+	BuildTool string
+	// BuildReused is set when the service runs an image it didn't build itself
+	BuildReused bool
+	// SpecHash digests the spec of the deployment or job of the service as last deployed
+	SpecHash string
+	// Job is set for services that run to completion as a job, they have no deployment
+	Job           bool
 	Index         int
 	PublicPort    string
 	InternalPorts pq.StringArray `gorm:"type:text[]"`
+	Urls          []ServiceUrl   `gorm:"foreignKey:ServiceID"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	DeletedAt     gorm.DeletedAt `gorm:"index"`
 }
 
+// ServiceUrl is one of the public routes of a service, Url is the host and Path the prefix
+// routed to Port on that host. Service.Url is kept as the first of them.
+type ServiceUrl struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ServiceID string    `gorm:"index"`
+	Url       string
+	Port      int
+	Path      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
 func (db *DB) FindServicesByEnvironment(environmentID uuid.UUID) ([]Service, error) {
 	services := make([]Service, 0)
 	result := db.Where(map[string]interface{}{
@@ -37,3 +70,54 @@ func (db *DB) FindServicesByEnvironment(environmentID uuid.UUID) ([]Service, err
 
 	return services, result.Error
 }
+
+// FindUrlsInUse returns which of urls are already served by an environment that is
+// not from the given branch of the repository.
+func (db *DB) FindUrlsInUse(urls []string, provider, owner, repo, branch string) ([]string, error) {
+	inUse := make([]string, 0)
+	if len(urls) == 0 {
+		return inUse, nil
+	}
+
+	var rows []struct {
+		ServiceUrl string
+		Url        sql.NullString
+	}
+	err := db.Table("services s").
+		Select("s.url AS service_url, u.url AS url").
+		Joins("JOIN environments e ON e.id = s.environment_id").
+		Joins("LEFT JOIN service_urls u ON u.service_id = s.id AND u.deleted_at IS NULL").
+		Where("s.deleted_at IS NULL AND e.deleted_at IS NULL").
+		Where("s.url IN ? OR u.url IN ?", urls, urls).
+		Where(
+			"NOT (e.provider = ? AND e.owner = ? AND e.repo = ? AND COALESCE(e.branch, '') = ?)",
+			provider, owner, repo, branch,
+		).
+		Scan(&rows).Error
+	if err != nil {
+		return inUse, errors.Wrap(err, "fail to query urls in use")
+	}
+
+	wanted := make(map[string]struct{})
+	for _, url := range urls {
+		wanted[url] = struct{}{}
+	}
+
+	seen := make(map[string]struct{})
+	for _, row := range rows {
+		for _, url := range []string{row.ServiceUrl, row.Url.String} {
+			if _, ok := wanted[url]; !ok {
+				continue
+			}
+
+			if _, ok := seen[url]; ok {
+				continue
+			}
+			seen[url] = struct{}{}
+
+			inUse = append(inUse, url)
+		}
+	}
+
+	return inUse, nil
+}
@@ -0,0 +1,39 @@
+package deploymodes
+
+import (
+	"context"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/reposettings"
+)
+
+type deployMode struct {
+	Mode string
+}
+
+type dbDeployModesProvider struct {
+	db *database.DB
+}
+
+func NewDBDeployModesProvider(db *database.DB) *dbDeployModesProvider {
+	return &dbDeployModesProvider{db}
+}
+
+func (dmp *dbDeployModesProvider) Get(ctx context.Context, provider, owner, repo string) (string, error) {
+	var mode deployMode
+	found, err := reposettings.Get(ctx, dmp.db.DB, "deploy_modes", reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}, &mode)
+	if err != nil || !found {
+		return Default, err
+	}
+
+	return mode.Mode, nil
+}
+
+func (dmp *dbDeployModesProvider) Upsert(ctx context.Context, provider, owner, repo, m string) error {
+	key := reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}
+	if m == "" || m == Default {
+		return reposettings.Reset(ctx, dmp.db.DB, "deploy_modes", key)
+	}
+
+	return reposettings.Set(ctx, dmp.db.DB, "deploy_modes", key, map[string]interface{}{"mode": m})
+}
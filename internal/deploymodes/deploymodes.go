@@ -0,0 +1,32 @@
+package deploymodes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Recreate terminates the environment and launches a new one on every commit
+	Recreate = "recreate"
+	// Update keeps the environment and only rebuilds and rolls the services that changed
+	Update = "update"
+
+	Default = Recreate
+)
+
+type DeployModesProvider interface {
+	// Get returns Default when the repo has no mode of its own
+	Get(ctx context.Context, provider, owner, repo string) (string, error)
+	// Upsert sets the mode of the repo, an empty mode goes back to Default
+	Upsert(ctx context.Context, provider, owner, repo, mode string) error
+}
+
+func Validate(mode string) error {
+	switch mode {
+	case Recreate, Update:
+		return nil
+	}
+
+	return errors.Errorf("unknown deploy mode %q, expected %q or %q", mode, Recreate, Update)
+}
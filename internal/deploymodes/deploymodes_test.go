@@ -0,0 +1,16 @@
+package deploymodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Validate(Recreate))
+	assert.NoError(t, Validate(Update))
+	assert.Error(t, Validate(""))
+	assert.Error(t, Validate("rolling"))
+}
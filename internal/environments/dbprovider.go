@@ -12,6 +12,8 @@ import (
 
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/payment"
 	"github.com/ergomake/ergomake/internal/permanentbranches"
 )
@@ -21,7 +23,8 @@ type environmentLimits struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
-	Owner     string         `gorm:"index"`
+	Provider  string
+	Owner     string `gorm:"index"`
 	EnvLimit  int
 }
 
@@ -31,6 +34,7 @@ type dbEnvironmentsProvider struct {
 	envLimitAmount            int
 	permanentBranchesProvider permanentbranches.PermanentBranchesProvider
 	clusterClient             cluster.Client
+	ghApp                     ghapp.GHAppClient
 }
 
 func NewDBEnvironmentsProvider(
@@ -39,18 +43,19 @@ func NewDBEnvironmentsProvider(
 	envLimitAmount int,
 	permanentBranchesProvider permanentbranches.PermanentBranchesProvider,
 	clusterClient cluster.Client,
+	ghApp ghapp.GHAppClient,
 ) *dbEnvironmentsProvider {
-	return &dbEnvironmentsProvider{db, paymentProvider, envLimitAmount, permanentBranchesProvider, clusterClient}
+	return &dbEnvironmentsProvider{db, paymentProvider, envLimitAmount, permanentBranchesProvider, clusterClient, ghApp}
 }
 
-func (ep *dbEnvironmentsProvider) IsOwnerLimited(ctx context.Context, owner string) (bool, error) {
+func (ep *dbEnvironmentsProvider) IsOwnerLimited(ctx context.Context, provider, owner string) (bool, error) {
 	limit := ep.envLimitAmount
 
 	var dbOwnerLimit environmentLimits
-	err := ep.db.First(&dbOwnerLimit, map[string]string{"owner": owner}).Error
+	err := ep.db.First(&dbOwnerLimit, map[string]string{"provider": provider, "owner": owner}).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		plan, err := ep.paymentProvider.GetOwnerPlan(ctx, owner)
+		plan, err := ep.paymentProvider.GetOwnerPlan(ctx, provider, owner)
 		if err != nil {
 			return false, errors.Wrapf(err, "fail to get owner %s plan ", owner)
 		}
@@ -68,13 +73,13 @@ func (ep *dbEnvironmentsProvider) IsOwnerLimited(ctx context.Context, owner stri
 		limit = dbOwnerLimit.EnvLimit
 	}
 
-	ownerEnvs, err := ep.db.FindEnvironmentsByOwner(owner, database.FindEnvironmentsOptions{})
+	ownerEnvs, err := ep.db.FindEnvironmentsByOwner(provider, owner, database.FindEnvironmentsOptions{})
 	if err != nil {
 		return false, errors.Wrapf(err, "fail to get current environments for owner %s", owner)
 	}
 	currentEnvCount := 0
 	for _, env := range ownerEnvs {
-		if env.Status == database.EnvLimited || env.Status == database.EnvDegraded {
+		if env.Status == database.EnvLimited || env.Status == database.EnvDegraded || env.Status == database.EnvCancelled {
 			continue
 		}
 		currentEnvCount += 1
@@ -90,7 +95,8 @@ func (ep *dbEnvironmentsProvider) GetEnvironmentFromHost(
 	var env database.Environment
 	err := ep.db.Table("environments").Select("environments.*").
 		Joins("INNER JOIN services s ON s.environment_id = environments.id").
-		Where("s.url = ?", host).
+		Joins("LEFT JOIN service_urls u ON u.service_id = s.id AND u.deleted_at IS NULL").
+		Where("s.url = ? OR u.url = ?", host, host).
 		Order("environments.created_at DESC").
 		Preload("Services").
 		First(&env).Error
@@ -130,7 +136,7 @@ func (ep *dbEnvironmentsProvider) ShouldDeploy(ctx context.Context, owner string
 
 func (ep *dbEnvironmentsProvider) ListEnvironmentsByBranch(
 	ctx context.Context,
-	owner, repo, branch string,
+	provider, owner, repo, branch string,
 ) ([]*database.Environment, error) {
 	envs := make([]*database.Environment, 0)
 
@@ -139,9 +145,10 @@ func (ep *dbEnvironmentsProvider) ListEnvironmentsByBranch(
 			return db.Order("services.index ASC")
 		}).
 		Find(&envs, map[string]string{
-			"owner":  owner,
-			"repo":   repo,
-			"branch": branch,
+			"provider": provider,
+			"owner":    owner,
+			"repo":     repo,
+			"branch":   branch,
 		}).Error
 
 	if err != nil {
@@ -158,7 +165,7 @@ func (ep *dbEnvironmentsProvider) DeleteEnvironment(ctx context.Context, id uuid
 }
 
 func (ep *dbEnvironmentsProvider) TerminateEnvironment(ctx context.Context, req TerminateEnvironmentRequest) error {
-	branchEnvs, err := ep.ListEnvironmentsByBranch(ctx, req.Owner, req.Repo, req.Branch)
+	branchEnvs, err := ep.ListEnvironmentsByBranch(ctx, req.Provider, req.Owner, req.Repo, req.Branch)
 	if err != nil {
 		return errors.Wrap(err, "fail to list environments by branch")
 	}
@@ -180,10 +187,34 @@ func (ep *dbEnvironmentsProvider) TerminateEnvironment(ctx context.Context, req
 			return errors.Wrap(err, "fail to delete namespace")
 		}
 
+		if env.Status == database.EnvPending || env.Status == database.EnvBuilding {
+			// images are built outside of the namespace, kpack builds even outlive the launch
+			err = cluster.DeleteBuilds(ctx, ep.clusterClient, env.ID.String())
+			if err != nil {
+				return errors.Wrap(err, "fail to delete builds")
+			}
+		} else {
+			// the builds are done but their secrets are still around
+			err = cluster.DeleteBuildSecrets(ctx, ep.clusterClient, env.ID.String())
+			if err != nil {
+				return errors.Wrap(err, "fail to delete build secrets")
+			}
+		}
+
 		err = ep.DeleteEnvironment(ctx, env.ID)
 		if err != nil {
 			return errors.Wrap(err, "fail to delete environment in DB")
 		}
+
+		if env.GHDeploymentID != 0 {
+			err = ep.ghApp.CreateDeploymentStatus(ctx, env.Owner, env.Repo, env.GHDeploymentID, ghapp.DeploymentStatus{
+				State:       ghapp.DeploymentInactive,
+				Description: "Environment was terminated",
+			})
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to deactivate deployment of terminated environment")
+			}
+		}
 	}
 
 	return nil
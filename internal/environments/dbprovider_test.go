@@ -2,6 +2,7 @@ package environments
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,7 @@ import (
 	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/payment"
 	clusterMocks "github.com/ergomake/ergomake/mocks/cluster"
+	ghAppMocks "github.com/ergomake/ergomake/mocks/github/ghapp"
 	paymentMocks "github.com/ergomake/ergomake/mocks/payment"
 	permanentbranchesMocks "github.com/ergomake/ergomake/mocks/permanentbranches"
 )
@@ -47,7 +49,7 @@ func TestDBEnvironmentsProvider_IsOwnerLimited(t *testing.T) {
 	}
 
 	paymentProvider := paymentMocks.NewPaymentProvider(t)
-	paymentProvider.EXPECT().GetOwnerPlan(mock.Anything, mock.Anything).Return(payment.PaymentPlanFree, nil)
+	paymentProvider.EXPECT().GetOwnerPlan(mock.Anything, mock.Anything, mock.Anything).Return(payment.PaymentPlanFree, nil)
 
 	tt := []struct {
 		name           string
@@ -103,10 +105,109 @@ func TestDBEnvironmentsProvider_IsOwnerLimited(t *testing.T) {
 				tc.limit,
 				permanentbranchesMocks.NewPermanentBranchesProvider(t),
 				clusterMocks.NewClient(t),
+				ghAppMocks.NewGHAppClient(t),
 			)
-			limited, err := ep.IsOwnerLimited(context.Background(), "owner")
+			limited, err := ep.IsOwnerLimited(context.Background(), database.ProviderGitHub, "owner")
 			require.NoError(t, err)
 			assert.Equal(t, tc.want, limited)
 		})
 	}
 }
+
+func TestDBEnvironmentsProvider_Providers(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*database.DB, map[string]*database.Environment) {
+		db := testutils.CreateRandomDB(t)
+
+		envs := map[string]*database.Environment{}
+		for _, provider := range []string{database.ProviderGitHub, database.ProviderGitLab, database.ProviderGitea} {
+			env := &database.Environment{
+				Provider:    provider,
+				Owner:       "acme",
+				Repo:        "web",
+				Branch:      sql.NullString{String: "feature", Valid: true},
+				PullRequest: sql.NullInt32{Int32: 3, Valid: true},
+				Status:      database.EnvSuccess,
+			}
+			err := db.Save(env).Error
+			require.NoError(t, err)
+
+			envs[provider] = env
+		}
+
+		return db, envs
+	}
+
+	t.Run("lookups only return environments of the provider", func(t *testing.T) {
+		t.Parallel()
+		db, envs := setup(t)
+
+		ep := NewDBEnvironmentsProvider(
+			db,
+			paymentMocks.NewPaymentProvider(t),
+			10,
+			permanentbranchesMocks.NewPermanentBranchesProvider(t),
+			clusterMocks.NewClient(t),
+			ghAppMocks.NewGHAppClient(t),
+		)
+
+		branchEnvs, err := ep.ListEnvironmentsByBranch(context.Background(), database.ProviderGitLab, "acme", "web", "feature")
+		require.NoError(t, err)
+		require.Len(t, branchEnvs, 1)
+		assert.Equal(t, envs[database.ProviderGitLab].ID, branchEnvs[0].ID)
+
+		prEnvs, err := db.FindEnvironmentsByPullRequest(
+			database.ProviderGitea, 3, "acme", "web", "feature", database.FindEnvironmentsOptions{})
+		require.NoError(t, err)
+		require.Len(t, prEnvs, 1)
+		assert.Equal(t, envs[database.ProviderGitea].ID, prEnvs[0].ID)
+
+		ownerEnvs, err := db.FindEnvironmentsByOwner(database.ProviderGitHub, "acme", database.FindEnvironmentsOptions{})
+		require.NoError(t, err)
+		require.Len(t, ownerEnvs, 1)
+		assert.Equal(t, envs[database.ProviderGitHub].ID, ownerEnvs[0].ID)
+	})
+
+	t.Run("terminate leaves environments of other providers alone", func(t *testing.T) {
+		t.Parallel()
+		db, envs := setup(t)
+
+		clusterClient := clusterMocks.NewClient(t)
+		clusterClient.EXPECT().DeleteNamespace(mock.Anything, envs[database.ProviderGitLab].ID.String()).Return(nil)
+		buildsSelector := "preview.ergomake.dev/environment=" + envs[database.ProviderGitLab].ID.String()
+		clusterClient.EXPECT().DeleteCollection(mock.Anything, mock.Anything, "preview-builds", buildsSelector).Return(nil)
+		clusterClient.EXPECT().DeleteCollection(mock.Anything, mock.Anything, "kpack", buildsSelector).Return(nil)
+
+		ep := NewDBEnvironmentsProvider(
+			db,
+			paymentMocks.NewPaymentProvider(t),
+			10,
+			permanentbranchesMocks.NewPermanentBranchesProvider(t),
+			clusterClient,
+			ghAppMocks.NewGHAppClient(t),
+		)
+
+		pr := 3
+		err := ep.TerminateEnvironment(context.Background(), TerminateEnvironmentRequest{
+			Provider: database.ProviderGitLab,
+			Owner:    "acme",
+			Repo:     "web",
+			Branch:   "feature",
+			PrNumber: &pr,
+		})
+		require.NoError(t, err)
+
+		for provider, env := range envs {
+			var count int64
+			err := db.Model(&database.Environment{}).Where("id = ?", env.ID).Count(&count).Error
+			require.NoError(t, err)
+
+			if provider == database.ProviderGitLab {
+				assert.Equal(t, int64(0), count)
+			} else {
+				assert.Equal(t, int64(1), count, provider)
+			}
+		}
+	})
+}
@@ -12,6 +12,8 @@ import (
 var ErrEnvironmentNotFound = errors.New("environment not found")
 
 type TerminateEnvironmentRequest struct {
+	// Provider is where the repository is hosted, repositories of different providers can share owner and name
+	Provider string
 	Owner    string
 	Repo     string
 	Branch   string
@@ -19,12 +21,12 @@ type TerminateEnvironmentRequest struct {
 }
 
 type EnvironmentsProvider interface {
-	IsOwnerLimited(ctx context.Context, owner string) (bool, error)
+	IsOwnerLimited(ctx context.Context, provider, owner string) (bool, error)
 	GetEnvironmentFromHost(ctx context.Context, host string) (*database.Environment, error)
 	SaveEnvironment(ctx context.Context, env *database.Environment) error
 	ListSuccessEnvironments(ctx context.Context) ([]*database.Environment, error)
 	ShouldDeploy(ctx context.Context, owner string, repo string, branch string) (bool, error)
-	ListEnvironmentsByBranch(ctx context.Context, owner, repo, branch string) ([]*database.Environment, error)
+	ListEnvironmentsByBranch(ctx context.Context, provider, owner, repo, branch string) ([]*database.Environment, error)
 	DeleteEnvironment(ctx context.Context, id uuid.UUID) error
 	TerminateEnvironment(ctx context.Context, req TerminateEnvironmentRequest) error
 }
@@ -3,6 +3,8 @@ package envvars
 import (
 	"context"
 	"database/sql"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,28 +16,43 @@ import (
 	"github.com/ergomake/ergomake/internal/database"
 )
 
+const redacted = "[redacted]"
+
 type EnvVar struct {
 	Name   string  `json:"name"`
 	Value  string  `json:"value"`
 	Branch *string `json:"branch"`
+	// BuildSecret vars are mounted as files into builds instead of being passed as build args,
+	// unless AllowBuildArg is also set, and are redacted from build logs
+	BuildSecret   bool `json:"buildSecret"`
+	AllowBuildArg bool `json:"allowBuildArg"`
+}
+
+// IsBuildArg is whether the var may be passed to builds as a build arg
+func (v EnvVar) IsBuildArg() bool {
+	return !v.BuildSecret || v.AllowBuildArg
 }
 
 type EnvVarsProvider interface {
-	Upsert(ctx context.Context, owner, repo, name, value string, branch *string) error
-	Delete(ctx context.Context, owner, repo, name string, branch *string) error
-	ListByRepo(ctx context.Context, owner, repo string) ([]EnvVar, error)
-	ListByRepoBranch(ctx context.Context, owner, repo, branch string) ([]EnvVar, error)
+	Upsert(ctx context.Context, provider, owner, repo string, v EnvVar) error
+	Delete(ctx context.Context, provider, owner, repo, name string, branch *string) error
+	ListByRepo(ctx context.Context, provider, owner, repo string) ([]EnvVar, error)
+	ListByRepoBranch(ctx context.Context, provider, owner, repo, branch string) ([]EnvVar, error)
 }
 
 type DBEnvVar struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Provider  string
 	Owner     string
 	Repo      string
 	Name      string
 	Value     string
 	Branch    sql.NullString
+
+	BuildSecret   bool
+	AllowBuildArg bool
 }
 
 type dbEnvVarsProvider struct {
@@ -47,44 +64,49 @@ func NewDBEnvVarProvider(db *database.DB, secret string) *dbEnvVarsProvider {
 	return &dbEnvVarsProvider{db, secret}
 }
 
-func (evp *dbEnvVarsProvider) Upsert(ctx context.Context, owner, repo, name, value string, branch *string) error {
-	encryptedValue, err := crypto.Encrypt(evp.secret, value)
+func (evp *dbEnvVarsProvider) Upsert(ctx context.Context, provider, owner, repo string, v EnvVar) error {
+	encryptedValue, err := crypto.Encrypt(evp.secret, v.Value)
 	if err != nil {
 		return errors.Wrap(err, "failed to encrypt value")
 	}
 
 	var dbVar DBEnvVar
 	err = evp.db.Table("env_vars").Where(map[string]interface{}{
-		"owner":  owner,
-		"repo":   repo,
-		"name":   name,
-		"branch": branch,
+		"provider": provider,
+		"owner":    owner,
+		"repo":     repo,
+		"name":     v.Name,
+		"branch":   v.Branch,
 	}).Assign(map[string]interface{}{
-		"value": encryptedValue,
+		"value":           encryptedValue,
+		"build_secret":    v.BuildSecret,
+		"allow_build_arg": v.AllowBuildArg,
 	}).FirstOrCreate(&dbVar).Error
 
 	return errors.Wrap(err, "failed to upsert env var")
 }
 
-func (evp *dbEnvVarsProvider) Delete(ctx context.Context, owner, repo, name string, branch *string) error {
+func (evp *dbEnvVarsProvider) Delete(ctx context.Context, provider, owner, repo, name string, branch *string) error {
 	err := evp.db.Table("env_vars").
 		Where(map[string]interface{}{
-			"owner":  owner,
-			"repo":   repo,
-			"name":   name,
-			"branch": branch,
+			"provider": provider,
+			"owner":    owner,
+			"repo":     repo,
+			"name":     name,
+			"branch":   branch,
 		}).
 		Delete(&DBEnvVar{}).Error
 
 	return err
 }
 
-func (evp *dbEnvVarsProvider) ListByRepo(ctx context.Context, owner, repo string) ([]EnvVar, error) {
+func (evp *dbEnvVarsProvider) ListByRepo(ctx context.Context, provider, owner, repo string) ([]EnvVar, error) {
 	vars := make([]EnvVar, 0)
 	var dbVars []DBEnvVar
 	err := evp.db.Table("env_vars").Where(map[string]string{
-		"owner": owner,
-		"repo":  repo,
+		"provider": provider,
+		"owner":    owner,
+		"repo":     repo,
 	}).Find(&dbVars).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -102,14 +124,20 @@ func (evp *dbEnvVarsProvider) ListByRepo(ctx context.Context, owner, repo string
 			branch = pointer.String(v.Branch.String)
 		}
 
-		vars = append(vars, EnvVar{v.Name, value, branch})
+		vars = append(vars, EnvVar{
+			Name:          v.Name,
+			Value:         value,
+			Branch:        branch,
+			BuildSecret:   v.BuildSecret,
+			AllowBuildArg: v.AllowBuildArg,
+		})
 	}
 
 	return vars, err
 }
 
-func (evp *dbEnvVarsProvider) ListByRepoBranch(ctx context.Context, owner, repo, branch string) ([]EnvVar, error) {
-	allRepoVars, err := evp.ListByRepo(ctx, owner, repo)
+func (evp *dbEnvVarsProvider) ListByRepoBranch(ctx context.Context, provider, owner, repo, branch string) ([]EnvVar, error) {
+	allRepoVars, err := evp.ListByRepo(ctx, provider, owner, repo)
 	if err != nil {
 		return nil, errors.Wrapf(err, "fail to list env vars for repo %s/%s", owner, repo)
 	}
@@ -136,3 +164,34 @@ func (evp *dbEnvVarsProvider) ListByRepoBranch(ctx context.Context, owner, repo,
 
 	return result, nil
 }
+
+// BuildSecretValues lists the values of the vars that are build secrets
+func BuildSecretValues(vars []EnvVar) []string {
+	values := []string{}
+	for _, v := range vars {
+		if v.BuildSecret && v.Value != "" {
+			values = append(values, v.Value)
+		}
+	}
+
+	return values
+}
+
+// Redact hides every one of values from text, longer values first so that
+// a secret that contains another one is not partially revealed
+func Redact(text string, values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	for _, v := range sorted {
+		if v == "" {
+			continue
+		}
+
+		text = strings.ReplaceAll(text, v, redacted)
+	}
+
+	return text
+}
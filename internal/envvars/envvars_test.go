@@ -0,0 +1,34 @@
+package envvars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvVar_IsBuildArg(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, EnvVar{Name: "A"}.IsBuildArg())
+	assert.False(t, EnvVar{Name: "A", BuildSecret: true}.IsBuildArg())
+	assert.True(t, EnvVar{Name: "A", BuildSecret: true, AllowBuildArg: true}.IsBuildArg())
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	vars := []EnvVar{
+		{Name: "PLAIN", Value: "visible"},
+		{Name: "TOKEN", Value: "abc", BuildSecret: true},
+		{Name: "LONG_TOKEN", Value: "abcdef", BuildSecret: true},
+		{Name: "EMPTY", Value: "", BuildSecret: true},
+	}
+
+	values := BuildSecretValues(vars)
+	assert.ElementsMatch(t, []string{"abc", "abcdef"}, values)
+	assert.Equal(
+		t,
+		"visible [redacted] [redacted]",
+		Redact("visible abcdef abc", values),
+	)
+}
@@ -2,12 +2,61 @@ package ergopack
 
 type Ergopack struct {
 	Apps map[string]ErgopackApp `yaml:"apps"`
+	Jobs map[string]ErgopackJob `yaml:"jobs"`
 }
 
+// In an ErgopackApp, Paths maps a public port to a path prefix on the app host,
+// public ports without a path get a host of their own. Apps at Path are built with
+// buildpacks unless Builder is buildkit, which builds Dockerfile up to Target.
 type ErgopackApp struct {
-	Path          string            `yaml:"path"`
-	Image         string            `yaml:"image"`
-	PublicPort    string            `yaml:"publicPort"`
-	InternalPorts []string          `yaml:"internalPorts"`
-	Env           map[string]string `yaml:"env"`
+	Path          string                        `yaml:"path"`
+	Builder       string                        `yaml:"builder"`
+	Dockerfile    string                        `yaml:"dockerfile"`
+	Target        string                        `yaml:"target"`
+	Image         string                        `yaml:"image"`
+	PublicPort    string                        `yaml:"publicPort"`
+	PublicPorts   []string                      `yaml:"publicPorts"`
+	Paths         map[string]string             `yaml:"paths"`
+	InternalPorts []string                      `yaml:"internalPorts"`
+	Env           map[string]string             `yaml:"env"`
+	DependsOn     map[string]ErgopackDependency `yaml:"dependsOn"`
+	Healthcheck   *ErgopackHealthcheck          `yaml:"healthcheck"`
+	Resources     *ErgopackResources            `yaml:"resources"`
+}
+
+// ErgopackDependency mirrors the long syntax of compose `depends_on`, condition
+// can be service_started, service_healthy or service_completed_successfully.
+type ErgopackDependency struct {
+	Condition string `yaml:"condition"`
+}
+
+// ErgopackHealthcheck mirrors compose `healthcheck`, durations are go durations like 10s.
+type ErgopackHealthcheck struct {
+	Test        []string `yaml:"test"`
+	Interval    string   `yaml:"interval"`
+	Timeout     string   `yaml:"timeout"`
+	Retries     int32    `yaml:"retries"`
+	StartPeriod string   `yaml:"startPeriod"`
+	Disable     bool     `yaml:"disable"`
+}
+
+// ErgopackResources values are k8s quantities like 512Mi or 500m
+type ErgopackResources struct {
+	Limits   ErgopackResourceList `yaml:"limits"`
+	Requests ErgopackResourceList `yaml:"requests"`
+}
+
+type ErgopackResourceList struct {
+	CPU    string `yaml:"cpu"`
+	Memory string `yaml:"memory"`
+}
+
+// ErgopackJob runs to completion before the environment is considered ready,
+// it uses the image of App unless Image is set.
+type ErgopackJob struct {
+	App       string                        `yaml:"app"`
+	Image     string                        `yaml:"image"`
+	Command   []string                      `yaml:"command"`
+	Env       map[string]string             `yaml:"env"`
+	DependsOn map[string]ErgopackDependency `yaml:"dependsOn"`
 }
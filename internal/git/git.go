@@ -8,7 +8,35 @@ type RemoteGitClient interface {
 	GetCloneToken(ctx context.Context, owner string, repo string) (string, error)
 	CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error
 	GetCloneUrl() string
+	// GetBaseURL is the scheme and host repositories are cloned from, without credentials
+	GetBaseURL() string
 	GetCloneParams() []string
 	GetDefaultBranch(ctx context.Context, owner string, repo string, branchOwner string) (string, error)
 	DoesBranchExist(ctx context.Context, owner string, repo string, branch string, branchOwner string) (bool, error)
 }
+
+const (
+	CommitStatePending  = "pending"
+	CommitStateRunning  = "running"
+	CommitStateSuccess  = "success"
+	CommitStateFailure  = "failure"
+	CommitStateCanceled = "canceled"
+)
+
+// CommitStatus is what a git host shows about the environment of a commit,
+// each client maps State into the states its host knows
+type CommitStatus struct {
+	State       string
+	Description string
+	TargetURL   string
+}
+
+// ReportingClient is a RemoteGitClient that can report environments back to
+// the pull requests, or merge requests, and commits they were launched for
+type ReportingClient interface {
+	RemoteGitClient
+	// UpsertComment edits the comment commentID of the pull request number, a new comment
+	// is created when commentID is 0 or the comment was deleted
+	UpsertComment(ctx context.Context, owner, repo string, number int, commentID int64, body string) (int64, error)
+	SetCommitStatus(ctx context.Context, owner, repo, sha string, status CommitStatus) error
+}
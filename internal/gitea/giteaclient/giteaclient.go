@@ -0,0 +1,230 @@
+package giteaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/git"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+const StatusContext string = "Ergomake"
+
+var RepoNotFoundError = errors.New("repository not found")
+
+// statusStates maps commit states into the ones of gitea, which has no running nor canceled states
+var statusStates = map[string]string{
+	git.CommitStatePending:  "pending",
+	git.CommitStateRunning:  "pending",
+	git.CommitStateSuccess:  "success",
+	git.CommitStateFailure:  "failure",
+	git.CommitStateCanceled: "warning",
+}
+
+type GiteaClient interface {
+	git.ReportingClient
+}
+
+// apiError is a response of the gitea API that is not a success
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("gitea responded with %d: %s", e.StatusCode, e.Message)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+type giteaClient struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+}
+
+// NewGiteaClient talks to the gitea instance at baseURL with the token of the account
+// that reports environments, the account must be able to read every repository launched
+func NewGiteaClient(baseURL string, token string) (GiteaClient, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to parse gitea url %s", baseURL)
+	}
+
+	return &giteaClient{u, http.DefaultClient, token}, nil
+}
+
+func (gt *giteaClient) GetCloneToken(ctx context.Context, owner string, repo string) (string, error) {
+	return gt.token, nil
+}
+
+func (gt *giteaClient) CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error {
+	cloneURL := fmt.Sprintf("%s://%s/%s/%s.git", gt.baseURL.Scheme, gt.baseURL.Host, owner, repo)
+	if !isPublic {
+		cloneURL = fmt.Sprintf("%s://ergomake:%s@%s/%s/%s.git", gt.baseURL.Scheme, gt.token, gt.baseURL.Host, owner, repo)
+	}
+
+	cmd := exec.Command("git", "clone", "--branch", branch, cloneURL, dir)
+
+	return errors.Wrap(cmd.Run(), "fail to run clone command")
+}
+
+func (gt *giteaClient) GetCloneUrl() string {
+	return fmt.Sprintf("%s://ergomake:$(GIT_TOKEN)@%s/$(OWNER)/$(REPO)", gt.baseURL.Scheme, gt.baseURL.Host)
+}
+
+func (gt *giteaClient) GetBaseURL() string {
+	return fmt.Sprintf("%s://%s", gt.baseURL.Scheme, gt.baseURL.Host)
+}
+
+func (gt *giteaClient) GetCloneParams() []string {
+	return []string{
+		"--depth", "1",
+		"--branch", "$(BRANCH)",
+	}
+}
+
+func (gt *giteaClient) GetDefaultBranch(ctx context.Context, owner string, repo string, branchOwner string) (string, error) {
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err := gt.do(ctx, http.MethodGet, repoPath(branchOwner, repo), nil, &repository)
+	if err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return "", RepoNotFoundError
+		}
+
+		return "", errors.Wrap(err, "failed to get repository")
+	}
+
+	return repository.DefaultBranch, nil
+}
+
+func (gt *giteaClient) DoesBranchExist(ctx context.Context, owner string, repo string, branch string, branchOwner string) (bool, error) {
+	path := fmt.Sprintf("%s/branches/%s", repoPath(branchOwner, repo), url.PathEscape(branch))
+	err := gt.do(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "failed to check branch existence")
+	}
+
+	return true, nil
+}
+
+func (gt *giteaClient) UpsertComment(
+	ctx context.Context,
+	owner, repo string,
+	prNumber int,
+	commentID int64,
+	body string,
+) (int64, error) {
+	req := map[string]string{"body": body}
+	var comment struct {
+		ID int64 `json:"id"`
+	}
+
+	if commentID != 0 {
+		path := fmt.Sprintf("%s/issues/comments/%d", repoPath(owner, repo), commentID)
+		err := gt.do(ctx, http.MethodPatch, path, req, &comment)
+		if err == nil {
+			return comment.ID, nil
+		}
+
+		if !hasStatus(err, http.StatusNotFound) {
+			return 0, errors.Wrapf(err, "failed to update comment %d", commentID)
+		}
+	}
+
+	path := fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, repo), prNumber)
+	err := gt.do(ctx, http.MethodPost, path, req, &comment)
+	if err != nil {
+		if hasStatus(err, http.StatusForbidden) {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Int("prNumber", prNumber).
+				Msg("fail to create pull request comment, missing permissions")
+			return 0, nil
+		}
+
+		return 0, errors.Wrap(err, "failed to create comment")
+	}
+
+	return comment.ID, nil
+}
+
+func (gt *giteaClient) SetCommitStatus(ctx context.Context, owner, repo, sha string, status git.CommitStatus) error {
+	req := map[string]string{
+		"state":       statusStates[status.State],
+		"context":     StatusContext,
+		"description": status.Description,
+		"target_url":  status.TargetURL,
+	}
+	err := gt.do(ctx, http.MethodPost, fmt.Sprintf("%s/statuses/%s", repoPath(owner, repo), sha), req, nil)
+	if err != nil {
+		if hasStatus(err, http.StatusForbidden) {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("state", status.State).
+				Msg("fail to set commit status, missing permissions")
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to set %s status of commit %s", status.State, sha)
+	}
+
+	return nil
+}
+
+// do calls the gitea API at path, body is sent and the response read into out as JSON when they are not nil
+func (gt *giteaClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "fail to marshal request body")
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/api/v1/%s", gt.baseURL, path), reqBody)
+	if err != nil {
+		return errors.Wrap(err, "fail to create request")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", gt.token))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := gt.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "fail to call %s %s", method, path)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return &apiError{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(res.Body).Decode(out)
+	return errors.Wrapf(err, "fail to decode response of %s %s", method, path)
+}
+
+func repoPath(owner, repo string) string {
+	return fmt.Sprintf("repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+}
@@ -0,0 +1,179 @@
+package giteaclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/internal/git"
+)
+
+// fakeGitea answers the gitea API calls in routes by method and escaped path, anything else is a 404
+type fakeGitea struct {
+	t      *testing.T
+	routes map[string]func(w http.ResponseWriter, body map[string]string)
+	calls  []string
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(f.t, "token secret", r.Header.Get("Authorization"))
+
+	key := r.Method + " " + r.URL.EscapedPath()
+	f.calls = append(f.calls, key)
+
+	route, ok := f.routes[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"not found"}`))
+		return
+	}
+
+	body := map[string]string{}
+	if r.Body != nil && r.ContentLength > 0 {
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+	}
+
+	route(w, body)
+}
+
+func newTestClient(t *testing.T, fake *fakeGitea) GiteaClient {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewGiteaClient(server.URL, "secret")
+	require.NoError(t, err)
+
+	return client
+}
+
+func respond(status int, payload string) func(w http.ResponseWriter, body map[string]string) {
+	return func(w http.ResponseWriter, body map[string]string) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(payload))
+	}
+}
+
+func TestGiteaClient_GetDefaultBranch(t *testing.T) {
+	fake := &fakeGitea{t: t, routes: map[string]func(http.ResponseWriter, map[string]string){
+		"GET /api/v1/repos/owner/repo": respond(http.StatusOK, `{"default_branch":"develop"}`),
+	}}
+	client := newTestClient(t, fake)
+
+	branch, err := client.GetDefaultBranch(context.Background(), "owner", "repo", "owner")
+	require.NoError(t, err)
+	assert.Equal(t, "develop", branch)
+
+	_, err = client.GetDefaultBranch(context.Background(), "owner", "repo", "other")
+	assert.ErrorIs(t, err, RepoNotFoundError)
+}
+
+func TestGiteaClient_DoesBranchExist(t *testing.T) {
+	fake := &fakeGitea{t: t, routes: map[string]func(http.ResponseWriter, map[string]string){
+		"GET /api/v1/repos/owner/repo/branches/feature%2Fnew": respond(http.StatusOK, `{}`),
+	}}
+	client := newTestClient(t, fake)
+
+	exists, err := client.DoesBranchExist(context.Background(), "owner", "repo", "feature/new", "owner")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.DoesBranchExist(context.Background(), "owner", "repo", "gone", "owner")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestGiteaClient_UpsertComment(t *testing.T) {
+	commentsPath := "/api/v1/repos/owner/repo/issues/3/comments"
+	commentPath := "/api/v1/repos/owner/repo/issues/comments/10"
+
+	testCases := []struct {
+		name      string
+		commentID int64
+		routes    map[string]func(http.ResponseWriter, map[string]string)
+		expected  int64
+		calls     []string
+	}{
+		{
+			name:      "creates a comment",
+			commentID: 0,
+			routes: map[string]func(http.ResponseWriter, map[string]string){
+				"POST " + commentsPath: respond(http.StatusCreated, `{"id":10}`),
+			},
+			expected: 10,
+			calls:    []string{"POST " + commentsPath},
+		},
+		{
+			name:      "edits the existing comment",
+			commentID: 10,
+			routes: map[string]func(http.ResponseWriter, map[string]string){
+				"PATCH " + commentPath: respond(http.StatusOK, `{"id":10}`),
+			},
+			expected: 10,
+			calls:    []string{"PATCH " + commentPath},
+		},
+		{
+			name:      "creates a comment when the existing one was deleted",
+			commentID: 10,
+			routes: map[string]func(http.ResponseWriter, map[string]string){
+				"POST " + commentsPath: respond(http.StatusCreated, `{"id":11}`),
+			},
+			expected: 11,
+			calls:    []string{"PATCH " + commentPath, "POST " + commentsPath},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeGitea{t: t, routes: tc.routes}
+			client := newTestClient(t, fake)
+
+			commentID, err := client.UpsertComment(context.Background(), "owner", "repo", 3, tc.commentID, "hello")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, commentID)
+			assert.Equal(t, tc.calls, fake.calls)
+		})
+	}
+}
+
+func TestGiteaClient_SetCommitStatus(t *testing.T) {
+	testCases := []struct {
+		state    string
+		expected string
+	}{
+		{state: git.CommitStateRunning, expected: "pending"},
+		{state: git.CommitStateSuccess, expected: "success"},
+		{state: git.CommitStateFailure, expected: "failure"},
+		{state: git.CommitStateCanceled, expected: "warning"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.state, func(t *testing.T) {
+			var got map[string]string
+			fake := &fakeGitea{t: t, routes: map[string]func(http.ResponseWriter, map[string]string){
+				"POST /api/v1/repos/owner/repo/statuses/abc123": func(w http.ResponseWriter, body map[string]string) {
+					got = body
+					w.WriteHeader(http.StatusCreated)
+				},
+			}}
+			client := newTestClient(t, fake)
+
+			err := client.SetCommitStatus(context.Background(), "owner", "repo", "abc123", git.CommitStatus{
+				State:       tc.state,
+				Description: "description",
+				TargetURL:   "https://app.ergomake.dev/environments/id",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{
+				"state":       tc.expected,
+				"context":     "Ergomake",
+				"description": "description",
+				"target_url":  "https://app.ergomake.dev/environments/id",
+			}, got)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package ghapp
+
+import "github.com/google/go-github/v52/github"
+
+// RedeployAction identifies the check run button that deploys the commit again
+const RedeployAction = "redeploy"
+
+var checkRunActions = []*github.CheckRunAction{
+	{
+		Label:       "Redeploy",
+		Description: "Deploy this commit again",
+		Identifier:  RedeployAction,
+	},
+}
+
+// CheckRun is what a check run shows about an environment, it stays in progress until it gets a Conclusion
+type CheckRun struct {
+	ExternalID string
+	DetailsURL string
+	Conclusion string
+	Title      string
+	Summary    string
+	Text       string
+}
+
+func (run CheckRun) status() string {
+	if run.Conclusion == "" {
+		return "in_progress"
+	}
+
+	return "completed"
+}
+
+func (run CheckRun) output() *github.CheckRunOutput {
+	if run.Title == "" {
+		return nil
+	}
+
+	return &github.CheckRunOutput{
+		Title:   github.String(run.Title),
+		Summary: github.String(run.Summary),
+		Text:    optionalString(run.Text),
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return github.String(s)
+}
@@ -0,0 +1,91 @@
+package ghapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+const (
+	DeploymentInProgress = "in_progress"
+	DeploymentSuccess    = "success"
+	DeploymentFailure    = "failure"
+	DeploymentInactive   = "inactive"
+)
+
+// DeploymentStatus is what the PR timeline shows about a deployment, EnvironmentURL backs its "View deployment" button
+type DeploymentStatus struct {
+	State          string
+	Description    string
+	EnvironmentURL string
+	LogURL         string
+}
+
+func (gh *ghAppClient) CreateDeployment(ctx context.Context, owner, repo, sha, environment string) (int64, error) {
+	installationClient, err := gh.getOwnerInstallationClient(ctx, owner)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create installation client")
+	}
+
+	req := github.DeploymentRequest{
+		Ref:         github.String(sha),
+		Environment: github.String(environment),
+		Description: github.String("Ergomake preview environment"),
+		// the deployment happens regardless of how the commit compares to the default branch and its checks
+		AutoMerge:             github.Bool(false),
+		RequiredContexts:      &[]string{},
+		TransientEnvironment:  github.Bool(true),
+		ProductionEnvironment: github.Bool(false),
+	}
+
+	deployment, res, err := installationClient.Repositories.CreateDeployment(ctx, owner, repo, &req)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusForbidden {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("environment", environment).
+				Msg("fail to create deployment, missing permissions")
+			return 0, nil
+		}
+
+		return 0, errors.Wrap(err, "failed to create deployment")
+	}
+
+	return deployment.GetID(), nil
+}
+
+func (gh *ghAppClient) CreateDeploymentStatus(
+	ctx context.Context,
+	owner, repo string,
+	deploymentID int64,
+	status DeploymentStatus,
+) error {
+	installationClient, err := gh.getOwnerInstallationClient(ctx, owner)
+	if err != nil {
+		return errors.Wrap(err, "failed to create installation client")
+	}
+
+	req := github.DeploymentStatusRequest{
+		State:          github.String(status.State),
+		Description:    optionalString(status.Description),
+		EnvironmentURL: optionalString(status.EnvironmentURL),
+		LogURL:         optionalString(status.LogURL),
+		// deployments of the same environment are made inactive explicitly
+		AutoInactive: github.Bool(false),
+	}
+
+	_, res, err := installationClient.Repositories.CreateDeploymentStatus(ctx, owner, repo, deploymentID, &req)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusForbidden {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("state", status.State).
+				Msg("fail to create deployment status, missing permissions")
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to create status of deployment %d", deploymentID)
+	}
+
+	return nil
+}
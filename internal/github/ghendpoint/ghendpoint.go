@@ -0,0 +1,115 @@
+package ghendpoint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v52/github"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const defaultAPIURL = "https://api.github.com/"
+
+// Endpoint is where a github instance serves its website, api and uploads,
+// either github.com or a GitHub Enterprise Server
+type Endpoint struct {
+	url       *url.URL
+	apiURL    *url.URL
+	uploadURL *url.URL
+}
+
+// GitHubDotCom is the endpoint of github.com
+var GitHubDotCom = mustNew("https://github.com", "", "")
+
+func mustNew(webURL, apiURL, uploadURL string) *Endpoint {
+	e, err := New(webURL, apiURL, uploadURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// New creates the endpoint of the github served at webURL. When apiURL is empty
+// github.com uses api.github.com and enterprise servers use /api/v3/ of webURL,
+// when uploadURL is empty it is the same as apiURL, or /api/uploads/ of webURL
+func New(webURL, apiURL, uploadURL string) (*Endpoint, error) {
+	u, err := url.Parse(strings.TrimSuffix(webURL, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to parse github url %s", webURL)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.Errorf("github url %s must have a scheme and a host", webURL)
+	}
+
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+		if u.Host != "github.com" {
+			apiURL = fmt.Sprintf("%s/api/v3/", u)
+			if uploadURL == "" {
+				uploadURL = fmt.Sprintf("%s/api/uploads/", u)
+			}
+		}
+	}
+
+	if uploadURL == "" {
+		uploadURL = apiURL
+	}
+
+	api, err := parseAPIURL(apiURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to parse github api url %s", apiURL)
+	}
+
+	upload, err := parseAPIURL(uploadURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to parse github upload url %s", uploadURL)
+	}
+
+	return &Endpoint{u, api, upload}, nil
+}
+
+// parseAPIURL parses u with the trailing slash go-github requires from its urls
+func parseAPIURL(u string) (*url.URL, error) {
+	if !strings.HasSuffix(u, "/") {
+		u += "/"
+	}
+
+	return url.Parse(u)
+}
+
+// NewClient creates a github client that talks to the api of the endpoint
+func (e *Endpoint) NewClient(httpClient *http.Client) *github.Client {
+	client := github.NewClient(httpClient)
+	client.BaseURL = e.apiURL
+	client.UploadURL = e.uploadURL
+
+	return client
+}
+
+// URL is the scheme and host of the website, which is also where repositories are cloned from
+func (e *Endpoint) URL() string {
+	return fmt.Sprintf("%s://%s", e.url.Scheme, e.url.Host)
+}
+
+// URLWithCredentials is URL with a username and password, they are not escaped
+// so that placeholders like $(GIT_TOKEN) are kept as they are
+func (e *Endpoint) URLWithCredentials(username, password string) string {
+	return fmt.Sprintf("%s://%s:%s@%s", e.url.Scheme, username, password, e.url.Host)
+}
+
+// APIURL is the api url without the trailing slash, as ghinstallation expects it
+func (e *Endpoint) APIURL() string {
+	return strings.TrimSuffix(e.apiURL.String(), "/")
+}
+
+func (e *Endpoint) OAuth2() oauth2.Endpoint {
+	return oauth2.Endpoint{
+		AuthURL:  fmt.Sprintf("%s/login/oauth/authorize", e.URL()),
+		TokenURL: fmt.Sprintf("%s/login/oauth/access_token", e.URL()),
+	}
+}
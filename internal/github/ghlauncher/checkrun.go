@@ -0,0 +1,147 @@
+package ghlauncher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/transformer"
+)
+
+const (
+	// checkRunLogsLength is how much of the logs of each failed build goes into the check run
+	checkRunLogsLength = 4000
+	// checkRunTextLength keeps the check run text under the 65535 characters github accepts
+	checkRunTextLength = 60000
+)
+
+const (
+	checkRunSuccess   = "success"
+	checkRunFailure   = "failure"
+	checkRunCancelled = "cancelled"
+)
+
+// ReportCheckRun shows the services of env and their builds in the check run of the commit, the check run
+// is created the first time. It stays in progress while conclusion is empty. The summary starts with message.
+func ReportCheckRun(
+	ctx context.Context,
+	ghApp ghapp.GHAppClient,
+	db *database.DB,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+	conclusion string,
+	title string,
+	message string,
+) error {
+	services := env.Services
+	checkRunID := env.GHCheckRunID
+	// builds save their services as they go, env may be older than them
+	dbEnv, err := db.FindEnvironmentByID(env.ID)
+	if err == nil {
+		services = dbEnv.Services
+		checkRunID = dbEnv.GHCheckRunID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.Wrapf(err, "fail to find environment %s", env.ID)
+	}
+
+	run := ghapp.CheckRun{
+		ExternalID: env.ID.String(),
+		DetailsURL: envFrontendLink,
+		Conclusion: conclusion,
+		Title:      title,
+		Summary:    makeCheckRunSummary(message, services),
+		Text:       makeCheckRunText(services),
+	}
+
+	if checkRunID != 0 {
+		err := ghApp.UpdateCheckRun(ctx, env.Owner, env.Repo, checkRunID, run)
+		return errors.Wrap(err, "fail to update check run")
+	}
+
+	checkRunID, err = ghApp.CreateCheckRun(ctx, env.Owner, env.Repo, sha, run)
+	if err != nil {
+		return errors.Wrap(err, "fail to create check run")
+	}
+
+	if checkRunID == 0 {
+		return nil
+	}
+
+	env.GHCheckRunID = checkRunID
+	err = db.Model(&database.Environment{}).Where("id = ?", env.ID).Update("gh_check_run_id", checkRunID).Error
+
+	return errors.Wrap(err, "fail to save check run id")
+}
+
+func makeCheckRunSummary(message string, services []database.Service) string {
+	parts := []string{}
+	if message != "" {
+		parts = append(parts, message)
+	}
+
+	if len(services) == 0 {
+		return strings.Join(append(parts, "No services were deployed."), "\n\n")
+	}
+
+	rows := []string{"| Service | Build | Image | URL |", "| - | - | - | - |"}
+	for _, service := range services {
+		image := "-"
+		if service.Image != "" {
+			image = fmt.Sprintf("`%s`", service.Image)
+		}
+
+		rows = append(rows, fmt.Sprintf(
+			"| %s | %s | %s | %s |",
+			service.Name,
+			getBuildStatus(service.BuildStatus),
+			image,
+			launcher.ServiceUrls(transformer.EnvironmentServiceFromDB(service)),
+		))
+	}
+
+	return strings.Join(append(parts, strings.Join(rows, "\n")), "\n\n")
+}
+
+func getBuildStatus(buildStatus string) string {
+	switch buildStatus {
+	case "building":
+		return "⏳ building"
+	case "build-success":
+		return "✅ built"
+	case "build-failed":
+		return "❌ failed"
+	}
+
+	return "-"
+}
+
+// makeCheckRunText has the tail of the logs of the builds that failed
+func makeCheckRunText(services []database.Service) string {
+	sections := []string{}
+	for _, service := range services {
+		if service.BuildStatus != "build-failed" || service.BuildLogs == "" {
+			continue
+		}
+
+		logs := service.BuildLogs
+		if len(logs) > checkRunLogsLength {
+			logs = "..." + strings.ToValidUTF8(logs[len(logs)-checkRunLogsLength:], "")
+		}
+
+		sections = append(sections, fmt.Sprintf("### %s build logs\n\n```\n%s\n```", service.Name, logs))
+	}
+
+	text := strings.Join(sections, "\n\n")
+	if len(text) > checkRunTextLength {
+		text = strings.ToValidUTF8(text[:checkRunTextLength], "") + "\n```\n\nLogs of the other builds are too long to show here."
+	}
+
+	return text
+}
@@ -0,0 +1,50 @@
+package ghlauncher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ergomake/ergomake/internal/database"
+)
+
+func TestMakeCheckRunSummary(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "No services were deployed.", makeCheckRunSummary("", nil))
+	assert.Equal(t, "limited\n\nNo services were deployed.", makeCheckRunSummary("limited", nil))
+
+	services := []database.Service{
+		{Name: "api", BuildStatus: "build-success", Image: "registry/api:image-abc", Urls: []database.ServiceUrl{
+			{Url: "api.preview.dev", Path: "/"},
+			{Url: "api.preview.dev", Path: "/admin/"},
+		}},
+		{Name: "web", BuildStatus: "building", Url: "web.preview.dev"},
+		{Name: "db", BuildStatus: "image", Image: "postgres"},
+	}
+	assert.Equal(t, `| Service | Build | Image | URL |
+| - | - | - | - |
+| api | ✅ built | `+"`registry/api:image-abc`"+` | https://api.preview.dev<br>https://api.preview.dev/admin |
+| web | ⏳ building | - | https://web.preview.dev |
+| db | - | `+"`postgres`"+` | [not exposed - internal service] |`, makeCheckRunSummary("", services))
+}
+
+func TestMakeCheckRunText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", makeCheckRunText([]database.Service{{Name: "api", BuildStatus: "build-success", BuildLogs: "ok"}}))
+
+	text := makeCheckRunText([]database.Service{
+		{Name: "api", BuildStatus: "build-failed", BuildLogs: "error: missing go.mod"},
+		{Name: "web", BuildStatus: "build-failed", BuildLogs: strings.Repeat("a", checkRunLogsLength+10)},
+	})
+	assert.Contains(t, text, "### api build logs\n\n```\nerror: missing go.mod\n```")
+	assert.Contains(t, text, "### web build logs\n\n```\n..."+strings.Repeat("a", checkRunLogsLength)+"\n```")
+
+	many := make([]database.Service, 30)
+	for i := range many {
+		many[i] = database.Service{Name: "svc", BuildStatus: "build-failed", BuildLogs: strings.Repeat("b", checkRunLogsLength)}
+	}
+	assert.Less(t, len(makeCheckRunText(many)), 65535)
+}
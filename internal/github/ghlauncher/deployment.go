@@ -0,0 +1,66 @@
+package ghlauncher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+)
+
+// StartDeployment creates the github deployment of the commit being launched into env and marks it in progress
+func StartDeployment(
+	ctx context.Context,
+	ghApp ghapp.GHAppClient,
+	db *database.DB,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+) error {
+	deploymentID, err := ghApp.CreateDeployment(ctx, env.Owner, env.Repo, sha, deploymentEnvironment(env))
+	if err != nil {
+		return errors.Wrap(err, "fail to create deployment")
+	}
+
+	if deploymentID == 0 {
+		return nil
+	}
+
+	env.GHDeploymentID = deploymentID
+	err = db.Model(&database.Environment{}).Where("id = ?", env.ID).Update("gh_deployment_id", deploymentID).Error
+	if err != nil {
+		return errors.Wrap(err, "fail to save deployment id")
+	}
+
+	return ReportDeployment(ctx, ghApp, env, ghapp.DeploymentStatus{
+		State:       ghapp.DeploymentInProgress,
+		Description: "Building environment",
+		LogURL:      envFrontendLink,
+	})
+}
+
+// ReportDeployment adds status to the github deployment of env, if it has one
+func ReportDeployment(
+	ctx context.Context,
+	ghApp ghapp.GHAppClient,
+	env *database.Environment,
+	status ghapp.DeploymentStatus,
+) error {
+	if env.GHDeploymentID == 0 {
+		return nil
+	}
+
+	err := ghApp.CreateDeploymentStatus(ctx, env.Owner, env.Repo, env.GHDeploymentID, status)
+	return errors.Wrapf(err, "fail to report %s deployment status", status.State)
+}
+
+// deploymentEnvironment names the github environment of env, each pull request and branch gets its own
+func deploymentEnvironment(env *database.Environment) string {
+	if env.PullRequest.Valid {
+		return fmt.Sprintf("ergomake/pr-%d", env.PullRequest.Int32)
+	}
+
+	return fmt.Sprintf("ergomake/%s", env.Branch.String)
+}
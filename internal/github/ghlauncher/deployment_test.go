@@ -0,0 +1,22 @@
+package ghlauncher
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ergomake/ergomake/internal/database"
+)
+
+func TestDeploymentEnvironment(t *testing.T) {
+	t.Parallel()
+
+	prNumber := 42
+	assert.Equal(t, "ergomake/pr-42", deploymentEnvironment(
+		database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "feature", &prNumber, "author", database.EnvPending),
+	))
+	assert.Equal(t, "ergomake/main", deploymentEnvironment(
+		database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "main", nil, "author", database.EnvPending),
+	))
+}
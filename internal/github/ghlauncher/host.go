@@ -0,0 +1,111 @@
+package ghlauncher
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/git"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+var checkRunConclusions = map[string]string{
+	git.CommitStateSuccess:  checkRunSuccess,
+	git.CommitStateFailure:  checkRunFailure,
+	git.CommitStateCanceled: checkRunCancelled,
+}
+
+type githubHost struct {
+	ghapp.GHAppClient
+}
+
+// NewGitHubHost reports launches through check runs, deployments and comments of the github app
+func NewGitHubHost(ghApp ghapp.GHAppClient) launcher.GitHost {
+	return &githubHost{ghApp}
+}
+
+func (h *githubHost) Provider() string {
+	return database.ProviderGitHub
+}
+
+func (h *githubHost) ReportComment(ctx context.Context, db *database.DB, env *database.Environment, body string) error {
+	if !env.PullRequest.Valid {
+		return nil
+	}
+
+	comment, err := h.UpsertComment(ctx, env.Owner, env.Repo, int(env.PullRequest.Int32), env.GHCommentID, body)
+	if err != nil {
+		return errors.Wrap(err, "fail to upsert comment")
+	}
+
+	env.GHCommentID = comment.GetID()
+	err = db.Model(&database.Environment{}).Where("id = ?", env.ID).Update("gh_comment_id", env.GHCommentID).Error
+
+	return errors.Wrap(err, "fail to save comment id")
+}
+
+// ReportStatus reports status on the check run of the commit and on its deployment, deployments
+// that fail to be reported are only logged since the app is not always allowed to create them
+func (h *githubHost) ReportStatus(
+	ctx context.Context,
+	db *database.DB,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+	status launcher.LaunchStatus,
+) error {
+	err := ReportCheckRun(ctx, h.GHAppClient, db, envFrontendLink, env, sha, checkRunConclusions[status.State], status.Title, status.Message)
+	if err != nil {
+		return err
+	}
+
+	switch status.State {
+	case git.CommitStatePending:
+		err = StartDeployment(ctx, h.GHAppClient, db, envFrontendLink, env, sha)
+	case git.CommitStateSuccess:
+		err = ReportDeployment(ctx, h.GHAppClient, env, ghapp.DeploymentStatus{
+			State:          ghapp.DeploymentSuccess,
+			Description:    status.Title,
+			EnvironmentURL: status.EnvironmentURL,
+			LogURL:         envFrontendLink,
+		})
+	case git.CommitStateFailure:
+		err = ReportDeployment(ctx, h.GHAppClient, env, ghapp.DeploymentStatus{
+			State:       ghapp.DeploymentFailure,
+			Description: status.Title,
+			LogURL:      envFrontendLink,
+		})
+	case git.CommitStateCanceled:
+		err = h.Deactivate(ctx, env, status.Title)
+	}
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Str("state", status.State).Msg("fail to report deployment")
+	}
+
+	return nil
+}
+
+func (h *githubHost) Deactivate(ctx context.Context, env *database.Environment, description string) error {
+	return ReportDeployment(ctx, h.GHAppClient, env, ghapp.DeploymentStatus{
+		State:       ghapp.DeploymentInactive,
+		Description: description,
+	})
+}
+
+func (h *githubHost) ListPullRequestChanges(ctx context.Context, owner, repo string, prNumber int, sha string) (string, []string, error) {
+	pr, err := h.GetPullRequest(ctx, owner, repo, prNumber)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "fail to get pull request")
+	}
+
+	baseBranch := pr.GetBase().GetRef()
+	changedFiles, err := h.ListChangedFiles(ctx, owner, repo, baseBranch, sha)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "fail to list changed files")
+	}
+
+	return baseBranch, changedFiles, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 
+	"github.com/ergomake/ergomake/internal/github/ghendpoint"
 	"github.com/ergomake/ergomake/internal/oauthutils"
 )
 
@@ -25,9 +26,21 @@ type ghOAuthClient struct {
 // 100MB
 var cache = lrucache.New(100*1024*1024, 0)
 
+var endpoint = ghendpoint.GitHubDotCom
+
+// UseEndpoint makes the clients of users talk to the github at e instead of github.com
+func UseEndpoint(e *ghendpoint.Endpoint) {
+	endpoint = e
+}
+
+// NewClient creates a github client of the configured endpoint that sends requests with httpClient
+func NewClient(httpClient *http.Client) *github.Client {
+	return endpoint.NewClient(httpClient)
+}
+
 func FromToken(token *oauth2.Token) GHOAuthClient {
 	tc := oauthutils.CachedHTTPClient(token, cache)
-	client := github.NewClient(tc)
+	client := NewClient(tc)
 
 	return &ghOAuthClient{client}
 }
@@ -0,0 +1,300 @@
+package glclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/git"
+	"github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+const StatusName string = "Ergomake"
+
+// maintainerAccessLevel is the gitlab access level of maintainers, owners have a higher one
+const maintainerAccessLevel = 40
+
+var RepoNotFoundError = errors.New("repository not found")
+
+// statusStates maps commit states into the ones of gitlab
+var statusStates = map[string]string{
+	git.CommitStatePending:  "pending",
+	git.CommitStateRunning:  "running",
+	git.CommitStateSuccess:  "success",
+	git.CommitStateFailure:  "failed",
+	git.CommitStateCanceled: "canceled",
+}
+
+type GLClient interface {
+	git.ReportingClient
+	// CanMaintainProject tells whether token has at least maintainer access to the project
+	CanMaintainProject(ctx context.Context, owner, repo, token string) (bool, error)
+}
+
+// apiError is a response of the gitlab API that is not a success
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("gitlab responded with %d: %s", e.StatusCode, e.Message)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+type glClient struct {
+	baseURL            *url.URL
+	httpClient         *http.Client
+	glProjectsProvider glprojects.GLProjectsProvider
+}
+
+// NewGitLabClient talks to the gitlab instance at baseURL, e.g. https://gitlab.com,
+// every project is accessed with the token it was registered with
+func NewGitLabClient(baseURL string, glProjectsProvider glprojects.GLProjectsProvider) (GLClient, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to parse gitlab url %s", baseURL)
+	}
+
+	return &glClient{u, http.DefaultClient, glProjectsProvider}, nil
+}
+
+func (gl *glClient) GetCloneToken(ctx context.Context, owner string, repo string) (string, error) {
+	project, err := gl.glProjectsProvider.Get(ctx, owner, repo)
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to get gitlab project %s/%s", owner, repo)
+	}
+
+	return project.Token, nil
+}
+
+func (gl *glClient) CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error {
+	cloneURL := fmt.Sprintf("%s://%s/%s/%s.git", gl.baseURL.Scheme, gl.baseURL.Host, owner, repo)
+	if !isPublic {
+		token, err := gl.GetCloneToken(ctx, owner, repo)
+		if err != nil {
+			return errors.Wrap(err, "fail to get clone token")
+		}
+
+		cloneURL = fmt.Sprintf("%s://oauth2:%s@%s/%s/%s.git", gl.baseURL.Scheme, token, gl.baseURL.Host, owner, repo)
+	}
+
+	cmd := exec.Command("git", "clone", "--branch", branch, cloneURL, dir)
+
+	return errors.Wrap(cmd.Run(), "fail to run clone command")
+}
+
+func (gl *glClient) GetCloneUrl() string {
+	return fmt.Sprintf("%s://oauth2:$(GIT_TOKEN)@%s/$(OWNER)/$(REPO)", gl.baseURL.Scheme, gl.baseURL.Host)
+}
+
+func (gl *glClient) GetBaseURL() string {
+	return fmt.Sprintf("%s://%s", gl.baseURL.Scheme, gl.baseURL.Host)
+}
+
+func (gl *glClient) GetCloneParams() []string {
+	return []string{
+		"--depth", "1",
+		"--branch", "$(BRANCH)",
+	}
+}
+
+func (gl *glClient) GetDefaultBranch(ctx context.Context, owner string, repo string, branchOwner string) (string, error) {
+	token, err := gl.GetCloneToken(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	err = gl.do(ctx, token, http.MethodGet, projectPath(branchOwner, repo), nil, &project)
+	if err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return "", RepoNotFoundError
+		}
+
+		return "", errors.Wrap(err, "failed to get project")
+	}
+
+	return project.DefaultBranch, nil
+}
+
+func (gl *glClient) DoesBranchExist(ctx context.Context, owner string, repo string, branch string, branchOwner string) (bool, error) {
+	token, err := gl.GetCloneToken(ctx, owner, repo)
+	if err != nil {
+		return false, err
+	}
+
+	path := fmt.Sprintf("%s/repository/branches/%s", projectPath(branchOwner, repo), url.PathEscape(branch))
+	err = gl.do(ctx, token, http.MethodGet, path, nil, nil)
+	if err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "failed to check branch existence")
+	}
+
+	return true, nil
+}
+
+func (gl *glClient) CanMaintainProject(ctx context.Context, owner, repo, token string) (bool, error) {
+	// public projects can be read with any token, only the access level tells the token belongs to the project
+	var project struct {
+		Permissions struct {
+			ProjectAccess *struct {
+				AccessLevel int `json:"access_level"`
+			} `json:"project_access"`
+			GroupAccess *struct {
+				AccessLevel int `json:"access_level"`
+			} `json:"group_access"`
+		} `json:"permissions"`
+	}
+	err := gl.do(ctx, token, http.MethodGet, projectPath(owner, repo), nil, &project)
+	if err != nil {
+		if hasStatus(err, http.StatusUnauthorized) || hasStatus(err, http.StatusForbidden) ||
+			hasStatus(err, http.StatusNotFound) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "failed to get project")
+	}
+
+	accessLevel := 0
+	if project.Permissions.ProjectAccess != nil {
+		accessLevel = project.Permissions.ProjectAccess.AccessLevel
+	}
+	if project.Permissions.GroupAccess != nil && project.Permissions.GroupAccess.AccessLevel > accessLevel {
+		accessLevel = project.Permissions.GroupAccess.AccessLevel
+	}
+
+	return accessLevel >= maintainerAccessLevel, nil
+}
+
+// UpsertComment edits the note noteID of the merge request mrIID, a new note is created
+// when noteID is 0 or the note was deleted
+func (gl *glClient) UpsertComment(
+	ctx context.Context,
+	owner, repo string,
+	mrIID int,
+	noteID int64,
+	body string,
+) (int64, error) {
+	token, err := gl.GetCloneToken(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+
+	notesPath := fmt.Sprintf("%s/merge_requests/%d/notes", projectPath(owner, repo), mrIID)
+	req := map[string]string{"body": body}
+	var note struct {
+		ID int64 `json:"id"`
+	}
+
+	if noteID != 0 {
+		err = gl.do(ctx, token, http.MethodPut, fmt.Sprintf("%s/%d", notesPath, noteID), req, &note)
+		if err == nil {
+			return note.ID, nil
+		}
+
+		if !hasStatus(err, http.StatusNotFound) {
+			return 0, errors.Wrapf(err, "failed to update note %d", noteID)
+		}
+	}
+
+	err = gl.do(ctx, token, http.MethodPost, notesPath, req, &note)
+	if err != nil {
+		if hasStatus(err, http.StatusForbidden) {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Int("mrIID", mrIID).
+				Msg("fail to create merge request note, missing permissions")
+			return 0, nil
+		}
+
+		return 0, errors.Wrap(err, "failed to create note")
+	}
+
+	return note.ID, nil
+}
+
+func (gl *glClient) SetCommitStatus(ctx context.Context, owner, repo, sha string, status git.CommitStatus) error {
+	token, err := gl.GetCloneToken(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	req := map[string]string{
+		"state":       statusStates[status.State],
+		"name":        StatusName,
+		"description": status.Description,
+		"target_url":  status.TargetURL,
+	}
+	err = gl.do(ctx, token, http.MethodPost, fmt.Sprintf("%s/statuses/%s", projectPath(owner, repo), sha), req, nil)
+	if err != nil {
+		if hasStatus(err, http.StatusForbidden) {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("state", status.State).
+				Msg("fail to set commit status, missing permissions")
+			return nil
+		}
+
+		return errors.Wrapf(err, "failed to set %s status of commit %s", status.State, sha)
+	}
+
+	return nil
+}
+
+// do calls the gitlab API at path, body is sent and the response read into out as JSON when they are not nil
+func (gl *glClient) do(ctx context.Context, token, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "fail to marshal request body")
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/api/v4/%s", gl.baseURL, path), reqBody)
+	if err != nil {
+		return errors.Wrap(err, "fail to create request")
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := gl.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "fail to call %s %s", method, path)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return &apiError{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(res.Body).Decode(out)
+	return errors.Wrapf(err, "fail to decode response of %s %s", method, path)
+}
+
+func projectPath(owner, repo string) string {
+	return fmt.Sprintf("projects/%s", url.PathEscape(fmt.Sprintf("%s/%s", owner, repo)))
+}
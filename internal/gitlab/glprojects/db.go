@@ -0,0 +1,84 @@
+package glprojects
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/crypto"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/reposettings"
+)
+
+type gitlabProject struct {
+	Token        string
+	WebhookToken string
+}
+
+type dbGLProjectsProvider struct {
+	db     *database.DB
+	secret string
+}
+
+func NewDBGLProjectsProvider(db *database.DB, secret string) *dbGLProjectsProvider {
+	return &dbGLProjectsProvider{db, secret}
+}
+
+func (gpp *dbGLProjectsProvider) Get(ctx context.Context, owner, repo string) (*Project, error) {
+	var project gitlabProject
+	found, err := reposettings.Get(ctx, gpp.db.DB, "gitlab_projects", reposettings.Repo{Provider: database.ProviderGitLab, Owner: owner, Repo: repo}, &project)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to query gitlab project %s/%s", owner, repo)
+	}
+
+	if !found {
+		return nil, ErrProjectNotFound
+	}
+
+	token, err := crypto.Decrypt(gpp.secret, project.Token)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to decrypt token")
+	}
+
+	webhookToken, err := crypto.Decrypt(gpp.secret, project.WebhookToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to decrypt webhook token")
+	}
+
+	return &Project{Owner: owner, Repo: repo, Token: token, WebhookToken: webhookToken}, nil
+}
+
+func (gpp *dbGLProjectsProvider) Register(ctx context.Context, owner, repo, token string) (*Project, error) {
+	webhookToken, err := makeWebhookToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to make webhook token")
+	}
+
+	encryptedToken, err := crypto.Encrypt(gpp.secret, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to encrypt token")
+	}
+
+	encryptedWebhookToken, err := crypto.Encrypt(gpp.secret, webhookToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to encrypt webhook token")
+	}
+
+	err = reposettings.Set(ctx, gpp.db.DB, "gitlab_projects", reposettings.Repo{Provider: database.ProviderGitLab, Owner: owner, Repo: repo}, map[string]interface{}{
+		"token":         encryptedToken,
+		"webhook_token": encryptedWebhookToken,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to save gitlab project %s/%s", owner, repo)
+	}
+
+	return &Project{Owner: owner, Repo: repo, Token: token, WebhookToken: webhookToken}, nil
+}
+
+func makeWebhookToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	return hex.EncodeToString(b), err
+}
@@ -0,0 +1,25 @@
+package glprojects
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var ErrProjectNotFound = errors.New("gitlab project not found")
+
+// Project is a gitlab project registered with one of its project access tokens
+type Project struct {
+	Owner string
+	Repo  string
+	Token string
+	// WebhookToken is the secret token gitlab sends along the webhooks of the project
+	WebhookToken string
+}
+
+type GLProjectsProvider interface {
+	// Get returns ErrProjectNotFound when the project was never registered
+	Get(ctx context.Context, owner, repo string) (*Project, error)
+	// Register saves the token of the project and returns it with a new webhook token
+	Register(ctx context.Context, owner, repo, token string) (*Project, error)
+}
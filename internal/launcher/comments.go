@@ -0,0 +1,254 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/transformer"
+)
+
+const (
+	commentSuccess = transformer.CommentSuccess
+	commentFailure = transformer.CommentFailure
+	commentLimited = transformer.CommentLimited
+)
+
+// commentInfo is what a comment template gets to know about a launch besides the services of its environment
+type commentInfo struct {
+	status       string
+	sha          string
+	frontendLink string
+	reason       string
+}
+
+// makeComment renders the comment template of the repository of env, the built-in comment is used
+// when there is no template or it does not render
+func makeComment(
+	ctx context.Context,
+	env *database.Environment,
+	compose *transformer.Environment,
+	info commentInfo,
+	builtIn string,
+) string {
+	if env.CommentTemplate == "" {
+		return builtIn
+	}
+
+	if compose == nil {
+		compose = transformer.EnvironmentFromDB(env)
+	}
+
+	prNumber := 0
+	if env.PullRequest.Valid {
+		prNumber = int(env.PullRequest.Int32)
+	}
+
+	templateContext := transformer.CommentTemplateContext(compose, transformer.CommentVars{
+		Status:         info.status,
+		Owner:          env.Owner,
+		Repo:           env.Repo,
+		Branch:         env.Branch.String,
+		PullRequest:    prNumber,
+		Author:         env.Author,
+		SHA:            info.sha,
+		FrontendLink:   info.frontendLink,
+		MainURL:        EnvironmentURL(compose),
+		Reason:         info.reason,
+		DefaultComment: builtIn,
+	})
+
+	comment, err := transformer.RenderCommentTemplate(env.CommentTemplate, templateContext)
+	if err != nil {
+		logger.Ctx(ctx).Warn().AnErr("err", err).Str("status", info.status).
+			Msg("fail to render comment template, using the built-in comment")
+		return builtIn
+	}
+
+	return comment
+}
+
+func createSuccessComment(provider string, env *transformer.Environment, frontendEnvLink string) string {
+	return fmt.Sprintf(`Hi 👋
+
+Here's a preview environment 🚀
+
+%s
+%s
+# Environment Summary 📑
+
+| Container | Source | URL |
+| - | - | - |
+%s
+
+Here are your environment's [logs](%s).
+
+For questions or comments, [join Discord](https://discord.gg/daGzchUGDt).%s`,
+		getServiceUrl(env.FirstService()),
+		getUpdateSummary(env.Update),
+		getServiceTable(env),
+		frontendEnvLink,
+		getDisableFooter(provider),
+	)
+}
+
+// getUpdateSummary tells which services an in place update touched, it is empty for new environments
+func getUpdateSummary(update *transformer.EnvironmentUpdate) string {
+	if update == nil {
+		return ""
+	}
+
+	lines := []string{}
+	if len(update.Updated) > 0 {
+		lines = append(lines, fmt.Sprintf("🔄 Updated services: %s", formatServiceNames(update.Updated)))
+	} else {
+		lines = append(lines, "🔄 No services had to be updated.")
+	}
+
+	if len(update.Removed) > 0 {
+		lines = append(lines, fmt.Sprintf("🗑️ Removed services: %s", formatServiceNames(update.Removed)))
+	}
+
+	return "\n" + strings.Join(lines, "\n\n") + "\n"
+}
+
+func formatServiceNames(names []string) string {
+	formatted := make([]string, len(names))
+	for i, name := range names {
+		formatted[i] = fmt.Sprintf("`%s`", name)
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func getFailureReason(frontendLink string, validationError *transformer.ProjectValidationError) string {
+	if validationError != nil {
+		return validationError.Message
+	}
+
+	return fmt.Sprintf(
+		`You can see your environment build logs [here](%s). Please double-check your `+"`docker-compose.yml`"+` file is valid.`,
+		frontendLink,
+	)
+}
+
+type failedJobLogs struct {
+	Name string
+	Logs string
+}
+
+func getJobsFailureReason(frontendLink string, jobs []failedJobLogs) string {
+	details := make([]string, len(jobs))
+	for i, job := range jobs {
+		details[i] = fmt.Sprintf("<details>\n<summary>%s</summary>\n\n```\n%s\n```\n</details>", job.Name, job.Logs)
+	}
+
+	return fmt.Sprintf(
+		`Some setup jobs failed, you can see their full logs [here](%s).
+
+%s`,
+		frontendLink,
+		strings.Join(details, "\n\n"),
+	)
+}
+
+func makeFailureComment(provider, reason string) string {
+	return fmt.Sprintf(`Hi 👋
+
+We couldn't create a preview environment for this %s 😥
+
+%s
+
+If you need help, email us at contact@getergomake.com or join [Discord](https://discord.gg/daGzchUGDt).%s`,
+		pullRequestName(provider),
+		reason,
+		getDisableFooter(provider),
+	)
+}
+
+func createLimitedComment(provider string) string {
+	return fmt.Sprintf(`Hi there 👋
+
+You’ve just reached your simultaneous environments limit.
+
+Please talk to us at contact@ergomake.dev to bump your limits.
+
+Alternatively, you can close a %s with an existing environment, and reopen this one to get a preview.
+
+Thanks for using Ergomake!%s`, pullRequestName(provider), getDisableFooter(provider))
+}
+
+// pullRequestName is what the git host of provider calls pull requests
+func pullRequestName(provider string) string {
+	if provider == database.ProviderGitLab {
+		return "merge request"
+	}
+
+	return "pull request"
+}
+
+// getDisableFooter points to the github app, repositories of the other providers have no app to uninstall
+func getDisableFooter(provider string) string {
+	if provider != database.ProviderGitHub {
+		return ""
+	}
+
+	return "\n\n[Click here](https://github.com/apps/ergomake) to disable Ergomake."
+}
+
+func getServiceTable(env *transformer.Environment) string {
+	rows := make([]string, len(env.Services))
+	for serviceName, serviceConfig := range env.Services {
+		// jobs run to completion, there is nothing to visit
+		if serviceConfig.Job {
+			continue
+		}
+
+		rows[serviceConfig.Index] = fmt.Sprintf("| %s | %s | %s |", serviceName, getSource(serviceConfig), ServiceUrls(serviceConfig))
+	}
+
+	table := []string{}
+	for _, row := range rows {
+		if row != "" {
+			table = append(table, row)
+		}
+	}
+
+	return strings.Join(table, "\n")
+}
+
+func getServiceUrl(svc transformer.EnvironmentService) string {
+	if svc.Url == "" {
+		return "[not exposed - internal service]"
+	}
+
+	return fmt.Sprintf("https://%s", svc.Url)
+}
+
+// ServiceUrls lists where svc is visited, one url per line of a markdown table
+func ServiceUrls(svc transformer.EnvironmentService) string {
+	if len(svc.Urls) <= 1 {
+		return getServiceUrl(svc)
+	}
+
+	urls := make([]string, len(svc.Urls))
+	for i, url := range svc.Urls {
+		urls[i] = fmt.Sprintf("https://%s%s", url.Url, strings.TrimSuffix(url.Path, "/"))
+	}
+
+	return strings.Join(urls, "<br>")
+}
+
+func getSource(svc transformer.EnvironmentService) string {
+	if svc.Build != "" && svc.BuildReused {
+		return "Dockerfile (reused)"
+	}
+
+	if svc.Build != "" {
+		return "Dockerfile (rebuilt)"
+	}
+
+	return svc.Image
+}
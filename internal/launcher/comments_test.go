@@ -0,0 +1,94 @@
+package launcher
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/transformer"
+)
+
+func TestMakeComment(t *testing.T) {
+	t.Parallel()
+
+	compose := transformer.NewEnvironment(
+		map[string]transformer.EnvironmentService{"web": {Url: "web.preview.dev"}},
+		"",
+	)
+	info := commentInfo{status: commentSuccess, sha: "abc123", frontendLink: "https://app.ergomake.dev/env"}
+
+	tt := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "uses the built-in comment without a template",
+			template: "",
+			want:     "built-in",
+		},
+		{
+			name: "renders the template",
+			template: "{{#success}}{{branch}}@{{sha}} is at {{mainUrl}}, web is {{services.web.url}}{{/success}}" +
+				"{{#failure}}failed{{/failure}}\n- [ ] QA",
+			want: "feature@abc123 is at https://web.preview.dev, web is web.preview.dev\n- [ ] QA",
+		},
+		{
+			name:     "can include the built-in comment",
+			template: "{{{defaultComment}}}\n\nExtra links",
+			want:     "built-in\n\nExtra links",
+		},
+		{
+			name:     "falls back to the built-in comment when rendering fails",
+			template: "{{unknown}}",
+			want:     "built-in",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := &database.Environment{
+				Owner:           "owner",
+				Repo:            "repo",
+				Branch:          sql.NullString{String: "feature", Valid: true},
+				CommentTemplate: tc.template,
+			}
+
+			assert.Equal(t, tc.want, makeComment(context.Background(), env, compose, info, "built-in"))
+		})
+	}
+}
+
+func TestGetServiceTable(t *testing.T) {
+	t.Parallel()
+
+	env := &transformer.Environment{Services: map[string]transformer.EnvironmentService{
+		"api":     {Index: 0, Build: "/api", Url: "api.preview.dev"},
+		"web":     {Index: 1, Build: "/web", BuildReused: true, Url: "web.preview.dev"},
+		"migrate": {Index: 2, Image: "api", Job: true},
+		"db":      {Index: 3, Image: "postgres:13"},
+	}}
+
+	want := "| api | Dockerfile (rebuilt) | https://api.preview.dev |\n" +
+		"| web | Dockerfile (reused) | https://web.preview.dev |\n" +
+		"| db | postgres:13 | [not exposed - internal service] |"
+	assert.Equal(t, want, getServiceTable(env))
+}
+
+func TestMakeFailureComment(t *testing.T) {
+	t.Parallel()
+
+	github := makeFailureComment(database.ProviderGitHub, "reason")
+	assert.Contains(t, github, "for this pull request")
+	assert.Contains(t, github, "https://github.com/apps/ergomake")
+
+	gitlab := makeFailureComment(database.ProviderGitLab, "reason")
+	assert.Contains(t, gitlab, "for this merge request")
+	assert.NotContains(t, gitlab, "https://github.com/apps/ergomake")
+}
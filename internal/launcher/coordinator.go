@@ -0,0 +1,100 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+// cancelTimeout bounds how long to wait for a cancelled launch to wind down
+const cancelTimeout = time.Minute
+
+type launch struct {
+	key    string
+	cancel context.CancelFunc
+	done   chan struct{}
+	lc     *launchCoordinator
+}
+
+// launchCoordinator keeps one launch in progress per branch or pull request. It is per process,
+// it only knows the launches of this process, the others still notice they were terminated before deploying.
+type launchCoordinator struct {
+	mu       sync.Mutex
+	launches map[string]*launch
+}
+
+func newLaunchCoordinator() *launchCoordinator {
+	return &launchCoordinator{launches: make(map[string]*launch)}
+}
+
+// LaunchKey identifies the branch or pull request a launch is for
+func LaunchKey(provider, owner, repo, branch string, prNumber *int) string {
+	if prNumber != nil {
+		return fmt.Sprintf("%s:%s/%s#%d", provider, owner, repo, *prNumber)
+	}
+
+	return fmt.Sprintf("%s:%s/%s@%s", provider, owner, repo, branch)
+}
+
+// begin cancels the launch in progress for key and registers a new one. The returned context is
+// cancelled when a newer launch begins and end must be called once the launch is over.
+func (lc *launchCoordinator) begin(ctx context.Context, key string) (context.Context, *launch) {
+	launchCtx, cancel := context.WithCancel(ctx)
+	l := &launch{key: key, cancel: cancel, done: make(chan struct{}), lc: lc}
+
+	lc.mu.Lock()
+	previous := lc.launches[key]
+	lc.launches[key] = l
+	lc.mu.Unlock()
+
+	lc.wait(ctx, key, previous)
+
+	return launchCtx, l
+}
+
+func (l *launch) end() {
+	l.lc.mu.Lock()
+	if l.lc.launches[l.key] == l {
+		delete(l.lc.launches, l.key)
+	}
+	l.lc.mu.Unlock()
+
+	l.cancel()
+	close(l.done)
+}
+
+// superseded tells whether a newer launch began for the same key. Waiting for a cancelled launch
+// gives up after cancelTimeout so the newer one may already own the environment, updates reuse it.
+func (l *launch) superseded() bool {
+	l.lc.mu.Lock()
+	defer l.lc.mu.Unlock()
+
+	return l.lc.launches[l.key] != l
+}
+
+// cancel stops the launch in progress for key, if any, and waits for it to wind down.
+// The launch stays registered until it ends so it knows nothing newer replaced it.
+func (lc *launchCoordinator) cancel(ctx context.Context, key string) {
+	lc.mu.Lock()
+	l := lc.launches[key]
+	lc.mu.Unlock()
+
+	lc.wait(ctx, key, l)
+}
+
+func (lc *launchCoordinator) wait(ctx context.Context, key string, l *launch) {
+	if l == nil {
+		return
+	}
+
+	l.cancel()
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+	case <-time.After(cancelTimeout):
+		logger.Ctx(ctx).Warn().Str("launch", key).Msg("cancelled launch is taking too long to stop")
+	}
+}
@@ -0,0 +1,95 @@
+package launcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaunchKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "github:owner/repo#7", LaunchKey("github", "owner", "repo", "branch", intPtr(7)))
+	assert.Equal(t, "github:owner/repo@branch", LaunchKey("github", "owner", "repo", "branch", nil))
+	assert.Equal(t, "gitlab:owner/repo#7", LaunchKey("gitlab", "owner", "repo", "branch", intPtr(7)))
+}
+
+func TestLaunchCoordinator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a newer launch cancels the previous one", func(t *testing.T) {
+		lc := newLaunchCoordinator()
+
+		first, firstLaunch := lc.begin(context.Background(), "key")
+		superseded := make(chan bool, 1)
+		go func() {
+			<-first.Done()
+			superseded <- firstLaunch.superseded()
+			firstLaunch.end()
+		}()
+
+		second, secondLaunch := lc.begin(context.Background(), "key")
+		assert.Error(t, first.Err())
+		assert.True(t, <-superseded)
+		assert.NoError(t, second.Err())
+		assert.False(t, secondLaunch.superseded())
+
+		secondLaunch.end()
+		assert.Empty(t, lc.launches)
+	})
+
+	t.Run("launches of other keys are left alone", func(t *testing.T) {
+		lc := newLaunchCoordinator()
+
+		first, firstLaunch := lc.begin(context.Background(), "a")
+		defer firstLaunch.end()
+
+		_, secondLaunch := lc.begin(context.Background(), "b")
+		defer secondLaunch.end()
+
+		assert.NoError(t, first.Err())
+	})
+
+	t.Run("cancel waits for the launch to end", func(t *testing.T) {
+		lc := newLaunchCoordinator()
+
+		ctx, l := lc.begin(context.Background(), "key")
+		superseded := true
+		go func() {
+			<-ctx.Done()
+			// nothing newer began, the launch cleans up after itself
+			superseded = l.superseded()
+			l.end()
+		}()
+
+		lc.cancel(context.Background(), "key")
+		assert.False(t, superseded)
+		assert.Empty(t, lc.launches)
+
+		// nothing to cancel
+		lc.cancel(context.Background(), "key")
+	})
+
+	t.Run("a launch that outlived a newer one is still superseded", func(t *testing.T) {
+		lc := newLaunchCoordinator()
+
+		_, slow := lc.begin(context.Background(), "key")
+		_, newer := lc.begin(ctxDone(), "key")
+		newer.end()
+
+		assert.True(t, slow.superseded())
+		slow.end()
+	})
+}
+
+// ctxDone is a cancelled context, begin does not wait for the previous launch with it
+func ctxDone() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func intPtr(i int) *int {
+	return &i
+}
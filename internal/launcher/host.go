@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/git"
+	"github.com/ergomake/ergomake/internal/transformer"
+)
+
+// GitHost is where the repositories of a provider are hosted, every launch runs the same
+// way and only clones from, and reports back to, the host of its provider
+type GitHost interface {
+	git.RemoteGitClient
+	// Provider is the database provider of the environments launched from the host
+	Provider() string
+	// ReportComment puts body into the comment of the pull request of env, the comment is created
+	// the first time and saved into env. Environments of branches have no comment.
+	ReportComment(ctx context.Context, db *database.DB, env *database.Environment, body string) error
+	// ReportStatus shows status on the commit sha env is launched from
+	ReportStatus(
+		ctx context.Context,
+		db *database.DB,
+		envFrontendLink string,
+		env *database.Environment,
+		sha string,
+		status LaunchStatus,
+	) error
+	// Deactivate tells that env no longer runs the commit it was reported for, e.g. when it is updated
+	Deactivate(ctx context.Context, env *database.Environment, description string) error
+}
+
+// LaunchStatus is what a launch shows on its commit
+type LaunchStatus struct {
+	// State is one of the git.CommitState*, pending is the first status of a launch
+	State   string
+	Title   string
+	Message string
+	// EnvironmentURL is where a ready environment is visited
+	EnvironmentURL string
+}
+
+// ChangesLister is implemented by the hosts that can list the changes of a pull request,
+// builds of the others are never skipped
+type ChangesLister interface {
+	// ListPullRequestChanges returns the base branch of the pull request and the files sha changes since it diverged from it
+	ListPullRequestChanges(ctx context.Context, owner, repo string, prNumber int, sha string) (string, []string, error)
+}
+
+// EnvironmentFrontendLink is the page of env on the frontend, environments of github repositories live under their owner
+func EnvironmentFrontendLink(frontendURL string, env *database.Environment) string {
+	if env.Provider == database.ProviderGitHub {
+		return fmt.Sprintf("%s/gh/%s/repos/%s/envs/%s", frontendURL, env.Owner, env.Repo, env.ID)
+	}
+
+	return fmt.Sprintf("%s/environments/%s", frontendURL, env.ID)
+}
+
+// EnvironmentURL is the url of the main service of env, the first one, where the "View deployment"
+// button leads. Environments without a public main service have none.
+func EnvironmentURL(env *transformer.Environment) string {
+	url := env.FirstService().Url
+	if url == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("https://%s", url)
+}
+
+type reportingHost struct {
+	git.ReportingClient
+	provider string
+}
+
+// NewReportingHost reports launches of provider through the commit statuses and comments of client
+func NewReportingHost(provider string, client git.ReportingClient) GitHost {
+	return &reportingHost{client, provider}
+}
+
+func (h *reportingHost) Provider() string {
+	return h.provider
+}
+
+func (h *reportingHost) ReportComment(ctx context.Context, db *database.DB, env *database.Environment, body string) error {
+	if !env.PullRequest.Valid {
+		return nil
+	}
+
+	commentID, err := h.UpsertComment(ctx, env.Owner, env.Repo, int(env.PullRequest.Int32), env.ProviderCommentID, body)
+	if err != nil {
+		return errors.Wrap(err, "fail to upsert comment")
+	}
+
+	env.ProviderCommentID = commentID
+	err = db.Model(&database.Environment{}).Where("id = ?", env.ID).Update("provider_comment_id", commentID).Error
+
+	return errors.Wrap(err, "fail to save comment id")
+}
+
+func (h *reportingHost) ReportStatus(
+	ctx context.Context,
+	db *database.DB,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+	status LaunchStatus,
+) error {
+	err := h.SetCommitStatus(ctx, env.Owner, env.Repo, sha, git.CommitStatus{
+		State:       status.State,
+		Description: status.Title,
+		TargetURL:   envFrontendLink,
+	})
+
+	return errors.Wrapf(err, "fail to set %s commit status", status.State)
+}
+
+// Deactivate does nothing, commit statuses belong to their commit and need no deactivation
+func (h *reportingHost) Deactivate(ctx context.Context, env *database.Environment, description string) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package launcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/git"
+	"github.com/ergomake/ergomake/internal/transformer"
+	gitMocks "github.com/ergomake/ergomake/mocks/git"
+)
+
+func TestEnvironmentFrontendLink(t *testing.T) {
+	t.Parallel()
+
+	env := database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "main", nil, "author", database.EnvPending)
+
+	env.Provider = database.ProviderGitHub
+	assert.Equal(t, "https://app.ergomake.dev/gh/owner/repos/repo/envs/"+env.ID.String(),
+		EnvironmentFrontendLink("https://app.ergomake.dev", env))
+
+	env.Provider = database.ProviderGitLab
+	assert.Equal(t, "https://app.ergomake.dev/environments/"+env.ID.String(),
+		EnvironmentFrontendLink("https://app.ergomake.dev", env))
+}
+
+func TestGetEnvironmentURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://web.preview.dev", EnvironmentURL(transformer.NewEnvironment(
+		map[string]transformer.EnvironmentService{"web": {Url: "web.preview.dev", Index: 0}},
+		"",
+	)))
+	assert.Equal(t, "", EnvironmentURL(transformer.NewEnvironment(
+		map[string]transformer.EnvironmentService{"db": {Index: 0}},
+		"",
+	)))
+}
+
+func TestReportingHost_ReportStatus(t *testing.T) {
+	t.Parallel()
+
+	env := database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "main", nil, "author", database.EnvPending)
+	env.Provider = database.ProviderGitea
+
+	client := gitMocks.NewReportingClient(t)
+	client.EXPECT().SetCommitStatus(mock.Anything, "owner", "repo", "sha", git.CommitStatus{
+		State:       git.CommitStateFailure,
+		Description: "Environment failed",
+		TargetURL:   "https://app.ergomake.dev/environments/id",
+	}).Return(nil)
+
+	host := NewReportingHost(database.ProviderGitea, client)
+	assert.Equal(t, database.ProviderGitea, host.Provider())
+
+	err := host.ReportStatus(context.Background(), nil, "https://app.ergomake.dev/environments/id", env, "sha", LaunchStatus{
+		State:   git.CommitStateFailure,
+		Title:   "Environment failed",
+		Message: "Setup jobs failed",
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,659 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	batchv1 "k8s.io/api/batch/v1"
+
+	"github.com/ergomake/ergomake/internal/allowedhosts"
+	"github.com/ergomake/ergomake/internal/cluster"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/envvars"
+	"github.com/ergomake/ergomake/internal/git"
+	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/payment"
+	"github.com/ergomake/ergomake/internal/privregistry"
+	"github.com/ergomake/ergomake/internal/transformer"
+	"github.com/ergomake/ergomake/internal/urltemplates"
+)
+
+type LaunchEnvironmentRequest struct {
+	// Provider is where the repository is hosted, one of the database providers
+	Provider    string
+	Owner       string
+	BranchOwner string
+	Repo        string
+	Branch      string
+	SHA         string
+	PrNumber    *int
+	Author      string
+	IsPrivate   bool
+	// Update keeps the running environment and only rolls what changed instead of launching a new one
+	Update bool
+}
+
+type Launcher interface {
+	// LaunchEnvironment returns nil once a failure is reported on the commit, launching again would only
+	// report it again, errors are left for what failed before anything could be reported
+	LaunchEnvironment(ctx context.Context, req LaunchEnvironmentRequest) error
+	// CancelLaunch stops the launch in progress for the branch or pull request and waits for it to wind down
+	CancelLaunch(ctx context.Context, provider, owner, repo, branch string, prNumber *int)
+}
+
+type envLauncher struct {
+	db                      *database.DB
+	hosts                   map[string]GitHost
+	clusterClient           cluster.Client
+	envVarsProvider         envvars.EnvVarsProvider
+	privRegistryProvider    privregistry.PrivRegistryProvider
+	environmentsProvider    environments.EnvironmentsProvider
+	paymentProvider         payment.PaymentProvider
+	urlTemplatesProvider    urltemplates.URLTemplatesProvider
+	allowedHostsProvider    allowedhosts.AllowedHostsProvider
+	dockerhubPullSecretName string
+	frontendURL             string
+	coordinator             *launchCoordinator
+}
+
+func NewLauncher(
+	db *database.DB,
+	clusterClient cluster.Client,
+	envVarsProvider envvars.EnvVarsProvider,
+	privRegistryProvider privregistry.PrivRegistryProvider,
+	environmentsProvider environments.EnvironmentsProvider,
+	paymentProvider payment.PaymentProvider,
+	urlTemplatesProvider urltemplates.URLTemplatesProvider,
+	allowedHostsProvider allowedhosts.AllowedHostsProvider,
+	dockerhubPullSecretName string,
+	frontendURL string,
+	hosts ...GitHost,
+) *envLauncher {
+	// repositories are only launched from the providers whose host is given
+	hostsByProvider := make(map[string]GitHost, len(hosts))
+	for _, host := range hosts {
+		hostsByProvider[host.Provider()] = host
+	}
+
+	return &envLauncher{
+		db,
+		hostsByProvider,
+		clusterClient,
+		envVarsProvider,
+		privRegistryProvider,
+		environmentsProvider,
+		paymentProvider,
+		urlTemplatesProvider,
+		allowedHostsProvider,
+		dockerhubPullSecretName,
+		frontendURL,
+		newLaunchCoordinator(),
+	}
+}
+
+func (el *envLauncher) CancelLaunch(ctx context.Context, provider, owner, repo, branch string, prNumber *int) {
+	el.coordinator.cancel(ctx, LaunchKey(provider, owner, repo, branch, prNumber))
+}
+
+func (el *envLauncher) LaunchEnvironment(ctx context.Context, req LaunchEnvironmentRequest) (err error) {
+	host, ok := el.hosts[req.Provider]
+	if !ok {
+		return errors.Errorf("no git host for provider %q", req.Provider)
+	}
+
+	// a newer commit of the same branch or pull request cancels this launch through ctx
+	ctx, l := el.coordinator.begin(ctx, LaunchKey(req.Provider, req.Owner, req.Repo, req.Branch, req.PrNumber))
+	defer l.end()
+
+	var previousEnvs []database.Environment
+	if req.PrNumber != nil {
+		envs, err := el.db.FindEnvironmentsByPullRequest(
+			req.Provider,
+			*req.PrNumber,
+			req.Owner,
+			req.Repo,
+			req.Branch,
+			database.FindEnvironmentsOptions{IncludeDeleted: true},
+		)
+		if err != nil {
+			return errors.Wrap(err, "fail to find previous envs of pull request")
+		}
+		previousEnvs = envs
+	} else {
+		envs, err := el.environmentsProvider.ListEnvironmentsByBranch(ctx, req.Provider, req.Owner, req.Repo, req.Branch)
+		if err != nil {
+			return errors.Wrap(err, "fail to find previous envs of branch")
+		}
+
+		for _, env := range envs {
+			if env.PullRequest.Valid {
+				continue
+			}
+
+			previousEnvs = append(previousEnvs, *env)
+		}
+	}
+
+	previousGHCommentID, previousProviderCommentID := int64(0), int64(0)
+	for _, previousEnv := range previousEnvs {
+		if previousEnv.GHCommentID > previousGHCommentID {
+			previousGHCommentID = previousEnv.GHCommentID
+		}
+
+		if previousEnv.ProviderCommentID > previousProviderCommentID {
+			previousProviderCommentID = previousEnv.ProviderCommentID
+		}
+	}
+
+	var updating *database.Environment
+	if req.Update {
+		env, err := el.findUpdatableEnvironment(previousEnvs)
+		if err != nil {
+			return errors.Wrap(err, "fail to find environment to update")
+		}
+		updating = env
+
+		if updating == nil {
+			// nothing is running to be updated, start over like when recreating
+			err := el.environmentsProvider.TerminateEnvironment(ctx, environments.TerminateEnvironmentRequest{
+				Provider: req.Provider,
+				Owner:    req.Owner,
+				Repo:     req.Repo,
+				Branch:   req.Branch,
+				PrNumber: req.PrNumber,
+			})
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Msg("fail to terminate environment that could not be updated")
+			}
+		}
+	}
+
+	// an environment being updated already counts towards the limit
+	isLimited := false
+	if updating == nil {
+		limited, err := el.environmentsProvider.IsOwnerLimited(ctx, req.Provider, req.Owner)
+		if err != nil {
+			return errors.Wrap(err, "fail to check if owner is limited")
+		}
+		isLimited = limited
+	}
+
+	plan, err := el.paymentProvider.GetOwnerPlan(ctx, req.Provider, req.Owner)
+	if err != nil {
+		return errors.Wrap(err, "fail to get owner plan")
+	}
+
+	urlTemplate, err := el.urlTemplatesProvider.Get(ctx, req.Provider, req.Owner, req.Repo)
+	if err != nil {
+		return errors.Wrap(err, "fail to get url template")
+	}
+
+	allowedHosts, err := el.allowedHostsProvider.List(ctx, req.Provider, req.Owner, req.Repo)
+	if err != nil {
+		return errors.Wrap(err, "fail to list allowed hosts")
+	}
+
+	uid := uuid.New()
+	if updating != nil {
+		uid = updating.ID
+	}
+
+	t := transformer.NewGitCompose(
+		el.clusterClient,
+		host,
+		el.db,
+		el.envVarsProvider,
+		el.privRegistryProvider,
+		req.Provider,
+		req.Owner,
+		req.BranchOwner,
+		req.Repo,
+		req.Branch,
+		req.SHA,
+		req.PrNumber,
+		req.Author,
+		!req.IsPrivate,
+		el.dockerhubPullSecretName,
+		plan,
+		urlTemplate,
+		allowedHosts,
+	)
+	defer t.Cleanup()
+
+	var prepare *transformer.PrepareResult
+	if updating != nil {
+		// the commit being replaced is no longer what the environment runs
+		err = host.Deactivate(ctx, updating, "")
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to deactivate deployment of updated environment")
+		}
+
+		prepare, err = t.PrepareUpdate(ctx, updating)
+	} else {
+		prepare, err = t.Prepare(ctx, uid)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			logger.Ctx(ctx).Info().Msg("launch cancelled by a newer one while preparing")
+			return nil
+		}
+
+		return errors.Wrap(err, "fail to prepare repo for transform")
+	}
+
+	env := prepare.Environment
+	envFrontendLink := EnvironmentFrontendLink(el.frontendURL, env)
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		// the launch is retried from scratch, a degraded environment is updated by the retry
+		// in update mode and terminated otherwise instead of being left pending
+		err := el.db.Model(env).Update("status", database.EnvDegraded).Error
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to update db environment status to degraded")
+		}
+	}()
+
+	if previousGHCommentID != 0 || previousProviderCommentID != 0 {
+		env.GHCommentID = previousGHCommentID
+		env.ProviderCommentID = previousProviderCommentID
+		err = el.db.Save(env).Error
+		if err != nil {
+			return errors.Wrap(err, "fail to save previous comment ids to env")
+		}
+	}
+
+	if isLimited {
+		err := host.ReportStatus(ctx, el.db, envFrontendLink, env, req.SHA, LaunchStatus{
+			State:   git.CommitStateFailure,
+			Title:   "Environments limit reached",
+			Message: "Close a pull request with an environment or talk to us to bump your limits.",
+		})
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to report status for limited env")
+		}
+
+		if req.PrNumber != nil {
+			comment := makeComment(
+				ctx, env, nil,
+				commentInfo{status: commentLimited, sha: req.SHA, frontendLink: envFrontendLink},
+				createLimitedComment(req.Provider),
+			)
+			err := host.ReportComment(ctx, el.db, env, comment)
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Msg("fail to comment for limited env")
+			}
+		}
+
+		env.Status = database.EnvLimited
+
+		err = el.db.Save(env).Error
+		if err != nil {
+			return errors.Wrap(err, "fail to save limited env")
+		}
+
+		logger.Ctx(ctx).Info().Msg("owner limited")
+
+		return nil
+	}
+
+	if prepare.Skip {
+		logger.Ctx(ctx).Info().Msg("pr skipped because .ergomake folder was not present")
+		return nil
+	}
+
+	if prepare.ValidationError != nil {
+		FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, prepare.ValidationError)
+		return nil
+	}
+
+	err = host.ReportStatus(ctx, el.db, envFrontendLink, prepare.Environment, req.SHA, LaunchStatus{
+		State: git.CommitStatePending,
+		Title: "Building environment",
+	})
+	if err != nil {
+		return errors.Wrap(err, "fail to report status")
+	}
+
+	t.OnBuildProgress(func(ctx context.Context) {
+		err := host.ReportStatus(ctx, el.db, envFrontendLink, prepare.Environment, req.SHA, LaunchStatus{
+			State: git.CommitStateRunning,
+			Title: "Building environment",
+		})
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to report build progress")
+		}
+	})
+
+	if lister, ok := host.(ChangesLister); ok && req.PrNumber != nil {
+		// everything is rebuilt when the changes of the pull request can't be listed
+		baseBranch, changedFiles, err := lister.ListPullRequestChanges(ctx, req.Owner, req.Repo, *req.PrNumber, req.SHA)
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to list pull request changes to skip unchanged builds")
+		} else {
+			t.SkipUnchangedBuilds(baseBranch, changedFiles)
+		}
+	}
+
+	transformResult, err := t.Transform(ctx, uid)
+
+	if err != nil {
+		if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+			return nil
+		}
+
+		logger.Ctx(ctx).Err(err).Msg("fail to transform compose into cluster env")
+		FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+		return nil
+	}
+
+	if transformResult.Failed() {
+		if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+			return nil
+		}
+
+		FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+		return nil
+	}
+
+	// we're done building, is the environment still supposed to be launched?
+	// try to find dbEnv in the database, if it is deleted, it is because we
+	// are not suppose to launch it anymore
+	_, err = el.db.FindEnvironmentByID(uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = host.ReportStatus(ctx, el.db, envFrontendLink, prepare.Environment, req.SHA, LaunchStatus{
+				State:   git.CommitStateCanceled,
+				Title:   "Environment was terminated",
+				Message: "The environment was terminated while it was being built.",
+			})
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Msg("fail to report status of terminated environment")
+			}
+			return nil
+		}
+
+		if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+			return nil
+		}
+
+		logger.Ctx(ctx).Err(err).Msg("fail to check if env should still be launched")
+		FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+		return nil
+	}
+
+	err = cluster.Deploy(ctx, el.clusterClient, transformResult.ClusterEnv)
+	if err != nil {
+		if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+			return nil
+		}
+
+		logger.Ctx(ctx).Err(err).Msg("fail to deploy cluster env to cluster")
+		FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+		return nil
+	}
+
+	if !transformResult.PendingBuilds {
+		dependencyJobsCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+		defer cancel()
+		dependencyJobsResult, err := cluster.RunDependencyJobs(dependencyJobsCtx, el.clusterClient, transformResult.ClusterEnv.Namespace)
+		if err != nil {
+			if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+				return nil
+			}
+
+			logger.Ctx(ctx).Err(err).Msg("fail to run dependency jobs")
+			FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+			return nil
+		}
+
+		if len(dependencyJobsResult.Failed) > 0 {
+			err := el.db.Model(prepare.Environment).Update("status", database.EnvDegraded).Error
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Msg("fail to update db environment status to degraded")
+			}
+
+			FailJobsRun(ctx, host, el.db, el.clusterClient, envFrontendLink, prepare.Environment, req.SHA, dependencyJobsResult.Failed)
+			return nil
+		}
+
+		deploymentsCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+		defer cancel()
+		err = el.clusterClient.WaitDeployments(deploymentsCtx, transformResult.ClusterEnv.Namespace)
+		if err != nil {
+			if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+				return nil
+			}
+
+			logger.Ctx(ctx).Err(err).Msg("fail to wait for deployments")
+			FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+			return nil
+		}
+
+		jobsCtx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+		defer cancel()
+		jobsResult, err := cluster.RunSetupJobs(jobsCtx, el.clusterClient, transformResult.ClusterEnv.Namespace)
+		if err != nil {
+			if el.cancelled(ctx, l, host, prepare.Environment, req.SHA) {
+				return nil
+			}
+
+			logger.Ctx(ctx).Err(err).Msg("fail to run setup jobs")
+			FailRun(ctx, host, el.db, envFrontendLink, prepare.Environment, req.SHA, nil)
+			return nil
+		}
+
+		if len(jobsResult.Failed) > 0 {
+			err := el.db.Model(prepare.Environment).Update("status", database.EnvDegraded).Error
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Msg("fail to update db environment status to degraded")
+			}
+
+			FailJobsRun(ctx, host, el.db, el.clusterClient, envFrontendLink, prepare.Environment, req.SHA, jobsResult.Failed)
+			return nil
+		}
+
+		SuccessRun(ctx, host, el.db, envFrontendLink, transformResult.Environment, prepare.Environment, req.SHA)
+	}
+
+	return nil
+}
+
+// findUpdatableEnvironment returns the newest of envs that is running and can be updated in place,
+// the ones still being launched are left to be recreated
+func (el *envLauncher) findUpdatableEnvironment(envs []database.Environment) (*database.Environment, error) {
+	var latest *database.Environment
+	for i, env := range envs {
+		if env.DeletedAt.Valid || (env.Status != database.EnvSuccess && env.Status != database.EnvDegraded) {
+			continue
+		}
+
+		if latest == nil || env.CreatedAt.After(latest.CreatedAt) {
+			latest = &envs[i]
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+
+	// services must come with their urls
+	env, err := el.db.FindEnvironmentByID(latest.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "fail to find environment %s", latest.ID)
+	}
+
+	return &env, nil
+}
+
+// cancelled tells whether the launch was cancelled, it then stops the builds of env and marks it
+// as cancelled. When a newer launch superseded it env is left alone, that launch either updates
+// env itself or terminated it already.
+func (el *envLauncher) cancelled(ctx context.Context, l *launch, host GitHost, env *database.Environment, sha string) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+
+	log := logger.Ctx(ctx)
+	if l.superseded() {
+		log.Info().Msg("launch superseded by a newer one")
+		return true
+	}
+	// ctx is already done, cleaning up must not be
+	cleanupCtx := log.WithContext(context.Background())
+
+	err := cluster.DeleteBuilds(cleanupCtx, el.clusterClient, env.ID.String())
+	if err != nil {
+		log.Err(err).Msg("fail to delete builds of cancelled launch")
+	}
+
+	err = el.db.Model(env).Update("status", database.EnvCancelled).Error
+	if err != nil {
+		log.Err(err).Msg("fail to update db environment status to cancelled")
+	}
+
+	err = host.ReportStatus(cleanupCtx, el.db, EnvironmentFrontendLink(el.frontendURL, env), env, sha, LaunchStatus{
+		State:   git.CommitStateCanceled,
+		Title:   "Launch cancelled",
+		Message: "A newer commit superseded this one before its environment was ready.",
+	})
+	if err != nil {
+		log.Err(err).Msg("fail to report status of cancelled launch")
+	}
+
+	log.Info().Msg("launch cancelled")
+
+	return true
+}
+
+func FailRun(
+	ctx context.Context,
+	host GitHost,
+	db *database.DB,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+	validationError *transformer.ProjectValidationError,
+) {
+	message := ""
+	if validationError != nil {
+		message = validationError.Message
+	}
+
+	failRun(ctx, host, db, envFrontendLink, env, sha, getFailureReason(envFrontendLink, validationError), message)
+}
+
+// jobLogsSize is how many characters of the logs of each failed job go into the failure comment
+const jobLogsSize = 2000
+
+// FailJobsRun is FailRun for when setup jobs fail, the comment carries the tail of their logs
+func FailJobsRun(
+	ctx context.Context,
+	host GitHost,
+	db *database.DB,
+	clusterClient cluster.Client,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+	jobs []*batchv1.Job,
+) {
+	failedJobs := make([]failedJobLogs, len(jobs))
+	for i, job := range jobs {
+		logs, err := clusterClient.GetJobLogs(ctx, job, jobLogsSize)
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Str("job", job.GetName()).Msg("fail to get setup job logs")
+			logs = "logs are not available"
+		}
+
+		failedJobs[i] = failedJobLogs{Name: job.GetName(), Logs: strings.TrimSpace(logs)}
+	}
+
+	names := make([]string, len(failedJobs))
+	for i, job := range failedJobs {
+		names[i] = job.Name
+	}
+	message := fmt.Sprintf("Setup jobs failed: %s", formatServiceNames(names))
+
+	failRun(ctx, host, db, envFrontendLink, env, sha, getJobsFailureReason(envFrontendLink, failedJobs), message)
+}
+
+func failRun(
+	ctx context.Context,
+	host GitHost,
+	db *database.DB,
+	envFrontendLink string,
+	env *database.Environment,
+	sha string,
+	reason string,
+	message string,
+) {
+	log := logger.Ctx(ctx)
+
+	if env.PullRequest.Valid {
+		comment := makeComment(
+			ctx, env, nil,
+			commentInfo{status: commentFailure, sha: sha, frontendLink: envFrontendLink, reason: reason},
+			makeFailureComment(host.Provider(), reason),
+		)
+		err := host.ReportComment(ctx, db, env, comment)
+		if err != nil {
+			log.Err(err).Msg("fail to post failure comment")
+		}
+	}
+
+	err := host.ReportStatus(ctx, db, envFrontendLink, env, sha, LaunchStatus{
+		State:   git.CommitStateFailure,
+		Title:   "Environment failed",
+		Message: message,
+	})
+	if err != nil {
+		log.Err(err).Msg("fail to report failure status")
+	}
+}
+
+func SuccessRun(
+	ctx context.Context,
+	host GitHost,
+	db *database.DB,
+	envFrontendLink string,
+	compose *transformer.Environment,
+	env *database.Environment,
+	sha string,
+) {
+	log := logger.Ctx(ctx)
+
+	if env.PullRequest.Valid {
+		comment := makeComment(
+			ctx, env, compose,
+			commentInfo{status: commentSuccess, sha: sha, frontendLink: envFrontendLink},
+			createSuccessComment(host.Provider(), compose, envFrontendLink),
+		)
+		err := host.ReportComment(ctx, db, env, comment)
+		if err != nil {
+			log.Err(err).Msg("fail to post success comment")
+		}
+	}
+
+	err := host.ReportStatus(ctx, db, envFrontendLink, env, sha, LaunchStatus{
+		State:          git.CommitStateSuccess,
+		Title:          "Environment is ready",
+		Message:        strings.TrimSpace(getUpdateSummary(compose.Update)),
+		EnvironmentURL: EnvironmentURL(compose),
+	})
+	if err != nil {
+		log.Err(err).Msg("fail to report success status")
+	}
+}
@@ -0,0 +1,79 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/launcher"
+	"github.com/ergomake/ergomake/internal/payment"
+	"github.com/ergomake/ergomake/internal/urltemplates"
+	allowedhostsMocks "github.com/ergomake/ergomake/mocks/allowedhosts"
+	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
+	launcherMocks "github.com/ergomake/ergomake/mocks/launcher"
+	paymentMocks "github.com/ergomake/ergomake/mocks/payment"
+	urltemplatesMocks "github.com/ergomake/ergomake/mocks/urltemplates"
+)
+
+func TestLauncher_LaunchEnvironment_prepareUpdateFails(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	ctx := context.Background()
+
+	env := database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "main", nil, "author", database.EnvSuccess)
+	env.Provider = database.ProviderGitHub
+	require.NoError(t, db.Create(env).Error)
+
+	environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+	environmentsProvider.EXPECT().ListEnvironmentsByBranch(mock.Anything, database.ProviderGitHub, "owner", "repo", "main").
+		Return([]*database.Environment{env}, nil)
+	paymentProvider := paymentMocks.NewPaymentProvider(t)
+	paymentProvider.EXPECT().GetOwnerPlan(mock.Anything, database.ProviderGitHub, "owner").Return(payment.PaymentPlanFree, nil)
+	urlTemplatesProvider := urltemplatesMocks.NewURLTemplatesProvider(t)
+	urlTemplatesProvider.EXPECT().Get(mock.Anything, database.ProviderGitHub, "owner", "repo").Return(urltemplates.DefaultTemplate, nil)
+	allowedHostsProvider := allowedhostsMocks.NewAllowedHostsProvider(t)
+	allowedHostsProvider.EXPECT().List(mock.Anything, database.ProviderGitHub, "owner", "repo").Return(nil, nil)
+
+	host := launcherMocks.NewGitHost(t)
+	host.EXPECT().Provider().Return(database.ProviderGitHub)
+	host.EXPECT().Deactivate(mock.Anything, mock.Anything, "").Return(nil)
+	host.EXPECT().CloneRepo(mock.Anything, "owner", "repo", "main", mock.Anything, true).Return(assert.AnError)
+
+	envLauncher := launcher.NewLauncher(
+		db,
+		nil,
+		nil,
+		nil,
+		environmentsProvider,
+		paymentProvider,
+		urlTemplatesProvider,
+		allowedHostsProvider,
+		"",
+		"https://app.ergomake.dev",
+		host,
+	)
+
+	err := envLauncher.LaunchEnvironment(ctx, launcher.LaunchEnvironmentRequest{
+		Provider:    database.ProviderGitHub,
+		Owner:       "owner",
+		BranchOwner: "owner",
+		Repo:        "repo",
+		Branch:      "main",
+		SHA:         "sha",
+		Author:      "author",
+		Update:      true,
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// the retry updates the environment again instead of leaving it pending
+	updated, err := db.FindEnvironmentByID(env.ID)
+	require.NoError(t, err)
+	assert.Equal(t, database.EnvDegraded, updated.Status)
+}
@@ -0,0 +1,318 @@
+package launchqueue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/deploymodes"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/launcher"
+)
+
+const (
+	defaultConcurrency      = 10
+	defaultOwnerConcurrency = 2
+	// maxAttempts is how many times a job runs before it is given up on
+	maxAttempts = 5
+	// lockDuration is how long a claimed job belongs to a worker without a heartbeat,
+	// jobs of workers that died are claimed again once it runs out
+	lockDuration = 2 * time.Minute
+	pollInterval = 5 * time.Second
+)
+
+type launchJob struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Kind        string
+	Key         string
+	Provider    string
+	Owner       string
+	Repo        string
+	Branch      string
+	PrNumber    *int
+	SHA         string
+	Payload     json.RawMessage `gorm:"type:jsonb"`
+	Status      string
+	Attempts    int
+	RunAt       time.Time
+	LockedUntil *time.Time
+	LastError   string
+}
+
+func (j *launchJob) toJob() Job {
+	return Job{
+		ID:        j.ID,
+		Kind:      j.Kind,
+		Provider:  j.Provider,
+		Owner:     j.Owner,
+		Repo:      j.Repo,
+		Branch:    j.Branch,
+		PrNumber:  j.PrNumber,
+		SHA:       j.SHA,
+		Status:    j.Status,
+		Attempts:  j.Attempts,
+		LastError: j.LastError,
+		CreatedAt: j.CreatedAt,
+		RunAt:     j.RunAt,
+	}
+}
+
+type payload struct {
+	Terminate environments.TerminateEnvironmentRequest
+	Launch    *launcher.LaunchEnvironmentRequest
+}
+
+type dbLaunchQueue struct {
+	db                   *database.DB
+	envLauncher          launcher.Launcher
+	environmentsProvider environments.EnvironmentsProvider
+	deployModesProvider  deploymodes.DeployModesProvider
+	concurrency          int
+	ownerConcurrency     int
+	wakeCh               chan struct{}
+}
+
+// NewDBLaunchQueue keeps jobs in the database so they survive restarts. Run handles at most
+// concurrency jobs at once and at most ownerConcurrency launches of the same owner.
+func NewDBLaunchQueue(
+	db *database.DB,
+	envLauncher launcher.Launcher,
+	environmentsProvider environments.EnvironmentsProvider,
+	deployModesProvider deploymodes.DeployModesProvider,
+	concurrency int,
+	ownerConcurrency int,
+) *dbLaunchQueue {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	if ownerConcurrency <= 0 {
+		ownerConcurrency = defaultOwnerConcurrency
+	}
+
+	return &dbLaunchQueue{
+		db,
+		envLauncher,
+		environmentsProvider,
+		deployModesProvider,
+		concurrency,
+		ownerConcurrency,
+		make(chan struct{}, 1),
+	}
+}
+
+func (q *dbLaunchQueue) EnqueueLaunch(
+	ctx context.Context,
+	terminateReq environments.TerminateEnvironmentRequest,
+	launchReq launcher.LaunchEnvironmentRequest,
+) error {
+	return q.enqueue(ctx, KindLaunch, launchReq.SHA, payload{Terminate: terminateReq, Launch: &launchReq})
+}
+
+func (q *dbLaunchQueue) EnqueueTerminate(ctx context.Context, req environments.TerminateEnvironmentRequest) error {
+	return q.enqueue(ctx, KindTerminate, "", payload{Terminate: req})
+}
+
+func (q *dbLaunchQueue) enqueue(ctx context.Context, kind, sha string, p payload) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrapf(err, "fail to marshal %s job payload", kind)
+	}
+
+	req := p.Terminate
+	job := launchJob{
+		Kind:     kind,
+		Key:      launcher.LaunchKey(req.Provider, req.Owner, req.Repo, req.Branch, req.PrNumber),
+		Provider: req.Provider,
+		Owner:    req.Owner,
+		Repo:     req.Repo,
+		Branch:   req.Branch,
+		PrNumber: req.PrNumber,
+		SHA:      sha,
+		Payload:  data,
+		Status:   StatusQueued,
+		RunAt:    time.Now(),
+	}
+
+	err = q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("launch_jobs").
+			Where("key = ? AND status = ?", job.Key, StatusQueued).
+			Updates(map[string]interface{}{"status": StatusSuperseded, "updated_at": time.Now()}).Error
+		if err != nil {
+			return errors.Wrapf(err, "fail to supersede jobs of %s", job.Key)
+		}
+
+		return errors.Wrapf(tx.Table("launch_jobs").Create(&job).Error, "fail to insert %s job", kind)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.wake()
+
+	return nil
+}
+
+func (q *dbLaunchQueue) Stats(ctx context.Context, provider, owner string) (*Stats, error) {
+	var depth int64
+	err := q.db.WithContext(ctx).Table("launch_jobs").
+		Where("provider = ? AND owner = ? AND status = ?", provider, owner, StatusQueued).
+		Count(&depth).Error
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to count queued jobs of %s", owner)
+	}
+
+	var running []launchJob
+	err = q.db.WithContext(ctx).Table("launch_jobs").
+		Where("provider = ? AND owner = ? AND status = ? AND locked_until >= ?", provider, owner, StatusRunning, time.Now()).
+		Order("updated_at").
+		Find(&running).Error
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to list running jobs of %s", owner)
+	}
+
+	stats := &Stats{Depth: int(depth), InFlight: make([]Job, len(running))}
+	for i, job := range running {
+		stats.InFlight[i] = job.toJob()
+	}
+
+	return stats, nil
+}
+
+// claimQuery picks the next job that is due, or whose worker stopped sending heartbeats, as long as
+// its owner is below the concurrency limit. Terminations are quick and never wait for the limit.
+const claimQuery = `
+SELECT j.* FROM launch_jobs j
+WHERE (
+	(j.status = 'queued' AND j.run_at <= NOW()) OR
+	(j.status = 'running' AND j.locked_until < NOW())
+) AND (
+	j.kind = 'terminate' OR (
+		SELECT COUNT(*) FROM launch_jobs r
+		WHERE r.provider = j.provider AND r.owner = j.owner AND r.kind = 'launch' AND r.status = 'running' AND r.locked_until >= NOW()
+	) < ?
+)
+ORDER BY j.run_at
+LIMIT 1
+FOR UPDATE OF j SKIP LOCKED`
+
+const lockOwnerQuery = `SELECT pg_advisory_xact_lock(hashtext(? || '/' || ?))`
+
+func (q *dbLaunchQueue) claim(ctx context.Context) (*launchJob, error) {
+	var claimed *launchJob
+	err := q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job launchJob
+		res := tx.Raw(claimQuery, q.ownerConcurrency).Scan(&job)
+		if res.Error != nil {
+			return errors.Wrap(res.Error, "fail to find job to claim")
+		}
+
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		if job.Kind == KindLaunch {
+			// the row lock only covers the job, workers claiming launches of the same owner at once
+			// would all count the same running ones, so they take turns until the transaction is over
+			err := tx.Exec(lockOwnerQuery, job.Provider, job.Owner).Error
+			if err != nil {
+				return errors.Wrapf(err, "fail to lock owner %s", job.Owner)
+			}
+
+			var running int64
+			err = tx.Table("launch_jobs").
+				Where(
+					"provider = ? AND owner = ? AND kind = ? AND status = ? AND locked_until >= NOW()",
+					job.Provider, job.Owner, KindLaunch, StatusRunning,
+				).
+				Count(&running).Error
+			if err != nil {
+				return errors.Wrapf(err, "fail to count running launches of %s", job.Owner)
+			}
+
+			if running >= int64(q.ownerConcurrency) {
+				return nil
+			}
+		}
+
+		err := tx.Table("launch_jobs").
+			Where("id = ?", job.ID).
+			Updates(map[string]interface{}{
+				"status":       StatusRunning,
+				"attempts":     gorm.Expr("attempts + 1"),
+				"locked_until": time.Now().Add(lockDuration),
+				"updated_at":   time.Now(),
+			}).Error
+		if err != nil {
+			return errors.Wrapf(err, "fail to claim job %s", job.ID)
+		}
+
+		err = tx.Table("launch_jobs").Take(&job, "id = ?", job.ID).Error
+		if err != nil {
+			return errors.Wrapf(err, "fail to reload claimed job %s", job.ID)
+		}
+
+		claimed = &job
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to claim job")
+	}
+
+	return claimed, nil
+}
+
+// extendLock keeps job claimed, it returns false when the job is no longer running because it was cancelled
+func (q *dbLaunchQueue) extendLock(ctx context.Context, job *launchJob) (bool, error) {
+	res := q.db.WithContext(ctx).Table("launch_jobs").
+		Where("id = ? AND status = ?", job.ID, StatusRunning).
+		Updates(map[string]interface{}{"locked_until": time.Now().Add(lockDuration)})
+	if res.Error != nil {
+		return true, errors.Wrapf(res.Error, "fail to extend lock of job %s", job.ID)
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// cancelLaunches cancels the launches of the branch or pull request of req that are running, but job.
+// Their workers may be in other processes, they stop once they notice it on their next heartbeat.
+func (q *dbLaunchQueue) cancelLaunches(ctx context.Context, job *launchJob, req environments.TerminateEnvironmentRequest) error {
+	key := launcher.LaunchKey(req.Provider, req.Owner, req.Repo, req.Branch, req.PrNumber)
+	err := q.db.WithContext(ctx).Table("launch_jobs").
+		Where("key = ? AND kind = ? AND status = ? AND id <> ?", key, KindLaunch, StatusRunning, job.ID).
+		Updates(map[string]interface{}{"status": StatusCancelled, "locked_until": nil, "updated_at": time.Now()}).Error
+
+	return errors.Wrapf(err, "fail to cancel launches of %s", key)
+}
+
+// finish records the outcome of a job, failed jobs are queued again with a backoff until they
+// run out of attempts
+func (q *dbLaunchQueue) finish(ctx context.Context, job *launchJob, jobErr error) error {
+	updates := map[string]interface{}{
+		"status":       StatusSucceeded,
+		"locked_until": nil,
+		"last_error":   "",
+		"updated_at":   time.Now(),
+	}
+	if jobErr != nil {
+		updates["last_error"] = jobErr.Error()
+		updates["status"] = StatusFailed
+		if job.Attempts < maxAttempts {
+			updates["status"] = StatusQueued
+			updates["run_at"] = time.Now().Add(backoff(job.Attempts))
+		}
+	}
+
+	err := q.db.WithContext(ctx).Table("launch_jobs").
+		Where("id = ? AND status = ?", job.ID, StatusRunning).
+		Updates(updates).Error
+
+	return errors.Wrapf(err, "fail to finish job %s", job.ID)
+}
@@ -0,0 +1,81 @@
+package launchqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/launcher"
+)
+
+const (
+	KindLaunch    = "launch"
+	KindTerminate = "terminate"
+)
+
+const (
+	StatusQueued     = "queued"
+	StatusRunning    = "running"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+	StatusSuperseded = "superseded"
+	// StatusCancelled is for launches stopped while running because their environment was terminated
+	StatusCancelled = "cancelled"
+)
+
+// Job is a launch or termination waiting for, or being handled by, a worker
+type Job struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	Provider  string    `json:"provider"`
+	Owner     string    `json:"owner"`
+	Repo      string    `json:"repo"`
+	Branch    string    `json:"branch"`
+	PrNumber  *int      `json:"prNumber"`
+	SHA       string    `json:"sha"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError"`
+	CreatedAt time.Time `json:"createdAt"`
+	RunAt     time.Time `json:"runAt"`
+}
+
+type Stats struct {
+	// Depth is how many jobs are waiting for a worker, including the ones waiting to be retried
+	Depth    int   `json:"depth"`
+	InFlight []Job `json:"inFlight"`
+}
+
+type LaunchQueue interface {
+	// EnqueueLaunch brings the environment of a branch to a new commit, jobs of the same branch
+	// that did not start yet are superseded by it
+	EnqueueLaunch(
+		ctx context.Context,
+		terminateReq environments.TerminateEnvironmentRequest,
+		launchReq launcher.LaunchEnvironmentRequest,
+	) error
+	// EnqueueTerminate terminates the environment of a branch, jobs of the same branch that did
+	// not start yet are superseded by it
+	EnqueueTerminate(ctx context.Context, req environments.TerminateEnvironmentRequest) error
+	Stats(ctx context.Context, provider, owner string) (*Stats, error)
+}
+
+const (
+	backoffBase = 15 * time.Second
+	backoffMax  = 10 * time.Minute
+)
+
+// backoff is how long a job waits before its next attempt, it doubles with every failed attempt
+func backoff(attempts int) time.Duration {
+	d := backoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= backoffMax {
+			return backoffMax
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,264 @@
+package launchqueue
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/deploymodes"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/launcher"
+	deploymodesMocks "github.com/ergomake/ergomake/mocks/deploymodes"
+	environmentsMocks "github.com/ergomake/ergomake/mocks/environments"
+	launcherMocks "github.com/ergomake/ergomake/mocks/launcher"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 15*time.Second, backoff(1))
+	assert.Equal(t, 30*time.Second, backoff(2))
+	assert.Equal(t, 60*time.Second, backoff(3))
+	assert.Equal(t, backoffMax, backoff(10))
+}
+
+func TestDBLaunchQueue_handle(t *testing.T) {
+	t.Parallel()
+
+	pr := 7
+	terminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    "owner",
+		Repo:     "repo",
+		Branch:   "branch",
+		PrNumber: &pr,
+	}
+	launchReq := launcher.LaunchEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    "owner",
+		Repo:     "repo",
+		Branch:   "branch",
+		SHA:      "sha",
+		PrNumber: &pr,
+	}
+
+	makeJob := func(t *testing.T, kind string, p payload) *launchJob {
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		return &launchJob{ID: uuid.New(), Kind: kind, Payload: data}
+	}
+
+	t.Run("recreates the environment", func(t *testing.T) {
+		envLauncher := launcherMocks.NewLauncher(t)
+		environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+		deployModesProvider := deploymodesMocks.NewDeployModesProvider(t)
+		deployModesProvider.EXPECT().Get(mock.Anything, database.ProviderGitHub, "owner", "repo").Return(deploymodes.Recreate, nil)
+		envLauncher.EXPECT().CancelLaunch(mock.Anything, database.ProviderGitHub, "owner", "repo", "branch", &pr).Return()
+		environmentsProvider.EXPECT().TerminateEnvironment(mock.Anything, terminateReq).Return(nil)
+		envLauncher.EXPECT().LaunchEnvironment(mock.Anything, launchReq).Return(nil)
+
+		// terminations cancel the running launches of the branch in the database
+		db := testutils.CreateRandomDB(t)
+		q := NewDBLaunchQueue(db, envLauncher, environmentsProvider, deployModesProvider, 0, 0)
+		err := q.handle(context.Background(), makeJob(t, KindLaunch, payload{Terminate: terminateReq, Launch: &launchReq}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("updates the environment in update mode", func(t *testing.T) {
+		envLauncher := launcherMocks.NewLauncher(t)
+		deployModesProvider := deploymodesMocks.NewDeployModesProvider(t)
+		deployModesProvider.EXPECT().Get(mock.Anything, database.ProviderGitHub, "owner", "repo").Return(deploymodes.Update, nil)
+		updateReq := launchReq
+		updateReq.Update = true
+		envLauncher.EXPECT().LaunchEnvironment(mock.Anything, updateReq).Return(nil)
+
+		q := NewDBLaunchQueue(nil, envLauncher, environmentsMocks.NewEnvironmentsProvider(t), deployModesProvider, 0, 0)
+		err := q.handle(context.Background(), makeJob(t, KindLaunch, payload{Terminate: terminateReq, Launch: &launchReq}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("recreates environments of other providers", func(t *testing.T) {
+		envLauncher := launcherMocks.NewLauncher(t)
+		environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+		deployModesProvider := deploymodesMocks.NewDeployModesProvider(t)
+		deployModesProvider.EXPECT().Get(mock.Anything, database.ProviderGitLab, "owner", "repo").Return(deploymodes.Recreate, nil)
+		glTerminateReq := terminateReq
+		glTerminateReq.Provider = database.ProviderGitLab
+		glLaunchReq := launchReq
+		glLaunchReq.Provider = database.ProviderGitLab
+		envLauncher.EXPECT().CancelLaunch(mock.Anything, database.ProviderGitLab, "owner", "repo", "branch", &pr).Return()
+		environmentsProvider.EXPECT().TerminateEnvironment(mock.Anything, glTerminateReq).Return(nil)
+		envLauncher.EXPECT().LaunchEnvironment(mock.Anything, glLaunchReq).Return(nil)
+
+		// terminations cancel the running launches of the branch in the database
+		db := testutils.CreateRandomDB(t)
+		q := NewDBLaunchQueue(db, envLauncher, environmentsProvider, deployModesProvider, 0, 0)
+		err := q.handle(context.Background(), makeJob(t, KindLaunch, payload{Terminate: glTerminateReq, Launch: &glLaunchReq}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("terminates the environment", func(t *testing.T) {
+		envLauncher := launcherMocks.NewLauncher(t)
+		environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+		envLauncher.EXPECT().CancelLaunch(mock.Anything, database.ProviderGitHub, "owner", "repo", "branch", &pr).Return()
+		environmentsProvider.EXPECT().TerminateEnvironment(mock.Anything, terminateReq).Return(nil)
+
+		// terminations cancel the running launches of the branch in the database
+		db := testutils.CreateRandomDB(t)
+		q := NewDBLaunchQueue(db, envLauncher, environmentsProvider, deploymodesMocks.NewDeployModesProvider(t), 0, 0)
+		err := q.handle(context.Background(), makeJob(t, KindTerminate, payload{Terminate: terminateReq}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("errors on unknown jobs", func(t *testing.T) {
+		q := NewDBLaunchQueue(
+			nil,
+			launcherMocks.NewLauncher(t),
+			environmentsMocks.NewEnvironmentsProvider(t),
+			deploymodesMocks.NewDeployModesProvider(t),
+			0,
+			0,
+		)
+		assert.Error(t, q.handle(context.Background(), makeJob(t, "deploy", payload{})))
+		assert.Error(t, q.handle(context.Background(), makeJob(t, KindLaunch, payload{})))
+	})
+}
+
+func TestDBLaunchQueue(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	q := NewDBLaunchQueue(db, nil, nil, nil, 0, 1)
+	ctx := context.Background()
+
+	launch := func(provider, owner, branch, sha string) {
+		err := q.EnqueueLaunch(
+			ctx,
+			environments.TerminateEnvironmentRequest{Provider: provider, Owner: owner, Repo: "repo", Branch: branch},
+			launcher.LaunchEnvironmentRequest{Provider: provider, Owner: owner, Repo: "repo", Branch: branch, SHA: sha},
+		)
+		require.NoError(t, err)
+	}
+
+	launch(database.ProviderGitHub, "owner", "main", "sha1")
+	launch(database.ProviderGitHub, "owner", "main", "sha2")
+	launch(database.ProviderGitHub, "owner", "dev", "sha3")
+	// the owner of the same name on gitlab is someone else
+	launch(database.ProviderGitLab, "owner", "main", "sha4")
+
+	stats, err := q.Stats(ctx, database.ProviderGitHub, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Depth, "sha1 is superseded by sha2")
+	assert.Empty(t, stats.InFlight)
+
+	first, err := q.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, "sha2", first.SHA)
+	assert.Equal(t, 1, first.Attempts)
+
+	// owner is at its limit so only the gitlab owner gets a job
+	second, err := q.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, "sha4", second.SHA)
+
+	none, err := q.claim(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	stats, err = q.Stats(ctx, database.ProviderGitHub, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Depth)
+	require.Len(t, stats.InFlight, 1)
+	assert.Equal(t, "sha2", stats.InFlight[0].SHA)
+
+	// terminations don't wait for the owner limit
+	err = q.EnqueueTerminate(ctx, environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    "owner",
+		Repo:     "repo",
+		Branch:   "gone",
+	})
+	require.NoError(t, err)
+	terminate, err := q.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, terminate)
+	assert.Equal(t, KindTerminate, terminate.Kind)
+	require.NoError(t, q.finish(ctx, terminate, nil))
+
+	// failed jobs are retried later
+	require.NoError(t, q.finish(ctx, first, assert.AnError))
+	var retried launchJob
+	require.NoError(t, db.Table("launch_jobs").First(&retried, "id = ?", first.ID).Error)
+	assert.Equal(t, StatusQueued, retried.Status)
+	assert.Equal(t, assert.AnError.Error(), retried.LastError)
+	assert.True(t, retried.RunAt.After(time.Now()))
+
+	third, err := q.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, third)
+	assert.Equal(t, "sha3", third.SHA)
+}
+
+func TestDBLaunchQueue_cancel(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	ctx := context.Background()
+
+	envLauncher := launcherMocks.NewLauncher(t)
+	environmentsProvider := environmentsMocks.NewEnvironmentsProvider(t)
+	q := NewDBLaunchQueue(db, envLauncher, environmentsProvider, nil, 0, 0)
+
+	terminateReq := environments.TerminateEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    "owner",
+		Repo:     "repo",
+		Branch:   "main",
+	}
+	err := q.EnqueueLaunch(ctx, terminateReq, launcher.LaunchEnvironmentRequest{
+		Provider: database.ProviderGitHub,
+		Owner:    "owner",
+		Repo:     "repo",
+		Branch:   "main",
+		SHA:      "sha",
+	})
+	require.NoError(t, err)
+
+	launch, err := q.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, launch)
+
+	locked, err := q.extendLock(ctx, launch)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	// the launch may be running in another process, it notices the cancellation on its next heartbeat
+	require.NoError(t, q.EnqueueTerminate(ctx, terminateReq))
+	terminate, err := q.claim(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, terminate)
+
+	envLauncher.EXPECT().CancelLaunch(mock.Anything, database.ProviderGitHub, "owner", "repo", "main", (*int)(nil)).Return()
+	environmentsProvider.EXPECT().TerminateEnvironment(mock.Anything, terminateReq).Return(nil)
+	require.NoError(t, q.handle(ctx, terminate))
+
+	locked, err = q.extendLock(ctx, launch)
+	require.NoError(t, err)
+	assert.False(t, locked)
+
+	// the outcome of the cancelled launch doesn't bring it back
+	require.NoError(t, q.finish(ctx, launch, assert.AnError))
+	var cancelled launchJob
+	require.NoError(t, db.Table("launch_jobs").First(&cancelled, "id = ?", launch.ID).Error)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+}
@@ -0,0 +1,198 @@
+package launchqueue
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/deploymodes"
+	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+// Run handles jobs until ctx is done, including the ones left behind by workers that stopped
+// midway, which are picked up again once their lock runs out
+func (q *dbLaunchQueue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	slots := make(chan struct{}, q.concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		job, err := q.claim(ctx)
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to claim launch job")
+		}
+
+		if job == nil {
+			<-slots
+
+			select {
+			case <-q.wakeCh:
+			case <-time.After(pollInterval):
+			case <-ctx.Done():
+				return
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			q.process(ctx, job)
+		}()
+	}
+}
+
+func (q *dbLaunchQueue) wake() {
+	select {
+	case q.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (q *dbLaunchQueue) process(ctx context.Context, job *launchJob) {
+	log := logger.With(logger.Ctx(ctx)).
+		Str("jobID", job.ID.String()).
+		Str("kind", job.Kind).
+		Str("owner", job.Owner).
+		Str("repo", job.Repo).
+		Str("branch", job.Branch).
+		Str("SHA", job.SHA).
+		Int("attempt", job.Attempts).
+		Logger()
+	ctx = log.WithContext(ctx)
+
+	var err error
+	if job.Attempts > maxAttempts {
+		// the job brought its worker down every time it ran
+		err = errors.Errorf("job ran out of attempts")
+	} else {
+		jobCtx, cancel := context.WithCancel(ctx)
+		stop := q.keepLocked(jobCtx, job, cancel)
+		err = q.handle(jobCtx, job)
+		stop()
+		cancel()
+	}
+
+	if err != nil {
+		log.Err(err).Msg("fail to handle launch job")
+	}
+
+	err = q.finish(ctx, job, err)
+	if err != nil {
+		log.Err(err).Msg("fail to record outcome of launch job")
+	}
+
+	// the owner may have freed a slot for jobs that were waiting
+	q.wake()
+}
+
+// keepLocked extends the lock of the job while it is being handled so other workers don't claim it,
+// cancel is called once the job is no longer running because a termination cancelled it
+func (q *dbLaunchQueue) keepLocked(ctx context.Context, job *launchJob, cancel context.CancelFunc) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				locked, err := q.extendLock(ctx, job)
+				if err != nil {
+					logger.Ctx(ctx).Err(err).Msg("fail to extend lock of launch job")
+				}
+
+				if !locked {
+					logger.Ctx(ctx).Info().Msg("launch job was cancelled")
+					cancel()
+					return
+				}
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// handle runs the job, the launcher reports the failures of a launch on the commit and doesn't return
+// them so errors that reach here come from the database, github or the cluster and are worth retrying
+func (q *dbLaunchQueue) handle(ctx context.Context, job *launchJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic while handling job: %v", r)
+		}
+	}()
+
+	var p payload
+	err = json.Unmarshal(job.Payload, &p)
+	if err != nil {
+		return errors.Wrap(err, "fail to unmarshal job payload")
+	}
+
+	switch job.Kind {
+	case KindLaunch:
+		if p.Launch == nil {
+			return errors.New("launch job without launch request")
+		}
+
+		return q.launch(ctx, job, p)
+	case KindTerminate:
+		return q.terminate(ctx, job, p.Terminate)
+	}
+
+	return errors.Errorf("unknown job kind %q", job.Kind)
+}
+
+// launch brings the environment of a branch to a new commit, repos in update mode
+// keep it running and let the launcher update it, the others have it terminated first
+func (q *dbLaunchQueue) launch(ctx context.Context, job *launchJob, p payload) error {
+	launchReq := *p.Launch
+
+	mode, err := q.deployModesProvider.Get(ctx, launchReq.Provider, launchReq.Owner, launchReq.Repo)
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Msg("fail to get deploy mode, recreating environment")
+		mode = deploymodes.Recreate
+	}
+
+	if mode == deploymodes.Update {
+		launchReq.Update = true
+	} else {
+		err := q.terminate(ctx, job, p.Terminate)
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Msg("fail to terminate environment")
+		}
+	}
+
+	return q.envLauncher.LaunchEnvironment(ctx, launchReq)
+}
+
+func (q *dbLaunchQueue) terminate(ctx context.Context, job *launchJob, req environments.TerminateEnvironmentRequest) error {
+	// a launch still building would otherwise deploy right after the environment is gone, the
+	// ones of this process are also stopped right away and waited for
+	err := q.cancelLaunches(ctx, job, req)
+	if err != nil {
+		return errors.Wrap(err, "fail to cancel running launches")
+	}
+	q.envLauncher.CancelLaunch(ctx, req.Provider, req.Owner, req.Repo, req.Branch, req.PrNumber)
+
+	return q.environmentsProvider.TerminateEnvironment(ctx, req)
+}
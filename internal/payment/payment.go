@@ -36,9 +36,37 @@ func (plan *PaymentPlan) PermanentEnvironmentsLimit() int {
 	panic("unreachable")
 }
 
+// ServiceMemoryLimit is the most memory a single service can use, as a k8s quantity
+func (plan *PaymentPlan) ServiceMemoryLimit() string {
+	switch *plan {
+	case PaymentPlanFree:
+		return "2Gi"
+	case PaymentPlanStandard:
+		return "4Gi"
+	case PaymentPlanProfessional:
+		return "8Gi"
+	}
+
+	panic("unreachable")
+}
+
+// ServiceCPULimit is the most cpu a single service can use, as a k8s quantity
+func (plan *PaymentPlan) ServiceCPULimit() string {
+	switch *plan {
+	case PaymentPlanFree:
+		return "1"
+	case PaymentPlanStandard:
+		return "2"
+	case PaymentPlanProfessional:
+		return "4"
+	}
+
+	panic("unreachable")
+}
+
 const StandardPlanEnvLimit = 10
 
 type PaymentProvider interface {
-	SaveSubscription(ctx context.Context, owner, subscriptionID string) error
-	GetOwnerPlan(ctx context.Context, owner string) (PaymentPlan, error)
+	SaveSubscription(ctx context.Context, provider, owner, subscriptionID string) error
+	GetOwnerPlan(ctx context.Context, provider, owner string) (PaymentPlan, error)
 }
@@ -19,6 +19,7 @@ type stripeSubscription struct {
 	CreatedAt      time.Time       `gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt      time.Time       `gorm:"default:CURRENT_TIMESTAMP"`
 	DeletedAt      *gorm.DeletedAt `gorm:"index"`
+	Provider       string          `gorm:"type:varchar(255);not null"`
 	Owner          string          `gorm:"index;type:varchar(255);not null"`
 	SubscriptionID string          `gorm:"index;type:varchar(255);not null"`
 }
@@ -51,8 +52,9 @@ func NewStripePaymentProvider(
 	return &stripePaymentProvider{db, secretKey, standardPlanProductID, professionalPlanProductID, friends, bestFriends}
 }
 
-func (stp *stripePaymentProvider) SaveSubscription(ctx context.Context, owner, subscriptionID string) error {
+func (stp *stripePaymentProvider) SaveSubscription(ctx context.Context, provider, owner, subscriptionID string) error {
 	subscription := stripeSubscription{
+		Provider:       provider,
 		Owner:          owner,
 		SubscriptionID: subscriptionID,
 	}
@@ -69,19 +71,22 @@ func (stp *stripePaymentProvider) SaveSubscription(ctx context.Context, owner, s
 	return nil
 }
 
-func (stp *stripePaymentProvider) GetOwnerPlan(ctx context.Context, owner string) (PaymentPlan, error) {
+func (stp *stripePaymentProvider) GetOwnerPlan(ctx context.Context, provider, owner string) (PaymentPlan, error) {
 	stripe.Key = stp.secretKey
 
-	if _, isBestFriend := stp.bestFriends[owner]; isBestFriend {
-		return PaymentPlanProfessional, nil
-	}
+	// friends are github logins
+	if provider == database.ProviderGitHub {
+		if _, isBestFriend := stp.bestFriends[owner]; isBestFriend {
+			return PaymentPlanProfessional, nil
+		}
 
-	if _, isFriend := stp.friends[owner]; isFriend {
-		return PaymentPlanStandard, nil
+		if _, isFriend := stp.friends[owner]; isFriend {
+			return PaymentPlanStandard, nil
+		}
 	}
 
 	var dbSubs []stripeSubscription
-	err := stp.db.Where("owner = ?", owner).Find(&dbSubs).Error
+	err := stp.db.Where("provider = ? AND owner = ?", provider, owner).Find(&dbSubs).Error
 	if err != nil {
 		return PaymentPlanFree, errors.Wrapf(err, "fail to list %s subscriptions from db", owner)
 	}
@@ -0,0 +1,49 @@
+package prfilters
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/reposettings"
+)
+
+type prFilter struct {
+	Rules json.RawMessage `gorm:"type:jsonb"`
+}
+
+type dbPRFiltersProvider struct {
+	db *database.DB
+}
+
+func NewDBPRFiltersProvider(db *database.DB) *dbPRFiltersProvider {
+	return &dbPRFiltersProvider{db}
+}
+
+func (pfp *dbPRFiltersProvider) Get(ctx context.Context, provider, owner, repo string) (Rules, error) {
+	var filter prFilter
+	found, err := reposettings.Get(ctx, pfp.db.DB, "pr_filters", reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}, &filter)
+	if err != nil || !found {
+		return Rules{}, err
+	}
+
+	var rules Rules
+	err = json.Unmarshal(filter.Rules, &rules)
+	return rules, errors.Wrapf(err, "fail to unmarshal pr filters of %s/%s", owner, repo)
+}
+
+func (pfp *dbPRFiltersProvider) Upsert(ctx context.Context, provider, owner, repo string, rules Rules) error {
+	key := reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}
+	if rules.Empty() {
+		return reposettings.Reset(ctx, pfp.db.DB, "pr_filters", key)
+	}
+
+	rawRules, err := json.Marshal(rules)
+	if err != nil {
+		return errors.Wrap(err, "fail to marshal pr filters")
+	}
+
+	return reposettings.Set(ctx, pfp.db.DB, "pr_filters", key, map[string]interface{}{"rules": string(rawRules)})
+}
@@ -0,0 +1,186 @@
+package prfilters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+)
+
+// Filter matches values against glob patterns, an empty Include lets every value in
+type Filter struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+func (f Filter) empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Rules decide which pull requests of a repo get an environment, the zero value lets all of them in
+type Rules struct {
+	Labels       Filter `json:"labels"`
+	Authors      Filter `json:"authors"`
+	BaseBranches Filter `json:"baseBranches"`
+	// Paths is matched against the files a pull request changes, excluded paths only
+	// keep a pull request out when all of its files are excluded
+	Paths      Filter `json:"paths"`
+	SkipDrafts bool   `json:"skipDrafts"`
+}
+
+type PullRequest struct {
+	Labels       []string
+	Author       string
+	BaseBranch   string
+	Draft        bool
+	ChangedFiles []string
+}
+
+type PRFiltersProvider interface {
+	// Get returns the zero Rules when the repo has none of its own
+	Get(ctx context.Context, provider, owner, repo string) (Rules, error)
+	// Upsert sets the rules of the repo, the zero Rules removes them
+	Upsert(ctx context.Context, provider, owner, repo string, rules Rules) error
+}
+
+// Empty tells whether the rules let every pull request in
+func (r Rules) Empty() bool {
+	return r.Labels.empty() && r.Authors.empty() && r.BaseBranches.empty() && r.Paths.empty() && !r.SkipDrafts
+}
+
+// NeedsChangedFiles tells whether the changed files of a pull request must be listed to evaluate the rules
+func (r Rules) NeedsChangedFiles() bool {
+	return !r.Paths.empty()
+}
+
+// Evaluate tells whether pr gets an environment, when it doesn't the reason says which rule kept it out
+func (r Rules) Evaluate(pr PullRequest) (bool, string) {
+	if r.SkipDrafts && pr.Draft {
+		return false, "pull request is a draft"
+	}
+
+	// patterns are compiled once per evaluation instead of once per value they are matched against
+	labels := compileFilter(r.Labels)
+	authors := compileFilter(r.Authors)
+	baseBranches := compileFilter(r.BaseBranches, '/')
+	paths := compileFilter(r.Paths, '/')
+
+	if !authors.matchesValue(pr.Author) {
+		return false, fmt.Sprintf("author %s is filtered out", pr.Author)
+	}
+
+	if !baseBranches.matchesValue(pr.BaseBranch) {
+		return false, fmt.Sprintf("base branch %s is filtered out", pr.BaseBranch)
+	}
+
+	for _, label := range pr.Labels {
+		if labels.exclude.matches(label) {
+			return false, fmt.Sprintf("label %s is filtered out", label)
+		}
+	}
+
+	if len(labels.include) > 0 && !labels.include.anyMatches(pr.Labels) {
+		return false, "pull request has none of the required labels"
+	}
+
+	if len(paths.include) > 0 && !paths.include.anyMatches(pr.ChangedFiles) {
+		return false, "pull request changes none of the included paths"
+	}
+
+	if len(paths.exclude) > 0 && len(pr.ChangedFiles) > 0 {
+		allExcluded := true
+		for _, file := range pr.ChangedFiles {
+			if !paths.exclude.matches(file) {
+				allExcluded = false
+				break
+			}
+		}
+
+		if allExcluded {
+			return false, "pull request only changes excluded paths"
+		}
+	}
+
+	return true, ""
+}
+
+// Validate checks that every pattern of the rules is a valid glob
+func Validate(rules Rules) error {
+	names := []string{"labels", "authors", "baseBranches", "paths"}
+	for i, filter := range []Filter{rules.Labels, rules.Authors, rules.BaseBranches, rules.Paths} {
+		name := names[i]
+		for _, pattern := range append(append([]string{}, filter.Include...), filter.Exclude...) {
+			if strings.TrimSpace(pattern) == "" {
+				return errors.Errorf("%s has an empty pattern", name)
+			}
+
+			_, err := glob.Compile(pattern, '/')
+			if err != nil {
+				return errors.Wrapf(err, "%s has an invalid pattern %q", name, pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+type globs []glob.Glob
+
+type compiledFilter struct {
+	include globs
+	exclude globs
+}
+
+// compileFilter compiles the patterns of filter with separators, branches and paths use / so * stays
+// within a segment and ** crosses them
+func compileFilter(filter Filter, separators ...rune) compiledFilter {
+	return compiledFilter{
+		include: compileGlobs(filter.Include, separators...),
+		exclude: compileGlobs(filter.Exclude, separators...),
+	}
+}
+
+func compileGlobs(patterns []string, separators ...rune) globs {
+	compiled := make(globs, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern, separators...)
+		if err != nil {
+			// patterns are validated before being saved
+			continue
+		}
+
+		compiled = append(compiled, g)
+	}
+
+	return compiled
+}
+
+func (f compiledFilter) matchesValue(value string) bool {
+	if len(f.include) > 0 && !f.include.matches(value) {
+		return false
+	}
+
+	return !f.exclude.matches(value)
+}
+
+func (gs globs) anyMatches(values []string) bool {
+	for _, value := range values {
+		if gs.matches(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gs globs) matches(value string) bool {
+	for _, g := range gs {
+		if g.Match(value) {
+			return true
+		}
+	}
+
+	return false
+}
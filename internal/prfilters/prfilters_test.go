@@ -0,0 +1,81 @@
+package prfilters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	pr := PullRequest{
+		Labels:       []string{"feature", "area/ui"},
+		Author:       "vieiralucas",
+		BaseBranch:   "main",
+		ChangedFiles: []string{"web/src/app.tsx", "docs/intro.md"},
+	}
+
+	tt := []struct {
+		name  string
+		rules Rules
+		pr    PullRequest
+		want  bool
+	}{
+		{name: "no rules", rules: Rules{}, pr: pr, want: true},
+		{name: "draft skipped", rules: Rules{SkipDrafts: true}, pr: PullRequest{Draft: true}, want: false},
+		{name: "draft allowed", rules: Rules{}, pr: PullRequest{Draft: true}, want: true},
+		{
+			name:  "excluded author",
+			rules: Rules{Authors: Filter{Exclude: []string{"dependabot*"}}},
+			pr:    PullRequest{Author: "dependabot[bot]"},
+			want:  false,
+		},
+		{name: "included author", rules: Rules{Authors: Filter{Include: []string{"vieira*"}}}, pr: pr, want: true},
+		{name: "not included author", rules: Rules{Authors: Filter{Include: []string{"someone"}}}, pr: pr, want: false},
+		{name: "included base branch", rules: Rules{BaseBranches: Filter{Include: []string{"main", "release/*"}}}, pr: pr, want: true},
+		{
+			name:  "not included base branch",
+			rules: Rules{BaseBranches: Filter{Include: []string{"release/*"}}},
+			pr:    pr,
+			want:  false,
+		},
+		{name: "required label", rules: Rules{Labels: Filter{Include: []string{"preview"}}}, pr: pr, want: false},
+		{name: "required label present", rules: Rules{Labels: Filter{Include: []string{"area/*"}}}, pr: pr, want: true},
+		{
+			name:  "excluded label wins over required one",
+			rules: Rules{Labels: Filter{Include: []string{"feature"}, Exclude: []string{"area/ui"}}},
+			pr:    pr,
+			want:  false,
+		},
+		{name: "included path", rules: Rules{Paths: Filter{Include: []string{"web/**"}}}, pr: pr, want: true},
+		{name: "not included path", rules: Rules{Paths: Filter{Include: []string{"api/**"}}}, pr: pr, want: false},
+		{name: "some paths excluded", rules: Rules{Paths: Filter{Exclude: []string{"docs/**"}}}, pr: pr, want: true},
+		{
+			name:  "all paths excluded",
+			rules: Rules{Paths: Filter{Exclude: []string{"docs/**", "**.md"}}},
+			pr:    PullRequest{ChangedFiles: []string{"docs/intro.md", "README.md"}},
+			want:  false,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, reason := tc.rules.Evaluate(tc.pr)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.want, reason == "")
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Validate(Rules{}))
+	assert.NoError(t, Validate(Rules{Paths: Filter{Include: []string{"web/**", "*.go"}}}))
+	assert.Error(t, Validate(Rules{Labels: Filter{Include: []string{""}}}))
+	assert.Error(t, Validate(Rules{Paths: Filter{Exclude: []string{"[docs"}}}))
+}
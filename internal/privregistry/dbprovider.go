@@ -19,6 +19,7 @@ type privateRegistry struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deletedAt"`
+	GitProvider string
 	Owner       string
 	URL         string
 	Provider    string
@@ -36,6 +37,7 @@ func NewDBPrivRegistryProvider(db *database.DB, secret string) *dbPrivRegistryPr
 
 func (prp *dbPrivRegistryProvider) FetchCreds(
 	ctx context.Context,
+	gitProvider string,
 	owner string,
 	image string,
 ) (*RegistryCreds, error) {
@@ -45,7 +47,7 @@ func (prp *dbPrivRegistryProvider) FetchCreds(
 	}
 
 	var registry privateRegistry
-	err = prp.db.First(&registry, map[string]string{"owner": owner, "url": url}).Error
+	err = prp.db.First(&registry, map[string]string{"git_provider": gitProvider, "owner": owner, "url": url}).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, ErrRegistryNotFound
 	}
@@ -84,6 +86,7 @@ func (prp *dbPrivRegistryProvider) getToken(registry privateRegistry) (string, e
 
 func (prp *dbPrivRegistryProvider) StoreRegistry(
 	ctx context.Context,
+	gitProvider string,
 	owner string,
 	url string,
 	provider string,
@@ -95,6 +98,7 @@ func (prp *dbPrivRegistryProvider) StoreRegistry(
 	}
 
 	registry := privateRegistry{
+		GitProvider: gitProvider,
 		Owner:       owner,
 		URL:         url,
 		Provider:    provider,
@@ -104,10 +108,15 @@ func (prp *dbPrivRegistryProvider) StoreRegistry(
 	return errors.Wrap(err, "fail to save registry to db")
 }
 
-func (prp *dbPrivRegistryProvider) ListCredsByOwner(ctx context.Context, owner string, skipToken bool) ([]RegistryCreds, error) {
+func (prp *dbPrivRegistryProvider) ListCredsByOwner(
+	ctx context.Context,
+	gitProvider string,
+	owner string,
+	skipToken bool,
+) ([]RegistryCreds, error) {
 	creds := make([]RegistryCreds, 0)
 	var privRegistries []privateRegistry
-	err := prp.db.Find(&privRegistries, map[string]string{"owner": owner}).Error
+	err := prp.db.Find(&privRegistries, map[string]string{"git_provider": gitProvider, "owner": owner}).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return creds, nil
 	}
@@ -16,9 +16,11 @@ type RegistryCreds struct {
 	Token    string    `json:"-"`
 }
 
+// PrivRegistryProvider keeps the registries of owners, gitProvider is where the owner is hosted,
+// provider is the kind of registry
 type PrivRegistryProvider interface {
-	ListCredsByOwner(ctx context.Context, owner string, skipToken bool) ([]RegistryCreds, error)
-	FetchCreds(ctx context.Context, owner, image string) (*RegistryCreds, error)
-	StoreRegistry(ctx context.Context, owner, url, provider, credentials string) error
+	ListCredsByOwner(ctx context.Context, gitProvider, owner string, skipToken bool) ([]RegistryCreds, error)
+	FetchCreds(ctx context.Context, gitProvider, owner, image string) (*RegistryCreds, error)
+	StoreRegistry(ctx context.Context, gitProvider, owner, url, provider, credentials string) error
 	DeleteRegistry(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,75 @@
+package reposettings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Repo is the repository settings belong to, settings tables are keyed by its provider, owner and name
+// since the same owner and name may be someone else on another provider
+type Repo struct {
+	Provider string
+	Owner    string
+	Repo     string
+}
+
+func (r Repo) String() string {
+	return fmt.Sprintf("%s:%s/%s", r.Provider, r.Owner, r.Repo)
+}
+
+// Scope queries the rows of repo in table that are not deleted
+func Scope(ctx context.Context, db *gorm.DB, table string, repo Repo) *gorm.DB {
+	return db.WithContext(ctx).Table(table).
+		Where("provider = ? AND owner = ? AND repo = ? AND deleted_at IS NULL", repo.Provider, repo.Owner, repo.Repo)
+}
+
+// Get loads the setting of repo in table into dest, it returns false when the repo has none
+func Get(ctx context.Context, db *gorm.DB, table string, repo Repo, dest interface{}) (bool, error) {
+	err := Scope(ctx, db, table, repo).Take(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "fail to query %s of %s", table, repo)
+	}
+
+	return true, nil
+}
+
+// Set saves values as the setting of repo in table. Settings tables have an unique constraint
+// on provider, owner and repo so the row of a setting that was reset is brought back instead of created.
+func Set(ctx context.Context, db *gorm.DB, table string, repo Repo, values map[string]interface{}) error {
+	updates := map[string]interface{}{"updated_at": time.Now(), "deleted_at": nil}
+	for k, v := range values {
+		updates[k] = v
+	}
+
+	result := db.WithContext(ctx).Table(table).
+		Where("provider = ? AND owner = ? AND repo = ?", repo.Provider, repo.Owner, repo.Repo).
+		Updates(updates)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "fail to update %s of %s", table, repo)
+	}
+
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	row := map[string]interface{}{"provider": repo.Provider, "owner": repo.Owner, "repo": repo.Repo}
+	for k, v := range values {
+		row[k] = v
+	}
+
+	err := db.WithContext(ctx).Table(table).Create(row).Error
+	return errors.Wrapf(err, "fail to create %s of %s", table, repo)
+}
+
+// Reset deletes the setting of repo in table so the repo goes back to the default
+func Reset(ctx context.Context, db *gorm.DB, table string, repo Repo) error {
+	err := Scope(ctx, db, table, repo).Update("deleted_at", time.Now()).Error
+	return errors.Wrapf(err, "fail to reset %s of %s", table, repo)
+}
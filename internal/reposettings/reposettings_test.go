@@ -0,0 +1,59 @@
+package reposettings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/database"
+)
+
+func TestSettings(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	ctx := context.Background()
+	repo := Repo{Provider: database.ProviderGitHub, Owner: "owner", Repo: "repo"}
+
+	type deployMode struct {
+		Mode string
+	}
+	get := func(repo Repo) (string, bool) {
+		var mode deployMode
+		found, err := Get(ctx, db.DB, "deploy_modes", repo, &mode)
+		require.NoError(t, err)
+		return mode.Mode, found
+	}
+
+	_, found := get(repo)
+	assert.False(t, found)
+
+	require.NoError(t, Set(ctx, db.DB, "deploy_modes", repo, map[string]interface{}{"mode": "update"}))
+	mode, found := get(repo)
+	assert.True(t, found)
+	assert.Equal(t, "update", mode)
+
+	_, found = get(Repo{Provider: database.ProviderGitHub, Owner: "owner", Repo: "other"})
+	assert.False(t, found)
+
+	// the same path on another provider is another repo
+	_, found = get(Repo{Provider: database.ProviderGitLab, Owner: "owner", Repo: "repo"})
+	assert.False(t, found)
+
+	require.NoError(t, Reset(ctx, db.DB, "deploy_modes", repo))
+	_, found = get(repo)
+	assert.False(t, found)
+
+	// the reset row comes back instead of breaking the unique constraint
+	require.NoError(t, Set(ctx, db.DB, "deploy_modes", repo, map[string]interface{}{"mode": "recreate"}))
+	mode, found = get(repo)
+	assert.True(t, found)
+	assert.Equal(t, "recreate", mode)
+
+	var count int64
+	require.NoError(t, db.Table("deploy_modes").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
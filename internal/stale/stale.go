@@ -12,12 +12,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/environments"
+	"github.com/ergomake/ergomake/internal/github/ghapp"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/logger"
 	"github.com/ergomake/ergomake/internal/payment"
+	"github.com/ergomake/ergomake/internal/transformer"
 )
 
 type server struct {
@@ -25,6 +29,7 @@ type server struct {
 	clusterClient        cluster.Client
 	environmentsProvider environments.EnvironmentsProvider
 	paymentProvider      payment.PaymentProvider
+	ghApp                ghapp.GHAppClient
 	frontendURL          string
 	timeoutToStale       time.Duration
 	ingressNamespace     string
@@ -35,6 +40,7 @@ func NewServer(
 	clusterClient cluster.Client,
 	environmentsProvider environments.EnvironmentsProvider,
 	paymentProvider payment.PaymentProvider,
+	ghApp ghapp.GHAppClient,
 	frontendURL string,
 	timeoutToStale time.Duration,
 	ingressNamespace string,
@@ -47,6 +53,7 @@ func NewServer(
 		clusterClient,
 		environmentsProvider,
 		paymentProvider,
+		ghApp,
 		frontendURL,
 		timeoutToStale,
 		ingressNamespace,
@@ -80,35 +87,11 @@ func (s *server) handle(c *gin.Context) {
 	logger.Ctx(c).Info().Interface("env", env).Msg("stale env")
 
 	if env.Status == database.EnvStale {
-		namespace := env.ID.String()
-		for _, svc := range env.Services {
-			err := s.clusterClient.ScaleDeployment(c, namespace, svc.Name, 1)
-			if err != nil {
-				logger.Ctx(c).Err(err).Str("host", host).Str("service", svc.Name).Msg("fail to scale deployment up")
-				c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
-				return
-			}
-			ingress, err := s.clusterClient.GetIngress(c, namespace, svc.Name)
-			if errors.Is(err, cluster.ErrIngressNotFound) {
-				continue
-			}
-
-			if err != nil {
-				logger.Ctx(c).Err(err).Str("host", host).Str("service", svc.Name).Msg("fail to get ingress of service")
-				c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
-				return
-			}
-
-			if len(ingress.Spec.Rules) > 0 {
-				ingress.Spec.Rules[0].Host = svc.Url
-			}
-
-			err = s.clusterClient.UpdateIngress(c, ingress)
-			if err != nil {
-				logger.Ctx(c).Err(err).Str("host", host).Str("service", svc.Name).Msg("fail to get ingress of service")
-				c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
-				return
-			}
+		err := WakeEnvironment(c, s.clusterClient, env)
+		if err != nil {
+			logger.Ctx(c).Err(err).Str("host", host).Msg("fail to wake environment up")
+			c.JSON(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
 		}
 	}
 
@@ -117,18 +100,7 @@ func (s *server) handle(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(log.WithContext(context.Background()), time.Minute*10)
 		defer cancel()
 
-		err := s.clusterClient.WaitDeployments(ctx, env.ID.String())
-		if err != nil {
-			logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to wait deployments")
-			return
-		}
-
-		env.Status = database.EnvSuccess
-		err = s.environmentsProvider.SaveEnvironment(ctx, env)
-		if err != nil {
-			logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to set env status to success")
-			return
-		}
+		MarkAwake(ctx, s.clusterClient, s.environmentsProvider, s.ghApp, s.frontendURL, env)
 	}()
 
 	c.Redirect(
@@ -251,26 +223,29 @@ func (s *server) MonitorStaleServices(ctx context.Context) {
 			continue
 		}
 
-		envsByOwner := make(map[string][]*database.Environment)
+		// owners of the same name on different providers are different owners
+		type ownerKey struct{ provider, owner string }
+		envsByOwner := make(map[ownerKey][]*database.Environment)
 		for _, env := range envs {
 			if !env.PullRequest.Valid {
 				// branch environments never die
 				continue
 			}
 
-			ownerEnvs, ok := envsByOwner[env.Owner]
+			key := ownerKey{env.Provider, env.Owner}
+			ownerEnvs, ok := envsByOwner[key]
 			if !ok {
 				ownerEnvs = make([]*database.Environment, 0)
 			}
 
-			envsByOwner[env.Owner] = append(ownerEnvs, env)
+			envsByOwner[key] = append(ownerEnvs, env)
 		}
 
 		envsToDownscale := []*database.Environment{}
-		for owner, envs := range envsByOwner {
-			plan, err := s.paymentProvider.GetOwnerPlan(ctx, owner)
+		for key, envs := range envsByOwner {
+			plan, err := s.paymentProvider.GetOwnerPlan(ctx, key.provider, key.owner)
 			if err != nil {
-				logger.Ctx(ctx).Err(err).Str("owner", owner).Msg("fail to get owner plan")
+				logger.Ctx(ctx).Err(err).Str("provider", key.provider).Str("owner", key.owner).Msg("fail to get owner plan")
 				continue
 			}
 
@@ -334,6 +309,10 @@ func (s *server) MonitorStaleServices(ctx context.Context) {
 			env.Status = database.EnvStale
 
 			for _, svc := range env.Services {
+				if svc.Job {
+					continue
+				}
+
 				err = s.clusterClient.ScaleDeployment(ctx, ns, svc.Name, 0)
 				if err != nil {
 					logger.Ctx(ctx).Err(err).Str("service", svc.Name).Str("env", ns).
@@ -353,7 +332,7 @@ func (s *server) MonitorStaleServices(ctx context.Context) {
 					continue
 				}
 
-				ingress.Spec.Rules[0].Host = fmt.Sprintf("stale-%s", ingress.Spec.Rules[0].Host)
+				renameIngressHosts(ingress, func(host string) string { return fmt.Sprintf("stale-%s", host) })
 				err = s.clusterClient.UpdateIngress(ctx, ingress)
 				if err != nil {
 					logger.Ctx(ctx).Err(err).Str("service", svc.Name).Str("env", ns).Msg("fail to update ingress to stale environment")
@@ -365,6 +344,98 @@ func (s *server) MonitorStaleServices(ctx context.Context) {
 			if err != nil {
 				logger.Ctx(ctx).Err(err).Str("env", ns).Str("status", string(env.Status)).Msg("fail to update environment status")
 			}
+
+			if env.Status == database.EnvStale && env.GHDeploymentID != 0 {
+				err = s.ghApp.CreateDeploymentStatus(ctx, env.Owner, env.Repo, env.GHDeploymentID, ghapp.DeploymentStatus{
+					State:       ghapp.DeploymentInactive,
+					Description: "Environment was scaled down, visit it to wake it up",
+				})
+				if err != nil {
+					logger.Ctx(ctx).Err(err).Str("env", ns).Msg("fail to deactivate deployment of stale environment")
+				}
+			}
+		}
+	}
+}
+
+// WakeEnvironment scales the services of a stale env back up and routes their hosts to them again
+func WakeEnvironment(ctx context.Context, clusterClient cluster.Client, env *database.Environment) error {
+	namespace := env.ID.String()
+	for _, svc := range env.Services {
+		if svc.Job {
+			continue
+		}
+
+		err := clusterClient.ScaleDeployment(ctx, namespace, svc.Name, 1)
+		if err != nil {
+			return errors.Wrapf(err, "fail to scale deployment %s up", svc.Name)
+		}
+
+		ingress, err := clusterClient.GetIngress(ctx, namespace, svc.Name)
+		if errors.Is(err, cluster.ErrIngressNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "fail to get ingress of service %s", svc.Name)
+		}
+
+		renameIngressHosts(ingress, func(host string) string { return strings.TrimPrefix(host, "stale-") })
+
+		err = clusterClient.UpdateIngress(ctx, ingress)
+		if err != nil {
+			return errors.Wrapf(err, "fail to update ingress of service %s", svc.Name)
+		}
+	}
+
+	return nil
+}
+
+// MarkAwake waits for the deployments of a woken up env to be ready and marks it as successful again
+func MarkAwake(
+	ctx context.Context,
+	clusterClient cluster.Client,
+	environmentsProvider environments.EnvironmentsProvider,
+	ghApp ghapp.GHAppClient,
+	frontendURL string,
+	env *database.Environment,
+) {
+	err := clusterClient.WaitDeployments(ctx, env.ID.String())
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to wait deployments")
+		return
+	}
+
+	env.Status = database.EnvSuccess
+	err = environmentsProvider.SaveEnvironment(ctx, env)
+	if err != nil {
+		logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to set env status to success")
+		return
+	}
+
+	if env.GHDeploymentID != 0 {
+		err = ghApp.CreateDeploymentStatus(ctx, env.Owner, env.Repo, env.GHDeploymentID, ghapp.DeploymentStatus{
+			State:          ghapp.DeploymentSuccess,
+			Description:    "Environment is ready",
+			EnvironmentURL: launcher.EnvironmentURL(transformer.EnvironmentFromDB(env)),
+			LogURL:         fmt.Sprintf("%s/gh/%s/repos/%s/envs/%s", frontendURL, env.Owner, env.Repo, env.ID),
+		})
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Str("env", env.ID.String()).Msg("fail to reactivate deployment of environment")
+		}
+	}
+}
+
+// renameIngressHosts renames the hosts of the rules and of the TLS section so the certificate
+// keeps covering the hosts that are routed
+func renameIngressHosts(ingress *networkingv1.Ingress, rename func(string) string) {
+	for i, rule := range ingress.Spec.Rules {
+		ingress.Spec.Rules[i].Host = rename(rule.Host)
+	}
+
+	for i, tls := range ingress.Spec.TLS {
+		for j, host := range tls.Hosts {
+			ingress.Spec.TLS[i].Hosts[j] = rename(host)
 		}
 	}
 }
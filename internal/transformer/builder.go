@@ -3,13 +3,13 @@ package transformer
 import (
 	"context"
 	"fmt"
-	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/kubernetes/kompose/pkg/kobject"
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -17,13 +17,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
+	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/envvars"
-
-	kpackBuild "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
-	kpackCore "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/ergomake/ergomake/internal/logger"
 )
 
+// buildLogsSize is how many characters of the logs of a failed build are kept
+const buildLogsSize = 4000
+
 type BuildImagesResult struct {
 	FailedJobs []*batchv1.Job
 }
@@ -59,6 +61,51 @@ func (c *gitCompose) computeRepoAndBuildPath(buildPath string, defaultRepo strin
 	return defaultRepo, buildPath
 }
 
+// Builder builds the images of services with one of the database.BuildTool*
+type Builder interface {
+	// Build starts building the image, builders that build in a job return it so it
+	// can be waited for, the others return a nil job
+	Build(ctx context.Context, build *ImageBuild) (*batchv1.Job, error)
+}
+
+// ImageBuild is an image to be built and everything it is built from
+type ImageBuild struct {
+	Tool        string
+	ServiceID   string
+	ServiceName string
+	Image       string
+	// Hash digests the inputs of the build, builds that run in jobs always have one
+	Hash string
+	// SettingsHash digests the inputs of the build that are not files, it is set along with Hash
+	SettingsHash string
+	Repo         string
+	Branch       string
+	// Context is the path of the build context inside of Repo
+	Context string
+	// ContextURL is a remote git context, Repo isn't cloned when it is set
+	ContextURL string
+	// AdditionalContexts are named contexts, local ones are paths inside of Repo
+	AdditionalContexts map[string]string
+	Dockerfile         string
+	Target             string
+	CacheFrom          []string
+	Labels             map[string]string
+	Platforms          []string
+	BuildArgs          map[string]string
+	// Secrets are only given to builders that keep them out of the image
+	Secrets map[string]string
+	// Env is the environment of buildpacks builds
+	Env map[string]string
+}
+
+func (c *gitCompose) makeBuilders(namespace string) map[string]Builder {
+	return map[string]Builder{
+		database.BuildToolKaniko:     &kanikoBuilder{c, namespace},
+		database.BuildToolBuildKit:   &buildkitBuilder{c, namespace},
+		database.BuildToolBuildpacks: &buildpacksBuilder{c, namespace, make(map[string]*string)},
+	}
+}
+
 func (c *gitCompose) buildImages(
 	ctx context.Context,
 	namespace string,
@@ -69,233 +116,424 @@ func (c *gitCompose) buildImages(
 		return nil, errors.Wrap(err, "fail to set env status to building")
 	}
 
+	c.cloneTokenSecrets = make(map[string]*string)
+	c.buildSecretValues = []string{}
+
+	var builds []*ImageBuild
 	if c.isCompose {
-		return c.buildImagesWithKaniko(ctx, namespace)
+		builds, err = c.makeComposeBuilds(ctx)
 	} else {
-		return c.buildImagesWithBuildpacks(ctx, namespace)
+		builds, err = c.makeErgopackBuilds(ctx)
+	}
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (c *gitCompose) buildImagesWithBuildpacks(ctx context.Context, namespace string) (*BuildImagesResult, error) {
-	cloneTokenSecrets := make(map[string]*string)
-	builds := make([]*kpackBuild.Build, 0)
+	builders := c.makeBuilders(namespace)
+	jobs := []*batchv1.Job{}
+	for _, build := range builds {
+		job, err := builders[build.Tool].Build(ctx, build)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to build image of service %s with %s", build.ServiceName, build.Tool)
+		}
 
-	for serviceName, service := range c.environment.Services {
-		if service.Build == "" {
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+
+	return c.waitBuildJobs(ctx, jobs)
+}
+
+func (c *gitCompose) makeComposeBuilds(ctx context.Context) ([]*ImageBuild, error) {
+	builds := []*ImageBuild{}
+	for k, service := range c.komposeObject.ServiceConfigs {
+		if service.Build == "" && service.Dockerfile == "" {
 			continue
 		}
 
-		repo, buildPath := c.computeRepoAndBuildPath(service.Build, c.repo)
-		if repo == c.repo {
-			buildPath, _ = filepath.Rel("/", path.Clean(path.Join("/", ".ergomake", buildPath)))
-		} else {
-			buildPath, _ = filepath.Rel(path.Join("/", repo), path.Clean(path.Join("/", c.repo, ".ergomake", buildPath)))
+		settings := c.builds[k]
+		repo, buildPath := c.repo, ""
+		if settings.ContextURL == "" {
+			repo, buildPath = c.computeRepoAndBuildPath(service.Build, c.repo)
 		}
 
-		cloneTokenSecretName, ok := cloneTokenSecrets[repo]
-		if !ok && !c.isPublic {
-			cloneToken, err := c.gitClient.GetCloneToken(ctx, c.branchOwner, repo)
-			if err != nil {
-				return nil, errors.Wrapf(err, "fail to get clone token for %s/%s", c.branchOwner, repo)
-			}
+		branch, err := c.getBuildBranch(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
 
-			cloneTokenSecret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      strings.ReplaceAll(strings.ToLower(fmt.Sprintf("%s-%s", repo, namespace)), "_", ""),
-					Namespace: "kpack",
-					Annotations: map[string]string{
-						"kpack.io/git": "https://github.com",
-					},
-				},
-				StringData: map[string]string{
-					"username": "x-access-token",
-					"password": cloneToken,
-				},
-				Type: "kubernetes.io/basic-auth",
-			}
+		vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.provider, c.owner, repo, branch)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to list env vars by repo")
+		}
 
-			err = c.clusterClient.CreateSecret(ctx, cloneTokenSecret)
-			if err != nil {
-				return nil, errors.Wrap(err, "fail to add github token secret into cluster")
-			}
+		additionalContexts, err := c.resolveAdditionalContexts(settings.AdditionalContexts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to resolve additional contexts of service %s", k)
+		}
 
-			cloneTokenSecretName = pointer.String(cloneTokenSecret.GetName())
-			cloneTokenSecrets[repo] = cloneTokenSecretName
+		build := &ImageBuild{
+			Tool:               settings.Tool,
+			ServiceID:          c.environment.Services[k].ID,
+			ServiceName:        k,
+			Repo:               repo,
+			Branch:             branch,
+			Context:            buildPath,
+			ContextURL:         settings.ContextURL,
+			AdditionalContexts: additionalContexts,
+			Dockerfile:         service.Dockerfile,
+			Target:             settings.Target,
+			CacheFrom:          settings.CacheFrom,
+			Labels:             settings.Labels,
+			Platforms:          settings.Platforms,
 		}
+		build.BuildArgs = makeBuildArgs(service.BuildArgs, vars)
+		build.Secrets = makeBuildSecrets(vars)
+		c.buildSecretValues = append(c.buildSecretValues, envvars.BuildSecretValues(vars)...)
 
-		branch := c.branch
-		branchExists, err := c.gitClient.DoesBranchExist(ctx, c.owner, repo, branch, c.branchOwner)
+		reused, err := c.prepareJobBuild(ctx, build)
 		if err != nil {
-			return nil, errors.Wrapf(err, "fail to check if branch %s for repo %s/%s exists", branch, c.branchOwner, repo)
+			return nil, err
 		}
-		if !branchExists {
-			defaultBranch, err := c.gitClient.GetDefaultBranch(ctx, c.owner, repo, c.branchOwner)
-			if err != nil {
-				return nil, errors.Wrapf(err, "fail to get default branch for repo %s/%s", c.branchOwner, repo)
-			}
-			branch = defaultBranch
+
+		if !reused {
+			builds = append(builds, build)
 		}
+	}
 
-		svcAcc := &corev1.ServiceAccount{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      service.ID,
-				Namespace: "kpack",
-			},
-			Secrets:          []corev1.ObjectReference{{Name: "kpack-registry-credentials"}},
-			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "kpack-registry-credentials"}},
+	return builds, nil
+}
+
+func (c *gitCompose) makeErgopackBuilds(ctx context.Context) ([]*ImageBuild, error) {
+	builds := []*ImageBuild{}
+	for serviceName, service := range c.environment.Services {
+		if service.Build == "" {
+			continue
 		}
-		if cloneTokenSecretName != nil {
-			svcAcc.Secrets = append(svcAcc.Secrets, corev1.ObjectReference{Name: *cloneTokenSecretName})
+
+		repo, buildPath := c.computeRepoAndBuildPath(service.Build, c.repo)
+		if repo == c.repo {
+			buildPath, _ = filepath.Rel("/", path.Clean(path.Join("/", ".ergomake", buildPath)))
+		} else {
+			buildPath, _ = filepath.Rel(path.Join("/", repo), path.Clean(path.Join("/", c.repo, ".ergomake", buildPath)))
 		}
 
-		err = c.clusterClient.CreateServiceAccount(ctx, svcAcc)
+		branch, err := c.getBuildBranch(ctx, repo)
 		if err != nil {
-			return nil, errors.Wrapf(err, "fail to create service account to build service %s", service.ID)
+			return nil, err
 		}
 
-		vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.owner, repo, branch)
+		vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.provider, c.owner, repo, branch)
 		if err != nil {
 			return nil, errors.Wrap(err, "fail to list env vars by repo")
 		}
 
-		addedVariables := make(map[string]struct{})
-		envs := []corev1.EnvVar{}
-		for _, v := range vars {
-			envs = append(envs, corev1.EnvVar{
-				Name:  v.Name,
-				Value: v.Value,
-				// TODO: use ValueFrom and store the vars in a secret
-			})
-			addedVariables[v.Name] = struct{}{}
+		settings := c.builds[serviceName]
+		build := &ImageBuild{
+			Tool:        settings.Tool,
+			ServiceID:   service.ID,
+			ServiceName: serviceName,
+			Image:       service.Image,
+			Repo:        repo,
+			Branch:      branch,
+			Context:     buildPath,
+			Dockerfile:  settings.Dockerfile,
+			Target:      settings.Target,
 		}
 
-		for k, v := range service.Env {
-			if _, ok := addedVariables[k]; ok {
-				continue
+		build.Secrets = makeBuildSecrets(vars)
+		c.buildSecretValues = append(c.buildSecretValues, envvars.BuildSecretValues(vars)...)
+
+		if build.Tool == database.BuildToolBuildpacks {
+			// kpack reads the secrets from a Secret, the rest of the vars are plain values
+			build.Env = make(map[string]string)
+			for k, v := range service.Env {
+				build.Env[k] = v
+			}
+			for _, v := range vars {
+				if !v.BuildSecret {
+					build.Env[v.Name] = v.Value
+				}
 			}
 
-			envs = append(envs, corev1.EnvVar{Name: k, Value: v})
+			builds = append(builds, build)
+			continue
 		}
 
-		labels := c.getLabels(service.ID, serviceName)
-		build := &kpackBuild.Build{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "Build",
-				APIVersion: "kpack.io/v1alpha2",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        service.ID,
-				Namespace:   "kpack",
-				Labels:      labels,
-				Annotations: labels,
-			},
-			Spec: kpackBuild.BuildSpec{
-				Builder: kpackCore.BuildBuilderSpec{
-					Image: "ergomake/kpack-builder",
-				},
-				RunImage: kpackBuild.BuildSpecImage{
-					Image: "paketobuildpacks/run-jammy-base",
-				},
-				ServiceAccountName: svcAcc.GetName(),
-				Source: kpackCore.SourceConfig{
-					Git: &kpackCore.Git{
-						URL:      fmt.Sprintf("https://github.com/%s/%s", c.branchOwner, repo),
-						Revision: branch,
-					},
-					SubPath: buildPath,
-				},
-				Tags: []string{service.Image},
-				Env:  envs,
-				Tolerations: []corev1.Toleration{
-					{
-						Key:      "preview.ergomake.dev/domain",
-						Operator: corev1.TolerationOpEqual,
-						Value:    "build",
-						Effect:   corev1.TaintEffectNoSchedule,
-					},
-				},
-				NodeSelector: map[string]string{
-					"preview.ergomake.dev/role": "build",
-				},
-			},
+		build.BuildArgs = makeBuildArgs(nil, vars)
+		reused, err := c.prepareJobBuild(ctx, build)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reused {
+			builds = append(builds, build)
+		}
+	}
+
+	return builds, nil
+}
+
+// resolveAdditionalContexts turns local additional contexts, which are relative to the compose file,
+// into paths of the repository, validation made sure they are in the same repository as the build
+func (c *gitCompose) resolveAdditionalContexts(contexts map[string]string) (map[string]string, error) {
+	if len(contexts) == 0 {
+		return nil, nil
+	}
+
+	resolved := make(map[string]string)
+	for name, ctx := range contexts {
+		if !isLocalContext(ctx) {
+			resolved[name] = ctx
+			continue
 		}
 
-		builds = append(builds, build)
+		rel, err := filepath.Rel(c.projectPath, filepath.Clean(path.Join(path.Dir(c.configFilePath), ctx)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to make additional context %s relative to the repository", name)
+		}
+
+		resolved[name] = rel
 	}
 
-	err := c.clusterClient.ApplyKPackBuilds(ctx, builds)
+	return resolved, nil
+}
+
+// getBuildBranch is the branch the images of repo are built from, repos other than the one
+// of the environment may not have the branch and are built from their default branch then
+func (c *gitCompose) getBuildBranch(ctx context.Context, repo string) (string, error) {
+	branchExists, err := c.gitClient.DoesBranchExist(ctx, c.owner, repo, c.branch, c.branchOwner)
 	if err != nil {
-		return nil, errors.Wrap(err, "fail to apply kpack build")
+		return "", errors.Wrapf(err, "fail to check if branch %s for repo %s/%s exists", c.branch, c.branchOwner, repo)
+	}
+
+	if branchExists {
+		return c.branch, nil
 	}
 
-	return &BuildImagesResult{}, nil
+	defaultBranch, err := c.gitClient.GetDefaultBranch(ctx, c.owner, repo, c.branchOwner)
+	return defaultBranch, errors.Wrapf(err, "fail to get default branch for repo %s/%s", c.branchOwner, repo)
 }
 
-func (c *gitCompose) buildImagesWithKaniko(ctx context.Context, namespace string) (*BuildImagesResult, error) {
-	cloneTokenSecrets := make(map[string]*string)
-	jobs := []*batchv1.Job{}
-	for k, service := range c.komposeObject.ServiceConfigs {
-		if service.Build == "" && service.Dockerfile == "" {
-			continue
+// prepareJobBuild hashes the inputs of a build that runs in a job and reuses the image built from the
+// same inputs, or from files the pull request doesn't change, when there is one, otherwise the build
+// gets the image to push
+func (c *gitCompose) prepareJobBuild(ctx context.Context, build *ImageBuild) (bool, error) {
+	buildHash, err := c.buildHash(build)
+	if err != nil {
+		return false, errors.Wrapf(err, "fail to hash build inputs of service %s", build.ServiceName)
+	}
+	build.Hash = buildHash
+	build.SettingsHash = buildSettingsHash(build)
+
+	image, ok := c.reusableImage(build.ServiceName, buildHash)
+	if !ok {
+		image, ok, err = c.findBuiltImage(ctx, buildHash)
+		if err != nil {
+			return false, errors.Wrapf(err, "fail to find built image of service %s", build.ServiceName)
 		}
+	}
+	if ok {
+		return true, c.reuseImage(build, image, buildHash)
+	}
 
-		repo, buildPath := c.computeRepoAndBuildPath(service.Build, c.repo)
+	if c.isBuildUnchanged(build) {
+		service, ok, err := c.findUnchangedImage(ctx, build)
+		if err != nil {
+			return false, errors.Wrapf(err, "fail to find unchanged image of service %s", build.ServiceName)
+		}
 
-		cloneTokenSecretName, ok := cloneTokenSecrets[repo]
-		if !ok && !c.isPublic {
-			cloneToken, err := c.gitClient.GetCloneToken(ctx, c.branchOwner, repo)
+		if ok {
+			// the hash is the one of the inputs the image was really built from
+			return true, c.reuseImage(build, service.Image, service.BuildHash)
+		}
+	}
+
+	// images are tagged by their build hash so that any environment built from the same inputs can reuse them
+	build.Image = makeBuildImage(buildHash)
+	c.setServiceImage(build.ServiceName, build.Image)
+
+	err = c.updateServiceBuild(build.ServiceID, build.Image, buildHash, build.SettingsHash, "building")
+	return false, errors.Wrapf(err, "fail to save build of service %s", build.ServiceName)
+}
+
+func (c *gitCompose) waitBuildJobs(ctx context.Context, jobs []*batchv1.Job) (*BuildImagesResult, error) {
+	if len(jobs) == 0 {
+		return &BuildImagesResult{}, nil
+	}
+
+	jobCtx, cancelFn := context.WithTimeout(ctx, time.Hour)
+	defer cancelFn()
+
+	// jobs are waited for one by one so each build is reported as soon as it finishes
+	var mu sync.Mutex
+	var progressMu sync.Mutex
+	var wg sync.WaitGroup
+	failed := []*batchv1.Job{}
+	errs := make([]error, len(jobs))
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job *batchv1.Job) {
+			defer wg.Done()
+
+			result, err := c.clusterClient.WaitJobs(jobCtx, []*batchv1.Job{job})
 			if err != nil {
-				return nil, errors.Wrapf(err, "fail to get clone token for %s/%s", c.branchOwner, repo)
+				errs[i] = errors.Wrapf(err, "fail to wait for build job %s to complete", job.GetName())
+				return
 			}
 
-			cloneTokenSecret := makeCloneTokenSecret(namespace, repo, cloneToken)
-			err = c.clusterClient.CreateSecret(ctx, cloneTokenSecret)
+			err = c.saveBuildResult(ctx, job, result)
 			if err != nil {
-				return nil, errors.Wrap(err, "fail to add github token secret into cluster")
+				errs[i] = err
+				return
 			}
 
-			cloneTokenSecretName = pointer.String(cloneTokenSecret.GetName())
-			cloneTokenSecrets[repo] = cloneTokenSecretName
-		}
+			mu.Lock()
+			failed = append(failed, result.Failed...)
+			mu.Unlock()
 
-		branch := c.branch
-		branchExists, err := c.gitClient.DoesBranchExist(ctx, c.owner, repo, branch, c.branchOwner)
-		if err != nil {
-			return nil, errors.Wrapf(err, "fail to check if branch %s for repo %s/%s exists", branch, c.branchOwner, repo)
-		}
-		if !branchExists {
-			defaultBranch, err := c.gitClient.GetDefaultBranch(ctx, c.owner, repo, c.branchOwner)
-			if err != nil {
-				return nil, errors.Wrapf(err, "fail to get default branch for repo %s/%s", c.branchOwner, repo)
+			if c.onBuildProgress != nil {
+				// one at a time so what is reported doesn't go back to an older progress
+				progressMu.Lock()
+				c.onBuildProgress(ctx)
+				progressMu.Unlock()
 			}
-			branch = defaultBranch
-		}
+		}(i, job)
+	}
+	wg.Wait()
 
-		vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.owner, repo, branch)
+	for _, err := range errs {
 		if err != nil {
-			return nil, errors.Wrap(err, "fail to list env vars by repo")
+			return nil, err
 		}
+	}
 
-		spec := c.makeJobSpec(c.environment.Services[k].ID, k, service, buildPath, vars)
-		spec.Spec.Template.Spec.InitContainers = []corev1.Container{
-			c.makeInitContainer(spec, c.branchOwner, repo, branch, cloneTokenSecretName),
-		}
+	return &BuildImagesResult{failed}, nil
+}
+
+// getJobCloneTokenSecret makes the secret build jobs clone repo with, once per repo
+func (c *gitCompose) getJobCloneTokenSecret(ctx context.Context, namespace, repo string) (*string, error) {
+	if c.isPublic {
+		return nil, nil
+	}
+
+	if name, ok := c.cloneTokenSecrets[repo]; ok {
+		return name, nil
+	}
+
+	cloneToken, err := c.gitClient.GetCloneToken(ctx, c.branchOwner, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to get clone token for %s/%s", c.branchOwner, repo)
+	}
+
+	cloneTokenSecret := makeCloneTokenSecret(namespace, repo, cloneToken)
+	err = c.clusterClient.CreateSecret(ctx, cloneTokenSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to add github token secret into cluster")
+	}
+
+	name := pointer.String(cloneTokenSecret.GetName())
+	c.cloneTokenSecrets[repo] = name
+
+	return name, nil
+}
+
+// saveBuildResult records how the build job of a service ended, failed builds keep the tail of their logs
+func (c *gitCompose) saveBuildResult(ctx context.Context, job *batchv1.Job, result *cluster.WaitJobsResult) error {
+	updates := map[string]interface{}{"build_status": "build-success"}
+	if len(result.Failed) > 0 {
+		updates["build_status"] = "build-failed"
 
-		job, err := c.clusterClient.CreateJob(ctx, spec)
+		logs, err := c.clusterClient.GetJobLogs(ctx, job, buildLogsSize)
 		if err != nil {
-			return nil, errors.Wrapf(err, "fail to create build job for service %s", k)
+			logger.Ctx(ctx).Err(err).Str("job", job.GetName()).Msg("fail to get build job logs")
+			logs = "logs are not available"
 		}
-		jobs = append(jobs, job)
+		updates["build_logs"] = strings.TrimSpace(envvars.Redact(logs, c.buildSecretValues))
+	} else if len(result.Succeeded) == 0 {
+		return nil
 	}
 
-	jobCtx, cancelFn := context.WithTimeout(ctx, time.Hour)
-	defer cancelFn()
-	result, err := c.clusterClient.WaitJobs(jobCtx, jobs)
+	err := c.db.Model(&database.Service{}).
+		Where("id = ?", job.GetLabels()["preview.ergomake.dev/id"]).
+		Updates(updates).Error
+
+	return errors.Wrapf(err, "fail to save build status of job %s", job.GetName())
+}
+
+// updateServiceBuild records which image the service runs and the inputs it was built from,
+// the next update of the environment reuses it when the inputs are the same
+func (c *gitCompose) updateServiceBuild(serviceID, image, buildHash, settingsHash, buildStatus string) error {
+	return c.db.Model(&database.Service{}).Where("id = ?", serviceID).Updates(map[string]interface{}{
+		"image":               image,
+		"build_hash":          buildHash,
+		"build_settings_hash": settingsHash,
+		"build_status":        buildStatus,
+	}).Error
+}
+
+// reuseImage makes the service run an image built by another deploy from the inputs of buildHash,
+// the settings of the build are always the same as the ones of the image
+func (c *gitCompose) reuseImage(build *ImageBuild, image, buildHash string) error {
+	c.setServiceImage(build.ServiceName, image)
+
+	service := c.environment.Services[build.ServiceName]
+	service.BuildReused = true
+	c.environment.Services[build.ServiceName] = service
+
+	err := c.db.Model(&database.Service{}).Where("id = ?", build.ServiceID).Updates(map[string]interface{}{
+		"image":               image,
+		"build_hash":          buildHash,
+		"build_settings_hash": build.SettingsHash,
+		"build_status":        "build-success",
+		"build_reused":        true,
+	}).Error
+	return errors.Wrapf(err, "fail to reuse image of service %s", build.ServiceName)
+}
+
+// findBuiltImage looks for an image successfully built from buildHash by any environment of the branch,
+// terminated ones included as long as their image is still in the registry
+func (c *gitCompose) findBuiltImage(ctx context.Context, buildHash string) (string, bool, error) {
+	image := makeBuildImage(buildHash)
+
+	var count int64
+	err := c.db.WithContext(ctx).Unscoped().Model(&database.Service{}).
+		Joins("JOIN environments ON environments.id = services.environment_id").
+		Where("environments.owner = ? AND environments.repo = ? AND environments.branch = ?", c.owner, c.repo, c.branch).
+		Where("services.build_hash = ? AND services.build_status = ? AND services.image = ?", buildHash, "build-success", image).
+		Count(&count).Error
 	if err != nil {
-		return nil, errors.Wrapf(err, "fail to wait for build jobs to complete")
+		return "", false, errors.Wrap(err, "fail to query services built from the same inputs")
 	}
 
-	return &BuildImagesResult{result.Failed}, nil
+	if count == 0 {
+		return "", false, nil
+	}
+
+	return image, c.isInRegistry(ctx, image), nil
+}
+
+// isInRegistry tells whether image can still be pulled, the registry drops images that are not used anymore
+func (c *gitCompose) isInRegistry(ctx context.Context, image string) bool {
+	exists, err := c.imageExists(ctx, image)
+	if err != nil {
+		// building again is always safe, reusing an image that is gone is not
+		logger.Ctx(ctx).Err(err).Str("image", image).Msg("fail to check if built image is in the registry")
+		return false
+	}
+
+	return exists
+}
+
+// makeBuildImage is the image of the build, the whole hash is in its tag so it can't collide with other builds
+func makeBuildImage(buildHash string) string {
+	return fmt.Sprintf("%s:%s", userlandRegistry, buildHash)
+}
+
+// makeCacheRepo is the repository kaniko keeps the cached layers of the builds of a repo in
+func makeCacheRepo(owner, repo string) string {
+	return strings.ToLower(fmt.Sprintf("%s/cache/%s/%s", userlandRegistry, owner, repo))
 }
 
 func makeCloneTokenSecret(namespace, repo, token string) *corev1.Secret {
@@ -310,47 +548,71 @@ func makeCloneTokenSecret(namespace, repo, token string) *corev1.Secret {
 	}
 }
 
-func (c *gitCompose) makeJobSpec(serviceID string, serviceName string, service kobject.ServiceConfig, buildPath string, vars []envvars.EnvVar) *batchv1.Job {
-
-	buildArgsSet := make(map[string]struct{})
+// makeBuildArgs merges the build args of the service with the variables of the repo, the ones of the service win.
+// Build secrets are left out unless they are allowed as build args.
+func makeBuildArgs(serviceArgs map[string]*string, vars []envvars.EnvVar) map[string]string {
+	buildArgs := make(map[string]string)
+	for _, v := range vars {
+		if v.IsBuildArg() {
+			buildArgs[v.Name] = v.Value
+		}
+	}
 
-	buildArgs := []string{}
-	for k, v := range service.BuildArgs {
+	for k, v := range serviceArgs {
 		if v == nil {
 			continue
 		}
 
-		buildArgs = append(buildArgs, "--build-arg", fmt.Sprintf("%s=%s", k, *v))
-		buildArgsSet[k] = struct{}{}
+		buildArgs[k] = *v
 	}
 
+	return buildArgs
+}
+
+func makeBuildSecrets(vars []envvars.EnvVar) map[string]string {
+	secrets := make(map[string]string)
 	for _, v := range vars {
-		if _, ok := buildArgsSet[v.Name]; ok {
-			continue
+		if v.BuildSecret {
+			secrets[v.Name] = v.Value
 		}
+	}
 
-		buildArgs = append(buildArgs, "--build-arg", fmt.Sprintf("%s=%s", v.Name, v.Value))
+	return secrets
+}
+
+// sortedKeys is used to render maps into args that don't change from one build to the next
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 
-	args := append([]string{
-		"--context=dir:///workspace",
-		fmt.Sprintf("--dockerfile=%s", service.Dockerfile),
-		fmt.Sprintf("--context-sub-path=%s", buildPath),
-		"--destination=" + service.Image,
-		"--use-new-run",
-		"--cleanup",
-		"--snapshot-mode=redo",
-	}, buildArgs...)
+	return keys
+}
 
-	if insecureRegistry != "" {
-		// get the hostname and port from Image using stdlib
-		args = append(args, fmt.Sprintf("--insecure-registry=%s", insecureRegistry))
+// makeBuildJob makes the job that clones the repo of the build into /workspace and builds it with container
+func (c *gitCompose) makeBuildJob(build *ImageBuild, container corev1.Container, cloneTokenSecretName *string) *batchv1.Job {
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "workspace",
+		MountPath: "/workspace",
+	})
+	container.ImagePullPolicy = "IfNotPresent"
+	container.Resources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("7Gi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("7Gi"),
+		},
 	}
 
-	labels := c.getLabels(serviceID, serviceName)
+	labels := c.getLabels(build.ServiceID, build.ServiceName)
+	labels[cluster.BuildEnvironmentLabel] = c.dbEnvironment.ID.String()
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        serviceID,
+			// the id is kept across updates, the previous build job may still be around
+			Name:        fmt.Sprintf("%s-%s", build.ServiceID, build.Hash[:jobNameHashLength]),
 			Namespace:   "preview-builds",
 			Labels:      labels,
 			Annotations: labels,
@@ -366,29 +628,8 @@ func (c *gitCompose) makeJobSpec(serviceID string, serviceName string, service k
 					Annotations: labels,
 				},
 				Spec: corev1.PodSpec{
-					ImagePullSecrets: []corev1.LocalObjectReference{{Name: c.dockerhubPullSecretName}},
-					Containers: []corev1.Container{
-						{
-							Name:  serviceID,
-							Image: "gcr.io/kaniko-project/executor:latest",
-							Args:  args,
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "workspace",
-									MountPath: "/workspace",
-								},
-							},
-							ImagePullPolicy: "IfNotPresent",
-							Resources: corev1.ResourceRequirements{
-								Limits: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("7Gi"),
-								},
-								Requests: corev1.ResourceList{
-									corev1.ResourceMemory: resource.MustParse("7Gi"),
-								},
-							},
-						},
-					},
+					ImagePullSecrets:   []corev1.LocalObjectReference{{Name: c.dockerhubPullSecretName}},
+					Containers:         []corev1.Container{container},
 					ServiceAccountName: "preview-builder",
 					RestartPolicy:      corev1.RestartPolicyNever,
 					Volumes: []corev1.Volume{
@@ -416,8 +657,11 @@ func (c *gitCompose) makeJobSpec(serviceID string, serviceName string, service k
 		},
 	}
 
-	if os.Getenv("CLUSTER") == "eks" {
-		appendUserlandCreds(job)
+	// remote contexts are fetched by the builder itself
+	if build.ContextURL == "" {
+		job.Spec.Template.Spec.InitContainers = []corev1.Container{
+			c.makeInitContainer(job, c.branchOwner, build.Repo, build.Branch, cloneTokenSecretName),
+		}
 	}
 
 	return job
@@ -494,10 +738,11 @@ func (c *gitCompose) makeInitContainer(
 	}
 }
 
-func appendUserlandCreds(job *batchv1.Job) {
+// appendUserlandCreds mounts the credentials of the userland registry where the builder of job reads them
+func appendUserlandCreds(job *batchv1.Job, mountPath string) {
 	dockerConfigVolumeMount := corev1.VolumeMount{
 		Name:      "docker-config",
-		MountPath: "/kaniko/.docker/",
+		MountPath: mountPath,
 	}
 	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(job.Spec.Template.Spec.Containers[0].VolumeMounts, dockerConfigVolumeMount)
 
@@ -1,9 +1,19 @@
 package transformer
 
 import (
+	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/envvars"
+	gitMock "github.com/ergomake/ergomake/mocks/git"
 )
 
 func TestGitCompose_computeRepoAndBuildPath(t *testing.T) {
@@ -81,3 +91,257 @@ func TestGitCompose_computeRepoAndBuildPath(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeBuildImage(t *testing.T) {
+	t.Parallel()
+
+	hash := "4c1e0a7cbd5e3e5c0b2c1d6f8f0e9a3b7d2c4e6f8a0b1c3d5e7f9a1b3c5d7e9f"
+	assert.Equal(t, userlandRegistry+":"+hash, makeBuildImage(hash))
+}
+
+func TestMakeCacheRepo(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, userlandRegistry+"/cache/ergomake/my-repo", makeCacheRepo("ErgoMake", "My-Repo"))
+}
+
+func TestGitCompose_makeBuildKitJob(t *testing.T) {
+	t.Parallel()
+
+	gitClient := gitMock.NewRemoteGitClient(t)
+	gitClient.EXPECT().GetCloneUrl().Return("https://github.com")
+	gitClient.EXPECT().GetCloneParams().Return([]string{})
+
+	c := &gitCompose{
+		owner:         "owner",
+		branchOwner:   "owner",
+		repo:          "repo",
+		gitClient:     gitClient,
+		dbEnvironment: &database.Environment{ID: uuid.New()},
+	}
+	job := c.makeBuildKitJob(&ImageBuild{
+		Tool:        database.BuildToolBuildKit,
+		ServiceID:   "id",
+		ServiceName: "api",
+		Image:       "registry/image:hash",
+		Hash:        "0123456789abcdef",
+		Repo:        "repo",
+		Branch:      "main",
+		Context:     "api",
+		Dockerfile:  "docker/Dockerfile",
+		Target:      "prod",
+		BuildArgs:   map[string]string{"B": "2", "A": "1"},
+		Secrets:     map[string]string{"TOKEN": "secret"},
+	}, nil, pointer.String("id-secrets"))
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"buildctl-daemonless.sh"}, container.Command)
+	assert.Subset(t, container.Args, []string{
+		"--local", "context=/workspace/api",
+		"dockerfile=/workspace/api/docker",
+		"filename=Dockerfile",
+		"target=prod",
+		"build-arg:A=1",
+		"build-arg:B=2",
+		"--secret", "id=TOKEN,src=/run/secrets/TOKEN",
+	})
+	for _, arg := range container.Args {
+		assert.NotContains(t, arg, "secret=")
+	}
+	for _, env := range container.Env {
+		assert.NotEqual(t, "secret", env.Value)
+	}
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "build-secrets", MountPath: "/run/secrets", ReadOnly: true})
+	assert.Contains(t, job.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         "build-secrets",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "id-secrets"}},
+	})
+	assert.Equal(t, "unconfined", job.Spec.Template.Annotations["container.apparmor.security.beta.kubernetes.io/id"])
+	assert.NotContains(t, job.Labels, "container.apparmor.security.beta.kubernetes.io/id")
+}
+
+func TestMakeBuildArgsAndSecrets(t *testing.T) {
+	t.Parallel()
+
+	vars := []envvars.EnvVar{
+		{Name: "PLAIN", Value: "plain"},
+		{Name: "NPM_TOKEN", Value: "npm", BuildSecret: true},
+		{Name: "LICENSE_KEY", Value: "license", BuildSecret: true, AllowBuildArg: true},
+	}
+
+	assert.Equal(t, map[string]string{
+		"PLAIN":       "service",
+		"LICENSE_KEY": "license",
+	}, makeBuildArgs(map[string]*string{"PLAIN": pointer.String("service"), "UNSET": nil}, vars))
+	assert.Equal(t, map[string]string{
+		"NPM_TOKEN":   "npm",
+		"LICENSE_KEY": "license",
+	}, makeBuildSecrets(vars))
+}
+
+func TestMakeBuildSecret(t *testing.T) {
+	t.Parallel()
+
+	build := &ImageBuild{ServiceID: "id", Secrets: map[string]string{"NPM_TOKEN": "npm"}}
+	secret := makeBuildSecret("preview-builds", "env-id", build)
+	assert.Equal(t, "preview-builds", secret.GetNamespace())
+	assert.Equal(t, map[string]string{"preview.ergomake.dev/environment": "env-id"}, secret.GetLabels())
+	assert.Equal(t, map[string][]byte{"NPM_TOKEN": []byte("npm")}, secret.Data)
+	assert.Equal(t, secret.GetName(), makeBuildSecret("preview-builds", "env-id", build).GetName())
+
+	build.Secrets["NPM_TOKEN"] = "rotated"
+	assert.NotEqual(t, secret.GetName(), makeBuildSecret("preview-builds", "env-id", build).GetName())
+}
+
+func TestMakeKanikoGitContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		contextURL string
+		context    string
+		subPath    string
+	}{
+		{"https://github.com/ergomake/api.git", "git://github.com/ergomake/api.git", ""},
+		{"https://github.com/ergomake/api.git#main", "git://github.com/ergomake/api.git#refs/heads/main", ""},
+		{"github.com/ergomake/api#refs/tags/v1:server", "git://github.com/ergomake/api#refs/tags/v1", "server"},
+	}
+
+	for _, tc := range testCases {
+		context, subPath := makeKanikoGitContext(tc.contextURL)
+		assert.Equal(t, tc.context, context, tc.contextURL)
+		assert.Equal(t, tc.subPath, subPath, tc.contextURL)
+	}
+}
+
+func TestGitCompose_makeKanikoJob(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		branchOwner:   "owner",
+		repo:          "repo",
+		dbEnvironment: &database.Environment{ID: uuid.New()},
+	}
+	job := c.makeKanikoJob(&ImageBuild{
+		Tool:        database.BuildToolKaniko,
+		ServiceID:   "id",
+		ServiceName: "api",
+		Image:       "registry/image:hash",
+		Hash:        "0123456789abcdef",
+		ContextURL:  "https://github.com/ergomake/api.git#main:server",
+		Dockerfile:  "Dockerfile",
+		Target:      "prod",
+		Labels:      map[string]string{"team": "core"},
+		Platforms:   []string{"linux/arm64"},
+	}, nil, nil)
+
+	assert.Empty(t, job.Spec.Template.Spec.InitContainers)
+	assert.Subset(t, job.Spec.Template.Spec.Containers[0].Args, []string{
+		"--context=git://github.com/ergomake/api.git#refs/heads/main",
+		"--context-sub-path=server",
+		"--target=prod",
+		"--custom-platform=linux/arm64",
+		"--label", "team=core",
+	})
+}
+
+func TestGitCompose_makeBuildKitJobBuildOptions(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		branchOwner:   "owner",
+		repo:          "repo",
+		dbEnvironment: &database.Environment{ID: uuid.New()},
+	}
+	job := c.makeBuildKitJob(&ImageBuild{
+		Tool:        database.BuildToolBuildKit,
+		ServiceID:   "id",
+		ServiceName: "api",
+		Image:       "registry/image:hash",
+		Hash:        "0123456789abcdef",
+		ContextURL:  "https://github.com/ergomake/api.git#main",
+		AdditionalContexts: map[string]string{
+			"shared": "libs/shared",
+			"base":   "docker-image://alpine:3.18",
+		},
+		Dockerfile: "Dockerfile",
+		CacheFrom:  []string{"registry/api:cache", "type=local,src=/cache"},
+		Labels:     map[string]string{"team": "core"},
+		Platforms:  []string{"linux/amd64", "linux/arm64"},
+	}, nil, nil)
+
+	assert.Empty(t, job.Spec.Template.Spec.InitContainers)
+	assert.Subset(t, job.Spec.Template.Spec.Containers[0].Args, []string{
+		"context=https://github.com/ergomake/api.git#main",
+		"filename=Dockerfile",
+		"shared=/workspace/libs/shared",
+		"context:shared=local:shared",
+		"context:base=docker-image://alpine:3.18",
+		"platform=linux/amd64,linux/arm64",
+		"label:team=core",
+		"type=registry,ref=registry/api:cache",
+		"type=local,src=/cache",
+	})
+}
+
+func TestGitCompose_findBuiltImage(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	env := database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "main", nil, "author", database.EnvSuccess)
+	require.NoError(t, db.Create(env).Error)
+	require.NoError(t, db.Create(&database.Service{
+		ID:            uuid.NewString(),
+		Name:          "api",
+		EnvironmentID: env.ID,
+		Image:         makeBuildImage("aaa"),
+		BuildHash:     "aaa",
+		BuildStatus:   "build-success",
+	}).Error)
+	// images of terminated environments are reused as well
+	require.NoError(t, db.Delete(env).Error)
+
+	tt := []struct {
+		name      string
+		buildHash string
+		exists    bool
+		err       error
+		want      bool
+	}{
+		{name: "reuses an image still in the registry", buildHash: "aaa", exists: true, want: true},
+		{name: "builds again when the image is gone from the registry", buildHash: "aaa", exists: false, want: false},
+		{name: "builds again when the registry can't be reached", buildHash: "aaa", err: assert.AnError, want: false},
+		{name: "builds when nothing was built from the inputs", buildHash: "bbb", want: false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checked := []string{}
+			c := &gitCompose{
+				db:     db,
+				owner:  "owner",
+				repo:   "repo",
+				branch: "main",
+				imageExists: func(ctx context.Context, image string) (bool, error) {
+					checked = append(checked, image)
+					return tc.exists, tc.err
+				},
+			}
+
+			image, ok, err := c.findBuiltImage(context.Background(), tc.buildHash)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ok)
+			if tc.want {
+				assert.Equal(t, makeBuildImage(tc.buildHash), image)
+			}
+
+			if tc.buildHash == "aaa" {
+				assert.Equal(t, []string{makeBuildImage("aaa")}, checked)
+			} else {
+				assert.Empty(t, checked, "the registry is only checked for images that were built")
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const buildkitImage = "moby/buildkit:v0.12.0-rootless"
+
+// buildkitCacheTag is the tag of the cache repo buildkit exports its layers to, kaniko
+// caches its layers by digest in the same repo
+const buildkitCacheTag = "buildkit"
+
+// buildkitBuilder runs buildkitd rootless inside of the build job, unlike kaniko it supports
+// `RUN --mount`, including secret mounts, and multi-stage targets
+type buildkitBuilder struct {
+	c         *gitCompose
+	namespace string
+}
+
+func (b *buildkitBuilder) Build(ctx context.Context, build *ImageBuild) (*batchv1.Job, error) {
+	cloneTokenSecretName, err := b.c.getJobCloneTokenSecret(ctx, b.namespace, build.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	buildSecretName, err := b.c.createBuildSecret(ctx, "preview-builds", build)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := b.c.clusterClient.CreateJob(ctx, b.c.makeBuildKitJob(build, cloneTokenSecretName, buildSecretName))
+	return job, errors.Wrapf(err, "fail to create buildkit job for service %s", build.ServiceName)
+}
+
+func (c *gitCompose) makeBuildKitJob(build *ImageBuild, cloneTokenSecretName, buildSecretName *string) *batchv1.Job {
+	contextDir := path.Join("/workspace", build.Context)
+	dockerfile := path.Join(contextDir, build.Dockerfile)
+
+	registryOpts := ""
+	if insecureRegistry != "" {
+		registryOpts = ",registry.insecure=true"
+	}
+	cacheRef := fmt.Sprintf("%s:%s", makeCacheRepo(c.branchOwner, c.repo), buildkitCacheTag)
+
+	args := []string{"build", "--frontend", "dockerfile.v0"}
+	if build.ContextURL != "" {
+		// the dockerfile frontend fetches remote contexts and reads the dockerfile from them
+		args = append(args, "--opt", "context="+build.ContextURL, "--opt", "filename="+build.Dockerfile)
+	} else {
+		args = append(args,
+			"--local", "context="+contextDir,
+			"--local", "dockerfile="+path.Dir(dockerfile),
+			"--opt", "filename="+path.Base(dockerfile),
+		)
+	}
+	if build.Target != "" {
+		args = append(args, "--opt", "target="+build.Target)
+	}
+	for _, name := range sortedKeys(build.AdditionalContexts) {
+		ctx := build.AdditionalContexts[name]
+		if isLocalContext(ctx) {
+			args = append(args,
+				"--local", fmt.Sprintf("%s=%s", name, path.Join("/workspace", ctx)),
+				"--opt", fmt.Sprintf("context:%s=local:%s", name, name),
+			)
+		} else {
+			args = append(args, "--opt", fmt.Sprintf("context:%s=%s", name, ctx))
+		}
+	}
+	if len(build.Platforms) > 0 {
+		args = append(args, "--opt", "platform="+strings.Join(build.Platforms, ","))
+	}
+	for _, k := range sortedKeys(build.Labels) {
+		args = append(args, "--opt", fmt.Sprintf("label:%s=%s", k, build.Labels[k]))
+	}
+	for _, k := range sortedKeys(build.BuildArgs) {
+		args = append(args, "--opt", fmt.Sprintf("build-arg:%s=%s", k, build.BuildArgs[k]))
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "BUILDKITD_FLAGS", Value: "--oci-worker-no-process-sandbox"},
+		{Name: "DOCKER_CONFIG", Value: "/home/user/.docker"},
+	}
+	for _, k := range sortedKeys(build.Secrets) {
+		args = append(args, "--secret", fmt.Sprintf("id=%s,src=%s", k, path.Join(buildSecretsPath, k)))
+	}
+
+	for _, cacheFrom := range build.CacheFrom {
+		if !strings.Contains(cacheFrom, "type=") {
+			cacheFrom = "type=registry,ref=" + cacheFrom
+		}
+		args = append(args, "--import-cache", cacheFrom)
+	}
+
+	args = append(args,
+		"--output", fmt.Sprintf("type=image,name=%s,push=true%s", build.Image, registryOpts),
+		"--export-cache", fmt.Sprintf("type=registry,ref=%s,mode=max%s", cacheRef, registryOpts),
+		"--import-cache", fmt.Sprintf("type=registry,ref=%s%s", cacheRef, registryOpts),
+	)
+
+	job := c.makeBuildJob(build, corev1.Container{
+		Name:    build.ServiceID,
+		Image:   buildkitImage,
+		Command: []string{"buildctl-daemonless.sh"},
+		Args:    args,
+		Env:     env,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  int64Ptr(1000),
+			RunAsGroup: int64Ptr(1000),
+			// rootless buildkit needs to create user namespaces, which the default profiles deny
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "buildkitd",
+				MountPath: "/home/user/.local/share/buildkit",
+			},
+		},
+	}, cloneTokenSecretName)
+	appendBuildSecrets(job, buildSecretName)
+
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "buildkitd",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	annotations := map[string]string{}
+	for k, v := range job.Spec.Template.Annotations {
+		annotations[k] = v
+	}
+	annotations[fmt.Sprintf("container.apparmor.security.beta.kubernetes.io/%s", build.ServiceID)] = "unconfined"
+	job.Spec.Template.Annotations = annotations
+
+	if os.Getenv("CLUSTER") == "eks" {
+		appendUserlandCreds(job, "/home/user/.docker/")
+	}
+
+	return job
+}
@@ -0,0 +1,147 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+
+	kpackBuild "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	kpackCore "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+)
+
+// buildpacksBuilder builds with kpack, which isn't waited for, the deployments
+// are scaled up once the builds finish
+type buildpacksBuilder struct {
+	c         *gitCompose
+	namespace string
+	// cloneTokenSecrets are in the kpack namespace, they can't be shared with the build jobs
+	cloneTokenSecrets map[string]*string
+}
+
+func (b *buildpacksBuilder) Build(ctx context.Context, build *ImageBuild) (*batchv1.Job, error) {
+	c := b.c
+
+	cloneTokenSecretName, ok := b.cloneTokenSecrets[build.Repo]
+	if !ok && !c.isPublic {
+		cloneToken, err := c.gitClient.GetCloneToken(ctx, c.branchOwner, build.Repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to get clone token for %s/%s", c.branchOwner, build.Repo)
+		}
+
+		cloneTokenSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      strings.ReplaceAll(strings.ToLower(fmt.Sprintf("%s-%s", build.Repo, b.namespace)), "_", ""),
+				Namespace: "kpack",
+				Annotations: map[string]string{
+					"kpack.io/git": c.gitClient.GetBaseURL(),
+				},
+			},
+			StringData: map[string]string{
+				"username": "x-access-token",
+				"password": cloneToken,
+			},
+			Type: "kubernetes.io/basic-auth",
+		}
+
+		err = c.clusterClient.CreateSecret(ctx, cloneTokenSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to add github token secret into cluster")
+		}
+
+		cloneTokenSecretName = pointer.String(cloneTokenSecret.GetName())
+		b.cloneTokenSecrets[build.Repo] = cloneTokenSecretName
+	}
+
+	svcAcc := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      build.ServiceID,
+			Namespace: "kpack",
+		},
+		Secrets:          []corev1.ObjectReference{{Name: "kpack-registry-credentials"}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "kpack-registry-credentials"}},
+	}
+	if cloneTokenSecretName != nil {
+		svcAcc.Secrets = append(svcAcc.Secrets, corev1.ObjectReference{Name: *cloneTokenSecretName})
+	}
+
+	err := c.clusterClient.CreateServiceAccount(ctx, svcAcc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to create service account to build service %s", build.ServiceID)
+	}
+
+	buildSecretName, err := c.createBuildSecret(ctx, "kpack", build)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := []corev1.EnvVar{}
+	for _, k := range sortedKeys(build.Env) {
+		envs = append(envs, corev1.EnvVar{Name: k, Value: build.Env[k]})
+	}
+	for _, k := range sortedKeys(build.Secrets) {
+		envs = append(envs, corev1.EnvVar{
+			Name: k,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: *buildSecretName},
+					Key:                  k,
+				},
+			},
+		})
+	}
+
+	labels := c.getLabels(build.ServiceID, build.ServiceName)
+	labels[cluster.BuildEnvironmentLabel] = c.dbEnvironment.ID.String()
+	kb := &kpackBuild.Build{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Build",
+			APIVersion: "kpack.io/v1alpha2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        build.ServiceID,
+			Namespace:   "kpack",
+			Labels:      labels,
+			Annotations: labels,
+		},
+		Spec: kpackBuild.BuildSpec{
+			Builder: kpackCore.BuildBuilderSpec{
+				Image: "ergomake/kpack-builder",
+			},
+			RunImage: kpackBuild.BuildSpecImage{
+				Image: "paketobuildpacks/run-jammy-base",
+			},
+			ServiceAccountName: svcAcc.GetName(),
+			Source: kpackCore.SourceConfig{
+				Git: &kpackCore.Git{
+					URL:      fmt.Sprintf("%s/%s/%s", c.gitClient.GetBaseURL(), c.branchOwner, build.Repo),
+					Revision: build.Branch,
+				},
+				SubPath: build.Context,
+			},
+			Tags: []string{build.Image},
+			Env:  envs,
+			Tolerations: []corev1.Toleration{
+				{
+					Key:      "preview.ergomake.dev/domain",
+					Operator: corev1.TolerationOpEqual,
+					Value:    "build",
+					Effect:   corev1.TaintEffectNoSchedule,
+				},
+			},
+			NodeSelector: map[string]string{
+				"preview.ergomake.dev/role": "build",
+			},
+		},
+	}
+
+	err = c.clusterClient.ApplyKPackBuilds(ctx, []*kpackBuild.Build{kb})
+	return nil, errors.Wrap(err, "fail to apply kpack build")
+}
@@ -0,0 +1,307 @@
+package transformer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/ergopack"
+)
+
+const builderLabel = "dev.ergomake.builder"
+
+const defaultDockerfile = "Dockerfile"
+
+// buildSettings is how the image of a service is built, Dockerfile is only
+// set for ergopack apps since compose services have their own
+type buildSettings struct {
+	Tool       string
+	Dockerfile string
+	Target     string
+	// ContextURL is set when the build context is a remote git repository
+	ContextURL string
+	// AdditionalContexts are named contexts, local ones are paths relative to the compose file
+	AdditionalContexts map[string]string
+	CacheFrom          []string
+	Labels             map[string]string
+	Platforms          []string
+}
+
+type rawComposeBuilds struct {
+	Services map[string]*struct {
+		Build  interface{} `yaml:"build"`
+		Labels interface{} `yaml:"labels"`
+	} `yaml:"services"`
+}
+
+// loadComposeBuilds reads the builder of every compose service from its `dev.ergomake.builder`
+// label, kaniko by default, and the `build` options that are passed on to it. Options the
+// builder doesn't support are errors rather than being left out of the image.
+func loadComposeBuilds(content []byte) (map[string]buildSettings, error) {
+	var raw rawComposeBuilds
+	err := yaml.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal compose builds")
+	}
+
+	builds := make(map[string]buildSettings)
+	for name, svc := range raw.Services {
+		if svc == nil {
+			continue
+		}
+
+		settings := buildSettings{Tool: database.BuildToolKaniko}
+		if tool, ok := parseComposeLabels(svc.Labels)[builderLabel]; ok {
+			settings.Tool = tool
+		}
+
+		if settings.Tool != database.BuildToolKaniko && settings.Tool != database.BuildToolBuildKit {
+			return nil, errors.Errorf(
+				"service %s: unknown builder %q, it must be %s or %s",
+				name, settings.Tool, database.BuildToolKaniko, database.BuildToolBuildKit,
+			)
+		}
+
+		err := settings.loadComposeBuild(svc.Build)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %s", name)
+		}
+
+		err = settings.checkSupported()
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %s", name)
+		}
+
+		builds[normalizeServiceName(name)] = settings
+	}
+
+	return builds, nil
+}
+
+func (s *buildSettings) loadComposeBuild(raw interface{}) error {
+	var build map[string]interface{}
+	switch raw := raw.(type) {
+	case nil:
+		return nil
+	case string:
+		build = map[string]interface{}{"context": raw}
+	case map[string]interface{}:
+		build = raw
+	default:
+		return errors.New("`build` must be a string or a map")
+	}
+
+	if context, ok := build["context"].(string); ok && isRemoteContext(context) {
+		s.ContextURL = context
+	}
+
+	if target, ok := build["target"]; ok {
+		s.Target = fmt.Sprint(target)
+	}
+
+	var err error
+	s.AdditionalContexts, err = parseComposeMapping(build["additional_contexts"])
+	if err != nil {
+		return errors.Wrap(err, "invalid `build.additional_contexts`")
+	}
+
+	s.Labels, err = parseComposeMapping(build["labels"])
+	if err != nil {
+		return errors.Wrap(err, "invalid `build.labels`")
+	}
+
+	s.CacheFrom, err = parseComposeList(build["cache_from"])
+	if err != nil {
+		return errors.Wrap(err, "invalid `build.cache_from`")
+	}
+
+	s.Platforms, err = parseComposeList(build["platforms"])
+	if err != nil {
+		return errors.Wrap(err, "invalid `build.platforms`")
+	}
+
+	return nil
+}
+
+// checkSupported fails with the first option the builder can't honor
+func (s *buildSettings) checkSupported() error {
+	if s.Tool == database.BuildToolBuildKit {
+		for name, ctx := range s.AdditionalContexts {
+			if strings.HasPrefix(ctx, "service:") {
+				return errors.Errorf("additional context %s: images of other services can't be used as contexts", name)
+			}
+		}
+
+		return nil
+	}
+
+	if s.ContextURL != "" && !isGitContext(s.ContextURL) {
+		return errors.Errorf("context %s: only git repositories over http(s) can be built with %s", s.ContextURL, s.Tool)
+	}
+
+	if len(s.AdditionalContexts) > 0 {
+		return errors.Errorf("`build.additional_contexts` needs the %s builder", database.BuildToolBuildKit)
+	}
+
+	if len(s.CacheFrom) > 0 {
+		return errors.Errorf("`build.cache_from` needs the %s builder", database.BuildToolBuildKit)
+	}
+
+	if len(s.Platforms) > 1 {
+		return errors.Errorf("building more than one of `build.platforms` needs the %s builder", database.BuildToolBuildKit)
+	}
+
+	return nil
+}
+
+// isRemoteContext follows what compose takes as a context that is not a local path
+func isRemoteContext(context string) bool {
+	for _, prefix := range []string{"https://", "http://", "git://", "github.com/", "git@"} {
+		if strings.HasPrefix(context, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLocalContext is whether an additional context is a path rather than an image or a url
+func isLocalContext(context string) bool {
+	return !strings.Contains(context, "://") && !strings.Contains(context, ":") && !isRemoteContext(context)
+}
+
+func isGitContext(context string) bool {
+	if strings.HasPrefix(context, "git@") {
+		return false
+	}
+
+	repo := strings.SplitN(context, "#", 2)[0]
+	return strings.HasPrefix(context, "git://") || strings.HasPrefix(context, "github.com/") ||
+		strings.HasSuffix(repo, ".git") || strings.Contains(context, "#")
+}
+
+// parseComposeMapping reads a compose mapping, which is either a map or a list of `key=value`
+func parseComposeMapping(raw interface{}) (map[string]string, error) {
+	switch raw.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}, []interface{}:
+		return parseComposeLabels(raw), nil
+	}
+
+	return nil, errors.New("expected a map or a list")
+}
+
+func parseComposeList(raw interface{}) ([]string, error) {
+	switch raw := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{raw}, nil
+	case []interface{}:
+		list := []string{}
+		for _, item := range raw {
+			list = append(list, fmt.Sprint(item))
+		}
+
+		return list, nil
+	}
+
+	return nil, errors.New("expected a list")
+}
+
+// loadErgopackBuilds reads the builder of every ergopack app, apps are built with buildpacks by default
+func loadErgopackBuilds(pack *ergopack.Ergopack) (map[string]buildSettings, error) {
+	names := []string{}
+	for name := range pack.Apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	builds := make(map[string]buildSettings)
+	for _, name := range names {
+		app := pack.Apps[name]
+
+		settings := buildSettings{Tool: app.Builder, Dockerfile: app.Dockerfile, Target: app.Target}
+		switch settings.Tool {
+		case "":
+			settings.Tool = database.BuildToolBuildpacks
+		case database.BuildToolBuildpacks, database.BuildToolBuildKit:
+		default:
+			return nil, errors.Errorf(
+				"app %s: unknown builder %q, it must be %s or %s",
+				name, app.Builder, database.BuildToolBuildpacks, database.BuildToolBuildKit,
+			)
+		}
+
+		if settings.Tool == database.BuildToolBuildpacks && (settings.Dockerfile != "" || settings.Target != "") {
+			return nil, errors.Errorf("app %s: dockerfile and target are only supported by the %s builder", name, database.BuildToolBuildKit)
+		}
+
+		if settings.Tool == database.BuildToolBuildKit && settings.Dockerfile == "" {
+			settings.Dockerfile = defaultDockerfile
+		}
+
+		builds[name] = settings
+	}
+
+	return builds, nil
+}
+
+func makeBuildValidationError(t string, err error) *ProjectValidationError {
+	return &ProjectValidationError{
+		T:       t,
+		Message: fmt.Sprintf("Unsupported build configuration\n```\n%s\n```", err.Error()),
+	}
+}
+
+// buildTools lists the tools that build the images of the environment, sorted
+func (c *gitCompose) buildTools() []string {
+	set := map[string]struct{}{}
+	for name, service := range c.environment.Services {
+		if service.Build == "" {
+			continue
+		}
+
+		set[c.builds[name].Tool] = struct{}{}
+	}
+
+	tools := []string{}
+	for tool := range set {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	return tools
+}
+
+// usesBuildpacks is whether any image of the environment is built by kpack
+func (c *gitCompose) usesBuildpacks() bool {
+	for _, tool := range c.buildTools() {
+		if tool == database.BuildToolBuildpacks {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setServiceImage points the service at the image that was built, or reused, for it
+func (c *gitCompose) setServiceImage(name, image string) {
+	service := c.environment.Services[name]
+	service.Image = image
+	c.environment.Services[name] = service
+
+	if c.komposeObject == nil {
+		return
+	}
+
+	if svc, ok := c.komposeObject.ServiceConfigs[name]; ok {
+		svc.Image = image
+		c.komposeObject.ServiceConfigs[name] = svc
+	}
+}
@@ -0,0 +1,236 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/ergopack"
+)
+
+func TestLoadComposeBuilds(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected map[string]buildSettings
+		err      bool
+	}{
+		{
+			name: "kaniko by default",
+			content: `
+services:
+  api:
+    build: .
+  db:
+    image: postgres
+`,
+			expected: map[string]buildSettings{
+				"api": {Tool: database.BuildToolKaniko},
+				"db":  {Tool: database.BuildToolKaniko},
+			},
+		},
+		{
+			name: "buildkit with a target",
+			content: `
+services:
+  my_api:
+    build:
+      context: .
+      target: prod
+    labels:
+      dev.ergomake.builder: buildkit
+  web:
+    build: ./web
+    labels:
+      - dev.ergomake.builder=kaniko
+`,
+			expected: map[string]buildSettings{
+				"my-api": {Tool: database.BuildToolBuildKit, Target: "prod"},
+				"web":    {Tool: database.BuildToolKaniko},
+			},
+		},
+		{
+			name: "unknown builder",
+			content: `
+services:
+  api:
+    build: .
+    labels:
+      dev.ergomake.builder: docker
+`,
+			err: true,
+		},
+		{
+			name: "kaniko build options",
+			content: `
+services:
+  api:
+    build:
+      context: https://github.com/ergomake/api.git#main:server
+      target: prod
+      labels:
+        team: core
+      platforms:
+        - linux/arm64
+`,
+			expected: map[string]buildSettings{
+				"api": {
+					Tool:       database.BuildToolKaniko,
+					Target:     "prod",
+					ContextURL: "https://github.com/ergomake/api.git#main:server",
+					Labels:     map[string]string{"team": "core"},
+					Platforms:  []string{"linux/arm64"},
+				},
+			},
+		},
+		{
+			name: "buildkit build options",
+			content: `
+services:
+  api:
+    build:
+      context: .
+      additional_contexts:
+        - shared=../shared
+        - base=docker-image://alpine:3.18
+      cache_from:
+        - registry/api:cache
+      platforms:
+        - linux/amd64
+        - linux/arm64
+    labels:
+      dev.ergomake.builder: buildkit
+`,
+			expected: map[string]buildSettings{
+				"api": {
+					Tool:               database.BuildToolBuildKit,
+					AdditionalContexts: map[string]string{"shared": "../shared", "base": "docker-image://alpine:3.18"},
+					CacheFrom:          []string{"registry/api:cache"},
+					Platforms:          []string{"linux/amd64", "linux/arm64"},
+				},
+			},
+		},
+		{
+			name: "additional contexts without buildkit",
+			content: `
+services:
+  api:
+    build:
+      context: .
+      additional_contexts:
+        shared: ../shared
+`,
+			err: true,
+		},
+		{
+			name: "many platforms without buildkit",
+			content: `
+services:
+  api:
+    build:
+      context: .
+      platforms: [linux/amd64, linux/arm64]
+`,
+			err: true,
+		},
+		{
+			name: "ssh context without buildkit",
+			content: `
+services:
+  api:
+    build: git@github.com:ergomake/api.git
+`,
+			err: true,
+		},
+		{
+			name: "service context",
+			content: `
+services:
+  api:
+    build:
+      context: .
+      additional_contexts:
+        base: service:base
+    labels:
+      dev.ergomake.builder: buildkit
+`,
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			builds, err := loadComposeBuilds([]byte(tc.content))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, builds)
+		})
+	}
+}
+
+func TestLoadErgopackBuilds(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		apps     map[string]ergopack.ErgopackApp
+		expected map[string]buildSettings
+		err      bool
+	}{
+		{
+			name: "buildpacks by default",
+			apps: map[string]ergopack.ErgopackApp{"api": {Path: "../api"}},
+			expected: map[string]buildSettings{
+				"api": {Tool: database.BuildToolBuildpacks},
+			},
+		},
+		{
+			name: "buildkit with the default dockerfile",
+			apps: map[string]ergopack.ErgopackApp{
+				"api": {Path: "../api", Builder: "buildkit", Target: "prod"},
+				"web": {Path: "../web", Builder: "buildkit", Dockerfile: "docker/web.Dockerfile"},
+			},
+			expected: map[string]buildSettings{
+				"api": {Tool: database.BuildToolBuildKit, Dockerfile: "Dockerfile", Target: "prod"},
+				"web": {Tool: database.BuildToolBuildKit, Dockerfile: "docker/web.Dockerfile"},
+			},
+		},
+		{
+			name: "unknown builder",
+			apps: map[string]ergopack.ErgopackApp{"api": {Path: "../api", Builder: "kaniko"}},
+			err:  true,
+		},
+		{
+			name: "dockerfile without buildkit",
+			apps: map[string]ergopack.ErgopackApp{"api": {Path: "../api", Dockerfile: "Dockerfile"}},
+			err:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			builds, err := loadErgopackBuilds(&ergopack.Ergopack{Apps: tc.apps})
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, builds)
+		})
+	}
+}
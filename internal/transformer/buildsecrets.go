@@ -0,0 +1,80 @@
+package transformer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+)
+
+// buildSecretsPath is where build jobs find a file for each build secret, it is
+// the same path `RUN --mount=type=secret` uses so dockerfiles work with any builder
+const buildSecretsPath = "/run/secrets"
+
+// createBuildSecret stores the build secrets of build in a Secret of namespace. The Secret is
+// named after a digest of the secrets so builds with the same secrets share it, it is labeled
+// with the environment so it is deleted along with its builds.
+func (c *gitCompose) createBuildSecret(ctx context.Context, namespace string, build *ImageBuild) (*string, error) {
+	if len(build.Secrets) == 0 {
+		return nil, nil
+	}
+
+	secret := makeBuildSecret(namespace, c.dbEnvironment.ID.String(), build)
+	err := c.clusterClient.CreateSecret(ctx, secret)
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "fail to create build secrets of service %s", build.ServiceName)
+	}
+
+	return pointer.String(secret.GetName()), nil
+}
+
+func makeBuildSecret(namespace, envID string, build *ImageBuild) *corev1.Secret {
+	h := sha256.New()
+	data := make(map[string][]byte)
+	for _, k := range sortedKeys(build.Secrets) {
+		fmt.Fprintf(h, "%s=%s\n", k, build.Secrets[k])
+		data[k] = []byte(build.Secrets[k])
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-secrets-%s", build.ServiceID, digest[:jobNameHashLength]),
+			Namespace: namespace,
+			Labels:    map[string]string{cluster.BuildEnvironmentLabel: envID},
+		},
+		Data: data,
+	}
+}
+
+// appendBuildSecrets mounts the build secrets into the builder container of job, read only
+func appendBuildSecrets(job *batchv1.Job, secretName *string) {
+	if secretName == nil {
+		return
+	}
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "build-secrets",
+		MountPath: buildSecretsPath,
+		ReadOnly:  true,
+	})
+
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "build-secrets",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: *secretName,
+			},
+		},
+	})
+}
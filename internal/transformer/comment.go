@@ -0,0 +1,100 @@
+package transformer
+
+import (
+	"fmt"
+
+	"github.com/cbroglie/mustache"
+)
+
+// statuses of a launch, comment templates can have a section for each of them
+const (
+	CommentSuccess = "success"
+	CommentFailure = "failure"
+	CommentLimited = "limited"
+)
+
+var commentStatuses = []string{CommentSuccess, CommentFailure, CommentLimited}
+
+// CommentVars is what a comment template gets to know about a launch besides the services of its environment
+type CommentVars struct {
+	Status         string
+	Owner          string
+	Repo           string
+	Branch         string
+	PullRequest    int
+	Author         string
+	SHA            string
+	FrontendLink   string
+	MainURL        string
+	Reason         string
+	DefaultComment string
+}
+
+// CommentTemplateContext is what comment templates are rendered with
+func CommentTemplateContext(env *Environment, vars CommentVars) map[string]interface{} {
+	templateContext := env.ToMap()
+	templateContext["status"] = vars.Status
+	for _, status := range commentStatuses {
+		templateContext[status] = vars.Status == status
+	}
+	templateContext["owner"] = vars.Owner
+	templateContext["repo"] = vars.Repo
+	templateContext["branch"] = vars.Branch
+	templateContext["pullRequest"] = vars.PullRequest
+	templateContext["author"] = vars.Author
+	templateContext["sha"] = vars.SHA
+	templateContext["frontendLink"] = vars.FrontendLink
+	templateContext["mainUrl"] = vars.MainURL
+	templateContext["reason"] = vars.Reason
+	templateContext["defaultComment"] = vars.DefaultComment
+
+	return templateContext
+}
+
+// RenderCommentTemplate renders template with templateContext, every variable it uses must exist
+func RenderCommentTemplate(template string, templateContext map[string]interface{}) (string, error) {
+	mustache.AllowMissingVariables = false
+	return mustache.Render(template, templateContext)
+}
+
+// checkCommentTemplate renders the comment template with the services of the environment for every
+// status, a variable that doesn't exist would otherwise only show up as the built-in comment later on
+func (c *gitCompose) checkCommentTemplate() *ProjectValidationError {
+	if c.commentTemplate == "" {
+		return nil
+	}
+
+	pullRequest := 0
+	if c.prNumber != nil {
+		pullRequest = *c.prNumber
+	}
+
+	mainURL := ""
+	if url := c.environment.FirstService().Url; url != "" {
+		mainURL = fmt.Sprintf("https://%s", url)
+	}
+
+	for _, status := range commentStatuses {
+		_, err := RenderCommentTemplate(c.commentTemplate, CommentTemplateContext(c.environment, CommentVars{
+			Status:         status,
+			Owner:          c.owner,
+			Repo:           c.repo,
+			Branch:         c.branch,
+			PullRequest:    pullRequest,
+			Author:         c.author,
+			SHA:            c.sha,
+			FrontendLink:   "https://app.ergomake.dev",
+			MainURL:        mainURL,
+			Reason:         "reason",
+			DefaultComment: "comment",
+		}))
+		if err != nil {
+			return &ProjectValidationError{
+				T:       "invalid-comment-template",
+				Message: fmt.Sprintf("Comment template `%s` can't be rendered with status `%s`: %s", commentTemplatePath, status, err.Error()),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitCompose_checkCommentTemplate(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		template string
+		missing  string
+	}{
+		{name: "no template", template: ""},
+		{
+			name:     "uses existing variables",
+			template: "{{#success}}{{mainUrl}} {{services.web.url}} {{sha}}{{/success}}{{#failure}}{{reason}}{{/failure}}",
+		},
+		{name: "typo in a variable", template: "{{#success}}{{mainUlr}}{{/success}}", missing: "mainUlr"},
+		{name: "service that does not exist", template: "{{services.api.url}}", missing: `"api"`},
+		{name: "typo in the section of another status", template: "{{#limited}}{{resaon}}{{/limited}}", missing: "resaon"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &gitCompose{
+				owner:           "owner",
+				repo:            "repo",
+				branch:          "branch",
+				sha:             "sha",
+				commentTemplate: tc.template,
+				environment: NewEnvironment(map[string]EnvironmentService{
+					"web": {Url: "web.preview.dev"},
+				}, ""),
+			}
+
+			validationErr := c.checkCommentTemplate()
+			if tc.missing == "" {
+				assert.Nil(t, validationErr)
+				return
+			}
+
+			require.NotNil(t, validationErr)
+			assert.Equal(t, "invalid-comment-template", validationErr.T)
+			assert.Contains(t, validationErr.Message, tc.missing)
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package transformer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+	"github.com/ergomake/ergomake/internal/ergopack"
+)
+
+const (
+	conditionStarted   = "service_started"
+	conditionHealthy   = "service_healthy"
+	conditionCompleted = "service_completed_successfully"
+)
+
+// compose defaults, see https://docs.docker.com/compose/compose-file/05-services/#healthcheck
+const (
+	defaultHealthcheckInterval = 30 * time.Second
+	defaultHealthcheckTimeout  = 30 * time.Second
+	defaultHealthcheckRetries  = 3
+)
+
+type dependency struct {
+	Service   string
+	Condition string
+}
+
+type healthcheck struct {
+	Command     []string
+	Interval    time.Duration
+	Timeout     time.Duration
+	Retries     int32
+	StartPeriod time.Duration
+}
+
+type rawComposeServices struct {
+	Services map[string]*struct {
+		DependsOn   interface{} `yaml:"depends_on"`
+		Healthcheck *struct {
+			Test        interface{} `yaml:"test"`
+			Interval    string      `yaml:"interval"`
+			Timeout     string      `yaml:"timeout"`
+			Retries     int32       `yaml:"retries"`
+			StartPeriod string      `yaml:"start_period"`
+			Disable     bool        `yaml:"disable"`
+		} `yaml:"healthcheck"`
+	} `yaml:"services"`
+}
+
+var serviceNameNormalizer = regexp.MustCompile("[._]")
+
+// normalizeServiceName matches the names kompose gives to the objects it generates
+func normalizeServiceName(name string) string {
+	return strings.ToLower(serviceNameNormalizer.ReplaceAllString(name, "-"))
+}
+
+// loadComposeDependencies reads `depends_on` and `healthcheck` of every compose service
+func loadComposeDependencies(content []byte) (map[string][]dependency, map[string]*healthcheck, error) {
+	var raw rawComposeServices
+	err := yaml.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fail to unmarshal compose services")
+	}
+
+	dependencies := make(map[string][]dependency)
+	healthchecks := make(map[string]*healthcheck)
+	for name, svc := range raw.Services {
+		if svc == nil {
+			continue
+		}
+		name = normalizeServiceName(name)
+
+		deps, err := parseDependsOn(svc.DependsOn)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "fail to parse depends_on of service %s", name)
+		}
+		if len(deps) > 0 {
+			dependencies[name] = deps
+		}
+
+		if svc.Healthcheck == nil || svc.Healthcheck.Disable {
+			continue
+		}
+
+		hc, err := makeHealthcheck(
+			parseHealthcheckTest(svc.Healthcheck.Test),
+			svc.Healthcheck.Interval,
+			svc.Healthcheck.Timeout,
+			svc.Healthcheck.Retries,
+			svc.Healthcheck.StartPeriod,
+		)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "fail to parse healthcheck of service %s", name)
+		}
+		if hc != nil {
+			healthchecks[name] = hc
+		}
+	}
+
+	return dependencies, healthchecks, nil
+}
+
+// loadErgopackDependencies reads `dependsOn` of every ergopack app and job and `healthcheck` of every app
+func loadErgopackDependencies(pack *ergopack.Ergopack) (map[string][]dependency, map[string]*healthcheck, error) {
+	dependencies := make(map[string][]dependency)
+	healthchecks := make(map[string]*healthcheck)
+	for name, job := range pack.Jobs {
+		if deps := makeErgopackDependencies(job.DependsOn); len(deps) > 0 {
+			dependencies[name] = deps
+		}
+	}
+
+	for name, app := range pack.Apps {
+		if deps := makeErgopackDependencies(app.DependsOn); len(deps) > 0 {
+			dependencies[name] = deps
+		}
+
+		if app.Healthcheck == nil || app.Healthcheck.Disable {
+			continue
+		}
+
+		hc, err := makeHealthcheck(
+			app.Healthcheck.Test,
+			app.Healthcheck.Interval,
+			app.Healthcheck.Timeout,
+			app.Healthcheck.Retries,
+			app.Healthcheck.StartPeriod,
+		)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "fail to parse healthcheck of app %s", name)
+		}
+		if hc != nil {
+			healthchecks[name] = hc
+		}
+	}
+
+	return dependencies, healthchecks, nil
+}
+
+func makeErgopackDependencies(dependsOn map[string]ergopack.ErgopackDependency) []dependency {
+	deps := []dependency{}
+	for depName, dep := range dependsOn {
+		condition := dep.Condition
+		if condition == "" {
+			condition = conditionStarted
+		}
+		deps = append(deps, dependency{Service: depName, Condition: condition})
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Service < deps[j].Service })
+
+	return deps
+}
+
+// parseDependsOn accepts both the list and the map syntax of compose `depends_on`
+func parseDependsOn(raw interface{}) ([]dependency, error) {
+	deps := []dependency{}
+
+	switch raw := raw.(type) {
+	case nil:
+	case []interface{}:
+		for _, name := range raw {
+			deps = append(deps, dependency{
+				Service:   normalizeServiceName(fmt.Sprint(name)),
+				Condition: conditionStarted,
+			})
+		}
+	case map[string]interface{}:
+		for name, rawDep := range raw {
+			condition := conditionStarted
+			if depMap, ok := rawDep.(map[string]interface{}); ok {
+				if c, ok := depMap["condition"].(string); ok && c != "" {
+					condition = c
+				}
+			}
+
+			deps = append(deps, dependency{Service: normalizeServiceName(name), Condition: condition})
+		}
+
+		sort.Slice(deps, func(i, j int) bool { return deps[i].Service < deps[j].Service })
+	default:
+		return nil, errors.Errorf("invalid depends_on type %T", raw)
+	}
+
+	for _, dep := range deps {
+		switch dep.Condition {
+		case conditionStarted, conditionHealthy, conditionCompleted:
+		default:
+			return nil, errors.Errorf("unknown condition %s for dependency %s", dep.Condition, dep.Service)
+		}
+	}
+
+	return deps, nil
+}
+
+func parseHealthcheckTest(raw interface{}) []string {
+	switch raw := raw.(type) {
+	case string:
+		return []string{"CMD-SHELL", raw}
+	case []interface{}:
+		test := []string{}
+		for _, part := range raw {
+			test = append(test, fmt.Sprint(part))
+		}
+		return test
+	}
+
+	return nil
+}
+
+// makeHealthcheck returns nil when test is empty or NONE
+func makeHealthcheck(test []string, interval, timeout string, retries int32, startPeriod string) (*healthcheck, error) {
+	if len(test) == 0 || test[0] == "NONE" {
+		return nil, nil
+	}
+
+	var command []string
+	switch test[0] {
+	case "CMD":
+		command = test[1:]
+	case "CMD-SHELL":
+		command = []string{"/bin/sh", "-c", strings.Join(test[1:], " ")}
+	default:
+		command = test
+	}
+
+	hc := &healthcheck{
+		Command:  command,
+		Interval: defaultHealthcheckInterval,
+		Timeout:  defaultHealthcheckTimeout,
+		Retries:  defaultHealthcheckRetries,
+	}
+
+	var err error
+	if interval != "" {
+		hc.Interval, err = time.ParseDuration(interval)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid interval %s", interval)
+		}
+	}
+
+	if timeout != "" {
+		hc.Timeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout %s", timeout)
+		}
+	}
+
+	if startPeriod != "" {
+		hc.StartPeriod, err = time.ParseDuration(startPeriod)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid start period %s", startPeriod)
+		}
+	}
+
+	if retries > 0 {
+		hc.Retries = retries
+	}
+
+	return hc, nil
+}
+
+func (hc *healthcheck) probe(initialDelay time.Duration) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: hc.Command},
+		},
+		InitialDelaySeconds: int32(initialDelay.Seconds()),
+		PeriodSeconds:       int32(hc.Interval.Seconds()),
+		TimeoutSeconds:      int32(hc.Timeout.Seconds()),
+		FailureThreshold:    hc.Retries,
+	}
+}
+
+// addProbes turns the service healthcheck into readiness and liveness probes, failures
+// during the start period must not restart the container so liveness waits for it.
+func addProbes(container *corev1.Container, hc *healthcheck) {
+	if hc == nil {
+		return
+	}
+
+	container.ReadinessProbe = hc.probe(0)
+	container.LivenessProbe = hc.probe(hc.StartPeriod)
+}
+
+func (c *gitCompose) addHealthcheck(deployment *appsv1.Deployment) {
+	serviceName := deployment.GetLabels()["io.kompose.service"]
+	podSpec := &deployment.Spec.Template.Spec
+
+	hc := c.healthchecks[serviceName]
+	for i := range podSpec.Containers {
+		// kompose drops CMD-SHELL from the test, so we always use our own probes
+		podSpec.Containers[i].ReadinessProbe = nil
+		podSpec.Containers[i].LivenessProbe = nil
+		addProbes(&podSpec.Containers[i], hc)
+	}
+}
+
+// dependencyOrder returns service names in an order where dependencies come first
+func (c *gitCompose) dependencyOrder() ([]string, error) {
+	names := []string{}
+	for name := range c.dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	order := []string{}
+	state := make(map[string]int)
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			return errors.Errorf("dependency cycle detected at service %s", name)
+		case 2:
+			return nil
+		}
+
+		state[name] = 1
+		for _, dep := range c.dependencies[name] {
+			err := visit(dep.Service)
+			if err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		order = append(order, name)
+
+		return nil
+	}
+
+	for _, name := range names {
+		err := visit(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// dependencyJobs maps the services others depend on to complete successfully to those dependants, they
+// run as jobs before their dependants are scaled up. Setup jobs are left out since they already are jobs.
+func (c *gitCompose) dependencyJobs() map[string][]string {
+	jobs := make(map[string][]string)
+	for name, deps := range c.dependencies {
+		for _, dep := range deps {
+			if dep.Condition != conditionCompleted {
+				continue
+			}
+
+			if _, ok := c.jobs[dep.Service]; ok {
+				continue
+			}
+
+			jobs[dep.Service] = append(jobs[dep.Service], name)
+		}
+	}
+
+	for _, dependants := range jobs {
+		sort.Strings(dependants)
+	}
+
+	return jobs
+}
+
+// applyDependencies enforces dependency conditions. A healthy dependency is awaited by an init
+// container through its Service, which only routes to ready pods. A dependency that must complete
+// becomes a job, see convertJobs, that runs once before the deployments that wait for it are scaled up,
+// jobs that depend on other jobs are annotated to run after them.
+func (c *gitCompose) applyDependencies(objs []runtime.Object) ([]runtime.Object, error) {
+	if len(c.dependencies) == 0 {
+		return objs, nil
+	}
+
+	deployments := make(map[string]*appsv1.Deployment)
+	services := make(map[string]*corev1.Service)
+	for _, obj := range objs {
+		switch obj := obj.(type) {
+		case *appsv1.Deployment:
+			deployments[obj.GetLabels()["preview.ergomake.dev/service"]] = obj
+		case *corev1.Service:
+			services[obj.GetName()] = obj
+		}
+	}
+
+	order, err := c.dependencyOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	dependencyJobs := c.dependencyJobs()
+	isSameKind := func(a, b string) bool {
+		_, aIsJob := c.jobs[a]
+		_, bIsJob := c.jobs[b]
+		_, aIsDependencyJob := dependencyJobs[a]
+		_, bIsDependencyJob := dependencyJobs[b]
+		return aIsJob == bIsJob && aIsDependencyJob == bIsDependencyJob
+	}
+
+	for _, name := range order {
+		deployment, ok := deployments[name]
+		if !ok {
+			continue
+		}
+
+		after := []string{}
+		waits := false
+		for _, dep := range c.dependencies[name] {
+			switch dep.Condition {
+			case conditionHealthy:
+				service, ok := services[dep.Service]
+				if !ok || len(service.Spec.Ports) == 0 {
+					return nil, errors.Errorf(
+						"service %s depends on %s being healthy but %s exposes no ports",
+						name,
+						dep.Service,
+						dep.Service,
+					)
+				}
+
+				addInitContainer(deployment, makeWaitForContainer(dep.Service, service.Spec.Ports[0].Port))
+			case conditionCompleted:
+				if _, ok := deployments[dep.Service]; !ok {
+					return nil, errors.Errorf("dependency %s of service %s not found", dep.Service, name)
+				}
+
+				// jobs of other kinds already ran by the time a job starts
+				if isSameKind(name, dep.Service) {
+					after = append(after, dep.Service)
+				} else {
+					waits = true
+				}
+			}
+		}
+
+		_, isJob := c.jobs[name]
+		_, isDependencyJob := dependencyJobs[name]
+		if isJob || isDependencyJob {
+			if len(after) > 0 {
+				setAnnotation(deployment, cluster.AfterJobsAnnotation, strings.Join(after, ","))
+			}
+			continue
+		}
+
+		if waits {
+			deployment.Spec.Replicas = pointer.Int32(0)
+			setLabel(deployment, cluster.WaitsForJobsLabel, "true")
+		}
+	}
+
+	for name, dependants := range dependencyJobs {
+		deployment, ok := deployments[name]
+		if !ok {
+			continue
+		}
+
+		waiting := []string{}
+		for _, dependant := range dependants {
+			if d, ok := deployments[dependant]; ok && d.GetLabels()[cluster.WaitsForJobsLabel] == "true" {
+				waiting = append(waiting, dependant)
+			}
+		}
+
+		if len(waiting) > 0 {
+			setAnnotation(deployment, cluster.DependantsAnnotation, strings.Join(waiting, ","))
+		}
+	}
+
+	return objs, nil
+}
+
+// setAnnotation copies the annotations before changing them, objects may share their labels and annotations
+func setAnnotation(obj metav1.Object, key, value string) {
+	annotations := map[string]string{key: value}
+	for k, v := range obj.GetAnnotations() {
+		if k != key {
+			annotations[k] = v
+		}
+	}
+
+	obj.SetAnnotations(annotations)
+}
+
+// setLabel copies the labels before changing them, objects may share their labels and annotations
+func setLabel(obj metav1.Object, key, value string) {
+	labels := map[string]string{key: value}
+	for k, v := range obj.GetLabels() {
+		if k != key {
+			labels[k] = v
+		}
+	}
+
+	obj.SetLabels(labels)
+}
+
+func makeWaitForContainer(service string, port int32) corev1.Container {
+	return corev1.Container{
+		Name:  fmt.Sprintf("wait-for-%s", service),
+		Image: "busybox",
+		Command: []string{
+			"sh",
+			"-c",
+			fmt.Sprintf("until nc -z -w 2 %s %d; do echo waiting for %s; sleep 2; done", service, port, service),
+		},
+		ImagePullPolicy: "IfNotPresent",
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	}
+}
+
+func addInitContainer(deployment *appsv1.Deployment, container corev1.Container) {
+	podSpec := &deployment.Spec.Template.Spec
+	for _, existing := range podSpec.InitContainers {
+		if existing.Name == container.Name {
+			return
+		}
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, container)
+}
@@ -0,0 +1,230 @@
+package transformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+	"github.com/ergomake/ergomake/internal/ergopack"
+)
+
+func TestLoadComposeDependencies(t *testing.T) {
+	t.Parallel()
+
+	dependencies, healthchecks, err := loadComposeDependencies([]byte(`
+version: '3'
+services:
+  api:
+    image: api
+    depends_on:
+      migrate:
+        condition: service_completed_successfully
+      db:
+        condition: service_healthy
+  web:
+    image: web
+    depends_on:
+      - api
+  db:
+    image: postgres
+    healthcheck:
+      test: pg_isready -U postgres
+      interval: 5s
+      retries: 10
+      start_period: 20s
+  cache:
+    image: redis
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+  disabled:
+    image: redis
+    healthcheck:
+      disable: true
+  migrate:
+    image: api
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]dependency{
+		"api": {
+			{Service: "db", Condition: conditionHealthy},
+			{Service: "migrate", Condition: conditionCompleted},
+		},
+		"web": {
+			{Service: "api", Condition: conditionStarted},
+		},
+	}, dependencies)
+
+	assert.Equal(t, map[string]*healthcheck{
+		"db": {
+			Command:     []string{"/bin/sh", "-c", "pg_isready -U postgres"},
+			Interval:    5 * time.Second,
+			Timeout:     defaultHealthcheckTimeout,
+			Retries:     10,
+			StartPeriod: 20 * time.Second,
+		},
+		"cache": {
+			Command:  []string{"redis-cli", "ping"},
+			Interval: defaultHealthcheckInterval,
+			Timeout:  defaultHealthcheckTimeout,
+			Retries:  defaultHealthcheckRetries,
+		},
+	}, healthchecks)
+}
+
+func TestLoadComposeDependenciesUnknownCondition(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := loadComposeDependencies([]byte(`
+services:
+  api:
+    depends_on:
+      db:
+        condition: service_happy
+  db:
+    image: postgres
+`))
+	assert.Error(t, err)
+}
+
+func TestLoadErgopackDependencies(t *testing.T) {
+	t.Parallel()
+
+	dependencies, healthchecks, err := loadErgopackDependencies(&ergopack.Ergopack{
+		Apps: map[string]ergopack.ErgopackApp{
+			"api": {
+				DependsOn: map[string]ergopack.ErgopackDependency{
+					"db":    {Condition: conditionHealthy},
+					"cache": {},
+				},
+			},
+			"db": {
+				Healthcheck: &ergopack.ErgopackHealthcheck{
+					Test:     []string{"CMD-SHELL", "pg_isready"},
+					Interval: "2s",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]dependency{
+		"api": {
+			{Service: "cache", Condition: conditionStarted},
+			{Service: "db", Condition: conditionHealthy},
+		},
+	}, dependencies)
+	assert.Equal(t, []string{"/bin/sh", "-c", "pg_isready"}, healthchecks["db"].Command)
+	assert.Equal(t, 2*time.Second, healthchecks["db"].Interval)
+}
+
+func TestAddProbes(t *testing.T) {
+	t.Parallel()
+
+	container := corev1.Container{}
+	addProbes(&container, &healthcheck{
+		Command:     []string{"true"},
+		Interval:    5 * time.Second,
+		Timeout:     time.Second,
+		Retries:     4,
+		StartPeriod: 30 * time.Second,
+	})
+
+	require.NotNil(t, container.ReadinessProbe)
+	require.NotNil(t, container.LivenessProbe)
+	assert.Equal(t, []string{"true"}, container.ReadinessProbe.Exec.Command)
+	assert.Equal(t, int32(0), container.ReadinessProbe.InitialDelaySeconds)
+	assert.Equal(t, int32(30), container.LivenessProbe.InitialDelaySeconds)
+	assert.Equal(t, int32(5), container.LivenessProbe.PeriodSeconds)
+	assert.Equal(t, int32(1), container.LivenessProbe.TimeoutSeconds)
+	assert.Equal(t, int32(4), container.LivenessProbe.FailureThreshold)
+}
+
+func makeDependencyTestDeployment(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"preview.ergomake.dev/service": name},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: name, Image: name}},
+				},
+			},
+		},
+	}
+}
+
+func TestGitCompose_applyDependencies(t *testing.T) {
+	t.Parallel()
+
+	api := makeDependencyTestDeployment("api")
+	migrate := makeDependencyTestDeployment("migrate")
+	migrate.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "data"}}
+	db := makeDependencyTestDeployment("db")
+	dbService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 5432}}},
+	}
+
+	c := &gitCompose{
+		dependencies: map[string][]dependency{
+			"api": {
+				{Service: "db", Condition: conditionHealthy},
+				{Service: "migrate", Condition: conditionCompleted},
+			},
+			"migrate": {
+				{Service: "db", Condition: conditionHealthy},
+			},
+		},
+	}
+
+	objs, err := c.applyDependencies([]runtime.Object{api, migrate, db, dbService})
+	require.NoError(t, err)
+
+	assert.Equal(t, []runtime.Object{api, migrate, db, dbService}, objs)
+
+	initContainers := api.Spec.Template.Spec.InitContainers
+	require.Len(t, initContainers, 1)
+	assert.Equal(t, "wait-for-db", initContainers[0].Name)
+	assert.Contains(t, initContainers[0].Command[2], "nc -z -w 2 db 5432")
+	assert.Empty(t, api.Spec.Template.Spec.Volumes)
+
+	// api is only scaled up by cluster.RunDependencyJobs once migrate completes
+	require.NotNil(t, api.Spec.Replicas)
+	assert.Equal(t, int32(0), *api.Spec.Replicas)
+	assert.Equal(t, "true", api.GetLabels()[cluster.WaitsForJobsLabel])
+	assert.Equal(t, "api", migrate.GetAnnotations()[cluster.DependantsAnnotation])
+
+	migrateInitContainers := migrate.Spec.Template.Spec.InitContainers
+	require.Len(t, migrateInitContainers, 1)
+	assert.Equal(t, "wait-for-db", migrateInitContainers[0].Name)
+}
+
+func TestGitCompose_dependencyJobs(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		dependencies: map[string][]dependency{
+			"api": {
+				{Service: "db", Condition: conditionHealthy},
+				{Service: "migrate", Condition: conditionCompleted},
+			},
+			"worker": {
+				{Service: "migrate", Condition: conditionCompleted},
+				{Service: "seed", Condition: conditionCompleted},
+			},
+		},
+		jobs: map[string]struct{}{"seed": {}},
+	}
+
+	assert.Equal(t, map[string][]string{"migrate": {"api", "worker"}}, c.dependencyJobs())
+}
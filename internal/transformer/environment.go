@@ -9,19 +9,26 @@ import (
 )
 
 type EnvironmentService struct {
-	ID            string            `json:"-"`
-	Url           string            `json:"url"`
-	Image         string            `json:"image"`
-	Build         string            `json:"build"`
-	Index         int               `json:"index"`
-	PublicPort    string            `json:"-"`
-	InternalPorts []string          `json:"-"`
-	Env           map[string]string `json:"-"`
+	ID            string                  `json:"-"`
+	Url           string                  `json:"url"`
+	Urls          []EnvironmentServiceUrl `json:"urls"`
+	Image         string                  `json:"image"`
+	Build         string                  `json:"build"`
+	BuildTool     string                  `json:"-"`
+	BuildReused   bool                    `json:"-"`
+	Index         int                     `json:"index"`
+	PublicPort    string                  `json:"-"`
+	InternalPorts []string                `json:"-"`
+	Env           map[string]string       `json:"-"`
+	// Job is set for services that run to completion as a job instead of a deployment
+	Job bool `json:"-"`
 }
 
 type Environment struct {
 	Services   map[string]EnvironmentService `json:"services"`
 	RawContent string                        `json:"-"`
+	// Update is nil unless the environment was updated in place
+	Update *EnvironmentUpdate `json:"-"`
 }
 
 func (c *Environment) ToMap() map[string]interface{} {
@@ -34,18 +41,30 @@ func (c *Environment) ToMap() map[string]interface{} {
 	return m
 }
 
+func EnvironmentServiceFromDB(svc database.Service) EnvironmentService {
+	urls := make([]EnvironmentServiceUrl, len(svc.Urls))
+	for i, url := range svc.Urls {
+		urls[i] = EnvironmentServiceUrl{Url: url.Url, Port: url.Port, Path: url.Path}
+	}
+
+	return EnvironmentService{
+		ID:            svc.ID,
+		Url:           svc.Url,
+		Urls:          urls,
+		Image:         svc.Image,
+		Build:         svc.Build,
+		BuildReused:   svc.BuildReused,
+		Index:         svc.Index,
+		PublicPort:    svc.PublicPort,
+		InternalPorts: svc.InternalPorts,
+		Job:           svc.Job,
+	}
+}
+
 func EnvironmentFromDB(env *database.Environment) *Environment {
 	services := make(map[string]EnvironmentService)
 	for _, svc := range env.Services {
-		services[svc.Name] = EnvironmentService{
-			ID:            svc.ID,
-			Url:           svc.Url,
-			Image:         svc.Image,
-			Build:         svc.Build,
-			Index:         svc.Index,
-			PublicPort:    svc.PublicPort,
-			InternalPorts: svc.InternalPorts,
-		}
+		services[svc.Name] = EnvironmentServiceFromDB(svc)
 	}
 
 	return &Environment{
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,8 +26,6 @@ import (
 	"github.com/kubernetes/kompose/pkg/loader"
 	"github.com/kubernetes/kompose/pkg/transformer/kubernetes"
 	"github.com/pkg/errors"
-	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -37,7 +36,9 @@ import (
 	"github.com/ergomake/ergomake/internal/ergopack"
 	"github.com/ergomake/ergomake/internal/git"
 	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/payment"
 	"github.com/ergomake/ergomake/internal/privregistry"
+	"github.com/ergomake/ergomake/internal/urltemplates"
 )
 
 var clusterDomain string
@@ -48,6 +49,8 @@ func init() {
 	setInsecureRegistry()
 	setUserlandRegistry()
 	setDomain()
+	setTLS()
+	setIngressNamespace()
 }
 
 func setInsecureRegistry() {
@@ -80,7 +83,7 @@ func setDomain() {
 			return
 		}
 
-		clusterDomain = "env.ergomake.test"
+		clusterDomain = urltemplates.DefaultDomain
 	}
 }
 
@@ -91,6 +94,8 @@ type gitCompose struct {
 	envVarsProvider      envvars.EnvVarsProvider
 	privRegistryProvider privregistry.PrivRegistryProvider
 
+	// provider is where the repository is hosted, one of the database providers
+	provider    string
 	owner       string
 	branchOwner string
 	repo        string
@@ -108,6 +113,41 @@ type gitCompose struct {
 	komposeObject  *kobject.KomposeObject
 	cleanup        func()
 
+	// previous is the environment being updated in place, nil when launching a new one
+	previous *database.Environment
+
+	builds map[string]buildSettings
+	// cloneTokenSecrets are the secrets the build jobs clone each repo with
+	cloneTokenSecrets map[string]*string
+	// buildSecretValues are redacted from the logs of the builds
+	buildSecretValues []string
+
+	// onBuildProgress is called whenever an image build finishes
+	onBuildProgress func(ctx context.Context)
+
+	// skipUnchanged is set when changedFiles, the paths the pull request changes since
+	// it diverged from baseBranch, are known
+	skipUnchanged bool
+	baseBranch    string
+	changedFiles  []string
+
+	// commentTemplate is the content of the comment template of the project, if it has one
+	commentTemplate string
+
+	// imageExists tells whether an image built before is still in the registry
+	imageExists func(ctx context.Context, image string) (bool, error)
+
+	volumes           map[string]composeVolume
+	persistentVolumes map[string][]kobject.Volumes
+	dependencies      map[string][]dependency
+	healthchecks      map[string]*healthcheck
+	resources         map[string]corev1.ResourceRequirements
+	jobs              map[string]struct{}
+	ergopackJobs      map[string]ergopack.ErgopackJob
+	plan              payment.PaymentPlan
+	urlTemplate       string
+	allowedHosts      []string
+
 	prepared                bool
 	dockerhubPullSecretName string
 }
@@ -118,6 +158,7 @@ func NewGitCompose(
 	db *database.DB,
 	envVarsProvider envvars.EnvVarsProvider,
 	privRegistryProvider privregistry.PrivRegistryProvider,
+	provider string,
 	owner string,
 	branchOwner string,
 	repo string,
@@ -127,6 +168,9 @@ func NewGitCompose(
 	author string,
 	isPublic bool,
 	dockerhubPullSecretName string,
+	plan payment.PaymentPlan,
+	urlTemplate string,
+	allowedHosts []string,
 ) *gitCompose {
 	return &gitCompose{
 		clusterClient:           clusterClient,
@@ -134,6 +178,7 @@ func NewGitCompose(
 		db:                      db,
 		envVarsProvider:         envVarsProvider,
 		privRegistryProvider:    privRegistryProvider,
+		provider:                provider,
 		owner:                   owner,
 		branchOwner:             branchOwner,
 		repo:                    repo,
@@ -143,14 +188,35 @@ func NewGitCompose(
 		author:                  author,
 		isPublic:                isPublic,
 		dockerhubPullSecretName: dockerhubPullSecretName,
+		plan:                    plan,
+		urlTemplate:             urlTemplate,
+		allowedHosts:            allowedHosts,
+		imageExists:             registryImageExists,
 	}
 }
 
+// OnBuildProgress registers fn to be called whenever an image build finishes, the build
+// status of the service is already saved by then. Builds run concurrently but fn is called
+// one build at a time.
+func (c *gitCompose) OnBuildProgress(fn func(ctx context.Context)) {
+	c.onBuildProgress = fn
+}
+
+// SkipUnchangedBuilds makes the builds that run in jobs and read none of changedFiles reuse the image
+// of the permanent environment of baseBranch, or of a previous environment of the pull request
+func (c *gitCompose) SkipUnchangedBuilds(baseBranch string, changedFiles []string) {
+	c.skipUnchanged = true
+	c.baseBranch = baseBranch
+	c.changedFiles = changedFiles
+}
+
 type TransformResult struct {
 	ClusterEnv  *cluster.ClusterEnv
 	Environment *Environment
 	FailedJobs  []*batchv1.Job
-	IsCompose   bool
+	// PendingBuilds is set when kpack is still building images, the buildpack
+	// watcher brings the environment up once they finish
+	PendingBuilds bool
 }
 
 func (tr *TransformResult) Failed() bool {
@@ -164,7 +230,6 @@ type PrepareResult struct {
 }
 
 func (c *gitCompose) Prepare(ctx context.Context, id uuid.UUID) (*PrepareResult, error) {
-	namespace := id.String()
 	dbEnv := database.NewEnvironment(
 		id,
 		c.owner,
@@ -175,11 +240,39 @@ func (c *gitCompose) Prepare(ctx context.Context, id uuid.UUID) (*PrepareResult,
 		c.author,
 		database.EnvPending,
 	)
+	dbEnv.Provider = c.provider
 	err := c.db.Create(&dbEnv).Error
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to create environment in db")
 	}
 
+	return c.prepare(ctx, dbEnv)
+}
+
+// PrepareUpdate is Prepare for updating previous in place, it keeps its ID and namespace
+// and the services whose build inputs didn't change keep their images
+func (c *gitCompose) PrepareUpdate(ctx context.Context, previous *database.Environment) (*PrepareResult, error) {
+	c.previous = previous
+
+	dbEnv := *previous
+	// services are replaced by Transform, saving them here would bring the old ones back
+	dbEnv.Services = nil
+	dbEnv.Author = c.author
+	dbEnv.Status = database.EnvPending
+	dbEnv.DegradedReason = nil
+	// the check run and the deployment belong to the previous commit
+	dbEnv.GHCheckRunID = 0
+	dbEnv.GHDeploymentID = 0
+	err := c.db.Save(&dbEnv).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to update environment in db")
+	}
+
+	return c.prepare(ctx, &dbEnv)
+}
+
+func (c *gitCompose) prepare(ctx context.Context, dbEnv *database.Environment) (*PrepareResult, error) {
+	namespace := dbEnv.ID.String()
 	c.dbEnvironment = dbEnv
 
 	loadErgopackResult, err := c.loadErgopack(ctx, namespace)
@@ -189,11 +282,28 @@ func (c *gitCompose) Prepare(ctx context.Context, id uuid.UUID) (*PrepareResult,
 
 	c.prepared = true
 
+	if !loadErgopackResult.Skip && loadErgopackResult.ValidationError == nil &&
+		c.provider != database.ProviderGitHub && c.usesBuildpacks() {
+		// kpack only knows how to clone from github
+		loadErgopackResult.ValidationError = &ProjectValidationError{
+			T: "unsupported-builder",
+			Message: "Buildpacks are only supported on GitHub, use a `docker-compose.yml` or set `builder: buildkit` " +
+				"on the apps of the ergopack.",
+		}
+	}
+
 	if loadErgopackResult.Skip {
 		err := c.db.Delete(c.dbEnvironment).Error
 		if err != nil {
 			logger.Ctx(ctx).Err(err).Msg("fail to delete skipped environment")
 		}
+
+		if c.previous != nil {
+			err := c.clusterClient.DeleteNamespace(ctx, namespace)
+			if err != nil {
+				logger.Ctx(ctx).Err(err).Msg("fail to delete namespace of skipped environment")
+			}
+		}
 	}
 
 	if loadErgopackResult.ValidationError != nil {
@@ -242,7 +352,7 @@ func (c *gitCompose) Transform(ctx context.Context, id uuid.UUID) (*TransformRes
 	}
 
 	namespace := id.String()
-	result := &TransformResult{IsCompose: c.isCompose}
+	result := &TransformResult{PendingBuilds: c.usesBuildpacks()}
 
 	err := c.saveServices(ctx, id, c.environment)
 	if err != nil {
@@ -279,9 +389,21 @@ func (c *gitCompose) Transform(ctx context.Context, id uuid.UUID) (*TransformRes
 		objects = objs
 	}
 
+	specHashes, err := makeSpecHashes(objects)
+	if err != nil {
+		return nil, c.fail(errors.Wrap(err, "fail to hash specs"))
+	}
+
+	err = c.saveSpecHashes(ctx, specHashes)
+	if err != nil {
+		return nil, c.fail(errors.Wrap(err, "fail to save spec hashes"))
+	}
+
+	c.environment.Update = c.makeEnvironmentUpdate(specHashes)
 	result.ClusterEnv = &cluster.ClusterEnv{
 		Namespace: namespace,
 		Objects:   objects,
+		Prune:     c.previous != nil,
 	}
 	result.Environment = c.environment
 
@@ -289,7 +411,7 @@ func (c *gitCompose) Transform(ctx context.Context, id uuid.UUID) (*TransformRes
 }
 
 func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) ([]runtime.Object, error) {
-	vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.owner, c.repo, c.branch)
+	vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.provider, c.owner, c.repo, c.branch)
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to list env vars by repo")
 	}
@@ -301,6 +423,12 @@ func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) (
 
 	objs := []runtime.Object{secret}
 
+	tlsObjs, err := c.makeTLSObjects(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to make tls objects")
+	}
+	objs = append(objs, tlsObjs...)
+
 	envVarsSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "env-vars",
@@ -330,7 +458,13 @@ func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) (
 
 	objs = append(objs, envVarsSecret)
 
+	dbEnv := append([]corev1.EnvVar{}, env...)
 	for serviceName, envService := range c.environment.Services {
+		// jobs are made out of c.ergopackJobs below
+		if envService.Job {
+			continue
+		}
+
 		for k, v := range envService.Env {
 			if _, ok := dbVars[k]; ok {
 				continue
@@ -343,8 +477,13 @@ func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) (
 
 		containerPorts := []corev1.ContainerPort{}
 		servicePorts := []corev1.ServicePort{}
+		publicPorts := []string{envService.PublicPort}
+		for _, url := range envService.Urls {
+			publicPorts = append(publicPorts, strconv.Itoa(url.Port))
+		}
+
 		ports := map[int]struct{}{}
-		for _, strPort := range append(envService.InternalPorts, envService.PublicPort) {
+		for _, strPort := range append(envService.InternalPorts, publicPorts...) {
 			if strPort == "" {
 				continue
 			}
@@ -360,6 +499,7 @@ func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) (
 			if _, ok := ports[port]; ok {
 				continue
 			}
+			ports[port] = struct{}{}
 
 			containerPorts = append(containerPorts, corev1.ContainerPort{
 				ContainerPort: int32(port),
@@ -374,66 +514,21 @@ func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) (
 		}
 
 		container := corev1.Container{
-			Name:  serviceName,
-			Image: envService.Image,
-			Ports: containerPorts,
-			Env:   env,
-			Resources: corev1.ResourceRequirements{
-				Limits: corev1.ResourceList{
-					corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
-					corev1.ResourceMemory:           resource.MustParse("1Gi"),
-				},
-				Requests: corev1.ResourceList{
-					corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
-					corev1.ResourceMemory:           resource.MustParse("1Gi"),
-				},
-			},
+			Name:            serviceName,
+			Image:           envService.Image,
+			Ports:           containerPorts,
+			Env:             env,
+			Resources:       c.getResources(serviceName, ergopackEphemeralStorage),
 			ImagePullPolicy: "IfNotPresent",
 		}
+		addProbes(&container, c.healthchecks[serviceName])
 
-		deployment := &appsv1.Deployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        serviceName,
-				Namespace:   namespace,
-				Labels:      labels,
-				Annotations: labels,
-			},
-			Spec: appsv1.DeploymentSpec{
-				Replicas: pointer.Int32(0),
-				Selector: &metav1.LabelSelector{
-					MatchLabels: map[string]string{
-						"preview.ergomake.dev/service": serviceName,
-					},
-				},
-				Template: corev1.PodTemplateSpec{
-					ObjectMeta: metav1.ObjectMeta{
-						Labels:      labels,
-						Annotations: labels,
-					},
-					Spec: corev1.PodSpec{
-						ImagePullSecrets: []corev1.LocalObjectReference{{Name: secret.GetName()}},
-						Containers:       []corev1.Container{container},
-						NodeSelector: map[string]string{
-							"preview.ergomake.dev/role": "preview",
-						},
-						SecurityContext: &corev1.PodSecurityContext{
-							SeccompProfile: &corev1.SeccompProfile{
-								Type: "RuntimeDefault",
-							},
-						},
-						Tolerations: []corev1.Toleration{
-							{
-								Key:      "preview.ergomake.dev/domain",
-								Operator: "Equal",
-								Value:    "previews",
-								Effect:   "NoSchedule",
-							},
-						},
-					},
-				},
-			},
+		// apps built by kpack are scaled up once every build finishes
+		replicas := int32(1)
+		if c.usesBuildpacks() {
+			replicas = 0
 		}
-
+		deployment := makeErgopackDeployment(namespace, serviceName, labels, container, secret.GetName(), replicas)
 		objs = append(objs, deployment)
 
 		service := &corev1.Service{
@@ -452,55 +547,121 @@ func (c *gitCompose) makeClusterObjects(ctx context.Context, namespace string) (
 		}
 		objs = append(objs, service)
 
-		if envService.PublicPort != "" {
-			port, err := strconv.Atoi(envService.PublicPort)
-			if err != nil {
-				logger.Ctx(ctx).Warn().AnErr("err", err).Str("strPort", envService.PublicPort).
-					Msg("fail to convert PublicPort to int")
-				continue
-			}
+		if len(envService.Urls) > 0 {
+			objs = append(objs, makeIngress(namespace, serviceName, labels, labels, envService.Urls))
+		}
+	}
+
+	for jobName, job := range c.ergopackJobs {
+		objs = append(objs, c.makeErgopackJobDeployment(ctx, namespace, jobName, job, dbEnv, secret.GetName()))
+	}
+
+	objs = append(objs, c.makeNetworkPolicies(ctx, namespace, nil)...)
+
+	objs, err = c.applyDependencies(objs)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to apply dependencies")
+	}
+
+	return c.convertJobs(objs), nil
+}
 
-			pathType := networkingv1.PathTypePrefix
-			ingress := &networkingv1.Ingress{
-				TypeMeta: metav1.TypeMeta{},
+func makeErgopackDeployment(
+	namespace string,
+	name string,
+	labels map[string]string,
+	container corev1.Container,
+	pullSecretName string,
+	replicas int32,
+) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"preview.ergomake.dev/service": name,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:        serviceName,
-					Namespace:   namespace,
 					Labels:      labels,
 					Annotations: labels,
 				},
-				Spec: networkingv1.IngressSpec{
-					IngressClassName: pointer.String("nginx"),
-					Rules: []networkingv1.IngressRule{
+				Spec: corev1.PodSpec{
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: pullSecretName}},
+					Containers:       []corev1.Container{container},
+					NodeSelector: map[string]string{
+						"preview.ergomake.dev/role": "preview",
+					},
+					SecurityContext: &corev1.PodSecurityContext{
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: "RuntimeDefault",
+						},
+					},
+					Tolerations: []corev1.Toleration{
 						{
-							Host: envService.Url,
-							IngressRuleValue: networkingv1.IngressRuleValue{
-								HTTP: &networkingv1.HTTPIngressRuleValue{
-									Paths: []networkingv1.HTTPIngressPath{
-										{
-											Path:     "/",
-											PathType: &pathType,
-											Backend: networkingv1.IngressBackend{
-												Service: &networkingv1.IngressServiceBackend{
-													Name: serviceName,
-													Port: networkingv1.ServiceBackendPort{
-														Number: int32(port),
-													},
-												},
-											},
-										},
-									},
-								},
-							},
+							Key:      "preview.ergomake.dev/domain",
+							Operator: "Equal",
+							Value:    "previews",
+							Effect:   "NoSchedule",
 						},
 					},
 				},
-			}
-			objs = append(objs, ingress)
+			},
+		},
+	}
+}
+
+func (c *gitCompose) makeErgopackJobDeployment(
+	ctx context.Context,
+	namespace string,
+	name string,
+	job ergopack.ErgopackJob,
+	dbEnv []corev1.EnvVar,
+	pullSecretName string,
+) *appsv1.Deployment {
+	env := append([]corev1.EnvVar{}, dbEnv...)
+	dbVars := make(map[string]struct{})
+	for _, v := range dbEnv {
+		dbVars[v.Name] = struct{}{}
+	}
+
+	keys := []string{}
+	for k := range job.Env {
+		if _, ok := dbVars[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	mustache.AllowMissingVariables = false
+	templateContext := c.environment.ToMap()
+	for _, k := range keys {
+		value, err := mustache.Render(job.Env[k], templateContext)
+		if err != nil {
+			logger.Ctx(ctx).Err(err).Str("var", k).Str("job", name).Msg("fail to render env var")
 		}
+
+		env = append(env, corev1.EnvVar{Name: k, Value: value})
+	}
+
+	envService := c.environment.Services[name]
+	container := corev1.Container{
+		Name:            name,
+		Image:           envService.Image,
+		Command:         job.Command,
+		Env:             env,
+		Resources:       c.getResources(name, ergopackEphemeralStorage),
+		ImagePullPolicy: "IfNotPresent",
 	}
 
-	return objs, nil
+	return makeErgopackDeployment(namespace, name, c.getLabels(envService.ID, name), container, pullSecretName, 0)
 }
 
 func (c *gitCompose) saveServices(ctx context.Context, envID uuid.UUID, compose *Environment) error {
@@ -511,20 +672,36 @@ func (c *gitCompose) saveServices(ctx context.Context, envID uuid.UUID, compose
 			buildStatus = "building"
 		}
 
+		urls := make([]database.ServiceUrl, len(service.Urls))
+		for i, url := range service.Urls {
+			urls[i] = database.ServiceUrl{Url: url.Url, Port: url.Port, Path: url.Path}
+		}
+
 		services = append(services, database.Service{
 			ID:            service.ID,
 			Name:          name,
 			EnvironmentID: envID,
 			Url:           service.Url,
+			Urls:          urls,
 			Build:         service.Build,
 			BuildStatus:   buildStatus,
+			BuildTool:     service.BuildTool,
 			Image:         service.Image,
 			Index:         service.Index,
 			PublicPort:    service.PublicPort,
 			InternalPorts: service.InternalPorts,
+			Job:           service.Job,
 		})
 	}
 
+	if c.previous != nil {
+		// ids are kept across updates so the old rows must really go, their urls go with them
+		err := c.db.Unscoped().Where("environment_id = ?", envID).Delete(&database.Service{}).Error
+		if err != nil {
+			return errors.Wrap(err, "fail to delete services of previous deploy")
+		}
+	}
+
 	if len(services) == 0 {
 		return nil
 	}
@@ -532,30 +709,8 @@ func (c *gitCompose) saveServices(ctx context.Context, envID uuid.UUID, compose
 	return c.db.Create(&services).Error
 }
 
-// returns empty when service should not be exposed
-func (c *gitCompose) getUrl(service kobject.ServiceConfig) string {
-	for _, port := range service.Port {
-		suffix := c.branch
-		if c.prNumber != nil {
-			suffix = strconv.Itoa(*c.prNumber)
-		}
-
-		if port.HostPort > 0 {
-			return strings.ToLower(fmt.Sprintf(
-				"%s-%s-%s-%s.%s",
-				service.Name,
-				c.owner,
-				strings.ReplaceAll(c.repo, "_", ""),
-				suffix,
-				clusterDomain,
-			))
-		}
-	}
-
-	return ""
-}
-
 func (c *gitCompose) fixComposeObject(projectPath, namespace string) error {
+	dependencyJobs := c.dependencyJobs()
 	for k, service := range c.komposeObject.ServiceConfigs {
 		if service.Build != "" {
 			service.Image = fmt.Sprintf(
@@ -567,17 +722,22 @@ func (c *gitCompose) fixComposeObject(projectPath, namespace string) error {
 			service.Build = strings.Replace(service.Build, projectPath, "", 1)
 		}
 
-		service.ExposeService = c.getUrl(service)
-		if service.ExposeService != "" {
-			service.ExposeServiceIngressClassName = "nginx"
-		}
+		// we make the ingresses ourselves since kompose can only route the first port
+		service.ExposeService = ""
 
 		err := evaluateLabels(&service, c.environment)
 		if err != nil {
 			return errors.Wrap(err, "fail to evaluate ergomake specific labels")
 		}
 
-		c.removeUnsupportedVolumes(&service)
+		c.extractPersistentVolumes(k, &service)
+
+		// kompose turns services that don't restart into pods, jobs have to stay deployments until we convert them
+		_, isJob := c.jobs[k]
+		_, isDependencyJob := dependencyJobs[k]
+		if isJob || isDependencyJob {
+			service.Restart = ""
+		}
 
 		c.komposeObject.ServiceConfigs[k] = service
 	}
@@ -585,18 +745,6 @@ func (c *gitCompose) fixComposeObject(projectPath, namespace string) error {
 	return nil
 }
 
-func (c *gitCompose) removeUnsupportedVolumes(service *kobject.ServiceConfig) {
-	volumes := []kobject.Volumes{}
-	for _, vol := range service.Volumes {
-		if strings.HasPrefix(vol.MountPath, ":") {
-			continue
-		}
-		volumes = append(volumes, vol)
-	}
-
-	service.Volumes = volumes
-}
-
 type LoadErgopackResult struct {
 	Skip            bool
 	ValidationError *ProjectValidationError
@@ -631,20 +779,15 @@ func (c *gitCompose) loadErgopack(ctx context.Context, namespace string) (*LoadE
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to validate project")
 	}
+	c.dbEnvironment.CommentTemplate = c.commentTemplate
 
 	if validationErr != nil {
 		return &LoadErgopackResult{Skip: false, ValidationError: validationErr}, nil
 	}
 
-	if c.isCompose {
-		c.dbEnvironment.BuildTool = "kaniko"
-	} else {
-		c.dbEnvironment.BuildTool = "buildpacks"
-	}
-
 	err = c.db.Save(&c.dbEnvironment).Error
 	if err != nil {
-		return nil, errors.Wrap(err, "fail to save env build_tool to db")
+		return nil, errors.Wrap(err, "fail to save env comment template to db")
 	}
 
 	configBytes, err := ioutil.ReadFile(c.configFilePath)
@@ -665,10 +808,38 @@ func (c *gitCompose) loadErgopack(ctx context.Context, namespace string) (*LoadE
 		}
 		c.komposeObject = &komposeObject
 
-		c.environment = c.makeEnvironmentFromKObjectServices(
+		c.volumes, err = loadComposeVolumes(configBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to load compose volumes")
+		}
+
+		c.dependencies, c.healthchecks, err = loadComposeDependencies(configBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to load compose dependencies")
+		}
+
+		if validationErr := c.loadComposeResources(); validationErr != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: validationErr}, nil
+		}
+
+		c.builds, err = loadComposeBuilds(configBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to load compose builds")
+		}
+
+		c.jobs = loadComposeJobs(komposeObject.ServiceConfigs)
+		err = checkJobDependencies(c.jobs, c.dependencies)
+		if err != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: makeJobValidationError("invalid-compose", err)}, nil
+		}
+
+		c.environment, err = c.makeEnvironmentFromKObjectServices(
 			komposeObject.ServiceConfigs,
 			configStr,
 		)
+		if err != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: makeUrlTemplateValidationError(err)}, nil
+		}
 
 		err = c.fixComposeObject(projectPath, namespace)
 		if err != nil {
@@ -692,7 +863,54 @@ func (c *gitCompose) loadErgopack(ctx context.Context, namespace string) (*LoadE
 			}, nil
 		}
 
-		c.environment = c.makeEnvironmentFromErgopack(ctx, &pack, string(configBytes))
+		c.dependencies, c.healthchecks, err = loadErgopackDependencies(&pack)
+		if err != nil {
+			return &LoadErgopackResult{
+				Skip: false,
+				ValidationError: &ProjectValidationError{
+					T:       "invalid-ergopack",
+					Message: fmt.Sprintf("Ergopack file has invalid dependencies\n```\n%s\n```", err.Error()),
+				},
+			}, nil
+		}
+
+		if validationErr := c.loadErgopackResources(&pack); validationErr != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: validationErr}, nil
+		}
+
+		c.jobs, err = loadErgopackJobs(&pack)
+		if err == nil {
+			err = checkJobDependencies(c.jobs, c.dependencies)
+		}
+		if err != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: makeJobValidationError("invalid-ergopack", err)}, nil
+		}
+		c.ergopackJobs = pack.Jobs
+
+		c.builds, err = loadErgopackBuilds(&pack)
+		if err != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: makeBuildValidationError("invalid-ergopack", err)}, nil
+		}
+
+		c.environment, err = c.makeEnvironmentFromErgopack(ctx, &pack, string(configBytes))
+		if err != nil {
+			return &LoadErgopackResult{Skip: false, ValidationError: makeUrlTemplateValidationError(err)}, nil
+		}
+	}
+
+	validationErr, err = c.checkUrlCollisions()
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to check url collisions")
+	}
+
+	if validationErr != nil {
+		return &LoadErgopackResult{Skip: false, ValidationError: validationErr}, nil
+	}
+
+	if validationErr := c.checkCommentTemplate(); validationErr != nil {
+		c.commentTemplate = ""
+		c.dbEnvironment.CommentTemplate = ""
+		return &LoadErgopackResult{Skip: false, ValidationError: validationErr}, nil
 	}
 
 	return &LoadErgopackResult{}, nil
@@ -706,6 +924,7 @@ func (c *gitCompose) transformCompose(ctx context.Context, namespace string) ([]
 		Replicas:   1,
 		PushImage:  false,
 		InputFiles: []string{c.configFilePath},
+		// named and anonymous volumes were already extracted into PVCs, only bind mounts get here
 		Volumes:    "configMap",
 		Controller: "deployment",
 	}
@@ -722,8 +941,32 @@ func (c *gitCompose) transformCompose(ctx context.Context, namespace string) ([]
 	}
 
 	extraObjs, err := c.fixOutput(ctx, &objects, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to fix output")
+	}
+
+	claims, err := c.makePersistentVolumeClaims(namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to make persistent volume claims")
+	}
+
+	objects = append(objects, claims...)
+
+	objects, err = c.applyDependencies(objects)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to apply dependencies")
+	}
+
+	objects = append(objects, c.makeComposeIngresses(namespace)...)
+	objects = c.mergeNetworkPolicies(ctx, namespace, objects)
 
-	return append(objects, extraObjs...), errors.Wrap(err, "fail to fix output")
+	tlsObjs, err := c.makeTLSObjects(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to make tls objects")
+	}
+	objects = append(objects, tlsObjs...)
+
+	return append(c.convertJobs(objects), extraObjs...), nil
 }
 
 func (c *gitCompose) cloneRepo(ctx context.Context, namespace string) (string, error) {
@@ -744,52 +987,98 @@ func (c *gitCompose) cloneRepo(ctx context.Context, namespace string) (string, e
 	return dir, errors.Wrap(err, "fail to clone from github")
 }
 
-func (c *gitCompose) makeEnvironmentFromKObjectServices(komposeServices map[string]kobject.ServiceConfig, rawCompose string) *Environment {
+func (c *gitCompose) makeEnvironmentFromKObjectServices(
+	komposeServices map[string]kobject.ServiceConfig,
+	rawCompose string,
+) (*Environment, error) {
+	dependencyJobs := c.dependencyJobs()
 	services := map[string]EnvironmentService{}
 	for _, service := range komposeServices {
+		_, isJob := c.jobs[service.Name]
+		if _, ok := dependencyJobs[service.Name]; ok {
+			isJob = true
+		}
+
+		// jobs are not routed to
+		var urls []EnvironmentServiceUrl
+		if !isJob {
+			var err error
+			urls, err = c.getUrls(service)
+			if err != nil {
+				return nil, err
+			}
+		}
+		url := ""
+		if len(urls) > 0 {
+			url = urls[0].Url
+		}
+
+		// updates keep the ids so the pods of services that didn't change are not rolled
+		id := uuid.NewString()
+		if previous, ok := c.previousService(service.Name); ok {
+			id = previous.ID
+		}
+
+		buildTool := ""
+		if service.Build != "" || service.Dockerfile != "" {
+			buildTool = c.builds[service.Name].Tool
+		}
+
 		services[service.Name] = EnvironmentService{
-			ID:    uuid.NewString(),
-			Url:   c.getUrl(service),
-			Image: service.Image,
-			Build: service.Build,
+			ID:        id,
+			Url:       url,
+			Urls:      urls,
+			Image:     service.Image,
+			Build:     service.Build,
+			BuildTool: buildTool,
+			Job:       isJob,
 		}
 	}
 
-	return NewEnvironment(services, rawCompose)
+	return NewEnvironment(services, rawCompose), nil
 }
 
-func (c *gitCompose) makeEnvironmentFromErgopack(ctx context.Context, pack *ergopack.Ergopack, rawFile string) *Environment {
+func (c *gitCompose) makeEnvironmentFromErgopack(
+	ctx context.Context,
+	pack *ergopack.Ergopack,
+	rawFile string,
+) (*Environment, error) {
 	services := map[string]EnvironmentService{}
 	i := 0
 	for name, service := range pack.Apps {
+		urls, err := c.makeServiceUrls(name, getErgopackPublicPorts(ctx, name, service), getErgopackPaths(ctx, name, service))
+		if err != nil {
+			return nil, err
+		}
 		url := ""
-		if service.PublicPort != "" {
-			suffix := c.branch
-			if c.prNumber != nil {
-				suffix = strconv.Itoa(*c.prNumber)
-			}
-
-			url = strings.ToLower(fmt.Sprintf(
-				"%s-%s-%s-%s.%s",
-				name,
-				c.owner,
-				strings.ReplaceAll(c.repo, "_", ""),
-				suffix,
-				clusterDomain,
-			))
+		if len(urls) > 0 {
+			url = urls[0].Url
 		}
 
+		// updates keep the ids so the pods of apps that didn't change are not rolled
 		id := uuid.NewString()
+		if previous, ok := c.previousService(name); ok {
+			id = previous.ID
+		}
+
 		image := service.Image
 		if image == "" {
-			image = strings.ToLower(fmt.Sprintf("ergomake/%s-%s-%s:%s", c.owner, c.repo, name, id))
+			// buildpacks push every build to this tag so it must not be the id, that is kept by updates
+			image = strings.ToLower(fmt.Sprintf("ergomake/%s-%s-%s:%s", c.owner, c.repo, name, uuid.NewString()))
+		}
+
+		buildTool := ""
+		if service.Path != "" {
+			buildTool = c.builds[name].Tool
 		}
 
 		services[name] = EnvironmentService{
 			ID:            id,
 			Url:           url,
+			Urls:          urls,
 			Image:         image,
 			Build:         service.Path,
+			BuildTool:     buildTool,
 			PublicPort:    service.PublicPort,
 			InternalPorts: service.InternalPorts,
 			Index:         i,
@@ -798,6 +1087,34 @@ func (c *gitCompose) makeEnvironmentFromErgopack(ctx context.Context, pack *ergo
 		i += 1
 	}
 
+	jobNames := make([]string, 0, len(pack.Jobs))
+	for name := range pack.Jobs {
+		jobNames = append(jobNames, name)
+	}
+	sort.Strings(jobNames)
+
+	// jobs come after the apps so they never take the place of the first one
+	for _, name := range jobNames {
+		job := pack.Jobs[name]
+		image := job.Image
+		if image == "" {
+			image = services[job.App].Image
+		}
+
+		id := uuid.NewString()
+		if previous, ok := c.previousService(name); ok {
+			id = previous.ID
+		}
+
+		services[name] = EnvironmentService{
+			ID:    id,
+			Image: image,
+			Index: i,
+			Job:   true,
+		}
+		i += 1
+	}
+
 	env := NewEnvironment(services, rawFile)
 
 	mustache.AllowMissingVariables = false
@@ -816,7 +1133,7 @@ func (c *gitCompose) makeEnvironmentFromErgopack(ctx context.Context, pack *ergo
 		env.Services[name] = service
 	}
 
-	return env
+	return env, nil
 }
 
 func evaluateLabels(service *kobject.ServiceConfig, env *Environment) error {
@@ -889,6 +1206,8 @@ func (c *gitCompose) fixDeployment(ctx context.Context, obj runtime.Object) ([]r
 	c.fixPullPolicy(deployment)
 	c.addResourceLimits(deployment)
 	c.removeHostPort(deployment)
+	c.addPersistentVolumes(deployment)
+	c.addHealthcheck(deployment)
 
 	envVarsSecret, err := c.addEnvVars(ctx, deployment)
 	if err != nil {
@@ -949,21 +1268,6 @@ func (c *gitCompose) fixPullPolicy(deployment *appsv1.Deployment) {
 	}
 }
 
-func (c *gitCompose) addResourceLimits(deployment *appsv1.Deployment) {
-	podSpec := &deployment.Spec.Template.Spec
-	for i := range podSpec.Containers {
-		podSpec.Containers[i].Resources.Limits = corev1.ResourceList{
-			corev1.ResourceEphemeralStorage: resource.MustParse("5Gi"),
-			corev1.ResourceMemory:           resource.MustParse("1Gi"),
-		}
-
-		podSpec.Containers[i].Resources.Requests = corev1.ResourceList{
-			corev1.ResourceEphemeralStorage: resource.MustParse("5Gi"),
-			corev1.ResourceMemory:           resource.MustParse("1Gi"),
-		}
-	}
-}
-
 func (c *gitCompose) removeHostPort(deployment *appsv1.Deployment) {
 	podSpec := &deployment.Spec.Template.Spec
 	for i := range podSpec.Containers {
@@ -999,6 +1303,9 @@ func (c *gitCompose) addLabels(deployment *appsv1.Deployment) {
 	}
 	deployment.SetAnnotations(mergedDeploymentAnnotations)
 
+	// pods don't carry the sha, otherwise every commit would roll every deployment of an update
+	delete(labels, "preview.ergomake.dev/sha")
+
 	mergedPodLabels := deployment.Spec.Template.GetObjectMeta().GetLabels()
 	for k, v := range labels {
 		mergedPodLabels[k] = v
@@ -1016,7 +1323,7 @@ func (c *gitCompose) addEnvVars(ctx context.Context, deployment *appsv1.Deployme
 	service := c.environment.Services[deployment.GetLabels()["io.kompose.service"]]
 	repo, _ := c.computeRepoAndBuildPath(service.Build, c.repo)
 
-	vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.owner, repo, c.branch)
+	vars, err := c.envVarsProvider.ListByRepoBranch(ctx, c.provider, c.owner, repo, c.branch)
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to list env vars by repo")
 	}
@@ -1069,7 +1376,7 @@ func (c *gitCompose) getSecretForImage(
 		return nil, nil
 	}
 
-	creds, err := c.privRegistryProvider.FetchCreds(ctx, c.owner, image)
+	creds, err := c.privRegistryProvider.FetchCreds(ctx, c.provider, c.owner, image)
 	if errors.Is(err, privregistry.ErrRegistryNotFound) {
 		return nil, nil
 	}
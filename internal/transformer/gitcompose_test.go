@@ -25,6 +25,8 @@ import (
 	"github.com/ergomake/ergomake/e2e/testutils"
 	"github.com/ergomake/ergomake/internal/cluster"
 	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/ergopack"
+	"github.com/ergomake/ergomake/internal/payment"
 	"github.com/ergomake/ergomake/internal/privregistry"
 	clusterMock "github.com/ergomake/ergomake/mocks/cluster"
 	envvarsMocks "github.com/ergomake/ergomake/mocks/envvars"
@@ -55,7 +57,7 @@ func TestGitCompose_Prepare(t *testing.T) {
 					clusterClient, gitClient, db,
 					envvarsMocks.NewEnvVarsProvider(t),
 					privregistryMock.NewPrivRegistryProvider(t),
-					"owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", true, "hub-secret",
+					database.ProviderGitHub, "owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", true, "hub-secret", payment.PaymentPlanFree, "", nil,
 				)
 			},
 		},
@@ -124,16 +126,16 @@ func TestGitCompose_Transform(t *testing.T) {
 				require.NoError(t, err)
 
 				envVarsProvider := envvarsMocks.NewEnvVarsProvider(t)
-				envVarsProvider.EXPECT().ListByRepoBranch(mock.Anything, "owner", "repo", "branch").Return(nil, nil)
+				envVarsProvider.EXPECT().ListByRepoBranch(mock.Anything, database.ProviderGitHub, "owner", "repo", "branch").Return(nil, nil)
 
 				privRegistryProvider := privregistryMock.NewPrivRegistryProvider(t)
-				privRegistryProvider.EXPECT().FetchCreds(mock.Anything, "owner", "mongo").Return(nil, privregistry.ErrRegistryNotFound)
-				privRegistryProvider.EXPECT().FetchCreds(mock.Anything, "owner", "willbuild").Return(nil, privregistry.ErrRegistryNotFound)
+				privRegistryProvider.EXPECT().FetchCreds(mock.Anything, database.ProviderGitHub, "owner", "mongo").Return(nil, privregistry.ErrRegistryNotFound)
+				privRegistryProvider.EXPECT().FetchCreds(mock.Anything, database.ProviderGitHub, "owner", "willbuild").Return(nil, privregistry.ErrRegistryNotFound)
 
 				gc := NewGitCompose(
 					clusterClient, gitClient, db, envVarsProvider,
 					privRegistryProvider,
-					"owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", false, "hub-secret",
+					database.ProviderGitHub, "owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", false, "hub-secret", payment.PaymentPlanFree, "", nil,
 				)
 				gc.komposeObject = &kobject.KomposeObject{
 					ServiceConfigs: map[string]kobject.ServiceConfig{
@@ -207,7 +209,7 @@ services:
 					clusterClient, gitClient, &database.DB{},
 					envvarsMocks.NewEnvVarsProvider(t),
 					privregistryMock.NewPrivRegistryProvider(t),
-					"owner", "owner", repo, "branch", "sha", pointer.Int(1337), "author", true, "hub-secret",
+					database.ProviderGitHub, "owner", "owner", repo, "branch", "sha", pointer.Int(1337), "author", true, "hub-secret", payment.PaymentPlanFree, "", nil,
 				)
 			},
 			namespace: "delete-repo",
@@ -296,12 +298,16 @@ services:
 					Build: "path/to/build",
 					Image: "",
 					Url:   "service1-owner-repo-1337.env.ergomake.test",
+					Urls: []EnvironmentServiceUrl{
+						{Url: "service1-owner-repo-1337.env.ergomake.test", Port: 8080, Path: "/"},
+					},
 					Index: 1,
 				},
 				"service2": {
 					Build: "",
 					Image: "existingImage",
 					Url:   "",
+					Urls:  []EnvironmentServiceUrl{},
 					Index: 0,
 				},
 			},
@@ -320,9 +326,10 @@ services:
 				clusterClient, gitClient, &database.DB{},
 				envvarsMocks.NewEnvVarsProvider(t),
 				privregistryMock.NewPrivRegistryProvider(t),
-				"owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", true, "hub-secret",
+				database.ProviderGitHub, "owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", true, "hub-secret", payment.PaymentPlanFree, "", nil,
 			)
-			env := gc.makeEnvironmentFromKObjectServices(tc.services, tc.rawCompose)
+			env, err := gc.makeEnvironmentFromKObjectServices(tc.services, tc.rawCompose)
+			require.NoError(t, err)
 
 			for k, s := range env.Services {
 				assert.NotEmpty(t, s.ID)
@@ -334,6 +341,51 @@ services:
 	}
 }
 
+func TestGitCompose_makeEnvironmentFromErgopack(t *testing.T) {
+	t.Parallel()
+
+	gc := NewGitCompose(
+		clusterMock.NewClient(t), gitMock.NewRemoteGitClient(t), &database.DB{},
+		envvarsMocks.NewEnvVarsProvider(t),
+		privregistryMock.NewPrivRegistryProvider(t),
+		database.ProviderGitHub, "owner", "owner", "repo", "branch", "sha", pointer.Int(1337), "author", true, "hub-secret", payment.PaymentPlanFree, "", nil,
+	)
+	pack := &ergopack.Ergopack{
+		Apps: map[string]ergopack.ErgopackApp{
+			"web": {Image: "nginx"},
+			"api": {Path: "api"},
+		},
+		Jobs: map[string]ergopack.ErgopackJob{
+			"migrate": {App: "api"},
+		},
+	}
+
+	first, err := gc.makeEnvironmentFromErgopack(context.Background(), pack, "")
+	require.NoError(t, err)
+
+	// updates keep the ids of the apps and jobs that are still there
+	gc.previous = &database.Environment{Services: []database.Service{
+		{Name: "web", ID: first.Services["web"].ID},
+		{Name: "api", ID: first.Services["api"].ID},
+		{Name: "migrate", ID: first.Services["migrate"].ID},
+	}}
+	pack.Apps["worker"] = ergopack.ErgopackApp{Image: "worker"}
+
+	second, err := gc.makeEnvironmentFromErgopack(context.Background(), pack, "")
+	require.NoError(t, err)
+
+	for _, name := range []string{"web", "api", "migrate"} {
+		assert.Equal(t, first.Services[name].ID, second.Services[name].ID, name)
+	}
+	assert.NotEmpty(t, second.Services["worker"].ID)
+	assert.NotContains(t, []string{first.Services["web"].ID, first.Services["api"].ID}, second.Services["worker"].ID)
+
+	// buildpacks push every build to the tag of the app, it must change for the new build to roll out
+	assert.Equal(t, "nginx", second.Services["web"].Image)
+	assert.NotEqual(t, first.Services["api"].Image, second.Services["api"].Image)
+	assert.Equal(t, second.Services["api"].Image, second.Services["migrate"].Image)
+}
+
 func TestGitCompose_addNodeConstraints(t *testing.T) {
 	t.Parallel()
 
@@ -417,7 +469,7 @@ func TestGitCompose_addNodeConstraints(t *testing.T) {
 	}
 }
 
-func TestGitCompose_getUrl(t *testing.T) {
+func TestGitCompose_getUrls(t *testing.T) {
 	t.Parallel()
 
 	c := &gitCompose{
@@ -429,7 +481,7 @@ func TestGitCompose_getUrl(t *testing.T) {
 	tt := []struct {
 		name     string
 		service  kobject.ServiceConfig
-		expected string
+		expected []EnvironmentServiceUrl
 	}{
 		{
 			name: "Service with host port",
@@ -441,7 +493,9 @@ func TestGitCompose_getUrl(t *testing.T) {
 					},
 				},
 			},
-			expected: fmt.Sprintf("myservice-myowner-myrepo-123.%s", clusterDomain),
+			expected: []EnvironmentServiceUrl{
+				{Url: fmt.Sprintf("myservice-myowner-myrepo-123.%s", clusterDomain), Port: 8080, Path: "/"},
+			},
 		},
 		{
 			name: "Service without host port",
@@ -449,7 +503,7 @@ func TestGitCompose_getUrl(t *testing.T) {
 				Name: "myservice",
 				Port: []kobject.Ports{},
 			},
-			expected: "",
+			expected: []EnvironmentServiceUrl{},
 		},
 		{
 			name: "Service with uppercase characters",
@@ -461,7 +515,27 @@ func TestGitCompose_getUrl(t *testing.T) {
 					},
 				},
 			},
-			expected: fmt.Sprintf("myservice-myowner-myrepo-123.%s", clusterDomain),
+			expected: []EnvironmentServiceUrl{
+				{Url: fmt.Sprintf("myservice-myowner-myrepo-123.%s", clusterDomain), Port: 8080, Path: "/"},
+			},
+		},
+		{
+			name: "Service with many host ports",
+			service: kobject.ServiceConfig{
+				Name: "myservice",
+				Port: []kobject.Ports{
+					{HostPort: 3000},
+					{HostPort: 3000, Protocol: "UDP"},
+					{HostPort: 9000},
+					{HostPort: 9090},
+				},
+				Labels: map[string]string{"dev.ergomake.route.9090": "admin/"},
+			},
+			expected: []EnvironmentServiceUrl{
+				{Url: fmt.Sprintf("myservice-myowner-myrepo-123.%s", clusterDomain), Port: 3000, Path: "/"},
+				{Url: fmt.Sprintf("myservice-9000-myowner-myrepo-123.%s", clusterDomain), Port: 9000, Path: "/"},
+				{Url: fmt.Sprintf("myservice-myowner-myrepo-123.%s", clusterDomain), Port: 9090, Path: "/admin"},
+			},
 		},
 	}
 
@@ -470,8 +544,9 @@ func TestGitCompose_getUrl(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			url := c.getUrl(tc.service)
-			assert.Equal(t, tc.expected, url)
+			urls, err := c.getUrls(tc.service)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, urls)
 		})
 	}
 }
@@ -0,0 +1,180 @@
+package transformer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+	"github.com/ergomake/ergomake/internal/ergopack"
+)
+
+// composeJobLabel turns a compose service into a job that must complete before the environment is ready
+const composeJobLabel = "dev.ergomake.job"
+
+func loadComposeJobs(services map[string]kobject.ServiceConfig) map[string]struct{} {
+	jobs := make(map[string]struct{})
+	for name, service := range services {
+		isJob, _ := strconv.ParseBool(service.Labels[composeJobLabel])
+		if isJob {
+			jobs[name] = struct{}{}
+		}
+	}
+
+	return jobs
+}
+
+func loadErgopackJobs(pack *ergopack.Ergopack) (map[string]struct{}, error) {
+	jobs := make(map[string]struct{})
+	for name, job := range pack.Jobs {
+		if _, ok := pack.Apps[name]; ok {
+			return nil, errors.Errorf("job %s has the same name as an app", name)
+		}
+
+		if job.Image == "" {
+			if job.App == "" {
+				return nil, errors.Errorf("job %s must have either an app or an image", name)
+			}
+
+			if _, ok := pack.Apps[job.App]; !ok {
+				return nil, errors.Errorf("job %s uses the image of app %s which does not exist", name, job.App)
+			}
+		}
+
+		jobs[name] = struct{}{}
+	}
+
+	return jobs, nil
+}
+
+// checkJobDependencies makes sure nothing waits on a job except other jobs that need it
+// to complete, jobs only start after every service is up so waiting on them would deadlock.
+func checkJobDependencies(jobs map[string]struct{}, dependencies map[string][]dependency) error {
+	names := []string{}
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, isJob := jobs[name]
+		for _, dep := range dependencies[name] {
+			if _, ok := jobs[dep.Service]; !ok {
+				continue
+			}
+
+			if !isJob {
+				return errors.Errorf(
+					"%s depends on %s which is a job, jobs only run after all services are up so services can not depend on them",
+					name,
+					dep.Service,
+				)
+			}
+
+			if dep.Condition != conditionCompleted {
+				return errors.Errorf(
+					"job %s depends on job %s, the condition of a dependency between jobs must be %s",
+					name,
+					dep.Service,
+					conditionCompleted,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertJobs replaces the deployments of setup jobs, and of the services others depend on to complete,
+// with suspended k8s jobs and drops their services and ingresses
+func (c *gitCompose) convertJobs(objs []runtime.Object) []runtime.Object {
+	dependencyJobs := c.dependencyJobs()
+	if len(c.jobs) == 0 && len(dependencyJobs) == 0 {
+		return objs
+	}
+
+	isJob := func(name string) bool {
+		_, isSetupJob := c.jobs[name]
+		_, isDependencyJob := dependencyJobs[name]
+		return isSetupJob || isDependencyJob
+	}
+
+	result := []runtime.Object{}
+	for _, obj := range objs {
+		switch obj := obj.(type) {
+		case *appsv1.Deployment:
+			name := obj.GetLabels()["preview.ergomake.dev/service"]
+			if _, ok := c.jobs[name]; ok {
+				result = append(result, makeJob(obj, cluster.SetupJobLabel))
+				continue
+			}
+
+			if _, ok := dependencyJobs[name]; ok {
+				result = append(result, makeJob(obj, cluster.DependencyJobLabel))
+				continue
+			}
+		case *corev1.Service:
+			if isJob(obj.GetName()) {
+				continue
+			}
+		case *networkingv1.Ingress:
+			if isJob(obj.GetName()) {
+				continue
+			}
+		}
+
+		result = append(result, obj)
+	}
+
+	return result
+}
+
+// makeJob turns the deployment into a suspended job labeled with label, it runs once when resumed
+func makeJob(deployment *appsv1.Deployment, label string) *batchv1.Job {
+	template := deployment.Spec.Template.DeepCopy()
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].ReadinessProbe = nil
+		template.Spec.Containers[i].LivenessProbe = nil
+	}
+
+	labels := map[string]string{label: "true"}
+	for k, v := range deployment.GetLabels() {
+		labels[k] = v
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        deployment.GetName(),
+			Namespace:   deployment.GetNamespace(),
+			Labels:      labels,
+			Annotations: deployment.GetAnnotations(),
+		},
+		Spec: batchv1.JobSpec{
+			Suspend:      pointer.Bool(true),
+			BackoffLimit: pointer.Int32(0),
+			Template:     *template,
+		},
+	}
+}
+
+func makeJobValidationError(t string, err error) *ProjectValidationError {
+	return &ProjectValidationError{
+		T:       t,
+		Message: fmt.Sprintf("Invalid jobs\n```\n%s\n```", err.Error()),
+	}
+}
@@ -0,0 +1,185 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ergomake/ergomake/internal/cluster"
+	"github.com/ergomake/ergomake/internal/ergopack"
+	"github.com/ergomake/ergomake/internal/payment"
+)
+
+func TestLoadComposeJobs(t *testing.T) {
+	t.Parallel()
+
+	jobs := loadComposeJobs(map[string]kobject.ServiceConfig{
+		"migrate": {Labels: map[string]string{composeJobLabel: "true"}},
+		"seed":    {Labels: map[string]string{composeJobLabel: "1"}},
+		"api":     {Labels: map[string]string{composeJobLabel: "false"}},
+		"web":     {},
+	})
+
+	assert.Equal(t, map[string]struct{}{"migrate": {}, "seed": {}}, jobs)
+}
+
+func TestLoadErgopackJobs(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name   string
+		pack   ergopack.Ergopack
+		errors bool
+	}{
+		{
+			name: "loads jobs",
+			pack: ergopack.Ergopack{
+				Apps: map[string]ergopack.ErgopackApp{"api": {}},
+				Jobs: map[string]ergopack.ErgopackJob{
+					"migrate": {App: "api"},
+					"seed":    {Image: "seeder"},
+				},
+			},
+		},
+		{
+			name: "fails when job has the name of an app",
+			pack: ergopack.Ergopack{
+				Apps: map[string]ergopack.ErgopackApp{"api": {}},
+				Jobs: map[string]ergopack.ErgopackJob{"api": {Image: "api"}},
+			},
+			errors: true,
+		},
+		{
+			name: "fails when job has no image",
+			pack: ergopack.Ergopack{
+				Jobs: map[string]ergopack.ErgopackJob{"migrate": {}},
+			},
+			errors: true,
+		},
+		{
+			name: "fails when app does not exist",
+			pack: ergopack.Ergopack{
+				Jobs: map[string]ergopack.ErgopackJob{"migrate": {App: "api"}},
+			},
+			errors: true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			jobs, err := loadErgopackJobs(&tc.pack)
+			if tc.errors {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, jobs, len(tc.pack.Jobs))
+		})
+	}
+}
+
+func TestCheckJobDependencies(t *testing.T) {
+	t.Parallel()
+
+	jobs := map[string]struct{}{"migrate": {}, "seed": {}}
+
+	assert.NoError(t, checkJobDependencies(jobs, map[string][]dependency{
+		"migrate": {{Service: "db", Condition: conditionHealthy}},
+		"seed":    {{Service: "migrate", Condition: conditionCompleted}},
+	}))
+
+	assert.Error(t, checkJobDependencies(jobs, map[string][]dependency{
+		"api": {{Service: "migrate", Condition: conditionCompleted}},
+	}))
+
+	assert.Error(t, checkJobDependencies(jobs, map[string][]dependency{
+		"seed": {{Service: "migrate", Condition: conditionStarted}},
+	}))
+}
+
+func TestGitCompose_convertJobs(t *testing.T) {
+	t.Parallel()
+
+	api := makeDependencyTestDeployment("api")
+	migrate := makeDependencyTestDeployment("migrate")
+	migrate.Spec.Template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{}
+	apiService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api"}}
+	migrateService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "migrate"}}
+	migrateIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "migrate"}}
+
+	c := &gitCompose{jobs: map[string]struct{}{"migrate": {}}}
+	objs := c.convertJobs([]runtime.Object{api, apiService, migrate, migrateService, migrateIngress})
+
+	require.Len(t, objs, 3)
+	assert.Equal(t, api, objs[0])
+	assert.Equal(t, apiService, objs[1])
+
+	job, ok := objs[2].(*batchv1.Job)
+	require.True(t, ok)
+	assert.Equal(t, "migrate", job.GetName())
+	assert.Equal(t, "true", job.GetLabels()[cluster.SetupJobLabel])
+	assert.True(t, *job.Spec.Suspend)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	assert.Nil(t, job.Spec.Template.Spec.Containers[0].ReadinessProbe)
+
+	// the deployment itself is left untouched
+	assert.NotNil(t, migrate.Spec.Template.Spec.Containers[0].ReadinessProbe)
+}
+
+func TestGitCompose_convertJobsDependencyJobs(t *testing.T) {
+	t.Parallel()
+
+	api := makeDependencyTestDeployment("api")
+	migrate := makeDependencyTestDeployment("migrate")
+	migrateService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "migrate"}}
+
+	c := &gitCompose{
+		dependencies: map[string][]dependency{
+			"api": {{Service: "migrate", Condition: conditionCompleted}},
+		},
+	}
+	objs := c.convertJobs([]runtime.Object{api, migrate, migrateService})
+
+	require.Len(t, objs, 2)
+	assert.Equal(t, api, objs[0])
+
+	job, ok := objs[1].(*batchv1.Job)
+	require.True(t, ok)
+	assert.Equal(t, "migrate", job.GetName())
+	assert.Equal(t, "true", job.GetLabels()[cluster.DependencyJobLabel])
+	assert.Empty(t, job.GetLabels()[cluster.SetupJobLabel])
+	assert.True(t, *job.Spec.Suspend)
+}
+
+func TestGitCompose_makeErgopackJobDeployment(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		plan: payment.PaymentPlanFree,
+		environment: NewEnvironment(map[string]EnvironmentService{
+			"api":     {ID: "api-id", Image: "api:latest"},
+			"migrate": {ID: "migrate-id", Image: "api:latest", Job: true},
+		}, ""),
+	}
+
+	deployment := c.makeErgopackJobDeployment(
+		context.Background(), "namespace", "migrate", ergopack.ErgopackJob{App: "api"}, nil, "pull-secret",
+	)
+
+	assert.Equal(t, "migrate-id", deployment.GetLabels()["preview.ergomake.dev/id"])
+	assert.Equal(t, "migrate", deployment.GetLabels()["preview.ergomake.dev/service"])
+	assert.Equal(t, "api:latest", deployment.Spec.Template.Spec.Containers[0].Image)
+}
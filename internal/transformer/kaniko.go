@@ -0,0 +1,107 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type kanikoBuilder struct {
+	c         *gitCompose
+	namespace string
+}
+
+func (b *kanikoBuilder) Build(ctx context.Context, build *ImageBuild) (*batchv1.Job, error) {
+	cloneTokenSecretName, err := b.c.getJobCloneTokenSecret(ctx, b.namespace, build.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	buildSecretName, err := b.c.createBuildSecret(ctx, "preview-builds", build)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := b.c.clusterClient.CreateJob(ctx, b.c.makeKanikoJob(build, cloneTokenSecretName, buildSecretName))
+	return job, errors.Wrapf(err, "fail to create build job for service %s", build.ServiceName)
+}
+
+func (c *gitCompose) makeKanikoJob(build *ImageBuild, cloneTokenSecretName, buildSecretName *string) *batchv1.Job {
+	context, contextSubPath := "dir:///workspace", build.Context
+	if build.ContextURL != "" {
+		context, contextSubPath = makeKanikoGitContext(build.ContextURL)
+	}
+
+	args := []string{
+		"--context=" + context,
+		fmt.Sprintf("--dockerfile=%s", build.Dockerfile),
+	}
+	if contextSubPath != "" {
+		args = append(args, fmt.Sprintf("--context-sub-path=%s", contextSubPath))
+	}
+	args = append(args,
+		"--destination="+build.Image,
+		"--use-new-run",
+		"--cleanup",
+		"--snapshot-mode=redo",
+	)
+	if build.Target != "" {
+		args = append(args, "--target="+build.Target)
+	}
+	// validation only lets kaniko build a single platform
+	if len(build.Platforms) == 1 {
+		args = append(args, "--custom-platform="+build.Platforms[0])
+	}
+	for _, k := range sortedKeys(build.Labels) {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, build.Labels[k]))
+	}
+	for _, k := range sortedKeys(build.BuildArgs) {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, build.BuildArgs[k]))
+	}
+	args = append(args, "--cache=true", fmt.Sprintf("--cache-repo=%s", makeCacheRepo(c.branchOwner, c.repo)))
+
+	if insecureRegistry != "" {
+		args = append(args, fmt.Sprintf("--insecure-registry=%s", insecureRegistry))
+	}
+
+	job := c.makeBuildJob(build, corev1.Container{
+		Name:  build.ServiceID,
+		Image: "gcr.io/kaniko-project/executor:latest",
+		Args:  args,
+	}, cloneTokenSecretName)
+
+	// kaniko leaves mounted paths out of the snapshots, so the secrets never land in a layer
+	appendBuildSecrets(job, buildSecretName)
+
+	if os.Getenv("CLUSTER") == "eks" {
+		appendUserlandCreds(job, "/kaniko/.docker/")
+	}
+
+	return job
+}
+
+// makeKanikoGitContext turns a compose git context, `url#ref:subdir`, into the context and the
+// sub path kaniko expects, refs that aren't fully qualified are taken as branches
+func makeKanikoGitContext(contextURL string) (string, string) {
+	repo, fragment, _ := strings.Cut(contextURL, "#")
+	ref, subPath, _ := strings.Cut(fragment, ":")
+
+	for _, scheme := range []string{"https://", "http://", "git://"} {
+		repo = strings.TrimPrefix(repo, scheme)
+	}
+	context := "git://" + repo
+
+	if ref != "" {
+		if !strings.HasPrefix(ref, "refs/") {
+			ref = "refs/heads/" + ref
+		}
+		context += "#" + ref
+	}
+
+	return context, subPath
+}
@@ -0,0 +1,142 @@
+package transformer
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/ergomake/ergomake/internal/allowedhosts"
+)
+
+// ingressNamespace is where the ingress controller runs, the only namespace allowed to reach environments
+var ingressNamespace string
+
+func setIngressNamespace() {
+	ingressNamespace = os.Getenv("INGRESS_NAMESPACE")
+	if ingressNamespace == "" {
+		ingressNamespace = "ingress-nginx"
+	}
+}
+
+// mergeNetworkPolicies keeps one of each policy kompose made for the compose networks
+// and adds the policies every environment gets around them
+func (c *gitCompose) mergeNetworkPolicies(ctx context.Context, namespace string, objs []runtime.Object) []runtime.Object {
+	result := []runtime.Object{}
+	policies := []runtime.Object{}
+	seen := map[string]struct{}{}
+	networkLabels := []string{}
+	for _, obj := range objs {
+		policy, ok := obj.(*networkingv1.NetworkPolicy)
+		if !ok {
+			result = append(result, obj)
+			continue
+		}
+
+		// kompose makes one policy per network of every service
+		if _, ok := seen[policy.GetName()]; ok {
+			continue
+		}
+		seen[policy.GetName()] = struct{}{}
+
+		policy.SetNamespace(namespace)
+		policies = append(policies, policy)
+		for label := range policy.Spec.PodSelector.MatchLabels {
+			networkLabels = append(networkLabels, label)
+		}
+	}
+	sort.Strings(networkLabels)
+
+	policies = append(policies, c.makeNetworkPolicies(ctx, namespace, networkLabels)...)
+
+	return append(result, policies...)
+}
+
+// makeNetworkPolicies denies all traffic except for pods of the namespace reaching each other,
+// the ingress controller reaching any pod, DNS and the allowed hosts of the repository.
+// Pods in one of networkLabels are left to the policies of their compose networks.
+// Names are prefixed so they don't clash with the policies of compose networks.
+func (c *gitCompose) makeNetworkPolicies(ctx context.Context, namespace string, networkLabels []string) []runtime.Object {
+	outsideNetworks := metav1.LabelSelector{}
+	for _, label := range networkLabels {
+		outsideNetworks.MatchExpressions = append(outsideNetworks.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      label,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		})
+	}
+
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+		{
+			To: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+			}},
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dnsPort},
+				{Protocol: &tcp, Port: &dnsPort},
+			},
+		},
+	}
+
+	cidrs := allowedhosts.ToCIDRs(ctx, c.allowedHosts)
+	if len(cidrs) > 0 {
+		peers := []networkingv1.NetworkPolicyPeer{}
+		for _, cidr := range cidrs {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	return []runtime.Object{
+		makeNetworkPolicy(namespace, "ergomake-default-deny", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		}),
+		makeNetworkPolicy(namespace, "ergomake-allow-same-namespace", networkingv1.NetworkPolicySpec{
+			PodSelector: outsideNetworks,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{PodSelector: outsideNetworks.DeepCopy()}},
+			}},
+		}),
+		makeNetworkPolicy(namespace, "ergomake-allow-ingress-controller", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"kubernetes.io/metadata.name": ingressNamespace},
+					},
+				}},
+			}},
+		}),
+		makeNetworkPolicy(namespace, "ergomake-allow-egress", networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		}),
+	}
+}
+
+func makeNetworkPolicy(namespace, name string, spec networkingv1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}
+}
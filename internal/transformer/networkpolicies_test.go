@@ -0,0 +1,84 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGitCompose_makeNetworkPolicies(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{allowedHosts: []string{"10.0.0.0/8", "1.2.3.4"}}
+	objs := c.makeNetworkPolicies(context.Background(), "ns", nil)
+
+	policies := map[string]*networkingv1.NetworkPolicy{}
+	for _, obj := range objs {
+		policy, ok := obj.(*networkingv1.NetworkPolicy)
+		require.True(t, ok)
+		assert.Equal(t, "ns", policy.GetNamespace())
+		policies[policy.GetName()] = policy
+	}
+	require.Len(t, policies, 4)
+
+	deny := policies["ergomake-default-deny"]
+	assert.Empty(t, deny.Spec.Ingress)
+	assert.Empty(t, deny.Spec.Egress)
+	assert.Len(t, deny.Spec.PolicyTypes, 2)
+
+	sameNamespace := policies["ergomake-allow-same-namespace"]
+	assert.Equal(t, metav1.LabelSelector{}, sameNamespace.Spec.PodSelector)
+
+	ingressController := policies["ergomake-allow-ingress-controller"]
+	assert.Equal(t,
+		map[string]string{"kubernetes.io/metadata.name": ingressNamespace},
+		ingressController.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels,
+	)
+
+	egress := policies["ergomake-allow-egress"].Spec.Egress
+	require.Len(t, egress, 3)
+	assert.Equal(t, "10.0.0.0/8", egress[2].To[0].IPBlock.CIDR)
+	assert.Equal(t, "1.2.3.4/32", egress[2].To[1].IPBlock.CIDR)
+}
+
+func TestGitCompose_mergeNetworkPolicies(t *testing.T) {
+	t.Parallel()
+
+	networkPolicy := func() *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"io.kompose.network/backend": "true"}},
+			},
+		}
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api"}}
+
+	c := &gitCompose{}
+	objs := c.mergeNetworkPolicies(context.Background(), "ns", []runtime.Object{
+		networkPolicy(), deployment, networkPolicy(),
+	})
+
+	require.Len(t, objs, 6)
+	assert.Equal(t, deployment, objs[0])
+
+	backend, ok := objs[1].(*networkingv1.NetworkPolicy)
+	require.True(t, ok)
+	assert.Equal(t, "backend", backend.GetName())
+	assert.Equal(t, "ns", backend.GetNamespace())
+
+	sameNamespace, ok := objs[3].(*networkingv1.NetworkPolicy)
+	require.True(t, ok)
+	assert.Equal(t, "ergomake-allow-same-namespace", sameNamespace.GetName())
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      "io.kompose.network/backend",
+		Operator: metav1.LabelSelectorOpDoesNotExist,
+	}}, sameNamespace.Spec.PodSelector.MatchExpressions)
+	assert.Equal(t, sameNamespace.Spec.PodSelector, *sameNamespace.Spec.Ingress[0].From[0].PodSelector)
+}
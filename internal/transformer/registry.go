@@ -0,0 +1,39 @@
+package transformer
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+)
+
+// registryImageExists tells whether the manifest of image is still in the registry, images built
+// by environments long gone may have been cleaned up from it
+func registryImageExists(ctx context.Context, image string) (bool, error) {
+	var opts []name.Option
+	if insecureRegistry != "" && strings.HasPrefix(image, insecureRegistry) {
+		opts = append(opts, name.Insecure)
+	}
+
+	ref, err := name.ParseReference(image, opts...)
+	if err != nil {
+		return false, errors.Wrapf(err, "fail to parse image %s", image)
+	}
+
+	_, err = remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		var transportErr *transport.Error
+		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+
+		return false, errors.Wrapf(err, "fail to get manifest of image %s", image)
+	}
+
+	return true, nil
+}
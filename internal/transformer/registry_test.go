@@ -0,0 +1,40 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryImageExists(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	built := fmt.Sprintf("%s/library:aaa", u.Host)
+	ref, err := name.ParseReference(built)
+	require.NoError(t, err)
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	exists, err := registryImageExists(context.Background(), built)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = registryImageExists(context.Background(), fmt.Sprintf("%s/library:bbb", u.Host))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
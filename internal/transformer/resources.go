@@ -0,0 +1,221 @@
+package transformer
+
+import (
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ergomake/ergomake/internal/ergopack"
+	"github.com/ergomake/ergomake/internal/payment"
+)
+
+const defaultServiceMemory = "1Gi"
+
+const (
+	composeEphemeralStorage  = "5Gi"
+	ergopackEphemeralStorage = "2Gi"
+)
+
+// serviceResources is what a service asks for, nil means it was not specified
+type serviceResources struct {
+	CPULimit      *resource.Quantity
+	MemoryLimit   *resource.Quantity
+	CPURequest    *resource.Quantity
+	MemoryRequest *resource.Quantity
+}
+
+// makeResourceRequirements clamps limits to the plan maximum and rejects requests above it.
+// Memory defaults to 1Gi, or to the reservation when it is more, and requests default to the limits.
+func makeResourceRequirements(
+	plan payment.PaymentPlan,
+	service string,
+	res serviceResources,
+	ephemeralStorage string,
+) (corev1.ResourceRequirements, string) {
+	maxCPU := resource.MustParse(plan.ServiceCPULimit())
+	maxMemory := resource.MustParse(plan.ServiceMemoryLimit())
+
+	memoryLimit := resource.MustParse(defaultServiceMemory)
+	if res.MemoryLimit != nil {
+		memoryLimit = *res.MemoryLimit
+	} else if res.MemoryRequest != nil && res.MemoryRequest.Cmp(memoryLimit) > 0 {
+		// the service set no limit so the default must not reject its reservation
+		memoryLimit = *res.MemoryRequest
+	}
+	if memoryLimit.Cmp(maxMemory) > 0 {
+		memoryLimit = maxMemory
+	}
+
+	memoryRequest := memoryLimit
+	if res.MemoryRequest != nil {
+		if res.MemoryRequest.Cmp(maxMemory) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Sprintf(
+				"Service `%s` reserves %s of memory but your plan allows at most %s per service.",
+				service,
+				res.MemoryRequest.String(),
+				maxMemory.String(),
+			)
+		}
+
+		if res.MemoryRequest.Cmp(memoryLimit) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Sprintf(
+				"Service `%s` reserves %s of memory which is more than its limit of %s.",
+				service,
+				res.MemoryRequest.String(),
+				memoryLimit.String(),
+			)
+		}
+
+		memoryRequest = *res.MemoryRequest
+	}
+
+	requirements := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
+			corev1.ResourceMemory:           memoryLimit,
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
+			corev1.ResourceMemory:           memoryRequest,
+		},
+	}
+
+	if res.CPULimit != nil {
+		cpuLimit := *res.CPULimit
+		if cpuLimit.Cmp(maxCPU) > 0 {
+			cpuLimit = maxCPU
+		}
+		requirements.Limits[corev1.ResourceCPU] = cpuLimit
+	}
+
+	if res.CPURequest != nil {
+		if res.CPURequest.Cmp(maxCPU) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Sprintf(
+				"Service `%s` reserves %s cpus but your plan allows at most %s per service.",
+				service,
+				res.CPURequest.String(),
+				maxCPU.String(),
+			)
+		}
+
+		if cpuLimit, ok := requirements.Limits[corev1.ResourceCPU]; ok && res.CPURequest.Cmp(cpuLimit) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Sprintf(
+				"Service `%s` reserves %s cpus which is more than its limit of %s.",
+				service,
+				res.CPURequest.String(),
+				cpuLimit.String(),
+			)
+		}
+
+		requirements.Requests[corev1.ResourceCPU] = *res.CPURequest
+	}
+
+	return requirements, ""
+}
+
+func (c *gitCompose) loadComposeResources() *ProjectValidationError {
+	names := []string{}
+	for name := range c.komposeObject.ServiceConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.resources = make(map[string]corev1.ResourceRequirements)
+	for _, name := range names {
+		service := c.komposeObject.ServiceConfigs[name]
+
+		res := serviceResources{}
+		if service.CPULimit != 0 {
+			res.CPULimit = resource.NewMilliQuantity(service.CPULimit, resource.DecimalSI)
+		}
+		if service.MemLimit != 0 {
+			res.MemoryLimit = resource.NewQuantity(int64(service.MemLimit), resource.BinarySI)
+		}
+		if service.CPUReservation != 0 {
+			res.CPURequest = resource.NewMilliQuantity(service.CPUReservation, resource.DecimalSI)
+		}
+		if service.MemReservation != 0 {
+			res.MemoryRequest = resource.NewQuantity(int64(service.MemReservation), resource.BinarySI)
+		}
+
+		requirements, message := makeResourceRequirements(c.plan, name, res, composeEphemeralStorage)
+		if message != "" {
+			return &ProjectValidationError{T: "invalid-compose", Message: message}
+		}
+
+		c.resources[name] = requirements
+	}
+
+	return nil
+}
+
+func (c *gitCompose) loadErgopackResources(pack *ergopack.Ergopack) *ProjectValidationError {
+	names := []string{}
+	for name := range pack.Apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.resources = make(map[string]corev1.ResourceRequirements)
+	for _, name := range names {
+		app := pack.Apps[name]
+
+		res := serviceResources{}
+		if app.Resources != nil {
+			values := []struct {
+				value string
+				field string
+				dest  **resource.Quantity
+			}{
+				{app.Resources.Limits.CPU, "limits.cpu", &res.CPULimit},
+				{app.Resources.Limits.Memory, "limits.memory", &res.MemoryLimit},
+				{app.Resources.Requests.CPU, "requests.cpu", &res.CPURequest},
+				{app.Resources.Requests.Memory, "requests.memory", &res.MemoryRequest},
+			}
+
+			for _, v := range values {
+				if v.value == "" {
+					continue
+				}
+
+				quantity, err := resource.ParseQuantity(v.value)
+				if err != nil {
+					return &ProjectValidationError{
+						T:       "invalid-ergopack",
+						Message: fmt.Sprintf("App `%s` has invalid `resources.%s` value `%s`.", name, v.field, v.value),
+					}
+				}
+				*v.dest = &quantity
+			}
+		}
+
+		requirements, message := makeResourceRequirements(c.plan, name, res, ergopackEphemeralStorage)
+		if message != "" {
+			return &ProjectValidationError{T: "invalid-ergopack", Message: message}
+		}
+
+		c.resources[name] = requirements
+	}
+
+	return nil
+}
+
+func (c *gitCompose) getResources(serviceName string, ephemeralStorage string) corev1.ResourceRequirements {
+	if requirements, ok := c.resources[serviceName]; ok {
+		return *requirements.DeepCopy()
+	}
+
+	requirements, _ := makeResourceRequirements(c.plan, serviceName, serviceResources{}, ephemeralStorage)
+	return requirements
+}
+
+func (c *gitCompose) addResourceLimits(deployment *appsv1.Deployment) {
+	serviceName := deployment.GetLabels()["io.kompose.service"]
+	podSpec := &deployment.Spec.Template.Spec
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Resources = c.getResources(serviceName, composeEphemeralStorage)
+	}
+}
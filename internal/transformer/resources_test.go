@@ -0,0 +1,164 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ergomake/ergomake/internal/ergopack"
+	"github.com/ergomake/ergomake/internal/payment"
+)
+
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func TestMakeResourceRequirements(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		plan    payment.PaymentPlan
+		res     serviceResources
+		limits  map[corev1.ResourceName]string
+		reqs    map[corev1.ResourceName]string
+		message bool
+	}{
+		{
+			name:   "defaults to 1Gi of memory",
+			plan:   payment.PaymentPlanFree,
+			limits: map[corev1.ResourceName]string{corev1.ResourceMemory: "1Gi"},
+			reqs:   map[corev1.ResourceName]string{corev1.ResourceMemory: "1Gi"},
+		},
+		{
+			name:   "uses the given limits and reservations",
+			plan:   payment.PaymentPlanStandard,
+			res:    serviceResources{MemoryLimit: quantity("3Gi"), MemoryRequest: quantity("64Mi"), CPULimit: quantity("500m")},
+			limits: map[corev1.ResourceName]string{corev1.ResourceMemory: "3Gi", corev1.ResourceCPU: "500m"},
+			reqs:   map[corev1.ResourceName]string{corev1.ResourceMemory: "64Mi"},
+		},
+		{
+			name:   "clamps limits to the plan maximum",
+			plan:   payment.PaymentPlanFree,
+			res:    serviceResources{MemoryLimit: quantity("16Gi"), CPULimit: quantity("8")},
+			limits: map[corev1.ResourceName]string{corev1.ResourceMemory: "2Gi", corev1.ResourceCPU: "1"},
+			reqs:   map[corev1.ResourceName]string{corev1.ResourceMemory: "2Gi"},
+		},
+		{
+			name:   "raises the default memory limit to the reservation",
+			plan:   payment.PaymentPlanFree,
+			res:    serviceResources{MemoryRequest: quantity("1536Mi")},
+			limits: map[corev1.ResourceName]string{corev1.ResourceMemory: "1536Mi"},
+			reqs:   map[corev1.ResourceName]string{corev1.ResourceMemory: "1536Mi"},
+		},
+		{
+			name:    "fails when memory reservation exceeds the plan without a limit",
+			plan:    payment.PaymentPlanFree,
+			res:     serviceResources{MemoryRequest: quantity("4Gi")},
+			message: true,
+		},
+		{
+			name:    "fails when reservation is above the given memory limit",
+			plan:    payment.PaymentPlanStandard,
+			res:     serviceResources{MemoryLimit: quantity("1Gi"), MemoryRequest: quantity("2Gi")},
+			message: true,
+		},
+		{
+			name:    "fails when memory reservation exceeds the plan",
+			plan:    payment.PaymentPlanFree,
+			res:     serviceResources{MemoryLimit: quantity("4Gi"), MemoryRequest: quantity("4Gi")},
+			message: true,
+		},
+		{
+			name:    "fails when cpu reservation exceeds the plan",
+			plan:    payment.PaymentPlanProfessional,
+			res:     serviceResources{CPURequest: quantity("6")},
+			message: true,
+		},
+		{
+			name:    "fails when reservation is above the limit",
+			plan:    payment.PaymentPlanProfessional,
+			res:     serviceResources{CPULimit: quantity("1"), CPURequest: quantity("2")},
+			message: true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			requirements, message := makeResourceRequirements(tc.plan, "api", tc.res, composeEphemeralStorage)
+			if tc.message {
+				assert.NotEmpty(t, message)
+				return
+			}
+			require.Empty(t, message)
+
+			assert.Equal(t, resource.MustParse(composeEphemeralStorage), requirements.Limits[corev1.ResourceEphemeralStorage])
+			for name, value := range tc.limits {
+				assert.Zero(t, quantity(value).Cmp(requirements.Limits[name]), name)
+			}
+			for name, value := range tc.reqs {
+				assert.Zero(t, quantity(value).Cmp(requirements.Requests[name]), name)
+			}
+			_, hasCPURequest := requirements.Requests[corev1.ResourceCPU]
+			assert.Equal(t, tc.res.CPURequest != nil, hasCPURequest)
+		})
+	}
+}
+
+func TestGitCompose_loadComposeResources(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		plan: payment.PaymentPlanStandard,
+		komposeObject: &kobject.KomposeObject{
+			ServiceConfigs: map[string]kobject.ServiceConfig{
+				"api":   {MemLimit: 3 * 1024 * 1024 * 1024, CPULimit: 1500},
+				"redis": {MemReservation: 32 * 1024 * 1024},
+			},
+		},
+	}
+
+	validationErr := c.loadComposeResources()
+	require.Nil(t, validationErr)
+
+	api := c.resources["api"]
+	assert.Zero(t, quantity("3Gi").Cmp(api.Limits[corev1.ResourceMemory]))
+	assert.Zero(t, quantity("1500m").Cmp(api.Limits[corev1.ResourceCPU]))
+
+	redis := c.resources["redis"]
+	assert.Zero(t, quantity("1Gi").Cmp(redis.Limits[corev1.ResourceMemory]))
+	assert.Zero(t, quantity("32Mi").Cmp(redis.Requests[corev1.ResourceMemory]))
+}
+
+func TestGitCompose_loadErgopackResources(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{plan: payment.PaymentPlanFree}
+	validationErr := c.loadErgopackResources(&ergopack.Ergopack{
+		Apps: map[string]ergopack.ErgopackApp{
+			"api": {Resources: &ergopack.ErgopackResources{
+				Limits: ergopack.ErgopackResourceList{Memory: "lots"},
+			}},
+		},
+	})
+	require.NotNil(t, validationErr)
+	assert.Equal(t, "invalid-ergopack", validationErr.T)
+
+	validationErr = c.loadErgopackResources(&ergopack.Ergopack{
+		Apps: map[string]ergopack.ErgopackApp{
+			"api": {Resources: &ergopack.ErgopackResources{
+				Requests: ergopack.ErgopackResourceList{Memory: "8Gi"},
+			}},
+		},
+	})
+	require.NotNil(t, validationErr)
+	assert.Contains(t, validationErr.Message, "your plan allows at most 2Gi")
+}
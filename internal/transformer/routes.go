@@ -0,0 +1,306 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/internal/ergopack"
+	"github.com/ergomake/ergomake/internal/logger"
+	"github.com/ergomake/ergomake/internal/urltemplates"
+)
+
+// composeRouteLabelPrefix serves a published port under a path of the service host
+// instead of giving it its own host, e.g. `dev.ergomake.route.9000: /admin`
+const composeRouteLabelPrefix = "dev.ergomake.route."
+
+type EnvironmentServiceUrl struct {
+	Url  string `json:"url"`
+	Port int    `json:"port"`
+	Path string `json:"path"`
+}
+
+// makeHost renders the url template of the repo, every place that builds a host goes through it
+func (c *gitCompose) makeHost(service string) (string, error) {
+	tmpl, err := urltemplates.Parse(c.urlTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.Host(urltemplates.Vars{
+		Owner:   c.owner,
+		Repo:    c.repo,
+		Branch:  c.branch,
+		PR:      c.prNumber,
+		Service: service,
+		Domain:  clusterDomain,
+	})
+}
+
+// makeServiceUrls gives the first port the service host, every other port gets
+// a `<service>-<port>` host of its own unless it has a path on the service host.
+func (c *gitCompose) makeServiceUrls(name string, ports []int, paths map[int]string) ([]EnvironmentServiceUrl, error) {
+	urls := []EnvironmentServiceUrl{}
+	if len(ports) == 0 {
+		return urls, nil
+	}
+
+	mainHost, err := c.makeHost(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to make host of service %s", name)
+	}
+
+	for i, port := range ports {
+		path, hasPath := paths[port]
+		if !hasPath {
+			path = "/"
+		}
+
+		host := mainHost
+		if i > 0 && !hasPath {
+			host, err = c.makeHost(fmt.Sprintf("%s-%d", name, port))
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to make host of port %d of service %s", port, name)
+			}
+		}
+
+		urls = append(urls, EnvironmentServiceUrl{Url: host, Port: port, Path: path})
+	}
+
+	return urls, nil
+}
+
+// returns empty when service should not be exposed
+func (c *gitCompose) getUrls(service kobject.ServiceConfig) ([]EnvironmentServiceUrl, error) {
+	ports := []int{}
+	seen := map[int]struct{}{}
+	for _, port := range service.Port {
+		if port.HostPort <= 0 {
+			continue
+		}
+
+		if _, ok := seen[int(port.HostPort)]; ok {
+			continue
+		}
+		seen[int(port.HostPort)] = struct{}{}
+
+		ports = append(ports, int(port.HostPort))
+	}
+
+	paths := map[int]string{}
+	for label, value := range service.Labels {
+		if !strings.HasPrefix(label, composeRouteLabelPrefix) {
+			continue
+		}
+
+		port, err := strconv.Atoi(strings.TrimPrefix(label, composeRouteLabelPrefix))
+		if err != nil {
+			continue
+		}
+
+		paths[port] = normalizeRoutePath(value)
+	}
+
+	return c.makeServiceUrls(service.Name, ports, paths)
+}
+
+func getErgopackPublicPorts(ctx context.Context, name string, app ergopack.ErgopackApp) []int {
+	ports := []int{}
+	seen := map[int]struct{}{}
+	for _, strPort := range append([]string{app.PublicPort}, app.PublicPorts...) {
+		if strPort == "" {
+			continue
+		}
+
+		port, err := strconv.Atoi(strPort)
+		if err != nil {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("app", name).Str("strPort", strPort).
+				Msg("fail to convert public port to int")
+			continue
+		}
+
+		if _, ok := seen[port]; ok {
+			continue
+		}
+		seen[port] = struct{}{}
+
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+func getErgopackPaths(ctx context.Context, name string, app ergopack.ErgopackApp) map[int]string {
+	paths := map[int]string{}
+	for strPort, path := range app.Paths {
+		port, err := strconv.Atoi(strPort)
+		if err != nil {
+			logger.Ctx(ctx).Warn().AnErr("err", err).Str("app", name).Str("strPort", strPort).
+				Msg("fail to convert path port to int")
+			continue
+		}
+
+		paths[port] = normalizeRoutePath(path)
+	}
+
+	return paths
+}
+
+func normalizeRoutePath(path string) string {
+	return "/" + strings.Trim(strings.TrimSpace(path), "/")
+}
+
+// makeIngress routes every url of a service, urls that share a host become paths of the same rule
+func makeIngress(
+	namespace string,
+	name string,
+	labels map[string]string,
+	annotations map[string]string,
+	urls []EnvironmentServiceUrl,
+) *networkingv1.Ingress {
+	hosts := []string{}
+	paths := map[string][]networkingv1.HTTPIngressPath{}
+	for _, url := range urls {
+		if _, ok := paths[url.Url]; !ok {
+			hosts = append(hosts, url.Url)
+		}
+
+		pathType := networkingv1.PathTypePrefix
+		paths[url.Url] = append(paths[url.Url], networkingv1.HTTPIngressPath{
+			Path:     url.Path,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: name,
+					Port: networkingv1.ServiceBackendPort{
+						Number: int32(url.Port),
+					},
+				},
+			},
+		})
+	}
+
+	rules := []networkingv1.IngressRule{}
+	for _, host := range hosts {
+		hostPaths := paths[host]
+		// longest prefixes first so they read in the order nginx matches them
+		sort.SliceStable(hostPaths, func(i, j int) bool { return len(hostPaths[i].Path) > len(hostPaths[j].Path) })
+
+		rules = append(rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{Paths: hostPaths},
+			},
+		})
+	}
+
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: pointer.String("nginx"),
+			Rules:            rules,
+		},
+	}
+	ingressTLS.applyTLS(ingress)
+
+	return ingress
+}
+
+func (c *gitCompose) makeComposeIngresses(namespace string) []runtime.Object {
+	names := []string{}
+	for name, service := range c.environment.Services {
+		if len(service.Urls) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	ingresses := []runtime.Object{}
+	for _, name := range names {
+		labels := map[string]string{"io.kompose.service": name}
+		ingresses = append(ingresses, makeIngress(namespace, name, labels, nil, c.environment.Services[name].Urls))
+	}
+
+	return ingresses
+}
+
+func makeUrlTemplateValidationError(err error) *ProjectValidationError {
+	return &ProjectValidationError{
+		T:       "invalid-url-template",
+		Message: fmt.Sprintf("The url template of the repository can't be rendered\n```\n%s\n```", err.Error()),
+	}
+}
+
+// findUrlCollision returns a message when two routes of the environment end up on the same host and path
+func findUrlCollision(services map[string]EnvironmentService) string {
+	names := []string{}
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	routes := map[string]string{}
+	for _, name := range names {
+		for _, url := range services[name].Urls {
+			route := url.Url + url.Path
+			if other, ok := routes[route]; ok {
+				return fmt.Sprintf("Services `%s` and `%s` are both routed at `%s`.", other, name, route)
+			}
+			routes[route] = name
+		}
+	}
+
+	return ""
+}
+
+func (c *gitCompose) checkUrlCollisions() (*ProjectValidationError, error) {
+	if message := findUrlCollision(c.environment.Services); message != "" {
+		return &ProjectValidationError{T: "url-collision", Message: message}, nil
+	}
+
+	hosts := []string{}
+	seen := map[string]struct{}{}
+	for _, service := range c.environment.Services {
+		for _, url := range service.Urls {
+			if _, ok := seen[url.Url]; ok {
+				continue
+			}
+			seen[url.Url] = struct{}{}
+
+			hosts = append(hosts, url.Url)
+		}
+	}
+	sort.Strings(hosts)
+
+	inUse, err := c.db.FindUrlsInUse(hosts, c.provider, c.owner, c.repo, c.branch)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to find urls in use")
+	}
+
+	if len(inUse) > 0 {
+		return &ProjectValidationError{
+			T:       "url-collision",
+			Message: fmt.Sprintf("Urls already in use by another environment: `%s`.", strings.Join(inUse, "`, `")),
+		}, nil
+	}
+
+	return nil, nil
+}
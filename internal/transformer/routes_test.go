@@ -0,0 +1,90 @@
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	"github.com/ergomake/ergomake/internal/ergopack"
+)
+
+func TestMakeIngress(t *testing.T) {
+	t.Parallel()
+
+	ingress := makeIngress("ns", "api", map[string]string{"a": "b"}, nil, []EnvironmentServiceUrl{
+		{Url: "api.example.com", Port: 3000, Path: "/"},
+		{Url: "api-9000.example.com", Port: 9000, Path: "/"},
+		{Url: "api.example.com", Port: 9090, Path: "/admin"},
+	})
+
+	assert.Equal(t, "api", ingress.GetName())
+	assert.Equal(t, "ns", ingress.GetNamespace())
+	require.Len(t, ingress.Spec.Rules, 2)
+
+	main := ingress.Spec.Rules[0]
+	assert.Equal(t, "api.example.com", main.Host)
+	require.Len(t, main.HTTP.Paths, 2)
+	assert.Equal(t, "/admin", main.HTTP.Paths[0].Path)
+	assert.Equal(t, int32(9090), main.HTTP.Paths[0].Backend.Service.Port.Number)
+	assert.Equal(t, "/", main.HTTP.Paths[1].Path)
+	assert.Equal(t, int32(3000), main.HTTP.Paths[1].Backend.Service.Port.Number)
+
+	other := ingress.Spec.Rules[1]
+	assert.Equal(t, "api-9000.example.com", other.Host)
+	require.Len(t, other.HTTP.Paths, 1)
+	assert.Equal(t, "api", other.HTTP.Paths[0].Backend.Service.Name)
+	assert.Equal(t, int32(9000), other.HTTP.Paths[0].Backend.Service.Port.Number)
+}
+
+func TestGetErgopackRoutes(t *testing.T) {
+	t.Parallel()
+
+	app := ergopack.ErgopackApp{
+		PublicPort:  "3000",
+		PublicPorts: []string{"3000", "9000", "nope"},
+		Paths:       map[string]string{"9000": "admin", "x": "/x"},
+	}
+
+	assert.Equal(t, []int{3000, 9000}, getErgopackPublicPorts(context.Background(), "api", app))
+	assert.Equal(t, map[int]string{9000: "/admin"}, getErgopackPaths(context.Background(), "api", app))
+}
+
+func TestGitCompose_makeServiceUrlsWithTemplate(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		owner:       "acme",
+		repo:        "repo",
+		branch:      "feature",
+		prNumber:    pointer.Int(7),
+		urlTemplate: "{{service}}.pr-{{pr}}.preview.acme.com",
+	}
+
+	urls, err := c.makeServiceUrls("api", []int{3000, 9000}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []EnvironmentServiceUrl{
+		{Url: "api.pr-7.preview.acme.com", Port: 3000, Path: "/"},
+		{Url: "api-9000.pr-7.preview.acme.com", Port: 9000, Path: "/"},
+	}, urls)
+
+	c.urlTemplate = "{{service}}-{{unknown}}.acme.com"
+	_, err = c.makeServiceUrls("api", []int{3000}, nil)
+	assert.Error(t, err)
+}
+
+func TestFindUrlCollision(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, findUrlCollision(map[string]EnvironmentService{
+		"api": {Urls: []EnvironmentServiceUrl{{Url: "a.acme.com", Path: "/"}, {Url: "a.acme.com", Path: "/admin"}}},
+		"web": {Urls: []EnvironmentServiceUrl{{Url: "w.acme.com", Path: "/"}}},
+	}))
+
+	assert.Equal(t, "Services `api` and `web` are both routed at `a.acme.com/`.", findUrlCollision(map[string]EnvironmentService{
+		"api": {Urls: []EnvironmentServiceUrl{{Url: "a.acme.com", Path: "/"}}},
+		"web": {Urls: []EnvironmentServiceUrl{{Url: "a.acme.com", Path: "/"}}},
+	}))
+}
@@ -0,0 +1,108 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ergomake/ergomake/internal/logger"
+)
+
+const (
+	tlsModeNone        = ""
+	tlsModeWildcard    = "wildcard"
+	tlsModeCertManager = "cert-manager"
+
+	certManagerIssuerAnnotation = "cert-manager.io/cluster-issuer"
+)
+
+type tlsConfig struct {
+	mode string
+	// wildcard mode copies secretName from secretNamespace into every environment namespace
+	secretNamespace string
+	secretName      string
+	// cert-manager mode asks issuer for one certificate per ingress
+	issuer string
+}
+
+var ingressTLS tlsConfig
+
+// setTLS reads TLS_MODE, with `wildcard` TLS_WILDCARD_SECRET must be a `<namespace>/<name>`
+// secret and with `cert-manager` TLS_CERT_MANAGER_ISSUER must be the name of a ClusterIssuer.
+func setTLS() {
+	cfg, err := loadTLSConfig(os.Getenv("TLS_MODE"), os.Getenv("TLS_WILDCARD_SECRET"), os.Getenv("TLS_CERT_MANAGER_ISSUER"))
+	if err != nil {
+		logger.Get().Fatal().AnErr("err", err).Msg("invalid TLS configuration")
+		return
+	}
+
+	ingressTLS = cfg
+}
+
+func loadTLSConfig(mode, wildcardSecret, issuer string) (tlsConfig, error) {
+	switch mode {
+	case tlsModeNone:
+		return tlsConfig{}, nil
+	case tlsModeWildcard:
+		namespace, name, found := strings.Cut(wildcardSecret, "/")
+		if !found || namespace == "" || name == "" {
+			return tlsConfig{}, errors.Errorf("TLS_WILDCARD_SECRET must be <namespace>/<name>, got %q", wildcardSecret)
+		}
+
+		return tlsConfig{mode: mode, secretNamespace: namespace, secretName: name}, nil
+	case tlsModeCertManager:
+		if issuer == "" {
+			return tlsConfig{}, errors.New("TLS_CERT_MANAGER_ISSUER environment variable not set")
+		}
+
+		return tlsConfig{mode: mode, issuer: issuer}, nil
+	}
+
+	return tlsConfig{}, errors.Errorf("unknown TLS_MODE %q, expected %q or %q", mode, tlsModeWildcard, tlsModeCertManager)
+}
+
+// makeTLSObjects returns the objects ingresses need in the namespace to terminate TLS
+func (c *gitCompose) makeTLSObjects(ctx context.Context, namespace string) ([]runtime.Object, error) {
+	if ingressTLS.mode != tlsModeWildcard {
+		return nil, nil
+	}
+
+	secret, err := c.clusterClient.CopySecret(ctx, ingressTLS.secretNamespace, namespace, ingressTLS.secretName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to copy wildcard certificate %s/%s", ingressTLS.secretNamespace, ingressTLS.secretName)
+	}
+
+	return []runtime.Object{secret}, nil
+}
+
+// applyTLS adds a TLS section with every host of the ingress rules
+func (cfg tlsConfig) applyTLS(ingress *networkingv1.Ingress) {
+	if cfg.mode == tlsModeNone {
+		return
+	}
+
+	hosts := []string{}
+	for _, rule := range ingress.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+	}
+
+	secretName := cfg.secretName
+	if cfg.mode == tlsModeCertManager {
+		secretName = fmt.Sprintf("%s-tls", ingress.GetName())
+
+		// annotations may be shared with other objects of the same service
+		annotations := map[string]string{}
+		for k, v := range ingress.GetAnnotations() {
+			annotations[k] = v
+		}
+		annotations[certManagerIssuerAnnotation] = cfg.issuer
+		ingress.SetAnnotations(annotations)
+	}
+
+	ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: hosts, SecretName: secretName}}
+}
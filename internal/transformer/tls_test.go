@@ -0,0 +1,84 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestLoadTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		mode     string
+		secret   string
+		issuer   string
+		expected tlsConfig
+		errors   bool
+	}{
+		{name: "disabled", expected: tlsConfig{}},
+		{
+			name:     "wildcard",
+			mode:     "wildcard",
+			secret:   "ingress-nginx/wildcard-tls",
+			expected: tlsConfig{mode: tlsModeWildcard, secretNamespace: "ingress-nginx", secretName: "wildcard-tls"},
+		},
+		{name: "wildcard without namespace", mode: "wildcard", secret: "wildcard-tls", errors: true},
+		{
+			name:     "cert-manager",
+			mode:     "cert-manager",
+			issuer:   "letsencrypt",
+			expected: tlsConfig{mode: tlsModeCertManager, issuer: "letsencrypt"},
+		},
+		{name: "cert-manager without issuer", mode: "cert-manager", errors: true},
+		{name: "unknown mode", mode: "self-signed", errors: true},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := loadTLSConfig(tc.mode, tc.secret, tc.issuer)
+			if tc.errors {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestTLSConfig_applyTLS(t *testing.T) {
+	t.Parallel()
+
+	urls := []EnvironmentServiceUrl{
+		{Url: "api.example.com", Port: 3000, Path: "/"},
+		{Url: "api-9000.example.com", Port: 9000, Path: "/"},
+	}
+
+	ingress := makeIngress("ns", "api", nil, nil, urls)
+	tlsConfig{}.applyTLS(ingress)
+	assert.Empty(t, ingress.Spec.TLS)
+
+	ingress = makeIngress("ns", "api", nil, nil, urls)
+	tlsConfig{mode: tlsModeWildcard, secretNamespace: "ingress-nginx", secretName: "wildcard-tls"}.applyTLS(ingress)
+	assert.Equal(t, []networkingv1.IngressTLS{
+		{Hosts: []string{"api.example.com", "api-9000.example.com"}, SecretName: "wildcard-tls"},
+	}, ingress.Spec.TLS)
+	assert.Empty(t, ingress.GetAnnotations())
+
+	annotations := map[string]string{"a": "b"}
+	ingress = makeIngress("ns", "api", nil, annotations, urls)
+	tlsConfig{mode: tlsModeCertManager, issuer: "letsencrypt"}.applyTLS(ingress)
+	assert.Equal(t, []networkingv1.IngressTLS{
+		{Hosts: []string{"api.example.com", "api-9000.example.com"}, SecretName: "api-tls"},
+	}, ingress.Spec.TLS)
+	assert.Equal(t, map[string]string{"a": "b", certManagerIssuerAnnotation: "letsencrypt"}, ingress.GetAnnotations())
+	assert.Equal(t, map[string]string{"a": "b"}, annotations)
+}
@@ -0,0 +1,335 @@
+package transformer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ergomake/ergomake/internal/database"
+)
+
+// jobNameHashLength is how much of the build hash goes into build job names, enough to
+// tell builds of the same service apart
+const jobNameHashLength = 12
+
+// EnvironmentUpdate tells what changed when an environment was updated in place
+type EnvironmentUpdate struct {
+	Updated []string
+	Removed []string
+}
+
+func (c *gitCompose) previousService(name string) (database.Service, bool) {
+	if c.previous == nil {
+		return database.Service{}, false
+	}
+
+	for _, service := range c.previous.Services {
+		if service.Name == name {
+			return service, true
+		}
+	}
+
+	return database.Service{}, false
+}
+
+// reusableImage returns the image the previous deploy built for the service when it
+// was built from the same inputs
+func (c *gitCompose) reusableImage(name, buildHash string) (string, bool) {
+	previous, ok := c.previousService(name)
+	if !ok || previous.BuildHash != buildHash || previous.BuildStatus != "build-success" || previous.Image == "" {
+		return "", false
+	}
+
+	return previous.Image, true
+}
+
+// isBuildUnchanged tells whether the pull request changes none of the files the build reads, the config
+// file included since it holds the settings of the build
+func (c *gitCompose) isBuildUnchanged(build *ImageBuild) bool {
+	if !c.skipUnchanged || build.Repo != c.repo || build.ContextURL != "" {
+		return false
+	}
+
+	configFile, err := filepath.Rel(c.projectPath, c.configFilePath)
+	if err != nil {
+		return false
+	}
+
+	paths := []string{configFile, build.Context}
+	if build.Dockerfile != "" {
+		paths = append(paths, path.Join(build.Context, build.Dockerfile))
+	}
+	for _, ctx := range build.AdditionalContexts {
+		if isLocalContext(ctx) {
+			paths = append(paths, ctx)
+		}
+	}
+
+	return !touchesPaths(c.changedFiles, paths)
+}
+
+// touchesPaths tells whether any of files is one of paths or is inside of one of them,
+// both are relative to the root of the repository
+func touchesPaths(files, paths []string) bool {
+	for _, p := range paths {
+		p = strings.TrimPrefix(path.Clean(path.Join("/", p)), "/")
+		for _, file := range files {
+			if p == "" || file == p || strings.HasPrefix(file, p+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// findUnchangedImage looks for an image of the service to reuse when the pull request doesn't change
+// the files of its build and the image was built with the same settings. The permanent environment of
+// the base branch comes first, then previous environments of the pull request that reused one too, the
+// images they built may come from changes the pull request no longer makes.
+func (c *gitCompose) findUnchangedImage(ctx context.Context, build *ImageBuild) (database.Service, bool, error) {
+	var services []database.Service
+	err := c.reusableServices(ctx, build).
+		Where("environments.branch = ? AND environments.branch_owner = ?", c.baseBranch, c.owner).
+		Where("environments.pull_request IS NULL").
+		Limit(1).Find(&services).Error
+	if err != nil {
+		return database.Service{}, false, errors.Wrap(err, "fail to query services of the base branch")
+	}
+	if len(services) > 0 && c.isInRegistry(ctx, services[0].Image) {
+		return services[0], true, nil
+	}
+
+	// updates in place replace the services of the previous deploy in the database
+	previous, ok := c.previousService(build.ServiceName)
+	if ok && previous.BuildReused && previous.BuildStatus == "build-success" && previous.Image != "" &&
+		previous.BuildSettingsHash == build.SettingsHash && c.isInRegistry(ctx, previous.Image) {
+		return previous, true, nil
+	}
+
+	if c.prNumber == nil {
+		return database.Service{}, false, nil
+	}
+
+	services = nil
+	err = c.reusableServices(ctx, build).
+		Where("environments.branch = ? AND environments.branch_owner = ?", c.branch, c.branchOwner).
+		Where("environments.pull_request = ? AND services.build_reused AND services.id <> ?", *c.prNumber, build.ServiceID).
+		Limit(1).Find(&services).Error
+	if err != nil {
+		return database.Service{}, false, errors.Wrap(err, "fail to query services of the pull request")
+	}
+	if len(services) > 0 && c.isInRegistry(ctx, services[0].Image) {
+		return services[0], true, nil
+	}
+
+	return database.Service{}, false, nil
+}
+
+// reusableServices queries the successfully built services of the repo with the name and the build settings
+// of build, latest first, terminated environments included since their images may still be in the registry
+func (c *gitCompose) reusableServices(ctx context.Context, build *ImageBuild) *gorm.DB {
+	return c.db.WithContext(ctx).Unscoped().Model(&database.Service{}).
+		Select("services.*").
+		Joins("JOIN environments ON environments.id = services.environment_id").
+		Where("environments.owner = ? AND environments.repo = ?", c.owner, c.repo).
+		Where("services.name = ? AND services.build_status = ? AND services.image <> ''", build.ServiceName, "build-success").
+		Where("services.build_settings_hash = ?", build.SettingsHash).
+		Order("services.created_at DESC")
+}
+
+// buildHash digests everything a build reads from the repo, the dockerfile, the builder and its inputs.
+// Builds from other repos and remote contexts are not cloned here so their hash changes with every commit.
+func (c *gitCompose) buildHash(build *ImageBuild) (string, error) {
+	h := sha256.New()
+
+	repo := build.Repo
+	buildPath := build.Context
+	dockerfile := build.Dockerfile
+
+	// images are shared by every repo so the hash of a context must not collide with the same files of another repo
+	fmt.Fprintf(h, "repo %s/%s\n", c.branchOwner, repo)
+	writeBuildSettings(h, build)
+
+	if repo != c.repo || build.ContextURL != "" {
+		fmt.Fprintf(h, "sha %s\n", c.sha)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	root := path.Join(c.projectPath, buildPath)
+	paths := []string{root}
+	if dockerfile != "" {
+		// the dockerfile may live outside of the context
+		paths = append(paths, path.Join(root, dockerfile))
+	}
+	for _, k := range sortedKeys(build.AdditionalContexts) {
+		if ctx := build.AdditionalContexts[k]; isLocalContext(ctx) {
+			paths = append(paths, path.Join(c.projectPath, ctx))
+		}
+	}
+
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(c.projectPath, file)
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			fmt.Fprintf(h, "file %s %s\n", rel, d.Type())
+			if d.Type()&fs.ModeSymlink != 0 {
+				target, err := os.Readlink(file)
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(h, "link %s\n", target)
+				return nil
+			}
+
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			_, err = io.Copy(h, f)
+			return err
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return "", errors.Wrapf(err, "fail to hash build context %s", p)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// buildSettingsHash digests the inputs of the build that are not files, a pull request that changes
+// none of the files of a build still needs a new image when they change
+func buildSettingsHash(build *ImageBuild) string {
+	h := sha256.New()
+	writeBuildSettings(h, build)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeBuildSettings(w io.Writer, build *ImageBuild) {
+	fmt.Fprintf(w, "tool %s\n", build.Tool)
+	fmt.Fprintf(w, "dockerfile %s\n", build.Dockerfile)
+	fmt.Fprintf(w, "target %s\n", build.Target)
+	for _, k := range sortedKeys(build.BuildArgs) {
+		fmt.Fprintf(w, "arg %s=%s\n", k, build.BuildArgs[k])
+	}
+	for _, k := range sortedKeys(build.Secrets) {
+		fmt.Fprintf(w, "secret %s=%s\n", k, build.Secrets[k])
+	}
+	for _, k := range sortedKeys(build.Labels) {
+		fmt.Fprintf(w, "label %s=%s\n", k, build.Labels[k])
+	}
+	for _, platform := range build.Platforms {
+		fmt.Fprintf(w, "platform %s\n", platform)
+	}
+	for _, k := range sortedKeys(build.AdditionalContexts) {
+		fmt.Fprintf(w, "context %s=%s\n", k, build.AdditionalContexts[k])
+	}
+	if build.ContextURL != "" {
+		fmt.Fprintf(w, "context %s\n", build.ContextURL)
+	}
+}
+
+// makeEnvironmentUpdate compares the environment with the one it updates, services are
+// updated when they did not exist before or the spec of their deployment or job changed
+func (c *gitCompose) makeEnvironmentUpdate(specHashes map[string]string) *EnvironmentUpdate {
+	if c.previous == nil {
+		return nil
+	}
+
+	update := &EnvironmentUpdate{Updated: []string{}, Removed: []string{}}
+	for name := range c.environment.Services {
+		previous, existed := c.previousService(name)
+		if !existed || previous.SpecHash != specHashes[name] {
+			update.Updated = append(update.Updated, name)
+		}
+	}
+
+	for _, service := range c.previous.Services {
+		if _, ok := c.environment.Services[service.Name]; !ok {
+			update.Removed = append(update.Removed, service.Name)
+		}
+	}
+
+	sort.Strings(update.Updated)
+	sort.Strings(update.Removed)
+
+	return update
+}
+
+// makeSpecHashes digests the spec of the deployments and jobs of objs by name, the same spec
+// applied again leaves the pods running
+func makeSpecHashes(objs []runtime.Object) (map[string]string, error) {
+	hashes := make(map[string]string)
+	for _, obj := range objs {
+		var name string
+		var spec interface{}
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			name, spec = o.GetName(), o.Spec
+		case *batchv1.Job:
+			name, spec = o.GetName(), o.Spec
+		default:
+			continue
+		}
+
+		b, err := json.Marshal(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to marshal spec of %s", name)
+		}
+
+		sum := sha256.Sum256(b)
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+
+	return hashes, nil
+}
+
+// saveSpecHashes stores the spec hashes of the services so the next update can tell what it changes
+func (c *gitCompose) saveSpecHashes(ctx context.Context, specHashes map[string]string) error {
+	for name, service := range c.environment.Services {
+		hash, ok := specHashes[name]
+		if !ok {
+			continue
+		}
+
+		err := c.db.WithContext(ctx).Model(&database.Service{}).Where("id = ?", service.ID).Update("spec_hash", hash).Error
+		if err != nil {
+			return errors.Wrapf(err, "fail to save spec hash of service %s", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,365 @@
+package transformer
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ergomake/ergomake/e2e/testutils"
+	"github.com/ergomake/ergomake/internal/database"
+)
+
+func TestGitCompose_buildHash(t *testing.T) {
+	t.Parallel()
+
+	projectPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(path.Join(projectPath, "api", ".git"), 0700))
+	require.NoError(t, os.MkdirAll(path.Join(projectPath, "web"), 0700))
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "api", "main.go"), []byte("package main"), 0600))
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "web", "index.html"), []byte("<html>"), 0600))
+
+	c := &gitCompose{projectPath: projectPath, repo: "repo", sha: "sha1"}
+	hash := func(repo, buildPath string, args ...string) string {
+		buildArgs := map[string]string{}
+		for i := 0; i+1 < len(args); i += 2 {
+			buildArgs[args[i]] = args[i+1]
+		}
+
+		h, err := c.buildHash(&ImageBuild{
+			Tool:       database.BuildToolKaniko,
+			Repo:       repo,
+			Context:    buildPath,
+			Dockerfile: "Dockerfile",
+			BuildArgs:  buildArgs,
+		})
+		require.NoError(t, err)
+		return h
+	}
+
+	apiHash := hash("repo", "/api", "A", "1")
+	assert.Equal(t, apiHash, hash("repo", "/api", "A", "1"))
+	assert.NotEqual(t, apiHash, hash("repo", "/api", "A", "2"))
+
+	// the same context built by another tool, stage or with other secrets is another image
+	build := &ImageBuild{
+		Tool:       database.BuildToolBuildKit,
+		Repo:       "repo",
+		Context:    "/api",
+		Dockerfile: "Dockerfile",
+		BuildArgs:  map[string]string{"A": "1"},
+	}
+	buildkitHash, err := c.buildHash(build)
+	require.NoError(t, err)
+	assert.NotEqual(t, apiHash, buildkitHash)
+
+	build.Target = "prod"
+	targetHash, err := c.buildHash(build)
+	require.NoError(t, err)
+	assert.NotEqual(t, buildkitHash, targetHash)
+
+	build.Secrets = map[string]string{"TOKEN": "secret"}
+	secretHash, err := c.buildHash(build)
+	require.NoError(t, err)
+	assert.NotEqual(t, targetHash, secretHash)
+
+	build.Labels = map[string]string{"team": "core"}
+	labelsHash, err := c.buildHash(build)
+	require.NoError(t, err)
+	assert.NotEqual(t, secretHash, labelsHash)
+
+	// remote contexts aren't cloned here, they are rebuilt on every commit
+	remote := &ImageBuild{Tool: database.BuildToolKaniko, Repo: "repo", ContextURL: "https://github.com/ergomake/api.git"}
+	remoteHash, err := c.buildHash(remote)
+	require.NoError(t, err)
+	c.sha = "sha3"
+	otherCommitHash, err := c.buildHash(remote)
+	require.NoError(t, err)
+	assert.NotEqual(t, remoteHash, otherCommitHash)
+	c.sha = "sha1"
+
+	// files of other services and of .git don't matter
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "web", "index.html"), []byte("<body>"), 0600))
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "api", ".git", "HEAD"), []byte("ref"), 0600))
+	assert.Equal(t, apiHash, hash("repo", "/api", "A", "1"))
+
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "api", "main.go"), []byte("package api"), 0600))
+	assert.NotEqual(t, apiHash, hash("repo", "/api", "A", "1"))
+
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "api", "Dockerfile"), []byte("FROM go"), 0600))
+	withDockerfile := hash("repo", "/api", "A", "1")
+	require.NoError(t, os.WriteFile(path.Join(projectPath, "api", "Dockerfile"), []byte("FROM node"), 0600))
+	assert.NotEqual(t, withDockerfile, hash("repo", "/api", "A", "1"))
+
+	otherRepoHash := hash("other", ".")
+	c.sha = "sha2"
+	assert.NotEqual(t, otherRepoHash, hash("other", "."))
+
+	// the same files in a fork are another build
+	forkHash := hash("repo", "/api", "A", "1")
+	c.branchOwner = "fork"
+	assert.NotEqual(t, forkHash, hash("repo", "/api", "A", "1"))
+}
+
+func TestGitCompose_reusableImage(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{previous: &database.Environment{Services: []database.Service{
+		{Name: "api", Image: "registry:ns-api-aaa", BuildHash: "aaa", BuildStatus: "build-success"},
+		{Name: "web", Image: "registry:ns-web-bbb", BuildHash: "bbb", BuildStatus: "build-failed"},
+	}}}
+
+	image, ok := c.reusableImage("api", "aaa")
+	assert.True(t, ok)
+	assert.Equal(t, "registry:ns-api-aaa", image)
+
+	_, ok = c.reusableImage("api", "ccc")
+	assert.False(t, ok)
+
+	_, ok = c.reusableImage("web", "bbb")
+	assert.False(t, ok)
+
+	_, ok = c.reusableImage("worker", "aaa")
+	assert.False(t, ok)
+
+	_, ok = (&gitCompose{}).reusableImage("api", "aaa")
+	assert.False(t, ok)
+}
+
+func TestGitCompose_isBuildUnchanged(t *testing.T) {
+	t.Parallel()
+
+	build := &ImageBuild{
+		Repo:               "repo",
+		Context:            "/services/api",
+		Dockerfile:         "../../docker/api.Dockerfile",
+		AdditionalContexts: map[string]string{"shared": "libs/shared", "alpine": "docker-image://alpine"},
+	}
+
+	tt := []struct {
+		name         string
+		skip         bool
+		changedFiles []string
+		build        *ImageBuild
+		want         bool
+	}{
+		{
+			name:         "changes of other services",
+			skip:         true,
+			changedFiles: []string{"services/web/index.html", "services/api-gateway/main.go", "README.md"},
+			build:        build,
+			want:         true,
+		},
+		{
+			name:         "change in the context",
+			skip:         true,
+			changedFiles: []string{"services/api/main.go"},
+			build:        build,
+			want:         false,
+		},
+		{
+			name:         "change of the dockerfile outside of the context",
+			skip:         true,
+			changedFiles: []string{"docker/api.Dockerfile"},
+			build:        build,
+			want:         false,
+		},
+		{
+			name:         "change in an additional context",
+			skip:         true,
+			changedFiles: []string{"libs/shared/util.go"},
+			build:        build,
+			want:         false,
+		},
+		{
+			name:         "change of the config file",
+			skip:         true,
+			changedFiles: []string{".ergomake/docker-compose.yml"},
+			build:        build,
+			want:         false,
+		},
+		{
+			name:         "context at the root of the repo",
+			skip:         true,
+			changedFiles: []string{"README.md"},
+			build:        &ImageBuild{Repo: "repo", Context: "/"},
+			want:         false,
+		},
+		{
+			name:         "build from another repo",
+			skip:         true,
+			changedFiles: []string{},
+			build:        &ImageBuild{Repo: "other", Context: "/api"},
+			want:         false,
+		},
+		{
+			name:         "remote context",
+			skip:         true,
+			changedFiles: []string{},
+			build:        &ImageBuild{Repo: "repo", ContextURL: "https://github.com/owner/api.git"},
+			want:         false,
+		},
+		{
+			name:  "unknown changes",
+			skip:  false,
+			build: build,
+			want:  false,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &gitCompose{
+				repo:           "repo",
+				projectPath:    "/tmp/project",
+				configFilePath: "/tmp/project/.ergomake/docker-compose.yml",
+				skipUnchanged:  tc.skip,
+				changedFiles:   tc.changedFiles,
+			}
+			assert.Equal(t, tc.want, c.isBuildUnchanged(tc.build))
+		})
+	}
+}
+
+func TestBuildSettingsHash(t *testing.T) {
+	t.Parallel()
+
+	build := &ImageBuild{
+		Tool:      database.BuildToolKaniko,
+		Context:   "api",
+		BuildArgs: map[string]string{"NODE_ENV": "production"},
+		Secrets:   map[string]string{"NPM_TOKEN": "npm"},
+	}
+	hash := buildSettingsHash(build)
+
+	// files are not settings
+	build.Context = "web"
+	assert.Equal(t, hash, buildSettingsHash(build))
+
+	build.BuildArgs["NODE_ENV"] = "development"
+	changedArg := buildSettingsHash(build)
+	assert.NotEqual(t, hash, changedArg)
+
+	build.Secrets["NPM_TOKEN"] = "rotated"
+	assert.NotEqual(t, changedArg, buildSettingsHash(build))
+}
+
+func TestGitCompose_findUnchangedImage(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.CreateRandomDB(t)
+	env := database.NewEnvironment(uuid.New(), "owner", "owner", "repo", "main", nil, "author", database.EnvSuccess)
+	require.NoError(t, db.Create(env).Error)
+	require.NoError(t, db.Create(&database.Service{
+		ID:                uuid.NewString(),
+		Name:              "api",
+		EnvironmentID:     env.ID,
+		Image:             makeBuildImage("aaa"),
+		BuildHash:         "aaa",
+		BuildSettingsHash: "settings",
+		BuildStatus:       "build-success",
+	}).Error)
+
+	tt := []struct {
+		name         string
+		settingsHash string
+		exists       bool
+		want         bool
+	}{
+		{name: "reuses the image of the base branch", settingsHash: "settings", exists: true, want: true},
+		{name: "builds when the settings of the build changed", settingsHash: "other", exists: true, want: false},
+		{name: "builds when the image is gone from the registry", settingsHash: "settings", exists: false, want: false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checked := []string{}
+			c := &gitCompose{
+				db:         db,
+				owner:      "owner",
+				repo:       "repo",
+				baseBranch: "main",
+				imageExists: func(ctx context.Context, image string) (bool, error) {
+					checked = append(checked, image)
+					return tc.exists, nil
+				},
+			}
+
+			build := &ImageBuild{ServiceID: uuid.NewString(), ServiceName: "api", SettingsHash: tc.settingsHash}
+			service, ok, err := c.findUnchangedImage(context.Background(), build)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ok)
+			if tc.want {
+				assert.Equal(t, makeBuildImage("aaa"), service.Image)
+			}
+
+			if tc.settingsHash == "settings" {
+				assert.Equal(t, []string{makeBuildImage("aaa")}, checked)
+			} else {
+				assert.Empty(t, checked, "the registry is only checked for images built with the same settings")
+			}
+		})
+	}
+}
+
+func TestGitCompose_makeEnvironmentUpdate(t *testing.T) {
+	t.Parallel()
+
+	environment := &Environment{Services: map[string]EnvironmentService{
+		"api":    {},
+		"web":    {},
+		"worker": {},
+	}}
+	specHashes := map[string]string{"api": "api-hash", "web": "new-web-hash", "worker": "worker-hash"}
+
+	c := &gitCompose{environment: environment, isCompose: true}
+	assert.Nil(t, c.makeEnvironmentUpdate(specHashes))
+
+	c.previous = &database.Environment{Services: []database.Service{
+		{Name: "api", SpecHash: "api-hash"},
+		{Name: "web", SpecHash: "web-hash"},
+		{Name: "db", SpecHash: "db-hash"},
+	}}
+	assert.Equal(t, &EnvironmentUpdate{
+		Updated: []string{"web", "worker"},
+		Removed: []string{"db"},
+	}, c.makeEnvironmentUpdate(specHashes))
+}
+
+func TestMakeSpecHashes(t *testing.T) {
+	t.Parallel()
+
+	makeObjs := func(image string) []runtime.Object {
+		api := makeDependencyTestDeployment("api")
+		api.Spec.Template.Spec.Containers[0].Image = image
+		migrate := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate"}}
+		return []runtime.Object{api, migrate, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api"}}}
+	}
+
+	hashes, err := makeSpecHashes(makeObjs("api:1"))
+	require.NoError(t, err)
+	assert.Len(t, hashes, 2)
+
+	same, err := makeSpecHashes(makeObjs("api:1"))
+	require.NoError(t, err)
+	assert.Equal(t, hashes, same)
+
+	changed, err := makeSpecHashes(makeObjs("api:2"))
+	require.NoError(t, err)
+	assert.NotEqual(t, hashes["api"], changed["api"])
+	assert.Equal(t, hashes["migrate"], changed["migrate"])
+}
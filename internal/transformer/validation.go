@@ -7,8 +7,12 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/cbroglie/mustache"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"gopkg.in/yaml.v3"
 )
@@ -39,6 +43,11 @@ func (v *ProjectValidationErrorInvalidCompose) Serialize() json.RawMessage {
 }
 
 func (c *gitCompose) validateProject() (*ProjectValidationError, error) {
+	validationErr, err := c.validateCommentTemplate()
+	if err != nil || validationErr != nil {
+		return validationErr, err
+	}
+
 	ergopackPath, err := findErgopackPath(c.projectPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "fail to find ergopack path")
@@ -64,6 +73,34 @@ func (c *gitCompose) validateProject() (*ProjectValidationError, error) {
 	return validateCompose(c.projectPath, composePath)
 }
 
+const commentTemplatePath = ".ergomake/comment.md"
+
+// validateCommentTemplate loads the mustache template the project wants its pull request comments made of
+func (c *gitCompose) validateCommentTemplate() (*ProjectValidationError, error) {
+	c.commentTemplate = ""
+
+	content, err := ioutil.ReadFile(path.Join(c.projectPath, commentTemplatePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "fail to read %s", commentTemplatePath)
+	}
+
+	_, err = mustache.ParseString(string(content))
+	if err != nil {
+		return &ProjectValidationError{
+			T:       "invalid-comment-template",
+			Message: fmt.Sprintf("Comment template `%s` is invalid: %s", commentTemplatePath, err.Error()),
+		}, nil
+	}
+
+	c.commentTemplate = string(content)
+
+	return nil, nil
+}
+
 var ergopackPaths = []string{
 	".ergomake/ergopack.yml",
 	".ergomake/ergopack.yaml",
@@ -163,8 +200,21 @@ func validateCompose(projectPath, composePath string) (*ProjectValidationError,
 	}
 
 	validationErr, err := validateEnvFiles(composePath, services)
+	if err != nil || validationErr != nil {
+		return validationErr, errors.Wrap(err, "fail to validate env files")
+	}
+
+	validationErr, err = validateVolumes(projectPath, composePath, content, services)
+	if err != nil || validationErr != nil {
+		return validationErr, errors.Wrap(err, "fail to validate volumes")
+	}
+
+	validationErr, err = validateBuilds(projectPath, composePath, content, services)
+	if err != nil || validationErr != nil {
+		return validationErr, errors.Wrap(err, "fail to validate builds")
+	}
 
-	return validationErr, errors.Wrap(err, "fail to validate env files")
+	return validateDependencies(content, services), nil
 }
 
 func validateEnvFiles(composePath string, services map[string]map[string]interface{}) (*ProjectValidationError, error) {
@@ -218,3 +268,277 @@ func validateEnvFiles(composePath string, services map[string]map[string]interfa
 
 	return nil, nil
 }
+
+func validateVolumes(
+	projectPath string,
+	composePath string,
+	content []byte,
+	services map[string]map[string]interface{},
+) (*ProjectValidationError, error) {
+	volumes, err := loadComposeVolumes(content)
+	if err != nil {
+		return &ProjectValidationError{
+			T:       "invalid-compose",
+			Message: "Compose `volumes` has invalid type, expect a map.",
+		}, nil
+	}
+
+	for name, vol := range volumes {
+		if vol.Size == "" {
+			continue
+		}
+
+		if _, err := resource.ParseQuantity(vol.Size); err != nil {
+			return &ProjectValidationError{
+				T:       "invalid-compose",
+				Message: fmt.Sprintf("Volume `%s` has invalid size `%s`.", name, vol.Size),
+			}, nil
+		}
+	}
+
+	// named volumes become ReadWriteOnce claims, pods of different services could not mount the same one
+	volumeServices := make(map[string]string)
+	serviceNames := make([]string, 0, len(services))
+	for name := range services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+
+	for _, name := range serviceNames {
+		rawVolumes, ok := services[name]["volumes"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, rawVolume := range rawVolumes {
+			if volume := namedVolumeSource(rawVolume); volume != "" {
+				if other, ok := volumeServices[volume]; ok && other != name {
+					return &ProjectValidationError{
+						T: "invalid-compose",
+						Message: fmt.Sprintf(
+							"Volume `%s` is used by services `%s` and `%s`, a volume can only be mounted by one service.",
+							volume,
+							other,
+							name,
+						),
+					}, nil
+				}
+				volumeServices[volume] = name
+				continue
+			}
+
+			source := bindMountSource(rawVolume)
+			if source == "" {
+				continue
+			}
+
+			supported, err := isBindMountSupported(projectPath, composePath, source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to check bind mount %s of service %s", source, name)
+			}
+
+			if !supported {
+				return &ProjectValidationError{
+					T: "invalid-compose",
+					Message: fmt.Sprintf(
+						"Service `%s` binds `%s` which is not a file or directory of the repository, "+
+							"use a named volume instead.",
+						name,
+						source,
+					),
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// validateBuilds reports the build options the builder of a service doesn't support. Local additional
+// contexts are cloned along with the build context so both must be inside of the repository.
+func validateBuilds(
+	projectPath string,
+	composePath string,
+	content []byte,
+	services map[string]map[string]interface{},
+) (*ProjectValidationError, error) {
+	builds, err := loadComposeBuilds(content)
+	if err != nil {
+		return makeBuildValidationError("invalid-compose", err), nil
+	}
+
+	for name, svc := range services {
+		settings := builds[normalizeServiceName(name)]
+
+		context := "."
+		switch build := svc["build"].(type) {
+		case string:
+			context = build
+		case map[string]interface{}:
+			if c, ok := build["context"].(string); ok {
+				context = c
+			}
+		}
+
+		for ctxName, ctx := range settings.AdditionalContexts {
+			if !isLocalContext(ctx) {
+				continue
+			}
+
+			contextSupported, err := isBindMountSupported(projectPath, composePath, context)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to check build context %s of service %s", context, name)
+			}
+
+			supported, err := isBindMountSupported(projectPath, composePath, ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to check additional context %s of service %s", ctx, name)
+			}
+
+			if !supported || !contextSupported {
+				return &ProjectValidationError{
+					T: "invalid-compose",
+					Message: fmt.Sprintf(
+						"Additional context `%s` of service `%s` and the build context must be inside of the repository.",
+						ctxName,
+						name,
+					),
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// bindMountSource returns the host path of a bind mount or empty when volume is not a bind mount
+func bindMountSource(rawVolume interface{}) string {
+	switch volume := rawVolume.(type) {
+	case string:
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 || !isHostPath(parts[0]) {
+			return ""
+		}
+
+		return parts[0]
+	case map[string]interface{}:
+		if volume["type"] != "bind" {
+			return ""
+		}
+
+		source, _ := volume["source"].(string)
+		return source
+	}
+
+	return ""
+}
+
+// namedVolumeSource is the name of the named volume a service mounts, empty for bind mounts and anonymous volumes
+func namedVolumeSource(rawVolume interface{}) string {
+	switch volume := rawVolume.(type) {
+	case string:
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 || isHostPath(parts[0]) {
+			return ""
+		}
+
+		return parts[0]
+	case map[string]interface{}:
+		if volume["type"] != "volume" {
+			return ""
+		}
+
+		source, _ := volume["source"].(string)
+		return source
+	}
+
+	return ""
+}
+
+func isHostPath(p string) bool {
+	return strings.HasPrefix(p, ".") || strings.HasPrefix(p, "/") || strings.HasPrefix(p, "~")
+}
+
+// isBindMountSupported checks that source lives inside the repository, those are the
+// only host paths we are able to mount since they get turned into config maps
+func isBindMountSupported(projectPath, composePath, source string) (bool, error) {
+	if filepath.IsAbs(source) || strings.HasPrefix(source, "~") {
+		return false, nil
+	}
+
+	sourcePath := filepath.Clean(path.Join(path.Dir(composePath), source))
+	relativePath, err := filepath.Rel(filepath.Clean(projectPath), sourcePath)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return false, nil
+	}
+
+	if _, err := os.Stat(sourcePath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, errors.Wrapf(err, "fail to stat %s", sourcePath)
+	}
+
+	return true, nil
+}
+
+func validateDependencies(content []byte, services map[string]map[string]interface{}) *ProjectValidationError {
+	dependencies, healthchecks, err := loadComposeDependencies(content)
+	if err != nil {
+		return &ProjectValidationError{
+			T:       "invalid-compose",
+			Message: fmt.Sprintf("Compose has invalid `depends_on` or `healthcheck`\n```\n%s\n```", err.Error()),
+		}
+	}
+
+	normalizedServices := make(map[string]map[string]interface{})
+	for name, svc := range services {
+		normalizedServices[normalizeServiceName(name)] = svc
+	}
+
+	for name, deps := range dependencies {
+		for _, dep := range deps {
+			svc, ok := normalizedServices[dep.Service]
+			if !ok {
+				return &ProjectValidationError{
+					T:       "invalid-compose",
+					Message: fmt.Sprintf("Service `%s` depends on `%s` which does not exist.", name, dep.Service),
+				}
+			}
+
+			if dep.Condition != conditionHealthy {
+				continue
+			}
+
+			if _, ok := healthchecks[dep.Service]; !ok {
+				return &ProjectValidationError{
+					T: "invalid-compose",
+					Message: fmt.Sprintf(
+						"Service `%s` depends on `%s` being healthy but `%s` has no `healthcheck`.",
+						name,
+						dep.Service,
+						dep.Service,
+					),
+				}
+			}
+
+			_, hasPorts := svc["ports"]
+			_, hasExpose := svc["expose"]
+			if !hasPorts && !hasExpose {
+				return &ProjectValidationError{
+					T: "invalid-compose",
+					Message: fmt.Sprintf(
+						"Service `%s` depends on `%s` being healthy but `%s` has no `ports` or `expose`, "+
+							"we need a port to know when it is ready.",
+						name,
+						dep.Service,
+						dep.Service,
+					),
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -126,6 +126,140 @@ services:
   web:
     env_file: 
       - 'this_file_does_not_exists.env'
+`,
+		},
+		{
+			name: "bind mount of absolute host path",
+			compose: `
+version: '3'
+services:
+  web:
+    volumes:
+      - /var/run/docker.sock:/var/run/docker.sock
+`,
+		},
+		{
+			name: "bind mount outside of the repository",
+			compose: `
+version: '3'
+services:
+  web:
+    volumes:
+      - type: bind
+        source: ../somewhere-else
+        target: /data
+`,
+		},
+		{
+			name: "bind mount of non existing path",
+			compose: `
+version: '3'
+services:
+  web:
+    volumes:
+      - ./this_dir_does_not_exist:/data
+`,
+		},
+		{
+			name: "volume with invalid size",
+			compose: `
+version: '3'
+services:
+  db:
+    volumes:
+      - data:/var/lib/postgresql/data
+volumes:
+  data:
+    x-ergomake:
+      size: 'a lot'
+`,
+		},
+		{
+			name: "named volume shared by two services",
+			compose: `
+version: '3'
+services:
+  api:
+    image: api
+    volumes:
+      - uploads:/app/uploads
+  worker:
+    image: worker
+    volumes:
+      - type: volume
+        source: uploads
+        target: /uploads
+volumes:
+  uploads:
+`,
+		},
+		{
+			name: "depends on unknown service",
+			compose: `
+version: '3'
+services:
+  web:
+    image: nginx
+    depends_on:
+      - api
+`,
+		},
+		{
+			name: "depends on healthy service without healthcheck",
+			compose: `
+version: '3'
+services:
+  web:
+    image: nginx
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: postgres
+    expose:
+      - 5432
+`,
+		},
+		{
+			name: "depends on healthy service without ports",
+			compose: `
+version: '3'
+services:
+  web:
+    image: nginx
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: postgres
+    healthcheck:
+      test: pg_isready
+`,
+		},
+		{
+			name: "build option kaniko does not support",
+			compose: `
+version: '3'
+services:
+  web:
+    build:
+      context: .
+      cache_from:
+        - registry/web:cache
+`,
+		},
+		{
+			name: "additional context outside of the repository",
+			compose: `
+version: '3'
+services:
+  web:
+    build:
+      context: .
+      additional_contexts:
+        shared: ../somewhere-else
+    labels:
+      dev.ergomake.builder: buildkit
 `,
 		},
 	}
@@ -196,3 +330,85 @@ services:
 
 	assert.Nil(t, vErr)
 }
+
+func TestGitCompose_validateProjectAcceptsNamedAndAnonymousVolumes(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "validate_project_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	err = ioutil.WriteFile(
+		path.Join(tmpDir, "compose.yaml"),
+		[]byte(`
+version: '3'
+services:
+  db:
+    image: postgres
+    volumes:
+      - data:/var/lib/postgresql/data
+      - /tmp
+      - ./init.sql:/docker-entrypoint-initdb.d/init.sql:ro
+volumes:
+  data:
+    labels:
+      dev.ergomake.volume.size: 5Gi
+`),
+		0644,
+	)
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(path.Join(tmpDir, "init.sql"), []byte("SELECT 1;"), 0644)
+	require.NoError(t, err)
+
+	gc := &gitCompose{
+		projectPath: tmpDir,
+	}
+
+	vErr, err := gc.validateProject()
+	require.NoError(t, err)
+
+	assert.Nil(t, vErr)
+}
+
+func TestGitCompose_validateProjectCommentTemplate(t *testing.T) {
+	tt := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "valid template", template: "{{#success}}Ready at {{mainUrl}}{{/success}}"},
+		{name: "unclosed section", template: "{{#success}}Ready", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "validate_project_test")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpDir)
+
+			err = os.Mkdir(path.Join(tmpDir, ".ergomake"), 0700)
+			require.NoError(t, err)
+
+			err = ioutil.WriteFile(path.Join(tmpDir, ".ergomake/compose.yaml"), []byte("services:\n  web:\n    image: nginx\n"), 0644)
+			require.NoError(t, err)
+
+			err = ioutil.WriteFile(path.Join(tmpDir, commentTemplatePath), []byte(tc.template), 0644)
+			require.NoError(t, err)
+
+			gc := &gitCompose{
+				projectPath: tmpDir,
+			}
+
+			vErr, err := gc.validateProject()
+			require.NoError(t, err)
+
+			if tc.wantErr {
+				require.NotNil(t, vErr)
+				assert.Equal(t, "invalid-comment-template", vErr.T)
+				assert.Equal(t, "", gc.commentTemplate)
+			} else {
+				assert.Nil(t, vErr)
+				assert.Equal(t, tc.template, gc.commentTemplate)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package transformer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const defaultVolumeSize = "1Gi"
+
+const (
+	volumeSizeLabel         = "dev.ergomake.volume.size"
+	volumeStorageClassLabel = "dev.ergomake.volume.storage-class"
+)
+
+// composeVolume holds the ergomake specific settings of a top-level compose volume
+type composeVolume struct {
+	Size         string
+	StorageClass string
+}
+
+type rawComposeVolumes struct {
+	Volumes map[string]*struct {
+		Labels   interface{} `yaml:"labels"`
+		Ergomake *struct {
+			Size         string `yaml:"size"`
+			StorageClass string `yaml:"storageClass"`
+		} `yaml:"x-ergomake"`
+	} `yaml:"volumes"`
+}
+
+// loadComposeVolumes reads the top-level volumes of a compose file. Settings can
+// come from a `x-ergomake` extension or from `dev.ergomake.volume.*` labels, the
+// extension wins when both are present.
+func loadComposeVolumes(content []byte) (map[string]composeVolume, error) {
+	var raw rawComposeVolumes
+	err := yaml.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal compose volumes")
+	}
+
+	volumes := make(map[string]composeVolume)
+	for name, rawVol := range raw.Volumes {
+		vol := composeVolume{}
+		if rawVol != nil {
+			labels := parseComposeLabels(rawVol.Labels)
+			vol.Size = labels[volumeSizeLabel]
+			vol.StorageClass = labels[volumeStorageClassLabel]
+
+			if rawVol.Ergomake != nil {
+				if rawVol.Ergomake.Size != "" {
+					vol.Size = rawVol.Ergomake.Size
+				}
+				if rawVol.Ergomake.StorageClass != "" {
+					vol.StorageClass = rawVol.Ergomake.StorageClass
+				}
+			}
+		}
+
+		volumes[normalizeVolumeName(name)] = vol
+	}
+
+	return volumes, nil
+}
+
+// parseComposeLabels accepts both the map and the list syntax of compose labels
+func parseComposeLabels(raw interface{}) map[string]string {
+	labels := make(map[string]string)
+
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		for k, v := range raw {
+			labels[k] = fmt.Sprint(v)
+		}
+	case []interface{}:
+		for _, item := range raw {
+			parts := strings.SplitN(fmt.Sprint(item), "=", 2)
+			if len(parts) == 2 {
+				labels[parts[0]] = parts[1]
+			} else {
+				labels[parts[0]] = ""
+			}
+		}
+	}
+
+	return labels
+}
+
+func normalizeVolumeName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// extractPersistentVolumes removes named and anonymous volumes from the service
+// so kompose only handles bind mounts, we turn the removed ones into PVCs later.
+func (c *gitCompose) extractPersistentVolumes(name string, service *kobject.ServiceConfig) {
+	volumes := []kobject.Volumes{}
+	persistent := []kobject.Volumes{}
+	for _, vol := range service.Volumes {
+		if vol.Host == "" {
+			persistent = append(persistent, vol)
+			continue
+		}
+		volumes = append(volumes, vol)
+	}
+
+	service.Volumes = volumes
+
+	if len(persistent) == 0 {
+		return
+	}
+
+	if c.persistentVolumes == nil {
+		c.persistentVolumes = make(map[string][]kobject.Volumes)
+	}
+	c.persistentVolumes[name] = persistent
+}
+
+func claimName(vol kobject.Volumes) string {
+	if vol.VolumeName != "" {
+		return normalizeVolumeName(vol.VolumeName)
+	}
+
+	return normalizeVolumeName(vol.PVCName)
+}
+
+func (c *gitCompose) addPersistentVolumes(deployment *appsv1.Deployment) {
+	serviceName := deployment.GetLabels()["io.kompose.service"]
+	volumes := c.persistentVolumes[serviceName]
+	if len(volumes) == 0 {
+		return
+	}
+
+	podSpec := &deployment.Spec.Template.Spec
+	for _, vol := range volumes {
+		name := claimName(vol)
+
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: name,
+				},
+			},
+		})
+
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      name,
+				MountPath: vol.Container,
+				ReadOnly:  vol.Mode == "ro",
+			})
+		}
+	}
+
+	// claims are ReadWriteOnce, a rolling update would get stuck waiting for the old pod to release them
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+}
+
+func (c *gitCompose) makePersistentVolumeClaims(namespace string) ([]runtime.Object, error) {
+	claims := make(map[string]*corev1.PersistentVolumeClaim)
+	objs := []runtime.Object{}
+
+	for serviceName, volumes := range c.persistentVolumes {
+		service := c.komposeObject.ServiceConfigs[serviceName]
+
+		for _, vol := range volumes {
+			name := claimName(vol)
+			if _, ok := claims[name]; ok {
+				continue
+			}
+
+			size := defaultVolumeSize
+			storageClass := ""
+			if vol.VolumeName != "" {
+				settings := c.volumes[name]
+				storageClass = settings.StorageClass
+				if settings.Size != "" {
+					size = settings.Size
+				} else if vol.PVCSize != "" {
+					size = vol.PVCSize
+				}
+			} else {
+				if v, ok := service.Labels[volumeSizeLabel]; ok {
+					size = v
+				}
+				storageClass = service.Labels[volumeStorageClassLabel]
+			}
+
+			quantity, err := resource.ParseQuantity(size)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to parse size %s of volume %s", size, name)
+			}
+
+			claim := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"preview.ergomake.dev/volume": name,
+						"preview.ergomake.dev/owner":  c.owner,
+						"preview.ergomake.dev/repo":   c.repo,
+					},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: quantity,
+						},
+					},
+				},
+			}
+			if storageClass != "" {
+				claim.Spec.StorageClassName = &storageClass
+			}
+
+			claims[name] = claim
+			objs = append(objs, claim)
+		}
+	}
+
+	return objs, nil
+}
@@ -0,0 +1,175 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestLoadComposeVolumes(t *testing.T) {
+	t.Parallel()
+
+	volumes, err := loadComposeVolumes([]byte(`
+version: '3'
+services:
+  db:
+    image: postgres
+volumes:
+  empty:
+  from_labels:
+    labels:
+      dev.ergomake.volume.size: 5Gi
+      dev.ergomake.volume.storage-class: gp3
+  from_list_labels:
+    labels:
+      - dev.ergomake.volume.size=2Gi
+  extension_wins:
+    labels:
+      dev.ergomake.volume.size: 5Gi
+    x-ergomake:
+      size: 10Gi
+      storageClass: standard
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]composeVolume{
+		"empty":            {},
+		"from-labels":      {Size: "5Gi", StorageClass: "gp3"},
+		"from-list-labels": {Size: "2Gi"},
+		"extension-wins":   {Size: "10Gi", StorageClass: "standard"},
+	}, volumes)
+}
+
+func TestGitCompose_extractPersistentVolumes(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{}
+	service := kobject.ServiceConfig{
+		Name: "db",
+		Volumes: []kobject.Volumes{
+			{VolumeName: "data", Container: "/var/lib/postgresql/data", PVCName: "db-claim0"},
+			{Container: "/tmp", PVCName: "db-claim1"},
+			{Host: "./init.sql", Container: "/docker-entrypoint-initdb.d/init.sql", PVCName: "db-claim2"},
+		},
+	}
+
+	c.extractPersistentVolumes("db", &service)
+
+	assert.Equal(t, []kobject.Volumes{
+		{Host: "./init.sql", Container: "/docker-entrypoint-initdb.d/init.sql", PVCName: "db-claim2"},
+	}, service.Volumes)
+	assert.Equal(t, map[string][]kobject.Volumes{
+		"db": {
+			{VolumeName: "data", Container: "/var/lib/postgresql/data", PVCName: "db-claim0"},
+			{Container: "/tmp", PVCName: "db-claim1"},
+		},
+	}, c.persistentVolumes)
+}
+
+func TestGitCompose_addPersistentVolumes(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		persistentVolumes: map[string][]kobject.Volumes{
+			"db": {
+				{VolumeName: "data", Container: "/var/lib/postgresql/data", PVCName: "db-claim0"},
+				{Container: "/backup", Mode: "ro", PVCName: "db-claim1"},
+			},
+		},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"io.kompose.service": "db"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "db"}},
+				},
+			},
+		},
+	}
+
+	c.addPersistentVolumes(deployment)
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, appsv1.RecreateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, []corev1.Volume{
+		{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+			},
+		},
+		{
+			Name: "db-claim1",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "db-claim1"},
+			},
+		},
+	}, podSpec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "data", MountPath: "/var/lib/postgresql/data"},
+		{Name: "db-claim1", MountPath: "/backup", ReadOnly: true},
+	}, podSpec.Containers[0].VolumeMounts)
+}
+
+func TestGitCompose_makePersistentVolumeClaims(t *testing.T) {
+	t.Parallel()
+
+	c := &gitCompose{
+		owner: "owner",
+		repo:  "repo",
+		komposeObject: &kobject.KomposeObject{
+			ServiceConfigs: map[string]kobject.ServiceConfig{
+				"db": {
+					Name:   "db",
+					Labels: map[string]string{volumeSizeLabel: "3Gi"},
+				},
+				"api": {Name: "api"},
+			},
+		},
+		volumes: map[string]composeVolume{
+			"data": {Size: "10Gi", StorageClass: "gp3"},
+		},
+		persistentVolumes: map[string][]kobject.Volumes{
+			"db": {
+				{VolumeName: "data", Container: "/var/lib/postgresql/data", PVCName: "db-claim0"},
+				{Container: "/tmp", PVCName: "db-claim1"},
+			},
+			"api": {
+				{VolumeName: "data", Container: "/data", PVCName: "api-claim0"},
+			},
+		},
+	}
+
+	objs, err := c.makePersistentVolumeClaims("namespace")
+	require.NoError(t, err)
+
+	claims := map[string]*corev1.PersistentVolumeClaim{}
+	for _, obj := range objs {
+		claim := obj.(*corev1.PersistentVolumeClaim)
+		claims[claim.GetName()] = claim
+	}
+
+	require.Len(t, claims, 2)
+
+	data := claims["data"]
+	require.NotNil(t, data)
+	assert.Equal(t, "namespace", data.GetNamespace())
+	assert.Equal(t, pointer.String("gp3"), data.Spec.StorageClassName)
+	assert.Equal(t, resource.MustParse("10Gi"), data.Spec.Resources.Requests[corev1.ResourceStorage])
+
+	anonymous := claims["db-claim1"]
+	require.NotNil(t, anonymous)
+	assert.Nil(t, anonymous.Spec.StorageClassName)
+	assert.Equal(t, resource.MustParse("3Gi"), anonymous.Spec.Resources.Requests[corev1.ResourceStorage])
+}
@@ -0,0 +1,43 @@
+package urltemplates
+
+import (
+	"context"
+
+	"github.com/ergomake/ergomake/internal/database"
+	"github.com/ergomake/ergomake/internal/reposettings"
+)
+
+type urlTemplate struct {
+	Template string
+}
+
+type dbURLTemplatesProvider struct {
+	db *database.DB
+}
+
+func NewDBURLTemplatesProvider(db *database.DB) *dbURLTemplatesProvider {
+	return &dbURLTemplatesProvider{db}
+}
+
+func (utp *dbURLTemplatesProvider) Get(ctx context.Context, provider, owner, repo string) (string, error) {
+	var tmpl urlTemplate
+	found, err := reposettings.Get(ctx, utp.db.DB, "url_templates", reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}, &tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return DefaultTemplate, nil
+	}
+
+	return tmpl.Template, nil
+}
+
+func (utp *dbURLTemplatesProvider) Upsert(ctx context.Context, provider, owner, repo, template string) error {
+	key := reposettings.Repo{Provider: provider, Owner: owner, Repo: repo}
+	if template == "" || template == DefaultTemplate {
+		return reposettings.Reset(ctx, utp.db.DB, "url_templates", key)
+	}
+
+	return reposettings.Set(ctx, utp.db.DB, "url_templates", key, map[string]interface{}{"template": template})
+}
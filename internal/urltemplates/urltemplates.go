@@ -0,0 +1,190 @@
+package urltemplates
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cbroglie/mustache"
+	"github.com/pkg/errors"
+)
+
+// DefaultTemplate renders the `<service>-<owner>-<repo>-<pr or branch>.<domain>` hosts
+// environments had before templates could be customized.
+const DefaultTemplate = "{{service}}-{{owner}}-{{repo}}-{{#pr}}{{pr}}{{/pr}}{{^pr}}{{branch}}{{/pr}}.{{domain}}"
+
+// DefaultDomain is the domain of environments when CLUSTER_DOMAIN is not set outside of the cluster
+const DefaultDomain = "env.ergomake.test"
+
+const (
+	maxLabelLength = 63
+	maxHostLength  = 253
+	hashLength     = 8
+)
+
+var invalidLabelChars = regexp.MustCompile("[^a-z0-9-]")
+
+type URLTemplatesProvider interface {
+	// Get returns DefaultTemplate when the repo has no template of its own
+	Get(ctx context.Context, provider, owner, repo string) (string, error)
+	// Upsert sets the template of the repo, an empty template goes back to DefaultTemplate
+	Upsert(ctx context.Context, provider, owner, repo, template string) error
+}
+
+type Vars struct {
+	Owner   string
+	Repo    string
+	Branch  string
+	PR      *int
+	Service string
+	Domain  string
+}
+
+func (v Vars) context() map[string]string {
+	pr := ""
+	if v.PR != nil {
+		pr = strconv.Itoa(*v.PR)
+	}
+
+	return map[string]string{
+		"owner": sanitizeValue(v.Owner),
+		// underscores are dropped instead of replaced to keep the hosts of DefaultTemplate stable
+		"repo":    sanitizeValue(strings.ReplaceAll(v.Repo, "_", "")),
+		"branch":  sanitizeValue(v.Branch),
+		"pr":      pr,
+		"service": sanitizeValue(v.Service),
+		"domain":  strings.ToLower(v.Domain),
+	}
+}
+
+type Template struct {
+	raw  string
+	tmpl *mustache.Template
+}
+
+func Parse(template string) (*Template, error) {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultTemplate
+	}
+
+	tmpl, err := mustache.ParseStringRaw(template, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to parse url template")
+	}
+
+	return &Template{raw: template, tmpl: tmpl}, nil
+}
+
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Host renders the template into a valid hostname. Every DNS label longer than 63
+// characters is truncated and suffixed with a hash of its full value so that the
+// result stays deterministic and distinct.
+func (t *Template) Host(vars Vars) (string, error) {
+	mustache.AllowMissingVariables = false
+	rendered, err := t.tmpl.Render(vars.context())
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to render url template %s", t.raw)
+	}
+
+	labels := strings.Split(strings.ToLower(strings.TrimSpace(rendered)), ".")
+	for i, label := range labels {
+		label = strings.Trim(invalidLabelChars.ReplaceAllString(label, "-"), "-")
+		if label == "" {
+			return "", errors.Errorf("url template %s renders the empty label of %s", t.raw, rendered)
+		}
+
+		labels[i] = shortenLabel(label)
+	}
+
+	host := strings.Join(labels, ".")
+	if len(host) > maxHostLength {
+		return "", errors.Errorf("url template %s renders %s which is longer than %d characters", t.raw, host, maxHostLength)
+	}
+
+	return host, nil
+}
+
+// Validate checks that the template renders valid hosts and that those hosts are different
+// for every service and every environment of the repository. Hosts are shared by every repository
+// so they must also be under the domain of the cluster and change with the owner and the repository,
+// otherwise a repository could take the hosts another one renders.
+func Validate(template string, owner string, repo string, domain string) error {
+	tmpl, err := Parse(template)
+	if err != nil {
+		return err
+	}
+
+	pr := 1
+	otherPr := 2
+	samples := []Vars{
+		{Owner: owner, Repo: repo, Branch: "main", Service: "web", Domain: domain},
+		{Owner: owner, Repo: repo, Branch: "main", Service: "api", Domain: domain},
+		{Owner: owner, Repo: repo, Branch: "develop", Service: "web", Domain: domain},
+		{Owner: owner, Repo: repo, Branch: "feature", PR: &pr, Service: "web", Domain: domain},
+		{Owner: owner, Repo: repo, Branch: "feature", PR: &otherPr, Service: "web", Domain: domain},
+	}
+
+	hosts := map[string]struct{}{}
+	for _, vars := range samples {
+		host, err := tmpl.Host(vars)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := hosts[host]; ok {
+			return errors.Errorf(
+				"url template %s renders %s for more than one service or environment, "+
+					"it must use {{service}} and either {{pr}} or {{branch}}",
+				tmpl.raw, host,
+			)
+		}
+		hosts[host] = struct{}{}
+
+		if !strings.HasSuffix(host, "."+strings.ToLower(domain)) {
+			return errors.Errorf("url template %s renders %s which is not under %s, it must end with .{{domain}}",
+				tmpl.raw, host, domain)
+		}
+
+		otherOwner := vars
+		otherOwner.Owner = "other-" + owner
+		otherRepo := vars
+		otherRepo.Repo = "other-" + repo
+		for _, other := range []Vars{otherOwner, otherRepo} {
+			otherHost, err := tmpl.Host(other)
+			if err != nil {
+				return err
+			}
+
+			if otherHost == host {
+				return errors.Errorf(
+					"url template %s renders %s for other repositories too, it must use {{owner}} and {{repo}}",
+					tmpl.raw, host,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func sanitizeValue(value string) string {
+	return strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
+
+func shortenLabel(label string) string {
+	if len(label) <= maxLabelLength {
+		return label
+	}
+
+	sum := sha256.Sum256([]byte(label))
+	prefix := strings.TrimRight(label[:maxLabelLength-hashLength-1], "-")
+
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(sum[:])[:hashLength])
+}
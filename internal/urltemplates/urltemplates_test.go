@@ -0,0 +1,130 @@
+package urltemplates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+)
+
+func TestTemplate_Host(t *testing.T) {
+	t.Parallel()
+
+	longRepo := strings.Repeat("a", 80)
+
+	tt := []struct {
+		name     string
+		template string
+		vars     Vars
+		expected string
+		errors   bool
+	}{
+		{
+			name:     "default template uses the pull request",
+			template: "",
+			vars:     Vars{Owner: "Ergomake", Repo: "my_repo", Branch: "feat", PR: pointer.Int(12), Service: "web", Domain: "env.ergomake.test"},
+			expected: "web-ergomake-myrepo-12.env.ergomake.test",
+		},
+		{
+			name:     "default template falls back to the branch",
+			template: DefaultTemplate,
+			vars:     Vars{Owner: "ergomake", Repo: "repo", Branch: "feature/Login", Service: "web", Domain: "env.ergomake.test"},
+			expected: "web-ergomake-repo-feature-login.env.ergomake.test",
+		},
+		{
+			name:     "vanity domain",
+			template: "{{service}}.pr-{{pr}}.preview.acme.com",
+			vars:     Vars{Owner: "acme", Repo: "repo", Branch: "feat", PR: pointer.Int(3), Service: "api", Domain: "env.ergomake.test"},
+			expected: "api.pr-3.preview.acme.com",
+		},
+		{
+			name:     "long labels are shortened with a hash",
+			template: DefaultTemplate,
+			vars:     Vars{Owner: "acme", Repo: longRepo, Branch: "main", Service: "web", Domain: "env.ergomake.test"},
+			expected: "web-acme-" + strings.Repeat("a", 45) + "-b2302c02.env.ergomake.test",
+		},
+		{
+			name:     "unknown variables fail",
+			template: "{{service}}-{{nope}}.acme.com",
+			vars:     Vars{Service: "web"},
+			errors:   true,
+		},
+		{
+			name:     "empty labels fail",
+			template: "{{service}}..acme.com",
+			vars:     Vars{Service: "web"},
+			errors:   true,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := Parse(tc.template)
+			require.NoError(t, err)
+
+			host, err := tmpl.Host(tc.vars)
+			if tc.errors {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, host)
+			for _, label := range strings.Split(host, ".") {
+				assert.LessOrEqual(t, len(label), 63)
+			}
+		})
+	}
+}
+
+func TestTemplate_HostIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := Parse(DefaultTemplate)
+	require.NoError(t, err)
+
+	vars := Vars{Owner: "acme", Repo: strings.Repeat("r", 70), Branch: "main", Service: "web", Domain: "acme.com"}
+	first, err := tmpl.Host(vars)
+	require.NoError(t, err)
+
+	vars.Branch = "develop"
+	other, err := tmpl.Host(vars)
+	require.NoError(t, err)
+
+	vars.Branch = "main"
+	second, err := tmpl.Host(vars)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	domain := "env.ergomake.test"
+	assert.NoError(t, Validate(DefaultTemplate, "acme", "repo", domain))
+	assert.NoError(t, Validate(
+		"{{service}}.{{#pr}}pr-{{pr}}{{/pr}}{{^pr}}{{branch}}{{/pr}}.{{repo}}-{{owner}}.{{domain}}", "acme", "repo", domain))
+
+	// every service would get the same host
+	assert.Error(t, Validate("{{owner}}-{{repo}}-{{branch}}.{{domain}}", "acme", "repo", domain))
+	// branch environments would share hosts
+	assert.Error(t, Validate("{{service}}-{{owner}}-{{repo}}-{{pr}}.{{domain}}", "acme", "repo", domain))
+	assert.Error(t, Validate("{{service", "acme", "repo", domain))
+	assert.Error(t, Validate("{{service}}-{{tag}}.{{domain}}", "acme", "repo", domain))
+
+	// other repositories would render the same hosts
+	assert.Error(t, Validate("{{service}}-{{repo}}-{{branch}}-{{pr}}.{{domain}}", "acme", "repo", domain))
+	assert.Error(t, Validate("{{service}}-{{owner}}-{{branch}}-{{pr}}.{{domain}}", "acme", "repo", domain))
+	assert.Error(t, Validate("{{service}}-{{#pr}}{{owner}}-{{repo}}-{{pr}}{{/pr}}{{^pr}}{{branch}}{{/pr}}.{{domain}}",
+		"acme", "repo", domain))
+	// hosts outside of the cluster domain
+	assert.Error(t, Validate("{{service}}-{{owner}}-{{repo}}-{{branch}}-{{pr}}.acme.com", "acme", "repo", domain))
+	assert.Error(t, Validate("{{service}}-{{owner}}-{{repo}}-{{branch}}-{{pr}}.{{domain}}.acme.com", "acme", "repo", domain))
+}
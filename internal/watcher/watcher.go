@@ -10,7 +10,7 @@ import (
 	"github.com/ergomake/ergomake/internal/database"
 	"github.com/ergomake/ergomake/internal/environments"
 	"github.com/ergomake/ergomake/internal/github/ghapp"
-	"github.com/ergomake/ergomake/internal/github/ghlauncher"
+	"github.com/ergomake/ergomake/internal/launcher"
 	"github.com/ergomake/ergomake/internal/logger"
 )
 
@@ -19,7 +19,7 @@ func WatchEnvironments(
 	db *database.DB,
 	environmentsProvider environments.EnvironmentsProvider,
 	ghApp ghapp.GHAppClient,
-	ghLauncher ghlauncher.GHLauncher,
+	envLauncher launcher.Launcher,
 ) func() {
 	stopCh := make(chan struct{})
 	go func() {
@@ -29,7 +29,11 @@ func WatchEnvironments(
 
 			logger.Ctx(ctx).Info().Msg("checking limited environments")
 			var envs []database.Environment
-			err := db.Table("environments").Find(&envs, map[string]interface{}{"status": database.EnvLimited}).Error
+			// gitlab launches give up when limited instead of leaving environments behind
+			err := db.Table("environments").Find(&envs, map[string]interface{}{
+				"status":   database.EnvLimited,
+				"provider": database.ProviderGitHub,
+			}).Error
 			if err != nil {
 				logger.Get().Err(err).Msg("fail to list limited environments")
 			}
@@ -43,7 +47,7 @@ func WatchEnvironments(
 					continue
 				}
 
-				isLimited, err := environmentsProvider.IsOwnerLimited(ctx, env.Owner)
+				isLimited, err := environmentsProvider.IsOwnerLimited(ctx, database.ProviderGitHub, env.Owner)
 				if err != nil {
 					log.Err(err).Msg("fail to check if owner is limited")
 					continue
@@ -62,6 +66,7 @@ func WatchEnvironments(
 				}
 
 				terminateReq := environments.TerminateEnvironmentRequest{
+					Provider: database.ProviderGitHub,
 					Owner:    env.Owner,
 					Repo:     env.Repo,
 					Branch:   env.Branch.String,
@@ -104,7 +109,8 @@ func WatchEnvironments(
 					log.Err(err).Msg("fail to terminate limited environment for relaunch")
 				}
 
-				launchReq := ghlauncher.LaunchEnvironmentRequest{
+				launchReq := launcher.LaunchEnvironmentRequest{
+					Provider:    database.ProviderGitHub,
 					Owner:       env.Owner,
 					BranchOwner: env.BranchOwner,
 					Repo:        env.Repo,
@@ -115,7 +121,7 @@ func WatchEnvironments(
 					IsPrivate:   isPrivate,
 				}
 				go func() {
-					err := ghLauncher.LaunchEnvironment(context.Background(), launchReq)
+					err := envLauncher.LaunchEnvironment(context.Background(), launchReq)
 					if err != nil {
 						log.Err(err).Interface("launch", launchReq).Msg("fail to launch environment")
 					}
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE service_urls (
+    id UUID DEFAULT uuid_generate_v4() NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    path TEXT NOT NULL DEFAULT '/'
+);
+
+CREATE INDEX service_urls_service_id_idx ON service_urls(service_id);
+CREATE INDEX service_urls_url_idx ON service_urls(url);
+
+-- +migrate Down
+DROP TABLE IF EXISTS service_urls;
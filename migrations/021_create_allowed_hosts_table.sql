@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE allowed_hosts (
+    id UUID DEFAULT uuid_generate_v4() NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    host VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX allowed_hosts_owner_repo_idx ON allowed_hosts(owner, repo);
+
+-- +migrate Down
+DROP TABLE IF EXISTS allowed_hosts;
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE deploy_modes (
+    id UUID DEFAULT uuid_generate_v4() NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    mode VARCHAR(255) NOT NULL,
+    UNIQUE(owner, repo)
+);
+
+ALTER TABLE services ADD COLUMN build_hash VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE services DROP COLUMN IF EXISTS build_hash;
+
+DROP TABLE IF EXISTS deploy_modes;
@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE launch_jobs (
+    id UUID DEFAULT uuid_generate_v4() NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    kind VARCHAR(255) NOT NULL CHECK (kind IN ('launch', 'terminate')),
+    key VARCHAR(255) NOT NULL,
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    branch VARCHAR(255) NOT NULL,
+    pr_number INTEGER NULL,
+    sha VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    status VARCHAR(255) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'superseded')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE NULL,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX launch_jobs_status_run_at_idx ON launch_jobs (status, run_at);
+CREATE INDEX launch_jobs_key_idx ON launch_jobs (key);
+CREATE INDEX launch_jobs_owner_idx ON launch_jobs (owner);
+
+-- +migrate Down
+DROP TABLE IF EXISTS launch_jobs;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE environments ADD COLUMN gh_check_run_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN build_logs TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE services DROP COLUMN IF EXISTS build_logs;
+ALTER TABLE environments DROP COLUMN IF EXISTS gh_check_run_id;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE environments ADD COLUMN gh_deployment_id BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE environments DROP COLUMN IF EXISTS gh_deployment_id;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE environments ADD COLUMN comment_template TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE environments DROP COLUMN IF EXISTS comment_template;
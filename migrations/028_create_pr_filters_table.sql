@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE pr_filters (
+    id UUID DEFAULT uuid_generate_v4() NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    rules JSONB NOT NULL,
+    UNIQUE(owner, repo)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pr_filters;
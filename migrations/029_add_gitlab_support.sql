@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE environments ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github' CHECK (provider in ('github', 'gitlab'));
+ALTER TABLE environments ADD COLUMN gl_note_id BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE gitlab_projects (
+    id UUID DEFAULT uuid_generate_v4() NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    token TEXT NOT NULL,
+    webhook_token TEXT NOT NULL,
+    UNIQUE(owner, repo)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS gitlab_projects;
+ALTER TABLE environments DROP COLUMN IF EXISTS gl_note_id;
+ALTER TABLE environments DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE environments RENAME COLUMN gl_note_id TO provider_comment_id;
+ALTER TABLE environments DROP CONSTRAINT IF EXISTS environments_provider_check;
+ALTER TABLE environments ADD CONSTRAINT environments_provider_check CHECK (provider in ('github', 'gitlab', 'gitea'));
+
+-- +migrate Down
+ALTER TABLE environments DROP CONSTRAINT IF EXISTS environments_provider_check;
+ALTER TABLE environments ADD CONSTRAINT environments_provider_check CHECK (provider in ('github', 'gitlab'));
+ALTER TABLE environments RENAME COLUMN provider_comment_id TO gl_note_id;
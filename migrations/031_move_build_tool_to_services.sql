@@ -0,0 +1,21 @@
+-- +migrate Up
+
+ALTER TABLE services ADD COLUMN build_tool VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE services SET build_tool = environments.build_tool
+FROM environments
+WHERE services.environment_id = environments.id AND services.build <> '';
+
+ALTER TABLE environments DROP COLUMN build_tool;
+
+-- +migrate Down
+
+ALTER TABLE environments ADD COLUMN build_tool VARCHAR(255) NOT NULL DEFAULT 'kaniko';
+
+UPDATE environments SET build_tool = 'buildpacks'
+WHERE EXISTS (
+    SELECT 1 FROM services
+    WHERE services.environment_id = environments.id AND services.build_tool = 'buildpacks'
+);
+
+ALTER TABLE services DROP COLUMN build_tool;
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+ALTER TABLE env_vars ADD COLUMN build_secret BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE env_vars ADD COLUMN allow_build_arg BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+
+ALTER TABLE env_vars DROP COLUMN allow_build_arg;
+ALTER TABLE env_vars DROP COLUMN build_secret;
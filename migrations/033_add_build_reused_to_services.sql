@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE services ADD COLUMN build_reused BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+
+ALTER TABLE services DROP COLUMN build_reused;
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE services ADD COLUMN job BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+
+ALTER TABLE services DROP COLUMN job;
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE services ADD COLUMN build_settings_hash TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE services DROP COLUMN build_settings_hash;
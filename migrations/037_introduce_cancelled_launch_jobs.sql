@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE launch_jobs DROP CONSTRAINT launch_jobs_status_check;
+
+ALTER TABLE launch_jobs
+ADD CONSTRAINT launch_jobs_status_check
+CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'superseded', 'cancelled'));
+
+-- +migrate Down
+UPDATE launch_jobs SET status = 'failed' WHERE status = 'cancelled';
+
+ALTER TABLE launch_jobs DROP CONSTRAINT launch_jobs_status_check;
+
+ALTER TABLE launch_jobs
+ADD CONSTRAINT launch_jobs_status_check
+CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'superseded'));
@@ -0,0 +1,79 @@
+-- +migrate Up
+ALTER TABLE env_vars ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+ALTER TABLE env_vars DROP CONSTRAINT env_vars_owner_repo_name_key;
+ALTER TABLE env_vars ADD CONSTRAINT env_vars_provider_owner_repo_name_key UNIQUE (provider, owner, repo, name, branch);
+
+-- provider already tells the kind of registry
+ALTER TABLE private_registries ADD COLUMN git_provider VARCHAR(255) NOT NULL DEFAULT 'github';
+
+ALTER TABLE stripe_subscriptions ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+ALTER TABLE stripe_subscriptions DROP CONSTRAINT stripe_subscriptions_owner_subscription_id_key;
+ALTER TABLE stripe_subscriptions ADD CONSTRAINT stripe_subscriptions_provider_owner_subscription_id_key UNIQUE (provider, owner, subscription_id);
+
+ALTER TABLE url_templates ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+ALTER TABLE url_templates DROP CONSTRAINT url_templates_owner_repo_key;
+ALTER TABLE url_templates ADD CONSTRAINT url_templates_provider_owner_repo_key UNIQUE (provider, owner, repo);
+
+ALTER TABLE allowed_hosts ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+DROP INDEX allowed_hosts_owner_repo_idx;
+CREATE INDEX allowed_hosts_provider_owner_repo_idx ON allowed_hosts(provider, owner, repo);
+
+ALTER TABLE deploy_modes ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+ALTER TABLE deploy_modes DROP CONSTRAINT deploy_modes_owner_repo_key;
+ALTER TABLE deploy_modes ADD CONSTRAINT deploy_modes_provider_owner_repo_key UNIQUE (provider, owner, repo);
+
+ALTER TABLE pr_filters ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+ALTER TABLE pr_filters DROP CONSTRAINT pr_filters_owner_repo_key;
+ALTER TABLE pr_filters ADD CONSTRAINT pr_filters_provider_owner_repo_key UNIQUE (provider, owner, repo);
+
+-- only gitlab projects are registered, the column lets them share the lookups of the other settings
+ALTER TABLE gitlab_projects ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'gitlab';
+ALTER TABLE gitlab_projects DROP CONSTRAINT gitlab_projects_owner_repo_key;
+ALTER TABLE gitlab_projects ADD CONSTRAINT gitlab_projects_provider_owner_repo_key UNIQUE (provider, owner, repo);
+
+ALTER TABLE environment_limits ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+ALTER TABLE environment_limits DROP CONSTRAINT environment_limits_owner_key;
+ALTER TABLE environment_limits ADD CONSTRAINT environment_limits_provider_owner_key UNIQUE (provider, owner);
+
+-- +migrate Down
+DELETE FROM environment_limits WHERE provider <> 'github';
+ALTER TABLE environment_limits DROP CONSTRAINT environment_limits_provider_owner_key;
+ALTER TABLE environment_limits ADD CONSTRAINT environment_limits_owner_key UNIQUE (owner);
+ALTER TABLE environment_limits DROP COLUMN provider;
+
+ALTER TABLE gitlab_projects DROP CONSTRAINT gitlab_projects_provider_owner_repo_key;
+ALTER TABLE gitlab_projects ADD CONSTRAINT gitlab_projects_owner_repo_key UNIQUE (owner, repo);
+ALTER TABLE gitlab_projects DROP COLUMN provider;
+
+DELETE FROM pr_filters WHERE provider <> 'github';
+ALTER TABLE pr_filters DROP CONSTRAINT pr_filters_provider_owner_repo_key;
+ALTER TABLE pr_filters ADD CONSTRAINT pr_filters_owner_repo_key UNIQUE (owner, repo);
+ALTER TABLE pr_filters DROP COLUMN provider;
+
+DELETE FROM deploy_modes WHERE provider <> 'github';
+ALTER TABLE deploy_modes DROP CONSTRAINT deploy_modes_provider_owner_repo_key;
+ALTER TABLE deploy_modes ADD CONSTRAINT deploy_modes_owner_repo_key UNIQUE (owner, repo);
+ALTER TABLE deploy_modes DROP COLUMN provider;
+
+DELETE FROM allowed_hosts WHERE provider <> 'github';
+DROP INDEX allowed_hosts_provider_owner_repo_idx;
+CREATE INDEX allowed_hosts_owner_repo_idx ON allowed_hosts(owner, repo);
+ALTER TABLE allowed_hosts DROP COLUMN provider;
+
+DELETE FROM url_templates WHERE provider <> 'github';
+ALTER TABLE url_templates DROP CONSTRAINT url_templates_provider_owner_repo_key;
+ALTER TABLE url_templates ADD CONSTRAINT url_templates_owner_repo_key UNIQUE (owner, repo);
+ALTER TABLE url_templates DROP COLUMN provider;
+
+DELETE FROM stripe_subscriptions WHERE provider <> 'github';
+ALTER TABLE stripe_subscriptions DROP CONSTRAINT stripe_subscriptions_provider_owner_subscription_id_key;
+ALTER TABLE stripe_subscriptions ADD CONSTRAINT stripe_subscriptions_owner_subscription_id_key UNIQUE (owner, subscription_id);
+ALTER TABLE stripe_subscriptions DROP COLUMN provider;
+
+DELETE FROM private_registries WHERE git_provider <> 'github';
+ALTER TABLE private_registries DROP COLUMN git_provider;
+
+DELETE FROM env_vars WHERE provider <> 'github';
+ALTER TABLE env_vars DROP CONSTRAINT env_vars_provider_owner_repo_name_key;
+ALTER TABLE env_vars ADD CONSTRAINT env_vars_owner_repo_name_key UNIQUE (owner, repo, name, branch);
+ALTER TABLE env_vars DROP COLUMN provider;
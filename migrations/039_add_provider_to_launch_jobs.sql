@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE launch_jobs ADD COLUMN provider VARCHAR(255) NOT NULL DEFAULT 'github';
+
+DROP INDEX IF EXISTS launch_jobs_owner_idx;
+CREATE INDEX launch_jobs_owner_idx ON launch_jobs (provider, owner);
+
+-- +migrate Down
+DELETE FROM launch_jobs WHERE provider != 'github';
+
+DROP INDEX IF EXISTS launch_jobs_owner_idx;
+CREATE INDEX launch_jobs_owner_idx ON launch_jobs (owner);
+
+ALTER TABLE launch_jobs DROP COLUMN provider;
@@ -0,0 +1,140 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AllowedHostsProvider is an autogenerated mock type for the AllowedHostsProvider type
+type AllowedHostsProvider struct {
+	mock.Mock
+}
+
+type AllowedHostsProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AllowedHostsProvider) EXPECT() *AllowedHostsProvider_Expecter {
+	return &AllowedHostsProvider_Expecter{mock: &_m.Mock}
+}
+
+// List provides a mock function with given fields: ctx, provider, owner, repo
+func (_m *AllowedHostsProvider) List(ctx context.Context, provider string, owner string, repo string) ([]string, error) {
+	ret := _m.Called(ctx, provider, owner, repo)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]string, error)); ok {
+		return rf(ctx, provider, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []string); ok {
+		r0 = rf(ctx, provider, owner, repo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AllowedHostsProvider_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type AllowedHostsProvider_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+func (_e *AllowedHostsProvider_Expecter) List(ctx interface{}, provider interface{}, owner interface{}, repo interface{}) *AllowedHostsProvider_List_Call {
+	return &AllowedHostsProvider_List_Call{Call: _e.mock.On("List", ctx, provider, owner, repo)}
+}
+
+func (_c *AllowedHostsProvider_List_Call) Run(run func(ctx context.Context, provider string, owner string, repo string)) *AllowedHostsProvider_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *AllowedHostsProvider_List_Call) Return(_a0 []string, _a1 error) *AllowedHostsProvider_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AllowedHostsProvider_List_Call) RunAndReturn(run func(context.Context, string, string, string) ([]string, error)) *AllowedHostsProvider_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replace provides a mock function with given fields: ctx, provider, owner, repo, hosts
+func (_m *AllowedHostsProvider) Replace(ctx context.Context, provider string, owner string, repo string, hosts []string) error {
+	ret := _m.Called(ctx, provider, owner, repo, hosts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string) error); ok {
+		r0 = rf(ctx, provider, owner, repo, hosts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AllowedHostsProvider_Replace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replace'
+type AllowedHostsProvider_Replace_Call struct {
+	*mock.Call
+}
+
+// Replace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+//   - hosts []string
+func (_e *AllowedHostsProvider_Expecter) Replace(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, hosts interface{}) *AllowedHostsProvider_Replace_Call {
+	return &AllowedHostsProvider_Replace_Call{Call: _e.mock.On("Replace", ctx, provider, owner, repo, hosts)}
+}
+
+func (_c *AllowedHostsProvider_Replace_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, hosts []string)) *AllowedHostsProvider_Replace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].([]string))
+	})
+	return _c
+}
+
+func (_c *AllowedHostsProvider_Replace_Call) Return(_a0 error) *AllowedHostsProvider_Replace_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AllowedHostsProvider_Replace_Call) RunAndReturn(run func(context.Context, string, string, string, []string) error) *AllowedHostsProvider_Replace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewAllowedHostsProvider interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAllowedHostsProvider creates a new instance of AllowedHostsProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAllowedHostsProvider(t mockConstructorTestingTNewAllowedHostsProvider) *AllowedHostsProvider {
+	mock := &AllowedHostsProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -16,6 +16,8 @@ import (
 
 	networkingv1 "k8s.io/api/networking/v1"
 
+	runtime "k8s.io/apimachinery/pkg/runtime"
+
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 
 	v1 "k8s.io/api/core/v1"
@@ -79,6 +81,49 @@ func (_c *Client_ApplyKPackBuilds_Call) RunAndReturn(run func(context.Context, [
 	return _c
 }
 
+// ApplyObject provides a mock function with given fields: ctx, obj
+func (_m *Client) ApplyObject(ctx context.Context, obj runtime.Object) error {
+	ret := _m.Called(ctx, obj)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, runtime.Object) error); ok {
+		r0 = rf(ctx, obj)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_ApplyObject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyObject'
+type Client_ApplyObject_Call struct {
+	*mock.Call
+}
+
+// ApplyObject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - obj runtime.Object
+func (_e *Client_Expecter) ApplyObject(ctx interface{}, obj interface{}) *Client_ApplyObject_Call {
+	return &Client_ApplyObject_Call{Call: _e.mock.On("ApplyObject", ctx, obj)}
+}
+
+func (_c *Client_ApplyObject_Call) Run(run func(ctx context.Context, obj runtime.Object)) *Client_ApplyObject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(runtime.Object))
+	})
+	return _c
+}
+
+func (_c *Client_ApplyObject_Call) Return(_a0 error) *Client_ApplyObject_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_ApplyObject_Call) RunAndReturn(run func(context.Context, runtime.Object) error) *Client_ApplyObject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AreServicesAlive provides a mock function with given fields: ctx, namespace
 func (_m *Client) AreServicesAlive(ctx context.Context, namespace string) (bool, error) {
 	ret := _m.Called(ctx, namespace)
@@ -189,49 +234,6 @@ func (_c *Client_CopySecret_Call) RunAndReturn(run func(context.Context, string,
 	return _c
 }
 
-// CreateConfigMap provides a mock function with given fields: ctx, configMap
-func (_m *Client) CreateConfigMap(ctx context.Context, configMap *v1.ConfigMap) error {
-	ret := _m.Called(ctx, configMap)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *v1.ConfigMap) error); ok {
-		r0 = rf(ctx, configMap)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// Client_CreateConfigMap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateConfigMap'
-type Client_CreateConfigMap_Call struct {
-	*mock.Call
-}
-
-// CreateConfigMap is a helper method to define mock.On call
-//   - ctx context.Context
-//   - configMap *v1.ConfigMap
-func (_e *Client_Expecter) CreateConfigMap(ctx interface{}, configMap interface{}) *Client_CreateConfigMap_Call {
-	return &Client_CreateConfigMap_Call{Call: _e.mock.On("CreateConfigMap", ctx, configMap)}
-}
-
-func (_c *Client_CreateConfigMap_Call) Run(run func(ctx context.Context, configMap *v1.ConfigMap)) *Client_CreateConfigMap_Call {
-	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*v1.ConfigMap))
-	})
-	return _c
-}
-
-func (_c *Client_CreateConfigMap_Call) Return(_a0 error) *Client_CreateConfigMap_Call {
-	_c.Call.Return(_a0)
-	return _c
-}
-
-func (_c *Client_CreateConfigMap_Call) RunAndReturn(run func(context.Context, *v1.ConfigMap) error) *Client_CreateConfigMap_Call {
-	_c.Call.Return(run)
-	return _c
-}
-
 // CreateDeployment provides a mock function with given fields: ctx, deployment
 func (_m *Client) CreateDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
 	ret := _m.Called(ctx, deployment)
@@ -275,49 +277,6 @@ func (_c *Client_CreateDeployment_Call) RunAndReturn(run func(context.Context, *
 	return _c
 }
 
-// CreateIngress provides a mock function with given fields: ctx, ingress
-func (_m *Client) CreateIngress(ctx context.Context, ingress *networkingv1.Ingress) error {
-	ret := _m.Called(ctx, ingress)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *networkingv1.Ingress) error); ok {
-		r0 = rf(ctx, ingress)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// Client_CreateIngress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIngress'
-type Client_CreateIngress_Call struct {
-	*mock.Call
-}
-
-// CreateIngress is a helper method to define mock.On call
-//   - ctx context.Context
-//   - ingress *networkingv1.Ingress
-func (_e *Client_Expecter) CreateIngress(ctx interface{}, ingress interface{}) *Client_CreateIngress_Call {
-	return &Client_CreateIngress_Call{Call: _e.mock.On("CreateIngress", ctx, ingress)}
-}
-
-func (_c *Client_CreateIngress_Call) Run(run func(ctx context.Context, ingress *networkingv1.Ingress)) *Client_CreateIngress_Call {
-	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*networkingv1.Ingress))
-	})
-	return _c
-}
-
-func (_c *Client_CreateIngress_Call) Return(_a0 error) *Client_CreateIngress_Call {
-	_c.Call.Return(_a0)
-	return _c
-}
-
-func (_c *Client_CreateIngress_Call) RunAndReturn(run func(context.Context, *networkingv1.Ingress) error) *Client_CreateIngress_Call {
-	_c.Call.Return(run)
-	return _c
-}
-
 // CreateJob provides a mock function with given fields: ctx, job
 func (_m *Client) CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
 	ret := _m.Called(ctx, job)
@@ -459,13 +418,13 @@ func (_c *Client_CreateSecret_Call) RunAndReturn(run func(context.Context, *v1.S
 	return _c
 }
 
-// CreateService provides a mock function with given fields: ctx, service
-func (_m *Client) CreateService(ctx context.Context, service *v1.Service) error {
-	ret := _m.Called(ctx, service)
+// CreateServiceAccount provides a mock function with given fields: ctx, svcAcc
+func (_m *Client) CreateServiceAccount(ctx context.Context, svcAcc *v1.ServiceAccount) error {
+	ret := _m.Called(ctx, svcAcc)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *v1.Service) error); ok {
-		r0 = rf(ctx, service)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount) error); ok {
+		r0 = rf(ctx, svcAcc)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -473,42 +432,42 @@ func (_m *Client) CreateService(ctx context.Context, service *v1.Service) error
 	return r0
 }
 
-// Client_CreateService_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateService'
-type Client_CreateService_Call struct {
+// Client_CreateServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateServiceAccount'
+type Client_CreateServiceAccount_Call struct {
 	*mock.Call
 }
 
-// CreateService is a helper method to define mock.On call
+// CreateServiceAccount is a helper method to define mock.On call
 //   - ctx context.Context
-//   - service *v1.Service
-func (_e *Client_Expecter) CreateService(ctx interface{}, service interface{}) *Client_CreateService_Call {
-	return &Client_CreateService_Call{Call: _e.mock.On("CreateService", ctx, service)}
+//   - svcAcc *v1.ServiceAccount
+func (_e *Client_Expecter) CreateServiceAccount(ctx interface{}, svcAcc interface{}) *Client_CreateServiceAccount_Call {
+	return &Client_CreateServiceAccount_Call{Call: _e.mock.On("CreateServiceAccount", ctx, svcAcc)}
 }
 
-func (_c *Client_CreateService_Call) Run(run func(ctx context.Context, service *v1.Service)) *Client_CreateService_Call {
+func (_c *Client_CreateServiceAccount_Call) Run(run func(ctx context.Context, svcAcc *v1.ServiceAccount)) *Client_CreateServiceAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*v1.Service))
+		run(args[0].(context.Context), args[1].(*v1.ServiceAccount))
 	})
 	return _c
 }
 
-func (_c *Client_CreateService_Call) Return(_a0 error) *Client_CreateService_Call {
+func (_c *Client_CreateServiceAccount_Call) Return(_a0 error) *Client_CreateServiceAccount_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Client_CreateService_Call) RunAndReturn(run func(context.Context, *v1.Service) error) *Client_CreateService_Call {
+func (_c *Client_CreateServiceAccount_Call) RunAndReturn(run func(context.Context, *v1.ServiceAccount) error) *Client_CreateServiceAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateServiceAccount provides a mock function with given fields: ctx, svcAcc
-func (_m *Client) CreateServiceAccount(ctx context.Context, svcAcc *v1.ServiceAccount) error {
-	ret := _m.Called(ctx, svcAcc)
+// DeleteCollection provides a mock function with given fields: ctx, gvr, namespace, labelSelector
+func (_m *Client) DeleteCollection(ctx context.Context, gvr schema.GroupVersionResource, namespace string, labelSelector string) error {
+	ret := _m.Called(ctx, gvr, namespace, labelSelector)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *v1.ServiceAccount) error); ok {
-		r0 = rf(ctx, svcAcc)
+	if rf, ok := ret.Get(0).(func(context.Context, schema.GroupVersionResource, string, string) error); ok {
+		r0 = rf(ctx, gvr, namespace, labelSelector)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -516,31 +475,33 @@ func (_m *Client) CreateServiceAccount(ctx context.Context, svcAcc *v1.ServiceAc
 	return r0
 }
 
-// Client_CreateServiceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateServiceAccount'
-type Client_CreateServiceAccount_Call struct {
+// Client_DeleteCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCollection'
+type Client_DeleteCollection_Call struct {
 	*mock.Call
 }
 
-// CreateServiceAccount is a helper method to define mock.On call
+// DeleteCollection is a helper method to define mock.On call
 //   - ctx context.Context
-//   - svcAcc *v1.ServiceAccount
-func (_e *Client_Expecter) CreateServiceAccount(ctx interface{}, svcAcc interface{}) *Client_CreateServiceAccount_Call {
-	return &Client_CreateServiceAccount_Call{Call: _e.mock.On("CreateServiceAccount", ctx, svcAcc)}
+//   - gvr schema.GroupVersionResource
+//   - namespace string
+//   - labelSelector string
+func (_e *Client_Expecter) DeleteCollection(ctx interface{}, gvr interface{}, namespace interface{}, labelSelector interface{}) *Client_DeleteCollection_Call {
+	return &Client_DeleteCollection_Call{Call: _e.mock.On("DeleteCollection", ctx, gvr, namespace, labelSelector)}
 }
 
-func (_c *Client_CreateServiceAccount_Call) Run(run func(ctx context.Context, svcAcc *v1.ServiceAccount)) *Client_CreateServiceAccount_Call {
+func (_c *Client_DeleteCollection_Call) Run(run func(ctx context.Context, gvr schema.GroupVersionResource, namespace string, labelSelector string)) *Client_DeleteCollection_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*v1.ServiceAccount))
+		run(args[0].(context.Context), args[1].(schema.GroupVersionResource), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *Client_CreateServiceAccount_Call) Return(_a0 error) *Client_CreateServiceAccount_Call {
+func (_c *Client_DeleteCollection_Call) Return(_a0 error) *Client_DeleteCollection_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Client_CreateServiceAccount_Call) RunAndReturn(run func(context.Context, *v1.ServiceAccount) error) *Client_CreateServiceAccount_Call {
+func (_c *Client_DeleteCollection_Call) RunAndReturn(run func(context.Context, schema.GroupVersionResource, string, string) error) *Client_DeleteCollection_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -588,6 +549,59 @@ func (_c *Client_DeleteNamespace_Call) RunAndReturn(run func(context.Context, st
 	return _c
 }
 
+// EnsureNamespace provides a mock function with given fields: ctx, namespace
+func (_m *Client) EnsureNamespace(ctx context.Context, namespace string) (bool, error) {
+	ret := _m.Called(ctx, namespace)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_EnsureNamespace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnsureNamespace'
+type Client_EnsureNamespace_Call struct {
+	*mock.Call
+}
+
+// EnsureNamespace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+func (_e *Client_Expecter) EnsureNamespace(ctx interface{}, namespace interface{}) *Client_EnsureNamespace_Call {
+	return &Client_EnsureNamespace_Call{Call: _e.mock.On("EnsureNamespace", ctx, namespace)}
+}
+
+func (_c *Client_EnsureNamespace_Call) Run(run func(ctx context.Context, namespace string)) *Client_EnsureNamespace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_EnsureNamespace_Call) Return(_a0 bool, _a1 error) *Client_EnsureNamespace_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_EnsureNamespace_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *Client_EnsureNamespace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetDeployment provides a mock function with given fields: ctx, namespace, deploymentName
 func (_m *Client) GetDeployment(ctx context.Context, namespace string, deploymentName string) (*appsv1.Deployment, error) {
 	ret := _m.Called(ctx, namespace, deploymentName)
@@ -863,6 +877,61 @@ func (_c *Client_GetPreviewNamespaces_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// GetServiceLogs provides a mock function with given fields: ctx, namespace, name, size
+func (_m *Client) GetServiceLogs(ctx context.Context, namespace string, name string, size int64) (string, error) {
+	ret := _m.Called(ctx, namespace, name, size)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) (string, error)); ok {
+		return rf(ctx, namespace, name, size)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) string); ok {
+		r0 = rf(ctx, namespace, name, size)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) error); ok {
+		r1 = rf(ctx, namespace, name, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetServiceLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetServiceLogs'
+type Client_GetServiceLogs_Call struct {
+	*mock.Call
+}
+
+// GetServiceLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - name string
+//   - size int64
+func (_e *Client_Expecter) GetServiceLogs(ctx interface{}, namespace interface{}, name interface{}, size interface{}) *Client_GetServiceLogs_Call {
+	return &Client_GetServiceLogs_Call{Call: _e.mock.On("GetServiceLogs", ctx, namespace, name, size)}
+}
+
+func (_c *Client_GetServiceLogs_Call) Run(run func(ctx context.Context, namespace string, name string, size int64)) *Client_GetServiceLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *Client_GetServiceLogs_Call) Return(_a0 string, _a1 error) *Client_GetServiceLogs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetServiceLogs_Call) RunAndReturn(run func(context.Context, string, string, int64) (string, error)) *Client_GetServiceLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListJobs provides a mock function with given fields: ctx, namespace
 func (_m *Client) ListJobs(ctx context.Context, namespace string) ([]*batchv1.Job, error) {
 	ret := _m.Called(ctx, namespace)
@@ -918,6 +987,61 @@ func (_c *Client_ListJobs_Call) RunAndReturn(run func(context.Context, string) (
 	return _c
 }
 
+// ResumeJob provides a mock function with given fields: ctx, job
+func (_m *Client) ResumeJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	ret := _m.Called(ctx, job)
+
+	var r0 *batchv1.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *batchv1.Job) (*batchv1.Job, error)); ok {
+		return rf(ctx, job)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *batchv1.Job) *batchv1.Job); ok {
+		r0 = rf(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*batchv1.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *batchv1.Job) error); ok {
+		r1 = rf(ctx, job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ResumeJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResumeJob'
+type Client_ResumeJob_Call struct {
+	*mock.Call
+}
+
+// ResumeJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *batchv1.Job
+func (_e *Client_Expecter) ResumeJob(ctx interface{}, job interface{}) *Client_ResumeJob_Call {
+	return &Client_ResumeJob_Call{Call: _e.mock.On("ResumeJob", ctx, job)}
+}
+
+func (_c *Client_ResumeJob_Call) Run(run func(ctx context.Context, job *batchv1.Job)) *Client_ResumeJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*batchv1.Job))
+	})
+	return _c
+}
+
+func (_c *Client_ResumeJob_Call) Return(_a0 *batchv1.Job, _a1 error) *Client_ResumeJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ResumeJob_Call) RunAndReturn(run func(context.Context, *batchv1.Job) (*batchv1.Job, error)) *Client_ResumeJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ScaleDeployment provides a mock function with given fields: ctx, namespace, deploymentName, replicas
 func (_m *Client) ScaleDeployment(ctx context.Context, namespace string, deploymentName string, replicas int32) error {
 	ret := _m.Called(ctx, namespace, deploymentName, replicas)
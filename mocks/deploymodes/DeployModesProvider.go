@@ -0,0 +1,138 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeployModesProvider is an autogenerated mock type for the DeployModesProvider type
+type DeployModesProvider struct {
+	mock.Mock
+}
+
+type DeployModesProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeployModesProvider) EXPECT() *DeployModesProvider_Expecter {
+	return &DeployModesProvider_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, provider, owner, repo
+func (_m *DeployModesProvider) Get(ctx context.Context, provider string, owner string, repo string) (string, error) {
+	ret := _m.Called(ctx, provider, owner, repo)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, provider, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, provider, owner, repo)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeployModesProvider_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type DeployModesProvider_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+func (_e *DeployModesProvider_Expecter) Get(ctx interface{}, provider interface{}, owner interface{}, repo interface{}) *DeployModesProvider_Get_Call {
+	return &DeployModesProvider_Get_Call{Call: _e.mock.On("Get", ctx, provider, owner, repo)}
+}
+
+func (_c *DeployModesProvider_Get_Call) Run(run func(ctx context.Context, provider string, owner string, repo string)) *DeployModesProvider_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *DeployModesProvider_Get_Call) Return(_a0 string, _a1 error) *DeployModesProvider_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeployModesProvider_Get_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *DeployModesProvider_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Upsert provides a mock function with given fields: ctx, provider, owner, repo, mode
+func (_m *DeployModesProvider) Upsert(ctx context.Context, provider string, owner string, repo string, mode string) error {
+	ret := _m.Called(ctx, provider, owner, repo, mode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, provider, owner, repo, mode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeployModesProvider_Upsert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Upsert'
+type DeployModesProvider_Upsert_Call struct {
+	*mock.Call
+}
+
+// Upsert is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+//   - mode string
+func (_e *DeployModesProvider_Expecter) Upsert(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, mode interface{}) *DeployModesProvider_Upsert_Call {
+	return &DeployModesProvider_Upsert_Call{Call: _e.mock.On("Upsert", ctx, provider, owner, repo, mode)}
+}
+
+func (_c *DeployModesProvider_Upsert_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, mode string)) *DeployModesProvider_Upsert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *DeployModesProvider_Upsert_Call) Return(_a0 error) *DeployModesProvider_Upsert_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeployModesProvider_Upsert_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *DeployModesProvider_Upsert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewDeployModesProvider interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewDeployModesProvider creates a new instance of DeployModesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewDeployModesProvider(t mockConstructorTestingTNewDeployModesProvider) *DeployModesProvider {
+	mock := &DeployModesProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -124,23 +124,23 @@ func (_c *EnvironmentsProvider_GetEnvironmentFromHost_Call) RunAndReturn(run fun
 	return _c
 }
 
-// IsOwnerLimited provides a mock function with given fields: ctx, owner
-func (_m *EnvironmentsProvider) IsOwnerLimited(ctx context.Context, owner string) (bool, error) {
-	ret := _m.Called(ctx, owner)
+// IsOwnerLimited provides a mock function with given fields: ctx, provider, owner
+func (_m *EnvironmentsProvider) IsOwnerLimited(ctx context.Context, provider string, owner string) (bool, error) {
+	ret := _m.Called(ctx, provider, owner)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, owner)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, provider, owner)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, owner)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, provider, owner)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, owner)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, owner)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -155,14 +155,15 @@ type EnvironmentsProvider_IsOwnerLimited_Call struct {
 
 // IsOwnerLimited is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
-func (_e *EnvironmentsProvider_Expecter) IsOwnerLimited(ctx interface{}, owner interface{}) *EnvironmentsProvider_IsOwnerLimited_Call {
-	return &EnvironmentsProvider_IsOwnerLimited_Call{Call: _e.mock.On("IsOwnerLimited", ctx, owner)}
+func (_e *EnvironmentsProvider_Expecter) IsOwnerLimited(ctx interface{}, provider interface{}, owner interface{}) *EnvironmentsProvider_IsOwnerLimited_Call {
+	return &EnvironmentsProvider_IsOwnerLimited_Call{Call: _e.mock.On("IsOwnerLimited", ctx, provider, owner)}
 }
 
-func (_c *EnvironmentsProvider_IsOwnerLimited_Call) Run(run func(ctx context.Context, owner string)) *EnvironmentsProvider_IsOwnerLimited_Call {
+func (_c *EnvironmentsProvider_IsOwnerLimited_Call) Run(run func(ctx context.Context, provider string, owner string)) *EnvironmentsProvider_IsOwnerLimited_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -172,30 +173,30 @@ func (_c *EnvironmentsProvider_IsOwnerLimited_Call) Return(_a0 bool, _a1 error)
 	return _c
 }
 
-func (_c *EnvironmentsProvider_IsOwnerLimited_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *EnvironmentsProvider_IsOwnerLimited_Call {
+func (_c *EnvironmentsProvider_IsOwnerLimited_Call) RunAndReturn(run func(context.Context, string, string) (bool, error)) *EnvironmentsProvider_IsOwnerLimited_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListEnvironmentsByBranch provides a mock function with given fields: ctx, owner, repo, branch
-func (_m *EnvironmentsProvider) ListEnvironmentsByBranch(ctx context.Context, owner string, repo string, branch string) ([]*database.Environment, error) {
-	ret := _m.Called(ctx, owner, repo, branch)
+// ListEnvironmentsByBranch provides a mock function with given fields: ctx, provider, owner, repo, branch
+func (_m *EnvironmentsProvider) ListEnvironmentsByBranch(ctx context.Context, provider string, owner string, repo string, branch string) ([]*database.Environment, error) {
+	ret := _m.Called(ctx, provider, owner, repo, branch)
 
 	var r0 []*database.Environment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]*database.Environment, error)); ok {
-		return rf(ctx, owner, repo, branch)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]*database.Environment, error)); ok {
+		return rf(ctx, provider, owner, repo, branch)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []*database.Environment); ok {
-		r0 = rf(ctx, owner, repo, branch)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []*database.Environment); ok {
+		r0 = rf(ctx, provider, owner, repo, branch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*database.Environment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, owner, repo, branch)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo, branch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -210,16 +211,17 @@ type EnvironmentsProvider_ListEnvironmentsByBranch_Call struct {
 
 // ListEnvironmentsByBranch is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
 //   - repo string
 //   - branch string
-func (_e *EnvironmentsProvider_Expecter) ListEnvironmentsByBranch(ctx interface{}, owner interface{}, repo interface{}, branch interface{}) *EnvironmentsProvider_ListEnvironmentsByBranch_Call {
-	return &EnvironmentsProvider_ListEnvironmentsByBranch_Call{Call: _e.mock.On("ListEnvironmentsByBranch", ctx, owner, repo, branch)}
+func (_e *EnvironmentsProvider_Expecter) ListEnvironmentsByBranch(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, branch interface{}) *EnvironmentsProvider_ListEnvironmentsByBranch_Call {
+	return &EnvironmentsProvider_ListEnvironmentsByBranch_Call{Call: _e.mock.On("ListEnvironmentsByBranch", ctx, provider, owner, repo, branch)}
 }
 
-func (_c *EnvironmentsProvider_ListEnvironmentsByBranch_Call) Run(run func(ctx context.Context, owner string, repo string, branch string)) *EnvironmentsProvider_ListEnvironmentsByBranch_Call {
+func (_c *EnvironmentsProvider_ListEnvironmentsByBranch_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, branch string)) *EnvironmentsProvider_ListEnvironmentsByBranch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
 	})
 	return _c
 }
@@ -229,7 +231,7 @@ func (_c *EnvironmentsProvider_ListEnvironmentsByBranch_Call) Return(_a0 []*data
 	return _c
 }
 
-func (_c *EnvironmentsProvider_ListEnvironmentsByBranch_Call) RunAndReturn(run func(context.Context, string, string, string) ([]*database.Environment, error)) *EnvironmentsProvider_ListEnvironmentsByBranch_Call {
+func (_c *EnvironmentsProvider_ListEnvironmentsByBranch_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]*database.Environment, error)) *EnvironmentsProvider_ListEnvironmentsByBranch_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -22,13 +22,13 @@ func (_m *EnvVarsProvider) EXPECT() *EnvVarsProvider_Expecter {
 	return &EnvVarsProvider_Expecter{mock: &_m.Mock}
 }
 
-// Delete provides a mock function with given fields: ctx, owner, repo, name, branch
-func (_m *EnvVarsProvider) Delete(ctx context.Context, owner string, repo string, name string, branch *string) error {
-	ret := _m.Called(ctx, owner, repo, name, branch)
+// Delete provides a mock function with given fields: ctx, provider, owner, repo, name, branch
+func (_m *EnvVarsProvider) Delete(ctx context.Context, provider string, owner string, repo string, name string, branch *string) error {
+	ret := _m.Called(ctx, provider, owner, repo, name, branch)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *string) error); ok {
-		r0 = rf(ctx, owner, repo, name, branch)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *string) error); ok {
+		r0 = rf(ctx, provider, owner, repo, name, branch)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -43,17 +43,18 @@ type EnvVarsProvider_Delete_Call struct {
 
 // Delete is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
 //   - repo string
 //   - name string
 //   - branch *string
-func (_e *EnvVarsProvider_Expecter) Delete(ctx interface{}, owner interface{}, repo interface{}, name interface{}, branch interface{}) *EnvVarsProvider_Delete_Call {
-	return &EnvVarsProvider_Delete_Call{Call: _e.mock.On("Delete", ctx, owner, repo, name, branch)}
+func (_e *EnvVarsProvider_Expecter) Delete(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, name interface{}, branch interface{}) *EnvVarsProvider_Delete_Call {
+	return &EnvVarsProvider_Delete_Call{Call: _e.mock.On("Delete", ctx, provider, owner, repo, name, branch)}
 }
 
-func (_c *EnvVarsProvider_Delete_Call) Run(run func(ctx context.Context, owner string, repo string, name string, branch *string)) *EnvVarsProvider_Delete_Call {
+func (_c *EnvVarsProvider_Delete_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, name string, branch *string)) *EnvVarsProvider_Delete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(*string))
 	})
 	return _c
 }
@@ -63,30 +64,30 @@ func (_c *EnvVarsProvider_Delete_Call) Return(_a0 error) *EnvVarsProvider_Delete
 	return _c
 }
 
-func (_c *EnvVarsProvider_Delete_Call) RunAndReturn(run func(context.Context, string, string, string, *string) error) *EnvVarsProvider_Delete_Call {
+func (_c *EnvVarsProvider_Delete_Call) RunAndReturn(run func(context.Context, string, string, string, string, *string) error) *EnvVarsProvider_Delete_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListByRepo provides a mock function with given fields: ctx, owner, repo
-func (_m *EnvVarsProvider) ListByRepo(ctx context.Context, owner string, repo string) ([]envvars.EnvVar, error) {
-	ret := _m.Called(ctx, owner, repo)
+// ListByRepo provides a mock function with given fields: ctx, provider, owner, repo
+func (_m *EnvVarsProvider) ListByRepo(ctx context.Context, provider string, owner string, repo string) ([]envvars.EnvVar, error) {
+	ret := _m.Called(ctx, provider, owner, repo)
 
 	var r0 []envvars.EnvVar
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]envvars.EnvVar, error)); ok {
-		return rf(ctx, owner, repo)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]envvars.EnvVar, error)); ok {
+		return rf(ctx, provider, owner, repo)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) []envvars.EnvVar); ok {
-		r0 = rf(ctx, owner, repo)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []envvars.EnvVar); ok {
+		r0 = rf(ctx, provider, owner, repo)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]envvars.EnvVar)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, owner, repo)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -101,15 +102,16 @@ type EnvVarsProvider_ListByRepo_Call struct {
 
 // ListByRepo is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
 //   - repo string
-func (_e *EnvVarsProvider_Expecter) ListByRepo(ctx interface{}, owner interface{}, repo interface{}) *EnvVarsProvider_ListByRepo_Call {
-	return &EnvVarsProvider_ListByRepo_Call{Call: _e.mock.On("ListByRepo", ctx, owner, repo)}
+func (_e *EnvVarsProvider_Expecter) ListByRepo(ctx interface{}, provider interface{}, owner interface{}, repo interface{}) *EnvVarsProvider_ListByRepo_Call {
+	return &EnvVarsProvider_ListByRepo_Call{Call: _e.mock.On("ListByRepo", ctx, provider, owner, repo)}
 }
 
-func (_c *EnvVarsProvider_ListByRepo_Call) Run(run func(ctx context.Context, owner string, repo string)) *EnvVarsProvider_ListByRepo_Call {
+func (_c *EnvVarsProvider_ListByRepo_Call) Run(run func(ctx context.Context, provider string, owner string, repo string)) *EnvVarsProvider_ListByRepo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -119,30 +121,30 @@ func (_c *EnvVarsProvider_ListByRepo_Call) Return(_a0 []envvars.EnvVar, _a1 erro
 	return _c
 }
 
-func (_c *EnvVarsProvider_ListByRepo_Call) RunAndReturn(run func(context.Context, string, string) ([]envvars.EnvVar, error)) *EnvVarsProvider_ListByRepo_Call {
+func (_c *EnvVarsProvider_ListByRepo_Call) RunAndReturn(run func(context.Context, string, string, string) ([]envvars.EnvVar, error)) *EnvVarsProvider_ListByRepo_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListByRepoBranch provides a mock function with given fields: ctx, owner, repo, branch
-func (_m *EnvVarsProvider) ListByRepoBranch(ctx context.Context, owner string, repo string, branch string) ([]envvars.EnvVar, error) {
-	ret := _m.Called(ctx, owner, repo, branch)
+// ListByRepoBranch provides a mock function with given fields: ctx, provider, owner, repo, branch
+func (_m *EnvVarsProvider) ListByRepoBranch(ctx context.Context, provider string, owner string, repo string, branch string) ([]envvars.EnvVar, error) {
+	ret := _m.Called(ctx, provider, owner, repo, branch)
 
 	var r0 []envvars.EnvVar
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]envvars.EnvVar, error)); ok {
-		return rf(ctx, owner, repo, branch)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]envvars.EnvVar, error)); ok {
+		return rf(ctx, provider, owner, repo, branch)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []envvars.EnvVar); ok {
-		r0 = rf(ctx, owner, repo, branch)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []envvars.EnvVar); ok {
+		r0 = rf(ctx, provider, owner, repo, branch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]envvars.EnvVar)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, owner, repo, branch)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo, branch)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -157,16 +159,17 @@ type EnvVarsProvider_ListByRepoBranch_Call struct {
 
 // ListByRepoBranch is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
 //   - repo string
 //   - branch string
-func (_e *EnvVarsProvider_Expecter) ListByRepoBranch(ctx interface{}, owner interface{}, repo interface{}, branch interface{}) *EnvVarsProvider_ListByRepoBranch_Call {
-	return &EnvVarsProvider_ListByRepoBranch_Call{Call: _e.mock.On("ListByRepoBranch", ctx, owner, repo, branch)}
+func (_e *EnvVarsProvider_Expecter) ListByRepoBranch(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, branch interface{}) *EnvVarsProvider_ListByRepoBranch_Call {
+	return &EnvVarsProvider_ListByRepoBranch_Call{Call: _e.mock.On("ListByRepoBranch", ctx, provider, owner, repo, branch)}
 }
 
-func (_c *EnvVarsProvider_ListByRepoBranch_Call) Run(run func(ctx context.Context, owner string, repo string, branch string)) *EnvVarsProvider_ListByRepoBranch_Call {
+func (_c *EnvVarsProvider_ListByRepoBranch_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, branch string)) *EnvVarsProvider_ListByRepoBranch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
 	})
 	return _c
 }
@@ -176,18 +179,18 @@ func (_c *EnvVarsProvider_ListByRepoBranch_Call) Return(_a0 []envvars.EnvVar, _a
 	return _c
 }
 
-func (_c *EnvVarsProvider_ListByRepoBranch_Call) RunAndReturn(run func(context.Context, string, string, string) ([]envvars.EnvVar, error)) *EnvVarsProvider_ListByRepoBranch_Call {
+func (_c *EnvVarsProvider_ListByRepoBranch_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]envvars.EnvVar, error)) *EnvVarsProvider_ListByRepoBranch_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Upsert provides a mock function with given fields: ctx, owner, repo, name, value, branch
-func (_m *EnvVarsProvider) Upsert(ctx context.Context, owner string, repo string, name string, value string, branch *string) error {
-	ret := _m.Called(ctx, owner, repo, name, value, branch)
+// Upsert provides a mock function with given fields: ctx, provider, owner, repo, v
+func (_m *EnvVarsProvider) Upsert(ctx context.Context, provider string, owner string, repo string, v envvars.EnvVar) error {
+	ret := _m.Called(ctx, provider, owner, repo, v)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *string) error); ok {
-		r0 = rf(ctx, owner, repo, name, value, branch)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, envvars.EnvVar) error); ok {
+		r0 = rf(ctx, provider, owner, repo, v)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -202,18 +205,17 @@ type EnvVarsProvider_Upsert_Call struct {
 
 // Upsert is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
 //   - repo string
-//   - name string
-//   - value string
-//   - branch *string
-func (_e *EnvVarsProvider_Expecter) Upsert(ctx interface{}, owner interface{}, repo interface{}, name interface{}, value interface{}, branch interface{}) *EnvVarsProvider_Upsert_Call {
-	return &EnvVarsProvider_Upsert_Call{Call: _e.mock.On("Upsert", ctx, owner, repo, name, value, branch)}
+//   - v envvars.EnvVar
+func (_e *EnvVarsProvider_Expecter) Upsert(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, v interface{}) *EnvVarsProvider_Upsert_Call {
+	return &EnvVarsProvider_Upsert_Call{Call: _e.mock.On("Upsert", ctx, provider, owner, repo, v)}
 }
 
-func (_c *EnvVarsProvider_Upsert_Call) Run(run func(ctx context.Context, owner string, repo string, name string, value string, branch *string)) *EnvVarsProvider_Upsert_Call {
+func (_c *EnvVarsProvider_Upsert_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, v envvars.EnvVar)) *EnvVarsProvider_Upsert_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(*string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(envvars.EnvVar))
 	})
 	return _c
 }
@@ -223,7 +225,7 @@ func (_c *EnvVarsProvider_Upsert_Call) Return(_a0 error) *EnvVarsProvider_Upsert
 	return _c
 }
 
-func (_c *EnvVarsProvider_Upsert_Call) RunAndReturn(run func(context.Context, string, string, string, string, *string) error) *EnvVarsProvider_Upsert_Call {
+func (_c *EnvVarsProvider_Upsert_Call) RunAndReturn(run func(context.Context, string, string, string, envvars.EnvVar) error) *EnvVarsProvider_Upsert_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -124,6 +124,47 @@ func (_c *RemoteGitClient_DoesBranchExist_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// GetBaseURL provides a mock function with given fields:
+func (_m *RemoteGitClient) GetBaseURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// RemoteGitClient_GetBaseURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBaseURL'
+type RemoteGitClient_GetBaseURL_Call struct {
+	*mock.Call
+}
+
+// GetBaseURL is a helper method to define mock.On call
+func (_e *RemoteGitClient_Expecter) GetBaseURL() *RemoteGitClient_GetBaseURL_Call {
+	return &RemoteGitClient_GetBaseURL_Call{Call: _e.mock.On("GetBaseURL")}
+}
+
+func (_c *RemoteGitClient_GetBaseURL_Call) Run(run func()) *RemoteGitClient_GetBaseURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *RemoteGitClient_GetBaseURL_Call) Return(_a0 string) *RemoteGitClient_GetBaseURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RemoteGitClient_GetBaseURL_Call) RunAndReturn(run func() string) *RemoteGitClient_GetBaseURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCloneParams provides a mock function with given fields:
 func (_m *RemoteGitClient) GetCloneParams() []string {
 	ret := _m.Called()
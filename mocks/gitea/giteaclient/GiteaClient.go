@@ -0,0 +1,479 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	git "github.com/ergomake/ergomake/internal/git"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GiteaClient is an autogenerated mock type for the GiteaClient type
+type GiteaClient struct {
+	mock.Mock
+}
+
+type GiteaClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GiteaClient) EXPECT() *GiteaClient_Expecter {
+	return &GiteaClient_Expecter{mock: &_m.Mock}
+}
+
+// CloneRepo provides a mock function with given fields: ctx, owner, repo, branch, dir, isPublic
+func (_m *GiteaClient) CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error {
+	ret := _m.Called(ctx, owner, repo, branch, dir, isPublic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool) error); ok {
+		r0 = rf(ctx, owner, repo, branch, dir, isPublic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GiteaClient_CloneRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloneRepo'
+type GiteaClient_CloneRepo_Call struct {
+	*mock.Call
+}
+
+// CloneRepo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+//   - dir string
+//   - isPublic bool
+func (_e *GiteaClient_Expecter) CloneRepo(ctx interface{}, owner interface{}, repo interface{}, branch interface{}, dir interface{}, isPublic interface{}) *GiteaClient_CloneRepo_Call {
+	return &GiteaClient_CloneRepo_Call{Call: _e.mock.On("CloneRepo", ctx, owner, repo, branch, dir, isPublic)}
+}
+
+func (_c *GiteaClient_CloneRepo_Call) Run(run func(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool)) *GiteaClient_CloneRepo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(bool))
+	})
+	return _c
+}
+
+func (_c *GiteaClient_CloneRepo_Call) Return(_a0 error) *GiteaClient_CloneRepo_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GiteaClient_CloneRepo_Call) RunAndReturn(run func(context.Context, string, string, string, string, bool) error) *GiteaClient_CloneRepo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DoesBranchExist provides a mock function with given fields: ctx, owner, repo, branch, branchOwner
+func (_m *GiteaClient) DoesBranchExist(ctx context.Context, owner string, repo string, branch string, branchOwner string) (bool, error) {
+	ret := _m.Called(ctx, owner, repo, branch, branchOwner)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (bool, error)); ok {
+		return rf(ctx, owner, repo, branch, branchOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) bool); ok {
+		r0 = rf(ctx, owner, repo, branch, branchOwner)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, branch, branchOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GiteaClient_DoesBranchExist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DoesBranchExist'
+type GiteaClient_DoesBranchExist_Call struct {
+	*mock.Call
+}
+
+// DoesBranchExist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+//   - branchOwner string
+func (_e *GiteaClient_Expecter) DoesBranchExist(ctx interface{}, owner interface{}, repo interface{}, branch interface{}, branchOwner interface{}) *GiteaClient_DoesBranchExist_Call {
+	return &GiteaClient_DoesBranchExist_Call{Call: _e.mock.On("DoesBranchExist", ctx, owner, repo, branch, branchOwner)}
+}
+
+func (_c *GiteaClient_DoesBranchExist_Call) Run(run func(ctx context.Context, owner string, repo string, branch string, branchOwner string)) *GiteaClient_DoesBranchExist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *GiteaClient_DoesBranchExist_Call) Return(_a0 bool, _a1 error) *GiteaClient_DoesBranchExist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GiteaClient_DoesBranchExist_Call) RunAndReturn(run func(context.Context, string, string, string, string) (bool, error)) *GiteaClient_DoesBranchExist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBaseURL provides a mock function with given fields:
+func (_m *GiteaClient) GetBaseURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GiteaClient_GetBaseURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBaseURL'
+type GiteaClient_GetBaseURL_Call struct {
+	*mock.Call
+}
+
+// GetBaseURL is a helper method to define mock.On call
+func (_e *GiteaClient_Expecter) GetBaseURL() *GiteaClient_GetBaseURL_Call {
+	return &GiteaClient_GetBaseURL_Call{Call: _e.mock.On("GetBaseURL")}
+}
+
+func (_c *GiteaClient_GetBaseURL_Call) Run(run func()) *GiteaClient_GetBaseURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GiteaClient_GetBaseURL_Call) Return(_a0 string) *GiteaClient_GetBaseURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GiteaClient_GetBaseURL_Call) RunAndReturn(run func() string) *GiteaClient_GetBaseURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneParams provides a mock function with given fields:
+func (_m *GiteaClient) GetCloneParams() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GiteaClient_GetCloneParams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneParams'
+type GiteaClient_GetCloneParams_Call struct {
+	*mock.Call
+}
+
+// GetCloneParams is a helper method to define mock.On call
+func (_e *GiteaClient_Expecter) GetCloneParams() *GiteaClient_GetCloneParams_Call {
+	return &GiteaClient_GetCloneParams_Call{Call: _e.mock.On("GetCloneParams")}
+}
+
+func (_c *GiteaClient_GetCloneParams_Call) Run(run func()) *GiteaClient_GetCloneParams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GiteaClient_GetCloneParams_Call) Return(_a0 []string) *GiteaClient_GetCloneParams_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GiteaClient_GetCloneParams_Call) RunAndReturn(run func() []string) *GiteaClient_GetCloneParams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneToken provides a mock function with given fields: ctx, owner, repo
+func (_m *GiteaClient) GetCloneToken(ctx context.Context, owner string, repo string) (string, error) {
+	ret := _m.Called(ctx, owner, repo)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, owner, repo)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GiteaClient_GetCloneToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneToken'
+type GiteaClient_GetCloneToken_Call struct {
+	*mock.Call
+}
+
+// GetCloneToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+func (_e *GiteaClient_Expecter) GetCloneToken(ctx interface{}, owner interface{}, repo interface{}) *GiteaClient_GetCloneToken_Call {
+	return &GiteaClient_GetCloneToken_Call{Call: _e.mock.On("GetCloneToken", ctx, owner, repo)}
+}
+
+func (_c *GiteaClient_GetCloneToken_Call) Run(run func(ctx context.Context, owner string, repo string)) *GiteaClient_GetCloneToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *GiteaClient_GetCloneToken_Call) Return(_a0 string, _a1 error) *GiteaClient_GetCloneToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GiteaClient_GetCloneToken_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *GiteaClient_GetCloneToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneUrl provides a mock function with given fields:
+func (_m *GiteaClient) GetCloneUrl() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GiteaClient_GetCloneUrl_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneUrl'
+type GiteaClient_GetCloneUrl_Call struct {
+	*mock.Call
+}
+
+// GetCloneUrl is a helper method to define mock.On call
+func (_e *GiteaClient_Expecter) GetCloneUrl() *GiteaClient_GetCloneUrl_Call {
+	return &GiteaClient_GetCloneUrl_Call{Call: _e.mock.On("GetCloneUrl")}
+}
+
+func (_c *GiteaClient_GetCloneUrl_Call) Run(run func()) *GiteaClient_GetCloneUrl_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GiteaClient_GetCloneUrl_Call) Return(_a0 string) *GiteaClient_GetCloneUrl_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GiteaClient_GetCloneUrl_Call) RunAndReturn(run func() string) *GiteaClient_GetCloneUrl_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDefaultBranch provides a mock function with given fields: ctx, owner, repo, branchOwner
+func (_m *GiteaClient) GetDefaultBranch(ctx context.Context, owner string, repo string, branchOwner string) (string, error) {
+	ret := _m.Called(ctx, owner, repo, branchOwner)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, owner, repo, branchOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, owner, repo, branchOwner)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, branchOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GiteaClient_GetDefaultBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDefaultBranch'
+type GiteaClient_GetDefaultBranch_Call struct {
+	*mock.Call
+}
+
+// GetDefaultBranch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branchOwner string
+func (_e *GiteaClient_Expecter) GetDefaultBranch(ctx interface{}, owner interface{}, repo interface{}, branchOwner interface{}) *GiteaClient_GetDefaultBranch_Call {
+	return &GiteaClient_GetDefaultBranch_Call{Call: _e.mock.On("GetDefaultBranch", ctx, owner, repo, branchOwner)}
+}
+
+func (_c *GiteaClient_GetDefaultBranch_Call) Run(run func(ctx context.Context, owner string, repo string, branchOwner string)) *GiteaClient_GetDefaultBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GiteaClient_GetDefaultBranch_Call) Return(_a0 string, _a1 error) *GiteaClient_GetDefaultBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GiteaClient_GetDefaultBranch_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *GiteaClient_GetDefaultBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetCommitStatus provides a mock function with given fields: ctx, owner, repo, sha, status
+func (_m *GiteaClient) SetCommitStatus(ctx context.Context, owner string, repo string, sha string, status git.CommitStatus) error {
+	ret := _m.Called(ctx, owner, repo, sha, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, git.CommitStatus) error); ok {
+		r0 = rf(ctx, owner, repo, sha, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GiteaClient_SetCommitStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCommitStatus'
+type GiteaClient_SetCommitStatus_Call struct {
+	*mock.Call
+}
+
+// SetCommitStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - sha string
+//   - status git.CommitStatus
+func (_e *GiteaClient_Expecter) SetCommitStatus(ctx interface{}, owner interface{}, repo interface{}, sha interface{}, status interface{}) *GiteaClient_SetCommitStatus_Call {
+	return &GiteaClient_SetCommitStatus_Call{Call: _e.mock.On("SetCommitStatus", ctx, owner, repo, sha, status)}
+}
+
+func (_c *GiteaClient_SetCommitStatus_Call) Run(run func(ctx context.Context, owner string, repo string, sha string, status git.CommitStatus)) *GiteaClient_SetCommitStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(git.CommitStatus))
+	})
+	return _c
+}
+
+func (_c *GiteaClient_SetCommitStatus_Call) Return(_a0 error) *GiteaClient_SetCommitStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GiteaClient_SetCommitStatus_Call) RunAndReturn(run func(context.Context, string, string, string, git.CommitStatus) error) *GiteaClient_SetCommitStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertComment provides a mock function with given fields: ctx, owner, repo, number, commentID, body
+func (_m *GiteaClient) UpsertComment(ctx context.Context, owner string, repo string, number int, commentID int64, body string) (int64, error) {
+	ret := _m.Called(ctx, owner, repo, number, commentID, body)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int64, string) (int64, error)); ok {
+		return rf(ctx, owner, repo, number, commentID, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int64, string) int64); ok {
+		r0 = rf(ctx, owner, repo, number, commentID, body)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int64, string) error); ok {
+		r1 = rf(ctx, owner, repo, number, commentID, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GiteaClient_UpsertComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertComment'
+type GiteaClient_UpsertComment_Call struct {
+	*mock.Call
+}
+
+// UpsertComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - number int
+//   - commentID int64
+//   - body string
+func (_e *GiteaClient_Expecter) UpsertComment(ctx interface{}, owner interface{}, repo interface{}, number interface{}, commentID interface{}, body interface{}) *GiteaClient_UpsertComment_Call {
+	return &GiteaClient_UpsertComment_Call{Call: _e.mock.On("UpsertComment", ctx, owner, repo, number, commentID, body)}
+}
+
+func (_c *GiteaClient_UpsertComment_Call) Run(run func(ctx context.Context, owner string, repo string, number int, commentID int64, body string)) *GiteaClient_UpsertComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int), args[4].(int64), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *GiteaClient_UpsertComment_Call) Return(_a0 int64, _a1 error) *GiteaClient_UpsertComment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GiteaClient_UpsertComment_Call) RunAndReturn(run func(context.Context, string, string, int, int64, string) (int64, error)) *GiteaClient_UpsertComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewGiteaClient interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewGiteaClient creates a new instance of GiteaClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewGiteaClient(t mockConstructorTestingTNewGiteaClient) *GiteaClient {
+	mock := &GiteaClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
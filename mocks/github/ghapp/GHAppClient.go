@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	ghapp "github.com/ergomake/ergomake/internal/github/ghapp"
 	github "github.com/google/go-github/v52/github"
 
 	mock "github.com/stretchr/testify/mock"
@@ -23,6 +24,61 @@ func (_m *GHAppClient) EXPECT() *GHAppClient_Expecter {
 	return &GHAppClient_Expecter{mock: &_m.Mock}
 }
 
+// CanWriteToRepo provides a mock function with given fields: ctx, owner, repo, username
+func (_m *GHAppClient) CanWriteToRepo(ctx context.Context, owner string, repo string, username string) (bool, error) {
+	ret := _m.Called(ctx, owner, repo, username)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, owner, repo, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, owner, repo, username)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GHAppClient_CanWriteToRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CanWriteToRepo'
+type GHAppClient_CanWriteToRepo_Call struct {
+	*mock.Call
+}
+
+// CanWriteToRepo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - username string
+func (_e *GHAppClient_Expecter) CanWriteToRepo(ctx interface{}, owner interface{}, repo interface{}, username interface{}) *GHAppClient_CanWriteToRepo_Call {
+	return &GHAppClient_CanWriteToRepo_Call{Call: _e.mock.On("CanWriteToRepo", ctx, owner, repo, username)}
+}
+
+func (_c *GHAppClient_CanWriteToRepo_Call) Run(run func(ctx context.Context, owner string, repo string, username string)) *GHAppClient_CanWriteToRepo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_CanWriteToRepo_Call) Return(_a0 bool, _a1 error) *GHAppClient_CanWriteToRepo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GHAppClient_CanWriteToRepo_Call) RunAndReturn(run func(context.Context, string, string, string) (bool, error)) *GHAppClient_CanWriteToRepo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CloneRepo provides a mock function with given fields: ctx, owner, repo, branch, dir, isPublic
 func (_m *GHAppClient) CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error {
 	ret := _m.Called(ctx, owner, repo, branch, dir, isPublic)
@@ -70,13 +126,125 @@ func (_c *GHAppClient_CloneRepo_Call) RunAndReturn(run func(context.Context, str
 	return _c
 }
 
-// CreateCommitStatus provides a mock function with given fields: ctx, owner, repo, sha, state, targetURL
-func (_m *GHAppClient) CreateCommitStatus(ctx context.Context, owner string, repo string, sha string, state string, targetURL *string) error {
-	ret := _m.Called(ctx, owner, repo, sha, state, targetURL)
+// CreateCheckRun provides a mock function with given fields: ctx, owner, repo, sha, run
+func (_m *GHAppClient) CreateCheckRun(ctx context.Context, owner string, repo string, sha string, run ghapp.CheckRun) (int64, error) {
+	ret := _m.Called(ctx, owner, repo, sha, run)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, ghapp.CheckRun) (int64, error)); ok {
+		return rf(ctx, owner, repo, sha, run)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, ghapp.CheckRun) int64); ok {
+		r0 = rf(ctx, owner, repo, sha, run)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, ghapp.CheckRun) error); ok {
+		r1 = rf(ctx, owner, repo, sha, run)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GHAppClient_CreateCheckRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCheckRun'
+type GHAppClient_CreateCheckRun_Call struct {
+	*mock.Call
+}
+
+// CreateCheckRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - sha string
+//   - run ghapp.CheckRun
+func (_e *GHAppClient_Expecter) CreateCheckRun(ctx interface{}, owner interface{}, repo interface{}, sha interface{}, run interface{}) *GHAppClient_CreateCheckRun_Call {
+	return &GHAppClient_CreateCheckRun_Call{Call: _e.mock.On("CreateCheckRun", ctx, owner, repo, sha, run)}
+}
+
+func (_c *GHAppClient_CreateCheckRun_Call) Run(run func(ctx context.Context, owner string, repo string, sha string, run ghapp.CheckRun)) *GHAppClient_CreateCheckRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(ghapp.CheckRun))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_CreateCheckRun_Call) Return(_a0 int64, _a1 error) *GHAppClient_CreateCheckRun_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GHAppClient_CreateCheckRun_Call) RunAndReturn(run func(context.Context, string, string, string, ghapp.CheckRun) (int64, error)) *GHAppClient_CreateCheckRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDeployment provides a mock function with given fields: ctx, owner, repo, sha, environment
+func (_m *GHAppClient) CreateDeployment(ctx context.Context, owner string, repo string, sha string, environment string) (int64, error) {
+	ret := _m.Called(ctx, owner, repo, sha, environment)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (int64, error)); ok {
+		return rf(ctx, owner, repo, sha, environment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) int64); ok {
+		r0 = rf(ctx, owner, repo, sha, environment)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, sha, environment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GHAppClient_CreateDeployment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeployment'
+type GHAppClient_CreateDeployment_Call struct {
+	*mock.Call
+}
+
+// CreateDeployment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - sha string
+//   - environment string
+func (_e *GHAppClient_Expecter) CreateDeployment(ctx interface{}, owner interface{}, repo interface{}, sha interface{}, environment interface{}) *GHAppClient_CreateDeployment_Call {
+	return &GHAppClient_CreateDeployment_Call{Call: _e.mock.On("CreateDeployment", ctx, owner, repo, sha, environment)}
+}
+
+func (_c *GHAppClient_CreateDeployment_Call) Run(run func(ctx context.Context, owner string, repo string, sha string, environment string)) *GHAppClient_CreateDeployment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_CreateDeployment_Call) Return(_a0 int64, _a1 error) *GHAppClient_CreateDeployment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GHAppClient_CreateDeployment_Call) RunAndReturn(run func(context.Context, string, string, string, string) (int64, error)) *GHAppClient_CreateDeployment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDeploymentStatus provides a mock function with given fields: ctx, owner, repo, deploymentID, status
+func (_m *GHAppClient) CreateDeploymentStatus(ctx context.Context, owner string, repo string, deploymentID int64, status ghapp.DeploymentStatus) error {
+	ret := _m.Called(ctx, owner, repo, deploymentID, status)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, *string) error); ok {
-		r0 = rf(ctx, owner, repo, sha, state, targetURL)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, ghapp.DeploymentStatus) error); ok {
+		r0 = rf(ctx, owner, repo, deploymentID, status)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -84,35 +252,34 @@ func (_m *GHAppClient) CreateCommitStatus(ctx context.Context, owner string, rep
 	return r0
 }
 
-// GHAppClient_CreateCommitStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCommitStatus'
-type GHAppClient_CreateCommitStatus_Call struct {
+// GHAppClient_CreateDeploymentStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeploymentStatus'
+type GHAppClient_CreateDeploymentStatus_Call struct {
 	*mock.Call
 }
 
-// CreateCommitStatus is a helper method to define mock.On call
+// CreateDeploymentStatus is a helper method to define mock.On call
 //   - ctx context.Context
 //   - owner string
 //   - repo string
-//   - sha string
-//   - state string
-//   - targetURL *string
-func (_e *GHAppClient_Expecter) CreateCommitStatus(ctx interface{}, owner interface{}, repo interface{}, sha interface{}, state interface{}, targetURL interface{}) *GHAppClient_CreateCommitStatus_Call {
-	return &GHAppClient_CreateCommitStatus_Call{Call: _e.mock.On("CreateCommitStatus", ctx, owner, repo, sha, state, targetURL)}
+//   - deploymentID int64
+//   - status ghapp.DeploymentStatus
+func (_e *GHAppClient_Expecter) CreateDeploymentStatus(ctx interface{}, owner interface{}, repo interface{}, deploymentID interface{}, status interface{}) *GHAppClient_CreateDeploymentStatus_Call {
+	return &GHAppClient_CreateDeploymentStatus_Call{Call: _e.mock.On("CreateDeploymentStatus", ctx, owner, repo, deploymentID, status)}
 }
 
-func (_c *GHAppClient_CreateCommitStatus_Call) Run(run func(ctx context.Context, owner string, repo string, sha string, state string, targetURL *string)) *GHAppClient_CreateCommitStatus_Call {
+func (_c *GHAppClient_CreateDeploymentStatus_Call) Run(run func(ctx context.Context, owner string, repo string, deploymentID int64, status ghapp.DeploymentStatus)) *GHAppClient_CreateDeploymentStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(*string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(ghapp.DeploymentStatus))
 	})
 	return _c
 }
 
-func (_c *GHAppClient_CreateCommitStatus_Call) Return(_a0 error) *GHAppClient_CreateCommitStatus_Call {
+func (_c *GHAppClient_CreateDeploymentStatus_Call) Return(_a0 error) *GHAppClient_CreateDeploymentStatus_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *GHAppClient_CreateCommitStatus_Call) RunAndReturn(run func(context.Context, string, string, string, string, *string) error) *GHAppClient_CreateCommitStatus_Call {
+func (_c *GHAppClient_CreateDeploymentStatus_Call) RunAndReturn(run func(context.Context, string, string, int64, ghapp.DeploymentStatus) error) *GHAppClient_CreateDeploymentStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -233,6 +400,47 @@ func (_c *GHAppClient_DoesBranchExist_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// GetBaseURL provides a mock function with given fields:
+func (_m *GHAppClient) GetBaseURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GHAppClient_GetBaseURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBaseURL'
+type GHAppClient_GetBaseURL_Call struct {
+	*mock.Call
+}
+
+// GetBaseURL is a helper method to define mock.On call
+func (_e *GHAppClient_Expecter) GetBaseURL() *GHAppClient_GetBaseURL_Call {
+	return &GHAppClient_GetBaseURL_Call{Call: _e.mock.On("GetBaseURL")}
+}
+
+func (_c *GHAppClient_GetBaseURL_Call) Run(run func()) *GHAppClient_GetBaseURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GHAppClient_GetBaseURL_Call) Return(_a0 string) *GHAppClient_GetBaseURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GHAppClient_GetBaseURL_Call) RunAndReturn(run func() string) *GHAppClient_GetBaseURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBranchSHA provides a mock function with given fields: ctx, owner, repo, branch
 func (_m *GHAppClient) GetBranchSHA(ctx context.Context, owner string, repo string, branch string) (string, error) {
 	ret := _m.Called(ctx, owner, repo, branch)
@@ -536,6 +744,63 @@ func (_c *GHAppClient_GetInstallation_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// GetPullRequest provides a mock function with given fields: ctx, owner, repo, prNumber
+func (_m *GHAppClient) GetPullRequest(ctx context.Context, owner string, repo string, prNumber int) (*github.PullRequest, error) {
+	ret := _m.Called(ctx, owner, repo, prNumber)
+
+	var r0 *github.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*github.PullRequest, error)); ok {
+		return rf(ctx, owner, repo, prNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *github.PullRequest); ok {
+		r0 = rf(ctx, owner, repo, prNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*github.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, owner, repo, prNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GHAppClient_GetPullRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPullRequest'
+type GHAppClient_GetPullRequest_Call struct {
+	*mock.Call
+}
+
+// GetPullRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - prNumber int
+func (_e *GHAppClient_Expecter) GetPullRequest(ctx interface{}, owner interface{}, repo interface{}, prNumber interface{}) *GHAppClient_GetPullRequest_Call {
+	return &GHAppClient_GetPullRequest_Call{Call: _e.mock.On("GetPullRequest", ctx, owner, repo, prNumber)}
+}
+
+func (_c *GHAppClient_GetPullRequest_Call) Run(run func(ctx context.Context, owner string, repo string, prNumber int)) *GHAppClient_GetPullRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_GetPullRequest_Call) Return(_a0 *github.PullRequest, _a1 error) *GHAppClient_GetPullRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GHAppClient_GetPullRequest_Call) RunAndReturn(run func(context.Context, string, string, int) (*github.PullRequest, error)) *GHAppClient_GetPullRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsOwnerInstalled provides a mock function with given fields: ctx, owner
 func (_m *GHAppClient) IsOwnerInstalled(ctx context.Context, owner string) (bool, error) {
 	ret := _m.Called(ctx, owner)
@@ -699,6 +964,64 @@ func (_c *GHAppClient_ListBranches_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// ListChangedFiles provides a mock function with given fields: ctx, owner, repo, base, head
+func (_m *GHAppClient) ListChangedFiles(ctx context.Context, owner string, repo string, base string, head string) ([]string, error) {
+	ret := _m.Called(ctx, owner, repo, base, head)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]string, error)); ok {
+		return rf(ctx, owner, repo, base, head)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []string); ok {
+		r0 = rf(ctx, owner, repo, base, head)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, base, head)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GHAppClient_ListChangedFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListChangedFiles'
+type GHAppClient_ListChangedFiles_Call struct {
+	*mock.Call
+}
+
+// ListChangedFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - base string
+//   - head string
+func (_e *GHAppClient_Expecter) ListChangedFiles(ctx interface{}, owner interface{}, repo interface{}, base interface{}, head interface{}) *GHAppClient_ListChangedFiles_Call {
+	return &GHAppClient_ListChangedFiles_Call{Call: _e.mock.On("ListChangedFiles", ctx, owner, repo, base, head)}
+}
+
+func (_c *GHAppClient_ListChangedFiles_Call) Run(run func(ctx context.Context, owner string, repo string, base string, head string)) *GHAppClient_ListChangedFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_ListChangedFiles_Call) Return(_a0 []string, _a1 error) *GHAppClient_ListChangedFiles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GHAppClient_ListChangedFiles_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]string, error)) *GHAppClient_ListChangedFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListInstalledOwners provides a mock function with given fields: ctx
 func (_m *GHAppClient) ListInstalledOwners(ctx context.Context) ([]string, error) {
 	ret := _m.Called(ctx)
@@ -808,6 +1131,155 @@ func (_c *GHAppClient_ListOwnerInstalledRepos_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// ListPullRequestFiles provides a mock function with given fields: ctx, owner, repo, prNumber
+func (_m *GHAppClient) ListPullRequestFiles(ctx context.Context, owner string, repo string, prNumber int) ([]string, error) {
+	ret := _m.Called(ctx, owner, repo, prNumber)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]string, error)); ok {
+		return rf(ctx, owner, repo, prNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []string); ok {
+		r0 = rf(ctx, owner, repo, prNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, owner, repo, prNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GHAppClient_ListPullRequestFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPullRequestFiles'
+type GHAppClient_ListPullRequestFiles_Call struct {
+	*mock.Call
+}
+
+// ListPullRequestFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - prNumber int
+func (_e *GHAppClient_Expecter) ListPullRequestFiles(ctx interface{}, owner interface{}, repo interface{}, prNumber interface{}) *GHAppClient_ListPullRequestFiles_Call {
+	return &GHAppClient_ListPullRequestFiles_Call{Call: _e.mock.On("ListPullRequestFiles", ctx, owner, repo, prNumber)}
+}
+
+func (_c *GHAppClient_ListPullRequestFiles_Call) Run(run func(ctx context.Context, owner string, repo string, prNumber int)) *GHAppClient_ListPullRequestFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_ListPullRequestFiles_Call) Return(_a0 []string, _a1 error) *GHAppClient_ListPullRequestFiles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GHAppClient_ListPullRequestFiles_Call) RunAndReturn(run func(context.Context, string, string, int) ([]string, error)) *GHAppClient_ListPullRequestFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReactToComment provides a mock function with given fields: ctx, owner, repo, commentID, reaction
+func (_m *GHAppClient) ReactToComment(ctx context.Context, owner string, repo string, commentID int64, reaction string) error {
+	ret := _m.Called(ctx, owner, repo, commentID, reaction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, string) error); ok {
+		r0 = rf(ctx, owner, repo, commentID, reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GHAppClient_ReactToComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReactToComment'
+type GHAppClient_ReactToComment_Call struct {
+	*mock.Call
+}
+
+// ReactToComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - commentID int64
+//   - reaction string
+func (_e *GHAppClient_Expecter) ReactToComment(ctx interface{}, owner interface{}, repo interface{}, commentID interface{}, reaction interface{}) *GHAppClient_ReactToComment_Call {
+	return &GHAppClient_ReactToComment_Call{Call: _e.mock.On("ReactToComment", ctx, owner, repo, commentID, reaction)}
+}
+
+func (_c *GHAppClient_ReactToComment_Call) Run(run func(ctx context.Context, owner string, repo string, commentID int64, reaction string)) *GHAppClient_ReactToComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_ReactToComment_Call) Return(_a0 error) *GHAppClient_ReactToComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GHAppClient_ReactToComment_Call) RunAndReturn(run func(context.Context, string, string, int64, string) error) *GHAppClient_ReactToComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCheckRun provides a mock function with given fields: ctx, owner, repo, checkRunID, run
+func (_m *GHAppClient) UpdateCheckRun(ctx context.Context, owner string, repo string, checkRunID int64, run ghapp.CheckRun) error {
+	ret := _m.Called(ctx, owner, repo, checkRunID, run)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, ghapp.CheckRun) error); ok {
+		r0 = rf(ctx, owner, repo, checkRunID, run)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GHAppClient_UpdateCheckRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCheckRun'
+type GHAppClient_UpdateCheckRun_Call struct {
+	*mock.Call
+}
+
+// UpdateCheckRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - checkRunID int64
+//   - run ghapp.CheckRun
+func (_e *GHAppClient_Expecter) UpdateCheckRun(ctx interface{}, owner interface{}, repo interface{}, checkRunID interface{}, run interface{}) *GHAppClient_UpdateCheckRun_Call {
+	return &GHAppClient_UpdateCheckRun_Call{Call: _e.mock.On("UpdateCheckRun", ctx, owner, repo, checkRunID, run)}
+}
+
+func (_c *GHAppClient_UpdateCheckRun_Call) Run(run func(ctx context.Context, owner string, repo string, checkRunID int64, run ghapp.CheckRun)) *GHAppClient_UpdateCheckRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].(ghapp.CheckRun))
+	})
+	return _c
+}
+
+func (_c *GHAppClient_UpdateCheckRun_Call) Return(_a0 error) *GHAppClient_UpdateCheckRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GHAppClient_UpdateCheckRun_Call) RunAndReturn(run func(context.Context, string, string, int64, ghapp.CheckRun) error) *GHAppClient_UpdateCheckRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpsertComment provides a mock function with given fields: ctx, owner, repo, prNumber, commentID, comment
 func (_m *GHAppClient) UpsertComment(ctx context.Context, owner string, repo string, prNumber int, commentID int64, comment string) (*github.IssueComment, error) {
 	ret := _m.Called(ctx, owner, repo, prNumber, commentID, comment)
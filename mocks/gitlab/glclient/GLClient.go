@@ -0,0 +1,534 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	git "github.com/ergomake/ergomake/internal/git"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GLClient is an autogenerated mock type for the GLClient type
+type GLClient struct {
+	mock.Mock
+}
+
+type GLClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GLClient) EXPECT() *GLClient_Expecter {
+	return &GLClient_Expecter{mock: &_m.Mock}
+}
+
+// CanMaintainProject provides a mock function with given fields: ctx, owner, repo, token
+func (_m *GLClient) CanMaintainProject(ctx context.Context, owner string, repo string, token string) (bool, error) {
+	ret := _m.Called(ctx, owner, repo, token)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, owner, repo, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, owner, repo, token)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLClient_CanMaintainProject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CanMaintainProject'
+type GLClient_CanMaintainProject_Call struct {
+	*mock.Call
+}
+
+// CanMaintainProject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - token string
+func (_e *GLClient_Expecter) CanMaintainProject(ctx interface{}, owner interface{}, repo interface{}, token interface{}) *GLClient_CanMaintainProject_Call {
+	return &GLClient_CanMaintainProject_Call{Call: _e.mock.On("CanMaintainProject", ctx, owner, repo, token)}
+}
+
+func (_c *GLClient_CanMaintainProject_Call) Run(run func(ctx context.Context, owner string, repo string, token string)) *GLClient_CanMaintainProject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GLClient_CanMaintainProject_Call) Return(_a0 bool, _a1 error) *GLClient_CanMaintainProject_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLClient_CanMaintainProject_Call) RunAndReturn(run func(context.Context, string, string, string) (bool, error)) *GLClient_CanMaintainProject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CloneRepo provides a mock function with given fields: ctx, owner, repo, branch, dir, isPublic
+func (_m *GLClient) CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error {
+	ret := _m.Called(ctx, owner, repo, branch, dir, isPublic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool) error); ok {
+		r0 = rf(ctx, owner, repo, branch, dir, isPublic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GLClient_CloneRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloneRepo'
+type GLClient_CloneRepo_Call struct {
+	*mock.Call
+}
+
+// CloneRepo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+//   - dir string
+//   - isPublic bool
+func (_e *GLClient_Expecter) CloneRepo(ctx interface{}, owner interface{}, repo interface{}, branch interface{}, dir interface{}, isPublic interface{}) *GLClient_CloneRepo_Call {
+	return &GLClient_CloneRepo_Call{Call: _e.mock.On("CloneRepo", ctx, owner, repo, branch, dir, isPublic)}
+}
+
+func (_c *GLClient_CloneRepo_Call) Run(run func(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool)) *GLClient_CloneRepo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(bool))
+	})
+	return _c
+}
+
+func (_c *GLClient_CloneRepo_Call) Return(_a0 error) *GLClient_CloneRepo_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GLClient_CloneRepo_Call) RunAndReturn(run func(context.Context, string, string, string, string, bool) error) *GLClient_CloneRepo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DoesBranchExist provides a mock function with given fields: ctx, owner, repo, branch, branchOwner
+func (_m *GLClient) DoesBranchExist(ctx context.Context, owner string, repo string, branch string, branchOwner string) (bool, error) {
+	ret := _m.Called(ctx, owner, repo, branch, branchOwner)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (bool, error)); ok {
+		return rf(ctx, owner, repo, branch, branchOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) bool); ok {
+		r0 = rf(ctx, owner, repo, branch, branchOwner)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, branch, branchOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLClient_DoesBranchExist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DoesBranchExist'
+type GLClient_DoesBranchExist_Call struct {
+	*mock.Call
+}
+
+// DoesBranchExist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+//   - branchOwner string
+func (_e *GLClient_Expecter) DoesBranchExist(ctx interface{}, owner interface{}, repo interface{}, branch interface{}, branchOwner interface{}) *GLClient_DoesBranchExist_Call {
+	return &GLClient_DoesBranchExist_Call{Call: _e.mock.On("DoesBranchExist", ctx, owner, repo, branch, branchOwner)}
+}
+
+func (_c *GLClient_DoesBranchExist_Call) Run(run func(ctx context.Context, owner string, repo string, branch string, branchOwner string)) *GLClient_DoesBranchExist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *GLClient_DoesBranchExist_Call) Return(_a0 bool, _a1 error) *GLClient_DoesBranchExist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLClient_DoesBranchExist_Call) RunAndReturn(run func(context.Context, string, string, string, string) (bool, error)) *GLClient_DoesBranchExist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBaseURL provides a mock function with given fields:
+func (_m *GLClient) GetBaseURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GLClient_GetBaseURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBaseURL'
+type GLClient_GetBaseURL_Call struct {
+	*mock.Call
+}
+
+// GetBaseURL is a helper method to define mock.On call
+func (_e *GLClient_Expecter) GetBaseURL() *GLClient_GetBaseURL_Call {
+	return &GLClient_GetBaseURL_Call{Call: _e.mock.On("GetBaseURL")}
+}
+
+func (_c *GLClient_GetBaseURL_Call) Run(run func()) *GLClient_GetBaseURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GLClient_GetBaseURL_Call) Return(_a0 string) *GLClient_GetBaseURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GLClient_GetBaseURL_Call) RunAndReturn(run func() string) *GLClient_GetBaseURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneParams provides a mock function with given fields:
+func (_m *GLClient) GetCloneParams() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GLClient_GetCloneParams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneParams'
+type GLClient_GetCloneParams_Call struct {
+	*mock.Call
+}
+
+// GetCloneParams is a helper method to define mock.On call
+func (_e *GLClient_Expecter) GetCloneParams() *GLClient_GetCloneParams_Call {
+	return &GLClient_GetCloneParams_Call{Call: _e.mock.On("GetCloneParams")}
+}
+
+func (_c *GLClient_GetCloneParams_Call) Run(run func()) *GLClient_GetCloneParams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GLClient_GetCloneParams_Call) Return(_a0 []string) *GLClient_GetCloneParams_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GLClient_GetCloneParams_Call) RunAndReturn(run func() []string) *GLClient_GetCloneParams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneToken provides a mock function with given fields: ctx, owner, repo
+func (_m *GLClient) GetCloneToken(ctx context.Context, owner string, repo string) (string, error) {
+	ret := _m.Called(ctx, owner, repo)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, owner, repo)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLClient_GetCloneToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneToken'
+type GLClient_GetCloneToken_Call struct {
+	*mock.Call
+}
+
+// GetCloneToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+func (_e *GLClient_Expecter) GetCloneToken(ctx interface{}, owner interface{}, repo interface{}) *GLClient_GetCloneToken_Call {
+	return &GLClient_GetCloneToken_Call{Call: _e.mock.On("GetCloneToken", ctx, owner, repo)}
+}
+
+func (_c *GLClient_GetCloneToken_Call) Run(run func(ctx context.Context, owner string, repo string)) *GLClient_GetCloneToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *GLClient_GetCloneToken_Call) Return(_a0 string, _a1 error) *GLClient_GetCloneToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLClient_GetCloneToken_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *GLClient_GetCloneToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneUrl provides a mock function with given fields:
+func (_m *GLClient) GetCloneUrl() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GLClient_GetCloneUrl_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneUrl'
+type GLClient_GetCloneUrl_Call struct {
+	*mock.Call
+}
+
+// GetCloneUrl is a helper method to define mock.On call
+func (_e *GLClient_Expecter) GetCloneUrl() *GLClient_GetCloneUrl_Call {
+	return &GLClient_GetCloneUrl_Call{Call: _e.mock.On("GetCloneUrl")}
+}
+
+func (_c *GLClient_GetCloneUrl_Call) Run(run func()) *GLClient_GetCloneUrl_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GLClient_GetCloneUrl_Call) Return(_a0 string) *GLClient_GetCloneUrl_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GLClient_GetCloneUrl_Call) RunAndReturn(run func() string) *GLClient_GetCloneUrl_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDefaultBranch provides a mock function with given fields: ctx, owner, repo, branchOwner
+func (_m *GLClient) GetDefaultBranch(ctx context.Context, owner string, repo string, branchOwner string) (string, error) {
+	ret := _m.Called(ctx, owner, repo, branchOwner)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, owner, repo, branchOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, owner, repo, branchOwner)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, branchOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLClient_GetDefaultBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDefaultBranch'
+type GLClient_GetDefaultBranch_Call struct {
+	*mock.Call
+}
+
+// GetDefaultBranch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branchOwner string
+func (_e *GLClient_Expecter) GetDefaultBranch(ctx interface{}, owner interface{}, repo interface{}, branchOwner interface{}) *GLClient_GetDefaultBranch_Call {
+	return &GLClient_GetDefaultBranch_Call{Call: _e.mock.On("GetDefaultBranch", ctx, owner, repo, branchOwner)}
+}
+
+func (_c *GLClient_GetDefaultBranch_Call) Run(run func(ctx context.Context, owner string, repo string, branchOwner string)) *GLClient_GetDefaultBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GLClient_GetDefaultBranch_Call) Return(_a0 string, _a1 error) *GLClient_GetDefaultBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLClient_GetDefaultBranch_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *GLClient_GetDefaultBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetCommitStatus provides a mock function with given fields: ctx, owner, repo, sha, status
+func (_m *GLClient) SetCommitStatus(ctx context.Context, owner string, repo string, sha string, status git.CommitStatus) error {
+	ret := _m.Called(ctx, owner, repo, sha, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, git.CommitStatus) error); ok {
+		r0 = rf(ctx, owner, repo, sha, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GLClient_SetCommitStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCommitStatus'
+type GLClient_SetCommitStatus_Call struct {
+	*mock.Call
+}
+
+// SetCommitStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - sha string
+//   - status git.CommitStatus
+func (_e *GLClient_Expecter) SetCommitStatus(ctx interface{}, owner interface{}, repo interface{}, sha interface{}, status interface{}) *GLClient_SetCommitStatus_Call {
+	return &GLClient_SetCommitStatus_Call{Call: _e.mock.On("SetCommitStatus", ctx, owner, repo, sha, status)}
+}
+
+func (_c *GLClient_SetCommitStatus_Call) Run(run func(ctx context.Context, owner string, repo string, sha string, status git.CommitStatus)) *GLClient_SetCommitStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(git.CommitStatus))
+	})
+	return _c
+}
+
+func (_c *GLClient_SetCommitStatus_Call) Return(_a0 error) *GLClient_SetCommitStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GLClient_SetCommitStatus_Call) RunAndReturn(run func(context.Context, string, string, string, git.CommitStatus) error) *GLClient_SetCommitStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertComment provides a mock function with given fields: ctx, owner, repo, number, commentID, body
+func (_m *GLClient) UpsertComment(ctx context.Context, owner string, repo string, number int, commentID int64, body string) (int64, error) {
+	ret := _m.Called(ctx, owner, repo, number, commentID, body)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int64, string) (int64, error)); ok {
+		return rf(ctx, owner, repo, number, commentID, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int64, string) int64); ok {
+		r0 = rf(ctx, owner, repo, number, commentID, body)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int64, string) error); ok {
+		r1 = rf(ctx, owner, repo, number, commentID, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLClient_UpsertComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertComment'
+type GLClient_UpsertComment_Call struct {
+	*mock.Call
+}
+
+// UpsertComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - number int
+//   - commentID int64
+//   - body string
+func (_e *GLClient_Expecter) UpsertComment(ctx interface{}, owner interface{}, repo interface{}, number interface{}, commentID interface{}, body interface{}) *GLClient_UpsertComment_Call {
+	return &GLClient_UpsertComment_Call{Call: _e.mock.On("UpsertComment", ctx, owner, repo, number, commentID, body)}
+}
+
+func (_c *GLClient_UpsertComment_Call) Run(run func(ctx context.Context, owner string, repo string, number int, commentID int64, body string)) *GLClient_UpsertComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int), args[4].(int64), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *GLClient_UpsertComment_Call) Return(_a0 int64, _a1 error) *GLClient_UpsertComment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLClient_UpsertComment_Call) RunAndReturn(run func(context.Context, string, string, int, int64, string) (int64, error)) *GLClient_UpsertComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewGLClient interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewGLClient creates a new instance of GLClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewGLClient(t mockConstructorTestingTNewGLClient) *GLClient {
+	mock := &GLClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,151 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	glprojects "github.com/ergomake/ergomake/internal/gitlab/glprojects"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GLProjectsProvider is an autogenerated mock type for the GLProjectsProvider type
+type GLProjectsProvider struct {
+	mock.Mock
+}
+
+type GLProjectsProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GLProjectsProvider) EXPECT() *GLProjectsProvider_Expecter {
+	return &GLProjectsProvider_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, owner, repo
+func (_m *GLProjectsProvider) Get(ctx context.Context, owner string, repo string) (*glprojects.Project, error) {
+	ret := _m.Called(ctx, owner, repo)
+
+	var r0 *glprojects.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*glprojects.Project, error)); ok {
+		return rf(ctx, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *glprojects.Project); ok {
+		r0 = rf(ctx, owner, repo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*glprojects.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLProjectsProvider_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type GLProjectsProvider_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+func (_e *GLProjectsProvider_Expecter) Get(ctx interface{}, owner interface{}, repo interface{}) *GLProjectsProvider_Get_Call {
+	return &GLProjectsProvider_Get_Call{Call: _e.mock.On("Get", ctx, owner, repo)}
+}
+
+func (_c *GLProjectsProvider_Get_Call) Run(run func(ctx context.Context, owner string, repo string)) *GLProjectsProvider_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *GLProjectsProvider_Get_Call) Return(_a0 *glprojects.Project, _a1 error) *GLProjectsProvider_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLProjectsProvider_Get_Call) RunAndReturn(run func(context.Context, string, string) (*glprojects.Project, error)) *GLProjectsProvider_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Register provides a mock function with given fields: ctx, owner, repo, token
+func (_m *GLProjectsProvider) Register(ctx context.Context, owner string, repo string, token string) (*glprojects.Project, error) {
+	ret := _m.Called(ctx, owner, repo, token)
+
+	var r0 *glprojects.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*glprojects.Project, error)); ok {
+		return rf(ctx, owner, repo, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *glprojects.Project); ok {
+		r0 = rf(ctx, owner, repo, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*glprojects.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GLProjectsProvider_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type GLProjectsProvider_Register_Call struct {
+	*mock.Call
+}
+
+// Register is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - token string
+func (_e *GLProjectsProvider_Expecter) Register(ctx interface{}, owner interface{}, repo interface{}, token interface{}) *GLProjectsProvider_Register_Call {
+	return &GLProjectsProvider_Register_Call{Call: _e.mock.On("Register", ctx, owner, repo, token)}
+}
+
+func (_c *GLProjectsProvider_Register_Call) Run(run func(ctx context.Context, owner string, repo string, token string)) *GLProjectsProvider_Register_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GLProjectsProvider_Register_Call) Return(_a0 *glprojects.Project, _a1 error) *GLProjectsProvider_Register_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GLProjectsProvider_Register_Call) RunAndReturn(run func(context.Context, string, string, string) (*glprojects.Project, error)) *GLProjectsProvider_Register_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewGLProjectsProvider interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewGLProjectsProvider creates a new instance of GLProjectsProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewGLProjectsProvider(t mockConstructorTestingTNewGLProjectsProvider) *GLProjectsProvider {
+	mock := &GLProjectsProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,102 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ChangesLister is an autogenerated mock type for the ChangesLister type
+type ChangesLister struct {
+	mock.Mock
+}
+
+type ChangesLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ChangesLister) EXPECT() *ChangesLister_Expecter {
+	return &ChangesLister_Expecter{mock: &_m.Mock}
+}
+
+// ListPullRequestChanges provides a mock function with given fields: ctx, owner, repo, prNumber, sha
+func (_m *ChangesLister) ListPullRequestChanges(ctx context.Context, owner string, repo string, prNumber int, sha string) (string, []string, error) {
+	ret := _m.Called(ctx, owner, repo, prNumber, sha)
+
+	var r0 string
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) (string, []string, error)); ok {
+		return rf(ctx, owner, repo, prNumber, sha)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) string); ok {
+		r0 = rf(ctx, owner, repo, prNumber, sha)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, string) []string); ok {
+		r1 = rf(ctx, owner, repo, prNumber, sha)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int, string) error); ok {
+		r2 = rf(ctx, owner, repo, prNumber, sha)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ChangesLister_ListPullRequestChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPullRequestChanges'
+type ChangesLister_ListPullRequestChanges_Call struct {
+	*mock.Call
+}
+
+// ListPullRequestChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - prNumber int
+//   - sha string
+func (_e *ChangesLister_Expecter) ListPullRequestChanges(ctx interface{}, owner interface{}, repo interface{}, prNumber interface{}, sha interface{}) *ChangesLister_ListPullRequestChanges_Call {
+	return &ChangesLister_ListPullRequestChanges_Call{Call: _e.mock.On("ListPullRequestChanges", ctx, owner, repo, prNumber, sha)}
+}
+
+func (_c *ChangesLister_ListPullRequestChanges_Call) Run(run func(ctx context.Context, owner string, repo string, prNumber int, sha string)) *ChangesLister_ListPullRequestChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *ChangesLister_ListPullRequestChanges_Call) Return(_a0 string, _a1 []string, _a2 error) *ChangesLister_ListPullRequestChanges_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *ChangesLister_ListPullRequestChanges_Call) RunAndReturn(run func(context.Context, string, string, int, string) (string, []string, error)) *ChangesLister_ListPullRequestChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewChangesLister interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewChangesLister creates a new instance of ChangesLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewChangesLister(t mockConstructorTestingTNewChangesLister) *ChangesLister {
+	mock := &ChangesLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
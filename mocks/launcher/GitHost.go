@@ -0,0 +1,554 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	database "github.com/ergomake/ergomake/internal/database"
+	launcher "github.com/ergomake/ergomake/internal/launcher"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GitHost is an autogenerated mock type for the GitHost type
+type GitHost struct {
+	mock.Mock
+}
+
+type GitHost_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GitHost) EXPECT() *GitHost_Expecter {
+	return &GitHost_Expecter{mock: &_m.Mock}
+}
+
+// CloneRepo provides a mock function with given fields: ctx, owner, repo, branch, dir, isPublic
+func (_m *GitHost) CloneRepo(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool) error {
+	ret := _m.Called(ctx, owner, repo, branch, dir, isPublic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool) error); ok {
+		r0 = rf(ctx, owner, repo, branch, dir, isPublic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GitHost_CloneRepo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloneRepo'
+type GitHost_CloneRepo_Call struct {
+	*mock.Call
+}
+
+// CloneRepo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+//   - dir string
+//   - isPublic bool
+func (_e *GitHost_Expecter) CloneRepo(ctx interface{}, owner interface{}, repo interface{}, branch interface{}, dir interface{}, isPublic interface{}) *GitHost_CloneRepo_Call {
+	return &GitHost_CloneRepo_Call{Call: _e.mock.On("CloneRepo", ctx, owner, repo, branch, dir, isPublic)}
+}
+
+func (_c *GitHost_CloneRepo_Call) Run(run func(ctx context.Context, owner string, repo string, branch string, dir string, isPublic bool)) *GitHost_CloneRepo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(bool))
+	})
+	return _c
+}
+
+func (_c *GitHost_CloneRepo_Call) Return(_a0 error) *GitHost_CloneRepo_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_CloneRepo_Call) RunAndReturn(run func(context.Context, string, string, string, string, bool) error) *GitHost_CloneRepo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Deactivate provides a mock function with given fields: ctx, env, description
+func (_m *GitHost) Deactivate(ctx context.Context, env *database.Environment, description string) error {
+	ret := _m.Called(ctx, env, description)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *database.Environment, string) error); ok {
+		r0 = rf(ctx, env, description)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GitHost_Deactivate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Deactivate'
+type GitHost_Deactivate_Call struct {
+	*mock.Call
+}
+
+// Deactivate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - env *database.Environment
+//   - description string
+func (_e *GitHost_Expecter) Deactivate(ctx interface{}, env interface{}, description interface{}) *GitHost_Deactivate_Call {
+	return &GitHost_Deactivate_Call{Call: _e.mock.On("Deactivate", ctx, env, description)}
+}
+
+func (_c *GitHost_Deactivate_Call) Run(run func(ctx context.Context, env *database.Environment, description string)) *GitHost_Deactivate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*database.Environment), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *GitHost_Deactivate_Call) Return(_a0 error) *GitHost_Deactivate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_Deactivate_Call) RunAndReturn(run func(context.Context, *database.Environment, string) error) *GitHost_Deactivate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DoesBranchExist provides a mock function with given fields: ctx, owner, repo, branch, branchOwner
+func (_m *GitHost) DoesBranchExist(ctx context.Context, owner string, repo string, branch string, branchOwner string) (bool, error) {
+	ret := _m.Called(ctx, owner, repo, branch, branchOwner)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (bool, error)); ok {
+		return rf(ctx, owner, repo, branch, branchOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) bool); ok {
+		r0 = rf(ctx, owner, repo, branch, branchOwner)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, branch, branchOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GitHost_DoesBranchExist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DoesBranchExist'
+type GitHost_DoesBranchExist_Call struct {
+	*mock.Call
+}
+
+// DoesBranchExist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branch string
+//   - branchOwner string
+func (_e *GitHost_Expecter) DoesBranchExist(ctx interface{}, owner interface{}, repo interface{}, branch interface{}, branchOwner interface{}) *GitHost_DoesBranchExist_Call {
+	return &GitHost_DoesBranchExist_Call{Call: _e.mock.On("DoesBranchExist", ctx, owner, repo, branch, branchOwner)}
+}
+
+func (_c *GitHost_DoesBranchExist_Call) Run(run func(ctx context.Context, owner string, repo string, branch string, branchOwner string)) *GitHost_DoesBranchExist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *GitHost_DoesBranchExist_Call) Return(_a0 bool, _a1 error) *GitHost_DoesBranchExist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GitHost_DoesBranchExist_Call) RunAndReturn(run func(context.Context, string, string, string, string) (bool, error)) *GitHost_DoesBranchExist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBaseURL provides a mock function with given fields:
+func (_m *GitHost) GetBaseURL() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GitHost_GetBaseURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBaseURL'
+type GitHost_GetBaseURL_Call struct {
+	*mock.Call
+}
+
+// GetBaseURL is a helper method to define mock.On call
+func (_e *GitHost_Expecter) GetBaseURL() *GitHost_GetBaseURL_Call {
+	return &GitHost_GetBaseURL_Call{Call: _e.mock.On("GetBaseURL")}
+}
+
+func (_c *GitHost_GetBaseURL_Call) Run(run func()) *GitHost_GetBaseURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GitHost_GetBaseURL_Call) Return(_a0 string) *GitHost_GetBaseURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_GetBaseURL_Call) RunAndReturn(run func() string) *GitHost_GetBaseURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneParams provides a mock function with given fields:
+func (_m *GitHost) GetCloneParams() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GitHost_GetCloneParams_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneParams'
+type GitHost_GetCloneParams_Call struct {
+	*mock.Call
+}
+
+// GetCloneParams is a helper method to define mock.On call
+func (_e *GitHost_Expecter) GetCloneParams() *GitHost_GetCloneParams_Call {
+	return &GitHost_GetCloneParams_Call{Call: _e.mock.On("GetCloneParams")}
+}
+
+func (_c *GitHost_GetCloneParams_Call) Run(run func()) *GitHost_GetCloneParams_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GitHost_GetCloneParams_Call) Return(_a0 []string) *GitHost_GetCloneParams_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_GetCloneParams_Call) RunAndReturn(run func() []string) *GitHost_GetCloneParams_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneToken provides a mock function with given fields: ctx, owner, repo
+func (_m *GitHost) GetCloneToken(ctx context.Context, owner string, repo string) (string, error) {
+	ret := _m.Called(ctx, owner, repo)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, owner, repo)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GitHost_GetCloneToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneToken'
+type GitHost_GetCloneToken_Call struct {
+	*mock.Call
+}
+
+// GetCloneToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+func (_e *GitHost_Expecter) GetCloneToken(ctx interface{}, owner interface{}, repo interface{}) *GitHost_GetCloneToken_Call {
+	return &GitHost_GetCloneToken_Call{Call: _e.mock.On("GetCloneToken", ctx, owner, repo)}
+}
+
+func (_c *GitHost_GetCloneToken_Call) Run(run func(ctx context.Context, owner string, repo string)) *GitHost_GetCloneToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *GitHost_GetCloneToken_Call) Return(_a0 string, _a1 error) *GitHost_GetCloneToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GitHost_GetCloneToken_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *GitHost_GetCloneToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCloneUrl provides a mock function with given fields:
+func (_m *GitHost) GetCloneUrl() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GitHost_GetCloneUrl_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloneUrl'
+type GitHost_GetCloneUrl_Call struct {
+	*mock.Call
+}
+
+// GetCloneUrl is a helper method to define mock.On call
+func (_e *GitHost_Expecter) GetCloneUrl() *GitHost_GetCloneUrl_Call {
+	return &GitHost_GetCloneUrl_Call{Call: _e.mock.On("GetCloneUrl")}
+}
+
+func (_c *GitHost_GetCloneUrl_Call) Run(run func()) *GitHost_GetCloneUrl_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GitHost_GetCloneUrl_Call) Return(_a0 string) *GitHost_GetCloneUrl_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_GetCloneUrl_Call) RunAndReturn(run func() string) *GitHost_GetCloneUrl_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDefaultBranch provides a mock function with given fields: ctx, owner, repo, branchOwner
+func (_m *GitHost) GetDefaultBranch(ctx context.Context, owner string, repo string, branchOwner string) (string, error) {
+	ret := _m.Called(ctx, owner, repo, branchOwner)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, owner, repo, branchOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, owner, repo, branchOwner)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repo, branchOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GitHost_GetDefaultBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDefaultBranch'
+type GitHost_GetDefaultBranch_Call struct {
+	*mock.Call
+}
+
+// GetDefaultBranch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repo string
+//   - branchOwner string
+func (_e *GitHost_Expecter) GetDefaultBranch(ctx interface{}, owner interface{}, repo interface{}, branchOwner interface{}) *GitHost_GetDefaultBranch_Call {
+	return &GitHost_GetDefaultBranch_Call{Call: _e.mock.On("GetDefaultBranch", ctx, owner, repo, branchOwner)}
+}
+
+func (_c *GitHost_GetDefaultBranch_Call) Run(run func(ctx context.Context, owner string, repo string, branchOwner string)) *GitHost_GetDefaultBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GitHost_GetDefaultBranch_Call) Return(_a0 string, _a1 error) *GitHost_GetDefaultBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GitHost_GetDefaultBranch_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *GitHost_GetDefaultBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Provider provides a mock function with given fields:
+func (_m *GitHost) Provider() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// GitHost_Provider_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Provider'
+type GitHost_Provider_Call struct {
+	*mock.Call
+}
+
+// Provider is a helper method to define mock.On call
+func (_e *GitHost_Expecter) Provider() *GitHost_Provider_Call {
+	return &GitHost_Provider_Call{Call: _e.mock.On("Provider")}
+}
+
+func (_c *GitHost_Provider_Call) Run(run func()) *GitHost_Provider_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *GitHost_Provider_Call) Return(_a0 string) *GitHost_Provider_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_Provider_Call) RunAndReturn(run func() string) *GitHost_Provider_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReportComment provides a mock function with given fields: ctx, db, env, body
+func (_m *GitHost) ReportComment(ctx context.Context, db *database.DB, env *database.Environment, body string) error {
+	ret := _m.Called(ctx, db, env, body)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *database.DB, *database.Environment, string) error); ok {
+		r0 = rf(ctx, db, env, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GitHost_ReportComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReportComment'
+type GitHost_ReportComment_Call struct {
+	*mock.Call
+}
+
+// ReportComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - db *database.DB
+//   - env *database.Environment
+//   - body string
+func (_e *GitHost_Expecter) ReportComment(ctx interface{}, db interface{}, env interface{}, body interface{}) *GitHost_ReportComment_Call {
+	return &GitHost_ReportComment_Call{Call: _e.mock.On("ReportComment", ctx, db, env, body)}
+}
+
+func (_c *GitHost_ReportComment_Call) Run(run func(ctx context.Context, db *database.DB, env *database.Environment, body string)) *GitHost_ReportComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*database.DB), args[2].(*database.Environment), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *GitHost_ReportComment_Call) Return(_a0 error) *GitHost_ReportComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_ReportComment_Call) RunAndReturn(run func(context.Context, *database.DB, *database.Environment, string) error) *GitHost_ReportComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReportStatus provides a mock function with given fields: ctx, db, envFrontendLink, env, sha, status
+func (_m *GitHost) ReportStatus(ctx context.Context, db *database.DB, envFrontendLink string, env *database.Environment, sha string, status launcher.LaunchStatus) error {
+	ret := _m.Called(ctx, db, envFrontendLink, env, sha, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *database.DB, string, *database.Environment, string, launcher.LaunchStatus) error); ok {
+		r0 = rf(ctx, db, envFrontendLink, env, sha, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GitHost_ReportStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReportStatus'
+type GitHost_ReportStatus_Call struct {
+	*mock.Call
+}
+
+// ReportStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - db *database.DB
+//   - envFrontendLink string
+//   - env *database.Environment
+//   - sha string
+//   - status launcher.LaunchStatus
+func (_e *GitHost_Expecter) ReportStatus(ctx interface{}, db interface{}, envFrontendLink interface{}, env interface{}, sha interface{}, status interface{}) *GitHost_ReportStatus_Call {
+	return &GitHost_ReportStatus_Call{Call: _e.mock.On("ReportStatus", ctx, db, envFrontendLink, env, sha, status)}
+}
+
+func (_c *GitHost_ReportStatus_Call) Run(run func(ctx context.Context, db *database.DB, envFrontendLink string, env *database.Environment, sha string, status launcher.LaunchStatus)) *GitHost_ReportStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*database.DB), args[2].(string), args[3].(*database.Environment), args[4].(string), args[5].(launcher.LaunchStatus))
+	})
+	return _c
+}
+
+func (_c *GitHost_ReportStatus_Call) Return(_a0 error) *GitHost_ReportStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GitHost_ReportStatus_Call) RunAndReturn(run func(context.Context, *database.DB, string, *database.Environment, string, launcher.LaunchStatus) error) *GitHost_ReportStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewGitHost interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewGitHost creates a new instance of GitHost. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewGitHost(t mockConstructorTestingTNewGitHost) *GitHost {
+	mock := &GitHost{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,119 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	launcher "github.com/ergomake/ergomake/internal/launcher"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Launcher is an autogenerated mock type for the Launcher type
+type Launcher struct {
+	mock.Mock
+}
+
+type Launcher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Launcher) EXPECT() *Launcher_Expecter {
+	return &Launcher_Expecter{mock: &_m.Mock}
+}
+
+// CancelLaunch provides a mock function with given fields: ctx, provider, owner, repo, branch, prNumber
+func (_m *Launcher) CancelLaunch(ctx context.Context, provider string, owner string, repo string, branch string, prNumber *int) {
+	_m.Called(ctx, provider, owner, repo, branch, prNumber)
+}
+
+// Launcher_CancelLaunch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelLaunch'
+type Launcher_CancelLaunch_Call struct {
+	*mock.Call
+}
+
+// CancelLaunch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+//   - branch string
+//   - prNumber *int
+func (_e *Launcher_Expecter) CancelLaunch(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, branch interface{}, prNumber interface{}) *Launcher_CancelLaunch_Call {
+	return &Launcher_CancelLaunch_Call{Call: _e.mock.On("CancelLaunch", ctx, provider, owner, repo, branch, prNumber)}
+}
+
+func (_c *Launcher_CancelLaunch_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, branch string, prNumber *int)) *Launcher_CancelLaunch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(*int))
+	})
+	return _c
+}
+
+func (_c *Launcher_CancelLaunch_Call) Return() *Launcher_CancelLaunch_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Launcher_CancelLaunch_Call) RunAndReturn(run func(context.Context, string, string, string, string, *int)) *Launcher_CancelLaunch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LaunchEnvironment provides a mock function with given fields: ctx, req
+func (_m *Launcher) LaunchEnvironment(ctx context.Context, req launcher.LaunchEnvironmentRequest) error {
+	ret := _m.Called(ctx, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, launcher.LaunchEnvironmentRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Launcher_LaunchEnvironment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LaunchEnvironment'
+type Launcher_LaunchEnvironment_Call struct {
+	*mock.Call
+}
+
+// LaunchEnvironment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req launcher.LaunchEnvironmentRequest
+func (_e *Launcher_Expecter) LaunchEnvironment(ctx interface{}, req interface{}) *Launcher_LaunchEnvironment_Call {
+	return &Launcher_LaunchEnvironment_Call{Call: _e.mock.On("LaunchEnvironment", ctx, req)}
+}
+
+func (_c *Launcher_LaunchEnvironment_Call) Run(run func(ctx context.Context, req launcher.LaunchEnvironmentRequest)) *Launcher_LaunchEnvironment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(launcher.LaunchEnvironmentRequest))
+	})
+	return _c
+}
+
+func (_c *Launcher_LaunchEnvironment_Call) Return(_a0 error) *Launcher_LaunchEnvironment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Launcher_LaunchEnvironment_Call) RunAndReturn(run func(context.Context, launcher.LaunchEnvironmentRequest) error) *Launcher_LaunchEnvironment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewLauncher interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLauncher creates a new instance of Launcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLauncher(t mockConstructorTestingTNewLauncher) *Launcher {
+	mock := &Launcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
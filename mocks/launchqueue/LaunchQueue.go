@@ -0,0 +1,185 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	environments "github.com/ergomake/ergomake/internal/environments"
+	launcher "github.com/ergomake/ergomake/internal/launcher"
+
+	launchqueue "github.com/ergomake/ergomake/internal/launchqueue"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LaunchQueue is an autogenerated mock type for the LaunchQueue type
+type LaunchQueue struct {
+	mock.Mock
+}
+
+type LaunchQueue_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LaunchQueue) EXPECT() *LaunchQueue_Expecter {
+	return &LaunchQueue_Expecter{mock: &_m.Mock}
+}
+
+// EnqueueLaunch provides a mock function with given fields: ctx, terminateReq, launchReq
+func (_m *LaunchQueue) EnqueueLaunch(ctx context.Context, terminateReq environments.TerminateEnvironmentRequest, launchReq launcher.LaunchEnvironmentRequest) error {
+	ret := _m.Called(ctx, terminateReq, launchReq)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, environments.TerminateEnvironmentRequest, launcher.LaunchEnvironmentRequest) error); ok {
+		r0 = rf(ctx, terminateReq, launchReq)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LaunchQueue_EnqueueLaunch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueLaunch'
+type LaunchQueue_EnqueueLaunch_Call struct {
+	*mock.Call
+}
+
+// EnqueueLaunch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - terminateReq environments.TerminateEnvironmentRequest
+//   - launchReq launcher.LaunchEnvironmentRequest
+func (_e *LaunchQueue_Expecter) EnqueueLaunch(ctx interface{}, terminateReq interface{}, launchReq interface{}) *LaunchQueue_EnqueueLaunch_Call {
+	return &LaunchQueue_EnqueueLaunch_Call{Call: _e.mock.On("EnqueueLaunch", ctx, terminateReq, launchReq)}
+}
+
+func (_c *LaunchQueue_EnqueueLaunch_Call) Run(run func(ctx context.Context, terminateReq environments.TerminateEnvironmentRequest, launchReq launcher.LaunchEnvironmentRequest)) *LaunchQueue_EnqueueLaunch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(environments.TerminateEnvironmentRequest), args[2].(launcher.LaunchEnvironmentRequest))
+	})
+	return _c
+}
+
+func (_c *LaunchQueue_EnqueueLaunch_Call) Return(_a0 error) *LaunchQueue_EnqueueLaunch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LaunchQueue_EnqueueLaunch_Call) RunAndReturn(run func(context.Context, environments.TerminateEnvironmentRequest, launcher.LaunchEnvironmentRequest) error) *LaunchQueue_EnqueueLaunch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnqueueTerminate provides a mock function with given fields: ctx, req
+func (_m *LaunchQueue) EnqueueTerminate(ctx context.Context, req environments.TerminateEnvironmentRequest) error {
+	ret := _m.Called(ctx, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, environments.TerminateEnvironmentRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LaunchQueue_EnqueueTerminate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueTerminate'
+type LaunchQueue_EnqueueTerminate_Call struct {
+	*mock.Call
+}
+
+// EnqueueTerminate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req environments.TerminateEnvironmentRequest
+func (_e *LaunchQueue_Expecter) EnqueueTerminate(ctx interface{}, req interface{}) *LaunchQueue_EnqueueTerminate_Call {
+	return &LaunchQueue_EnqueueTerminate_Call{Call: _e.mock.On("EnqueueTerminate", ctx, req)}
+}
+
+func (_c *LaunchQueue_EnqueueTerminate_Call) Run(run func(ctx context.Context, req environments.TerminateEnvironmentRequest)) *LaunchQueue_EnqueueTerminate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(environments.TerminateEnvironmentRequest))
+	})
+	return _c
+}
+
+func (_c *LaunchQueue_EnqueueTerminate_Call) Return(_a0 error) *LaunchQueue_EnqueueTerminate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LaunchQueue_EnqueueTerminate_Call) RunAndReturn(run func(context.Context, environments.TerminateEnvironmentRequest) error) *LaunchQueue_EnqueueTerminate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: ctx, provider, owner
+func (_m *LaunchQueue) Stats(ctx context.Context, provider string, owner string) (*launchqueue.Stats, error) {
+	ret := _m.Called(ctx, provider, owner)
+
+	var r0 *launchqueue.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*launchqueue.Stats, error)); ok {
+		return rf(ctx, provider, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *launchqueue.Stats); ok {
+		r0 = rf(ctx, provider, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*launchqueue.Stats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LaunchQueue_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type LaunchQueue_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+func (_e *LaunchQueue_Expecter) Stats(ctx interface{}, provider interface{}, owner interface{}) *LaunchQueue_Stats_Call {
+	return &LaunchQueue_Stats_Call{Call: _e.mock.On("Stats", ctx, provider, owner)}
+}
+
+func (_c *LaunchQueue_Stats_Call) Run(run func(ctx context.Context, provider string, owner string)) *LaunchQueue_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *LaunchQueue_Stats_Call) Return(_a0 *launchqueue.Stats, _a1 error) *LaunchQueue_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LaunchQueue_Stats_Call) RunAndReturn(run func(context.Context, string, string) (*launchqueue.Stats, error)) *LaunchQueue_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewLaunchQueue interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLaunchQueue creates a new instance of LaunchQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLaunchQueue(t mockConstructorTestingTNewLaunchQueue) *LaunchQueue {
+	mock := &LaunchQueue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,23 +22,23 @@ func (_m *PaymentProvider) EXPECT() *PaymentProvider_Expecter {
 	return &PaymentProvider_Expecter{mock: &_m.Mock}
 }
 
-// GetOwnerPlan provides a mock function with given fields: ctx, owner
-func (_m *PaymentProvider) GetOwnerPlan(ctx context.Context, owner string) (payment.PaymentPlan, error) {
-	ret := _m.Called(ctx, owner)
+// GetOwnerPlan provides a mock function with given fields: ctx, provider, owner
+func (_m *PaymentProvider) GetOwnerPlan(ctx context.Context, provider string, owner string) (payment.PaymentPlan, error) {
+	ret := _m.Called(ctx, provider, owner)
 
 	var r0 payment.PaymentPlan
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (payment.PaymentPlan, error)); ok {
-		return rf(ctx, owner)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (payment.PaymentPlan, error)); ok {
+		return rf(ctx, provider, owner)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) payment.PaymentPlan); ok {
-		r0 = rf(ctx, owner)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) payment.PaymentPlan); ok {
+		r0 = rf(ctx, provider, owner)
 	} else {
 		r0 = ret.Get(0).(payment.PaymentPlan)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, owner)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, owner)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,14 +53,15 @@ type PaymentProvider_GetOwnerPlan_Call struct {
 
 // GetOwnerPlan is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
-func (_e *PaymentProvider_Expecter) GetOwnerPlan(ctx interface{}, owner interface{}) *PaymentProvider_GetOwnerPlan_Call {
-	return &PaymentProvider_GetOwnerPlan_Call{Call: _e.mock.On("GetOwnerPlan", ctx, owner)}
+func (_e *PaymentProvider_Expecter) GetOwnerPlan(ctx interface{}, provider interface{}, owner interface{}) *PaymentProvider_GetOwnerPlan_Call {
+	return &PaymentProvider_GetOwnerPlan_Call{Call: _e.mock.On("GetOwnerPlan", ctx, provider, owner)}
 }
 
-func (_c *PaymentProvider_GetOwnerPlan_Call) Run(run func(ctx context.Context, owner string)) *PaymentProvider_GetOwnerPlan_Call {
+func (_c *PaymentProvider_GetOwnerPlan_Call) Run(run func(ctx context.Context, provider string, owner string)) *PaymentProvider_GetOwnerPlan_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -70,18 +71,18 @@ func (_c *PaymentProvider_GetOwnerPlan_Call) Return(_a0 payment.PaymentPlan, _a1
 	return _c
 }
 
-func (_c *PaymentProvider_GetOwnerPlan_Call) RunAndReturn(run func(context.Context, string) (payment.PaymentPlan, error)) *PaymentProvider_GetOwnerPlan_Call {
+func (_c *PaymentProvider_GetOwnerPlan_Call) RunAndReturn(run func(context.Context, string, string) (payment.PaymentPlan, error)) *PaymentProvider_GetOwnerPlan_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveSubscription provides a mock function with given fields: ctx, owner, subscriptionID
-func (_m *PaymentProvider) SaveSubscription(ctx context.Context, owner string, subscriptionID string) error {
-	ret := _m.Called(ctx, owner, subscriptionID)
+// SaveSubscription provides a mock function with given fields: ctx, provider, owner, subscriptionID
+func (_m *PaymentProvider) SaveSubscription(ctx context.Context, provider string, owner string, subscriptionID string) error {
+	ret := _m.Called(ctx, provider, owner, subscriptionID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, owner, subscriptionID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, provider, owner, subscriptionID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -96,15 +97,16 @@ type PaymentProvider_SaveSubscription_Call struct {
 
 // SaveSubscription is a helper method to define mock.On call
 //   - ctx context.Context
+//   - provider string
 //   - owner string
 //   - subscriptionID string
-func (_e *PaymentProvider_Expecter) SaveSubscription(ctx interface{}, owner interface{}, subscriptionID interface{}) *PaymentProvider_SaveSubscription_Call {
-	return &PaymentProvider_SaveSubscription_Call{Call: _e.mock.On("SaveSubscription", ctx, owner, subscriptionID)}
+func (_e *PaymentProvider_Expecter) SaveSubscription(ctx interface{}, provider interface{}, owner interface{}, subscriptionID interface{}) *PaymentProvider_SaveSubscription_Call {
+	return &PaymentProvider_SaveSubscription_Call{Call: _e.mock.On("SaveSubscription", ctx, provider, owner, subscriptionID)}
 }
 
-func (_c *PaymentProvider_SaveSubscription_Call) Run(run func(ctx context.Context, owner string, subscriptionID string)) *PaymentProvider_SaveSubscription_Call {
+func (_c *PaymentProvider_SaveSubscription_Call) Run(run func(ctx context.Context, provider string, owner string, subscriptionID string)) *PaymentProvider_SaveSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -114,7 +116,7 @@ func (_c *PaymentProvider_SaveSubscription_Call) Return(_a0 error) *PaymentProvi
 	return _c
 }
 
-func (_c *PaymentProvider_SaveSubscription_Call) RunAndReturn(run func(context.Context, string, string) error) *PaymentProvider_SaveSubscription_Call {
+func (_c *PaymentProvider_SaveSubscription_Call) RunAndReturn(run func(context.Context, string, string, string) error) *PaymentProvider_SaveSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,139 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	prfilters "github.com/ergomake/ergomake/internal/prfilters"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PRFiltersProvider is an autogenerated mock type for the PRFiltersProvider type
+type PRFiltersProvider struct {
+	mock.Mock
+}
+
+type PRFiltersProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PRFiltersProvider) EXPECT() *PRFiltersProvider_Expecter {
+	return &PRFiltersProvider_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, provider, owner, repo
+func (_m *PRFiltersProvider) Get(ctx context.Context, provider string, owner string, repo string) (prfilters.Rules, error) {
+	ret := _m.Called(ctx, provider, owner, repo)
+
+	var r0 prfilters.Rules
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (prfilters.Rules, error)); ok {
+		return rf(ctx, provider, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) prfilters.Rules); ok {
+		r0 = rf(ctx, provider, owner, repo)
+	} else {
+		r0 = ret.Get(0).(prfilters.Rules)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PRFiltersProvider_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type PRFiltersProvider_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+func (_e *PRFiltersProvider_Expecter) Get(ctx interface{}, provider interface{}, owner interface{}, repo interface{}) *PRFiltersProvider_Get_Call {
+	return &PRFiltersProvider_Get_Call{Call: _e.mock.On("Get", ctx, provider, owner, repo)}
+}
+
+func (_c *PRFiltersProvider_Get_Call) Run(run func(ctx context.Context, provider string, owner string, repo string)) *PRFiltersProvider_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *PRFiltersProvider_Get_Call) Return(_a0 prfilters.Rules, _a1 error) *PRFiltersProvider_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PRFiltersProvider_Get_Call) RunAndReturn(run func(context.Context, string, string, string) (prfilters.Rules, error)) *PRFiltersProvider_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Upsert provides a mock function with given fields: ctx, provider, owner, repo, rules
+func (_m *PRFiltersProvider) Upsert(ctx context.Context, provider string, owner string, repo string, rules prfilters.Rules) error {
+	ret := _m.Called(ctx, provider, owner, repo, rules)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, prfilters.Rules) error); ok {
+		r0 = rf(ctx, provider, owner, repo, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PRFiltersProvider_Upsert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Upsert'
+type PRFiltersProvider_Upsert_Call struct {
+	*mock.Call
+}
+
+// Upsert is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+//   - rules prfilters.Rules
+func (_e *PRFiltersProvider_Expecter) Upsert(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, rules interface{}) *PRFiltersProvider_Upsert_Call {
+	return &PRFiltersProvider_Upsert_Call{Call: _e.mock.On("Upsert", ctx, provider, owner, repo, rules)}
+}
+
+func (_c *PRFiltersProvider_Upsert_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, rules prfilters.Rules)) *PRFiltersProvider_Upsert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(prfilters.Rules))
+	})
+	return _c
+}
+
+func (_c *PRFiltersProvider_Upsert_Call) Return(_a0 error) *PRFiltersProvider_Upsert_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PRFiltersProvider_Upsert_Call) RunAndReturn(run func(context.Context, string, string, string, prfilters.Rules) error) *PRFiltersProvider_Upsert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewPRFiltersProvider interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPRFiltersProvider creates a new instance of PRFiltersProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPRFiltersProvider(t mockConstructorTestingTNewPRFiltersProvider) *PRFiltersProvider {
+	mock := &PRFiltersProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
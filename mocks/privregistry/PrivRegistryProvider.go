@@ -67,25 +67,25 @@ func (_c *PrivRegistryProvider_DeleteRegistry_Call) RunAndReturn(run func(contex
 	return _c
 }
 
-// FetchCreds provides a mock function with given fields: ctx, owner, image
-func (_m *PrivRegistryProvider) FetchCreds(ctx context.Context, owner string, image string) (*privregistry.RegistryCreds, error) {
-	ret := _m.Called(ctx, owner, image)
+// FetchCreds provides a mock function with given fields: ctx, gitProvider, owner, image
+func (_m *PrivRegistryProvider) FetchCreds(ctx context.Context, gitProvider string, owner string, image string) (*privregistry.RegistryCreds, error) {
+	ret := _m.Called(ctx, gitProvider, owner, image)
 
 	var r0 *privregistry.RegistryCreds
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*privregistry.RegistryCreds, error)); ok {
-		return rf(ctx, owner, image)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*privregistry.RegistryCreds, error)); ok {
+		return rf(ctx, gitProvider, owner, image)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *privregistry.RegistryCreds); ok {
-		r0 = rf(ctx, owner, image)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *privregistry.RegistryCreds); ok {
+		r0 = rf(ctx, gitProvider, owner, image)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*privregistry.RegistryCreds)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, owner, image)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, gitProvider, owner, image)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,15 +100,16 @@ type PrivRegistryProvider_FetchCreds_Call struct {
 
 // FetchCreds is a helper method to define mock.On call
 //   - ctx context.Context
+//   - gitProvider string
 //   - owner string
 //   - image string
-func (_e *PrivRegistryProvider_Expecter) FetchCreds(ctx interface{}, owner interface{}, image interface{}) *PrivRegistryProvider_FetchCreds_Call {
-	return &PrivRegistryProvider_FetchCreds_Call{Call: _e.mock.On("FetchCreds", ctx, owner, image)}
+func (_e *PrivRegistryProvider_Expecter) FetchCreds(ctx interface{}, gitProvider interface{}, owner interface{}, image interface{}) *PrivRegistryProvider_FetchCreds_Call {
+	return &PrivRegistryProvider_FetchCreds_Call{Call: _e.mock.On("FetchCreds", ctx, gitProvider, owner, image)}
 }
 
-func (_c *PrivRegistryProvider_FetchCreds_Call) Run(run func(ctx context.Context, owner string, image string)) *PrivRegistryProvider_FetchCreds_Call {
+func (_c *PrivRegistryProvider_FetchCreds_Call) Run(run func(ctx context.Context, gitProvider string, owner string, image string)) *PrivRegistryProvider_FetchCreds_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -118,30 +119,30 @@ func (_c *PrivRegistryProvider_FetchCreds_Call) Return(_a0 *privregistry.Registr
 	return _c
 }
 
-func (_c *PrivRegistryProvider_FetchCreds_Call) RunAndReturn(run func(context.Context, string, string) (*privregistry.RegistryCreds, error)) *PrivRegistryProvider_FetchCreds_Call {
+func (_c *PrivRegistryProvider_FetchCreds_Call) RunAndReturn(run func(context.Context, string, string, string) (*privregistry.RegistryCreds, error)) *PrivRegistryProvider_FetchCreds_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListCredsByOwner provides a mock function with given fields: ctx, owner, skipToken
-func (_m *PrivRegistryProvider) ListCredsByOwner(ctx context.Context, owner string, skipToken bool) ([]privregistry.RegistryCreds, error) {
-	ret := _m.Called(ctx, owner, skipToken)
+// ListCredsByOwner provides a mock function with given fields: ctx, gitProvider, owner, skipToken
+func (_m *PrivRegistryProvider) ListCredsByOwner(ctx context.Context, gitProvider string, owner string, skipToken bool) ([]privregistry.RegistryCreds, error) {
+	ret := _m.Called(ctx, gitProvider, owner, skipToken)
 
 	var r0 []privregistry.RegistryCreds
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) ([]privregistry.RegistryCreds, error)); ok {
-		return rf(ctx, owner, skipToken)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) ([]privregistry.RegistryCreds, error)); ok {
+		return rf(ctx, gitProvider, owner, skipToken)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) []privregistry.RegistryCreds); ok {
-		r0 = rf(ctx, owner, skipToken)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) []privregistry.RegistryCreds); ok {
+		r0 = rf(ctx, gitProvider, owner, skipToken)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]privregistry.RegistryCreds)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
-		r1 = rf(ctx, owner, skipToken)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, gitProvider, owner, skipToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -156,15 +157,16 @@ type PrivRegistryProvider_ListCredsByOwner_Call struct {
 
 // ListCredsByOwner is a helper method to define mock.On call
 //   - ctx context.Context
+//   - gitProvider string
 //   - owner string
 //   - skipToken bool
-func (_e *PrivRegistryProvider_Expecter) ListCredsByOwner(ctx interface{}, owner interface{}, skipToken interface{}) *PrivRegistryProvider_ListCredsByOwner_Call {
-	return &PrivRegistryProvider_ListCredsByOwner_Call{Call: _e.mock.On("ListCredsByOwner", ctx, owner, skipToken)}
+func (_e *PrivRegistryProvider_Expecter) ListCredsByOwner(ctx interface{}, gitProvider interface{}, owner interface{}, skipToken interface{}) *PrivRegistryProvider_ListCredsByOwner_Call {
+	return &PrivRegistryProvider_ListCredsByOwner_Call{Call: _e.mock.On("ListCredsByOwner", ctx, gitProvider, owner, skipToken)}
 }
 
-func (_c *PrivRegistryProvider_ListCredsByOwner_Call) Run(run func(ctx context.Context, owner string, skipToken bool)) *PrivRegistryProvider_ListCredsByOwner_Call {
+func (_c *PrivRegistryProvider_ListCredsByOwner_Call) Run(run func(ctx context.Context, gitProvider string, owner string, skipToken bool)) *PrivRegistryProvider_ListCredsByOwner_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(bool))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(bool))
 	})
 	return _c
 }
@@ -174,18 +176,18 @@ func (_c *PrivRegistryProvider_ListCredsByOwner_Call) Return(_a0 []privregistry.
 	return _c
 }
 
-func (_c *PrivRegistryProvider_ListCredsByOwner_Call) RunAndReturn(run func(context.Context, string, bool) ([]privregistry.RegistryCreds, error)) *PrivRegistryProvider_ListCredsByOwner_Call {
+func (_c *PrivRegistryProvider_ListCredsByOwner_Call) RunAndReturn(run func(context.Context, string, string, bool) ([]privregistry.RegistryCreds, error)) *PrivRegistryProvider_ListCredsByOwner_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// StoreRegistry provides a mock function with given fields: ctx, owner, url, provider, credentials
-func (_m *PrivRegistryProvider) StoreRegistry(ctx context.Context, owner string, url string, provider string, credentials string) error {
-	ret := _m.Called(ctx, owner, url, provider, credentials)
+// StoreRegistry provides a mock function with given fields: ctx, gitProvider, owner, url, provider, credentials
+func (_m *PrivRegistryProvider) StoreRegistry(ctx context.Context, gitProvider string, owner string, url string, provider string, credentials string) error {
+	ret := _m.Called(ctx, gitProvider, owner, url, provider, credentials)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
-		r0 = rf(ctx, owner, url, provider, credentials)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, gitProvider, owner, url, provider, credentials)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -200,17 +202,18 @@ type PrivRegistryProvider_StoreRegistry_Call struct {
 
 // StoreRegistry is a helper method to define mock.On call
 //   - ctx context.Context
+//   - gitProvider string
 //   - owner string
 //   - url string
 //   - provider string
 //   - credentials string
-func (_e *PrivRegistryProvider_Expecter) StoreRegistry(ctx interface{}, owner interface{}, url interface{}, provider interface{}, credentials interface{}) *PrivRegistryProvider_StoreRegistry_Call {
-	return &PrivRegistryProvider_StoreRegistry_Call{Call: _e.mock.On("StoreRegistry", ctx, owner, url, provider, credentials)}
+func (_e *PrivRegistryProvider_Expecter) StoreRegistry(ctx interface{}, gitProvider interface{}, owner interface{}, url interface{}, provider interface{}, credentials interface{}) *PrivRegistryProvider_StoreRegistry_Call {
+	return &PrivRegistryProvider_StoreRegistry_Call{Call: _e.mock.On("StoreRegistry", ctx, gitProvider, owner, url, provider, credentials)}
 }
 
-func (_c *PrivRegistryProvider_StoreRegistry_Call) Run(run func(ctx context.Context, owner string, url string, provider string, credentials string)) *PrivRegistryProvider_StoreRegistry_Call {
+func (_c *PrivRegistryProvider_StoreRegistry_Call) Run(run func(ctx context.Context, gitProvider string, owner string, url string, provider string, credentials string)) *PrivRegistryProvider_StoreRegistry_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(string))
 	})
 	return _c
 }
@@ -220,7 +223,7 @@ func (_c *PrivRegistryProvider_StoreRegistry_Call) Return(_a0 error) *PrivRegist
 	return _c
 }
 
-func (_c *PrivRegistryProvider_StoreRegistry_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *PrivRegistryProvider_StoreRegistry_Call {
+func (_c *PrivRegistryProvider_StoreRegistry_Call) RunAndReturn(run func(context.Context, string, string, string, string, string) error) *PrivRegistryProvider_StoreRegistry_Call {
 	_c.Call.Return(run)
 	return _c
 }
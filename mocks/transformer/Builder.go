@@ -0,0 +1,95 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	transformer "github.com/ergomake/ergomake/internal/transformer"
+	mock "github.com/stretchr/testify/mock"
+
+	v1 "k8s.io/api/batch/v1"
+)
+
+// Builder is an autogenerated mock type for the Builder type
+type Builder struct {
+	mock.Mock
+}
+
+type Builder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Builder) EXPECT() *Builder_Expecter {
+	return &Builder_Expecter{mock: &_m.Mock}
+}
+
+// Build provides a mock function with given fields: ctx, build
+func (_m *Builder) Build(ctx context.Context, build *transformer.ImageBuild) (*v1.Job, error) {
+	ret := _m.Called(ctx, build)
+
+	var r0 *v1.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transformer.ImageBuild) (*v1.Job, error)); ok {
+		return rf(ctx, build)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transformer.ImageBuild) *v1.Job); ok {
+		r0 = rf(ctx, build)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transformer.ImageBuild) error); ok {
+		r1 = rf(ctx, build)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Builder_Build_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Build'
+type Builder_Build_Call struct {
+	*mock.Call
+}
+
+// Build is a helper method to define mock.On call
+//   - ctx context.Context
+//   - build *transformer.ImageBuild
+func (_e *Builder_Expecter) Build(ctx interface{}, build interface{}) *Builder_Build_Call {
+	return &Builder_Build_Call{Call: _e.mock.On("Build", ctx, build)}
+}
+
+func (_c *Builder_Build_Call) Run(run func(ctx context.Context, build *transformer.ImageBuild)) *Builder_Build_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*transformer.ImageBuild))
+	})
+	return _c
+}
+
+func (_c *Builder_Build_Call) Return(_a0 *v1.Job, _a1 error) *Builder_Build_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Builder_Build_Call) RunAndReturn(run func(context.Context, *transformer.ImageBuild) (*v1.Job, error)) *Builder_Build_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewBuilder interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewBuilder creates a new instance of Builder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewBuilder(t mockConstructorTestingTNewBuilder) *Builder {
+	mock := &Builder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
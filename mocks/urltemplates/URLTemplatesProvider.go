@@ -0,0 +1,138 @@
+// Code generated by mockery v2.26.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// URLTemplatesProvider is an autogenerated mock type for the URLTemplatesProvider type
+type URLTemplatesProvider struct {
+	mock.Mock
+}
+
+type URLTemplatesProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLTemplatesProvider) EXPECT() *URLTemplatesProvider_Expecter {
+	return &URLTemplatesProvider_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, provider, owner, repo
+func (_m *URLTemplatesProvider) Get(ctx context.Context, provider string, owner string, repo string) (string, error) {
+	ret := _m.Called(ctx, provider, owner, repo)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, provider, owner, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, provider, owner, repo)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, provider, owner, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLTemplatesProvider_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type URLTemplatesProvider_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+func (_e *URLTemplatesProvider_Expecter) Get(ctx interface{}, provider interface{}, owner interface{}, repo interface{}) *URLTemplatesProvider_Get_Call {
+	return &URLTemplatesProvider_Get_Call{Call: _e.mock.On("Get", ctx, provider, owner, repo)}
+}
+
+func (_c *URLTemplatesProvider_Get_Call) Run(run func(ctx context.Context, provider string, owner string, repo string)) *URLTemplatesProvider_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *URLTemplatesProvider_Get_Call) Return(_a0 string, _a1 error) *URLTemplatesProvider_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLTemplatesProvider_Get_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *URLTemplatesProvider_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Upsert provides a mock function with given fields: ctx, provider, owner, repo, template
+func (_m *URLTemplatesProvider) Upsert(ctx context.Context, provider string, owner string, repo string, template string) error {
+	ret := _m.Called(ctx, provider, owner, repo, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, provider, owner, repo, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLTemplatesProvider_Upsert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Upsert'
+type URLTemplatesProvider_Upsert_Call struct {
+	*mock.Call
+}
+
+// Upsert is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - owner string
+//   - repo string
+//   - template string
+func (_e *URLTemplatesProvider_Expecter) Upsert(ctx interface{}, provider interface{}, owner interface{}, repo interface{}, template interface{}) *URLTemplatesProvider_Upsert_Call {
+	return &URLTemplatesProvider_Upsert_Call{Call: _e.mock.On("Upsert", ctx, provider, owner, repo, template)}
+}
+
+func (_c *URLTemplatesProvider_Upsert_Call) Run(run func(ctx context.Context, provider string, owner string, repo string, template string)) *URLTemplatesProvider_Upsert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *URLTemplatesProvider_Upsert_Call) Return(_a0 error) *URLTemplatesProvider_Upsert_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLTemplatesProvider_Upsert_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *URLTemplatesProvider_Upsert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+type mockConstructorTestingTNewURLTemplatesProvider interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewURLTemplatesProvider creates a new instance of URLTemplatesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewURLTemplatesProvider(t mockConstructorTestingTNewURLTemplatesProvider) *URLTemplatesProvider {
+	mock := &URLTemplatesProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}